@@ -221,10 +221,21 @@ type Keyframes[T any] struct {
 	// nil then T must implement [Lerper] or be one of the built-in integer or
 	// float types.
 	Lerp func(T, T, float64) T
+	// Expression, if not nil, computes the value at a frame instead of the
+	// keyframes. It may use EvaluateKeyframes.
+	Expression func(frame float64) T
 }
 
 // Evaluate implements Animatable.
 func (v *Keyframes[T]) Evaluate(frame float64) T {
+	if v.Expression != nil {
+		return v.Expression(frame)
+	}
+	return v.EvaluateKeyframes(frame)
+}
+
+// EvaluateKeyframes interpolates between the keyframes, ignoring Expression.
+func (v *Keyframes[T]) EvaluateKeyframes(frame float64) T {
 	var def T
 
 	v1, v2, t, ok := v.ComputeFramesAndWeight(frame)
@@ -270,6 +281,11 @@ func (v *Keyframes[T]) Evaluate(frame float64) T {
 	}
 }
 
+// IsStatic reports whether the value doesn't change over time.
+func (kfs Keyframes[T]) IsStatic() bool {
+	return kfs.Expression == nil && len(kfs.Frames) <= 1
+}
+
 func (kfs Keyframes[T]) ComputeFramesAndWeight(frame float64) (startValue, endValue T, t float64, ok bool) {
 	if len(kfs.Frames) == 0 {
 		return *new(T), *new(T), 0, false
@@ -331,6 +347,16 @@ func (t KeyframedTransform) Evaluate(frame float64) curve.Affine {
 		Mul(curve.Translate(curve.Vec(-anchor.X, -anchor.Y)))
 }
 
+// IsStatic reports whether the transform doesn't change over time.
+func (t KeyframedTransform) IsStatic() bool {
+	return t.Anchor.IsStatic() &&
+		t.Position.IsStatic() &&
+		t.Rotation.IsStatic() &&
+		t.Scale.IsStatic() &&
+		t.Skew.IsStatic() &&
+		t.SkewAngle.IsStatic()
+}
+
 type KeyframedVec2 struct {
 	X, Y Keyframes[float64]
 }
@@ -343,6 +369,8 @@ func (p KeyframedVec2) Evaluate(frame float64) curve.Vec2 {
 	}
 }
 
+func (p KeyframedVec2) IsStatic() bool { return p.X.IsStatic() && p.Y.IsStatic() }
+
 type KeyframedPoint struct {
 	X, Y Keyframes[float64]
 }
@@ -355,6 +383,8 @@ func (p KeyframedPoint) Evaluate(frame float64) curve.Point {
 	}
 }
 
+func (p KeyframedPoint) IsStatic() bool { return p.X.IsStatic() && p.Y.IsStatic() }
+
 type KeyframedSize struct {
 	Width, Height Keyframes[float64]
 }
@@ -367,6 +397,8 @@ func (sz KeyframedSize) Evaluate(frame float64) curve.Size {
 	}
 }
 
+func (sz KeyframedSize) IsStatic() bool { return sz.Width.IsStatic() && sz.Height.IsStatic() }
+
 // Evaluate implements Animatable.
 type KeyframedStroke struct {
 	Width      Keyframes[float64]
@@ -384,6 +416,8 @@ func (s KeyframedStroke) Evaluate(frame float64) curve.Stroke {
 	return stroke
 }
 
+func (s KeyframedStroke) IsStatic() bool { return s.Width.IsStatic() }
+
 type KeyframedEllipse struct {
 	Position KeyframedPoint
 	Size     KeyframedSize
@@ -397,6 +431,8 @@ func (e KeyframedEllipse) Evaluate(frame float64) curve.Ellipse {
 	return curve.NewEllipse(pos, radii, 0)
 }
 
+func (e KeyframedEllipse) IsStatic() bool { return e.Position.IsStatic() && e.Size.IsStatic() }
+
 type KeyframedRoundedRect struct {
 	Position     KeyframedPoint
 	Size         KeyframedSize
@@ -416,6 +452,10 @@ func (r KeyframedRoundedRect) Evaluate(frame float64) curve.RoundedRect {
 	})
 }
 
+func (r KeyframedRoundedRect) IsStatic() bool {
+	return r.Position.IsStatic() && r.Size.IsStatic() && r.CornerRadius.IsStatic()
+}
+
 type KeyframedColorStops struct {
 	Keyframes[[]gfx.GradientStop]
 	ColorSpace *color.Space
@@ -477,3 +517,7 @@ func (g KeyframedGradient) Evaluate(frame float64) gfx.Paint {
 		}
 	}
 }
+
+func (g KeyframedGradient) IsStatic() bool {
+	return g.StartPoint.IsStatic() && g.EndPoint.IsStatic() && g.Stops.IsStatic()
+}
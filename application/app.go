@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"time"
 
 	"honnef.co/go/color"
@@ -36,6 +37,41 @@ type application struct {
 	size          wsi.LogicalSize
 
 	renderer *sparse.Renderer
+
+	textInput TextInputClient
+}
+
+// TextInputClient is a text field that receives text from input methods, such
+// as IMEs.
+type TextInputClient interface {
+	// HandleTextInput handles a [wsi.PreeditString], [wsi.CommitString], or
+	// [wsi.DeleteSurroundingText] event.
+	HandleTextInput(ev wsi.Event)
+	// TextInputCursorRect returns the area around the text cursor, in logical
+	// window coordinates. Input methods use it to position candidate windows.
+	TextInputCursorRect() curve.Rect
+}
+
+// SetTextInputClient directs text from input methods to client, which should
+// be the text field that has focus. Setting a nil client, such as when a text
+// field loses focus, disables input methods. SetTextInputClient must be called
+// from the application's event loop, for example from widget callbacks.
+func (app *application) SetTextInputClient(client TextInputClient) {
+	app.textInput = client
+	if app.win != nil {
+		app.updateTextInput()
+	}
+}
+
+func (app *application) updateTextInput() {
+	if app.textInput == nil {
+		app.win.DisableTextInput()
+		return
+	}
+	app.win.EnableTextInput()
+	r := app.textInput.TextInputCursorRect()
+	x0, y0 := math.Floor(r.X0), math.Floor(r.Y0)
+	app.win.SetTextInputCursorRect(int(x0), int(y0), int(math.Ceil(r.X1)-x0), int(math.Ceil(r.Y1)-y0))
 }
 
 // WindowEvent implements wsi.Application.
@@ -44,6 +80,9 @@ func (app *application) WindowEvent(ctx *wsi.Context, ev wsi.Event) {
 	case *wsi.EventInitialized:
 		app.win = ctx.CreateWindow().(*wsi.WaylandWindow)
 		app.widgetBinding = widget.RunApp(app.sys, app.win, app.root)
+		if app.textInput != nil {
+			app.updateTextInput()
+		}
 	case *wsi.Resized:
 		if ev.Size == (wsi.LogicalSize{}) {
 			ev.Size = wsi.LogicalSize{Width: 500, Height: 500}
@@ -85,6 +124,10 @@ func (app *application) WindowEvent(ctx *wsi.Context, ev wsi.Event) {
 		if printDetailedTimings {
 			log.Printf("recorded frame in: %s", time.Since(t))
 		}
+		if app.textInput != nil {
+			// The frame may have moved the text cursor.
+			app.updateTextInput()
+		}
 
 		t = time.Now()
 		app.renderer.Reset()
@@ -126,6 +169,11 @@ func (app *application) WindowEvent(ctx *wsi.Context, ev wsi.Event) {
 		}
 
 		app.win.Present(buf, 0, 0, sz.Width, sz.Height)
+	case *wsi.PreeditString, *wsi.CommitString, *wsi.DeleteSurroundingText:
+		// Events that arrive after text input has been disabled are dropped.
+		if app.textInput != nil {
+			app.textInput.HandleTextInput(ev)
+		}
 	case widgets.CallbackEvent:
 		ev()
 	default:
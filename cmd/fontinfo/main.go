@@ -0,0 +1,572 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Command fontinfo prints what gutter knows about fonts.
+//
+// Usage:
+//
+//	fontinfo dump file...
+//	fontinfo families [-fonts dir] [-faces]
+//	fontinfo match [-fonts dir] family [axis=value...]
+//
+// The dump command prints the tables of font files, including their names,
+// variation axes, OS/2 classes, cmap coverage and layout features. The
+// families and match commands print the font database's view of the
+// system's fonts, or of the fonts in a directory, and explain which face
+// gets matched for a query, such as "match Roboto wght=700 ital=1".
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"honnef.co/go/gutter/fontdb"
+	"honnef.co/go/gutter/opentype"
+	"honnef.co/go/gutter/opentype/opentypehl"
+
+	"golang.org/x/text/language"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: fontinfo dump file...
+       fontinfo families [-fonts dir] [-faces]
+       fontinfo match [-fonts dir] family [axis=value...]`)
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("fontinfo: ")
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+	switch cmd {
+	case "dump":
+		fs := flag.NewFlagSet("dump", flag.ExitOnError)
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			usage()
+		}
+		for _, path := range fs.Args() {
+			if err := dump(os.Stdout, path); err != nil {
+				log.Print(err)
+			}
+		}
+	case "families":
+		fs := flag.NewFlagSet("families", flag.ExitOnError)
+		dir := fs.String("fonts", "", "use the fonts in `dir` instead of the system's fonts")
+		faces := fs.Bool("faces", false, "print each family's faces")
+		fs.Parse(args)
+		families(os.Stdout, loadDB(*dir), *faces)
+	case "match":
+		fs := flag.NewFlagSet("match", flag.ExitOnError)
+		dir := fs.String("fonts", "", "use the fonts in `dir` instead of the system's fonts")
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			usage()
+		}
+		query, err := parseQuery(fs.Args()[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		match(os.Stdout, loadDB(*dir), fs.Arg(0), query)
+	default:
+		usage()
+	}
+}
+
+func loadDB(dir string) *fontdb.Faces {
+	if dir == "" {
+		return fontdb.New()
+	}
+	db := fontdb.NewEmpty()
+	if err := db.AddFS(os.DirFS(dir)); err != nil {
+		// Some fonts may have been added regardless.
+		log.Print(err)
+	}
+	return db
+}
+
+// parseQuery parses axis values of the form wght=700.
+func parseQuery(args []string) (map[opentype.Tag]float64, error) {
+	query := make(map[opentype.Tag]float64)
+	for _, arg := range args {
+		tag, value, ok := strings.Cut(arg, "=")
+		if !ok || len(tag) == 0 || len(tag) > 4 {
+			return nil, fmt.Errorf("invalid axis value %q, expected e.g. wght=700", arg)
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid axis value %q: %s", arg, err)
+		}
+		query[opentype.Tag(fmt.Sprintf("%-4s", tag))] = v
+	}
+	return query, nil
+}
+
+func families(w io.Writer, db *fontdb.Faces, faces bool) {
+	for _, fam := range slices.Sorted(maps.Keys(db.Faces)) {
+		fmt.Fprintf(w, "%s (%d faces)\n", fam, len(db.Faces[fam]))
+		if !faces {
+			continue
+		}
+		for _, face := range db.Faces[fam] {
+			fmt.Fprintf(w, "\t%s\t%s\n", faceName(face), formatAxes(face.Axes))
+		}
+	}
+}
+
+func match(w io.Writer, db *fontdb.Faces, family string, query map[opentype.Tag]float64) {
+	candidates := db.Candidates(family, query)
+	if len(candidates) == 0 {
+		fmt.Fprintf(w, "no faces found for family %q\n", family)
+		return
+	}
+
+	fmt.Fprintf(w, "query: %s %s\n", family, formatValues(query))
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "rank\tdistance\tdot\tface\taxes")
+	for i, c := range candidates {
+		fmt.Fprintf(tw, "%d\t%.3f\t%.3f\t%s\t%s\n", i+1, c.Distance, c.Dot, faceName(c.Face), formatAxes(c.Face.Axes))
+	}
+	tw.Flush()
+
+	v, _ := db.Match(family, query)
+	fmt.Fprintf(w, "match: %s %s\n", faceName(v.Font), formatValues(v.AxisValues))
+}
+
+func faceName(face *fontdb.Face) string {
+	if face.Index != 0 {
+		return fmt.Sprintf("%s#%d", face.Path, face.Index)
+	}
+	return face.Path
+}
+
+func formatAxes(axes map[opentype.Tag]fontdb.Axis) string {
+	var parts []string
+	for _, tag := range slices.Sorted(maps.Keys(axes)) {
+		a := axes[tag]
+		if a.Min == a.Max {
+			parts = append(parts, fmt.Sprintf("%s=%g", tag, a.Default))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s=%g..%g(%g)", tag, a.Min, a.Max, a.Default))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatValues(values map[opentype.Tag]float64) string {
+	var parts []string
+	for _, tag := range slices.Sorted(maps.Keys(values)) {
+		parts = append(parts, fmt.Sprintf("%s=%g", tag, values[tag]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func dump(w io.Writer, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	n := opentype.NumFonts(data)
+	if n == 0 {
+		return fmt.Errorf("%s: not a font", path)
+	}
+	for i := range n {
+		fmt.Fprintf(w, "== %s (face %d of %d)\n", path, i, n)
+		f, err := opentypehl.NewFileIndex(data, i)
+		if err != nil {
+			fmt.Fprintf(w, "error: %s\n\n", err)
+			continue
+		}
+		d := dumper{w: w, f: f}
+		d.tables()
+		d.names()
+		d.fvar()
+		d.stat()
+		d.os2()
+		d.cmap()
+		d.layout("GSUB")
+		d.layout("GPOS")
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+type dumper struct {
+	w         io.Writer
+	f         *opentypehl.File
+	nameTable *opentypehl.NameTable
+}
+
+func (d *dumper) table(tag opentype.Tag) ([]byte, bool) {
+	rec, ok := d.f.Raw().FindTable(tag)
+	if !ok {
+		return nil, false
+	}
+	return rec.Data(), true
+}
+
+func (d *dumper) section(title string) {
+	fmt.Fprintf(d.w, "\n%s:\n", title)
+}
+
+// name returns the English version of a name, for labeling things that
+// refer to names by ID.
+func (d *dumper) name(id opentype.NameID) string {
+	if d.nameTable == nil {
+		return strconv.Itoa(int(id))
+	}
+	name, ok := d.nameTable.Lookup(id, language.English)
+	if !ok {
+		return strconv.Itoa(int(id))
+	}
+	return strconv.Quote(name.String())
+}
+
+func (d *dumper) tables() {
+	d.section("Tables")
+	tw := tabwriter.NewWriter(d.w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "\ttag\toffset\tlength\tchecksum\t")
+	for _, rec := range d.f.Raw().TableRecords() {
+		status := "ok"
+		data := rec.Data()
+		sum := opentype.TableChecksum(data)
+		if rec.Tag == "head" && len(data) >= 12 {
+			// The head table's checksum is computed with the checksum
+			// adjustment set to zero.
+			sum -= binary.BigEndian.Uint32(data[8:])
+		}
+		if sum != rec.Checksum {
+			status = fmt.Sprintf("mismatch, computed %#08x", sum)
+		}
+		fmt.Fprintf(tw, "\t%q\t%d\t%d\t%#08x\t%s\n", rec.Tag, rec.Offset, rec.Length, rec.Checksum, status)
+	}
+	tw.Flush()
+}
+
+func (d *dumper) names() {
+	if _, ok := d.table("name"); !ok {
+		return
+	}
+	d.nameTable = d.f.Names()
+	d.section("Names")
+	tw := tabwriter.NewWriter(d.w, 0, 8, 2, ' ', 0)
+	for name := range d.nameTable.All() {
+		str := "<undecodable>"
+		if name.Decodable() {
+			str = strconv.Quote(name.String())
+		}
+		fmt.Fprintf(tw, "\t%s\t%s\t%s\n", name.ID(), name.Language(), str)
+	}
+	tw.Flush()
+}
+
+func (d *dumper) fvar() {
+	data, ok := d.table("fvar")
+	if !ok {
+		return
+	}
+	var fvar opentype.FvarTable
+	opentype.ParseFvarTable(data, &fvar)
+
+	d.section("Axes (fvar)")
+	tw := tabwriter.NewWriter(d.w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "\ttag\tmin\tdefault\tmax\tname\t")
+	var tags []opentype.Tag
+	for _, axis := range fvar.Axes() {
+		tags = append(tags, axis.Tag)
+		hidden := ""
+		if axis.Flags&0x0001 != 0 {
+			hidden = "hidden"
+		}
+		fmt.Fprintf(tw, "\t%q\t%g\t%g\t%g\t%s\t%s\n", axis.Tag,
+			axis.MinValue.Float(), axis.DefaultValue.Float(), axis.MaxValue.Float(), d.name(axis.NameID), hidden)
+	}
+	tw.Flush()
+
+	if fvar.NumInstances() == 0 {
+		return
+	}
+	d.section("Named instances (fvar)")
+	tw = tabwriter.NewWriter(d.w, 0, 8, 2, ' ', 0)
+	for _, inst := range fvar.Instances() {
+		var coords []string
+		for i, v := range inst.Coordinates {
+			coords = append(coords, fmt.Sprintf("%s=%g", tags[i], v))
+		}
+		ps := ""
+		if inst.PostScriptNameID != 0xFFFF {
+			ps = "PostScript name " + d.name(inst.PostScriptNameID)
+		}
+		fmt.Fprintf(tw, "\t%s\t%s\t%s\n", d.name(inst.SubfamilyNameID), strings.Join(coords, " "), ps)
+	}
+	tw.Flush()
+}
+
+func (d *dumper) stat() {
+	data, ok := d.table("STAT")
+	if !ok {
+		return
+	}
+	var stat opentype.STATTable
+	opentype.ParseSTATTable(data, &stat)
+
+	d.section("Design axes (STAT)")
+	tw := tabwriter.NewWriter(d.w, 0, 8, 2, ' ', 0)
+	var tags []opentype.Tag
+	for _, axis := range stat.DesignAxes() {
+		tags = append(tags, axis.AxisTag)
+		fmt.Fprintf(tw, "\t%q\t%s\tordering %d\n", axis.AxisTag, d.name(axis.AxisNameID), axis.AxisOrdering)
+	}
+	tw.Flush()
+
+	axisTag := func(i uint16) string {
+		if int(i) < len(tags) {
+			return string(tags[i])
+		}
+		return fmt.Sprintf("axis %d", i)
+	}
+	d.section("Axis values (STAT)")
+	tw = tabwriter.NewWriter(d.w, 0, 8, 2, ' ', 0)
+	var val opentype.AxisValueTable
+	for i := range stat.NumAxisValueOffsets() {
+		if !stat.AxisValue(i, &val) {
+			continue
+		}
+		var name opentype.NameID
+		var flags uint16
+		var desc string
+		switch val.Format {
+		case 1:
+			v := &val.Format1
+			name, flags = v.ValueNameID, v.Flags
+			desc = fmt.Sprintf("%s=%g", axisTag(v.AxisIndex), v.Value.Float())
+		case 2:
+			v := &val.Format2
+			name, flags = v.ValueNameID, v.Flags
+			desc = fmt.Sprintf("%s=%g (%g..%g)", axisTag(v.AxisIndex),
+				v.NominalValue.Float(), v.RangeMinValue.Float(), v.RangeMaxValue.Float())
+		case 3:
+			v := &val.Format3
+			name, flags = v.ValueNameID, v.Flags
+			desc = fmt.Sprintf("%s=%g, linked to %g", axisTag(v.AxisIndex), v.Value.Float(), v.LinkedValue.Float())
+		case 4:
+			v := &val.Format4
+			name, flags = v.ValueNameID, v.Flags
+			var parts []string
+			for _, av := range v.AxisValues() {
+				parts = append(parts, fmt.Sprintf("%s=%g", axisTag(av.AxisIndex), av.Value.Float()))
+			}
+			desc = strings.Join(parts, " ")
+		default:
+			fmt.Fprintf(tw, "\tformat %d\n", val.Format)
+			continue
+		}
+		var notes []string
+		if flags&0x0001 != 0 {
+			notes = append(notes, "older sibling")
+		}
+		if flags&0x0002 != 0 {
+			notes = append(notes, "elidable")
+		}
+		fmt.Fprintf(tw, "\t%s\t%s\t%s\n", d.name(name), desc, strings.Join(notes, ", "))
+	}
+	tw.Flush()
+}
+
+var fsSelectionNames = []struct {
+	flag opentype.FontSelection
+	name string
+}{
+	{opentype.FsItalic, "italic"},
+	{opentype.FsUnderscore, "underscore"},
+	{opentype.FsNegative, "negative"},
+	{opentype.FsOutlined, "outlined"},
+	{opentype.FsStrikeout, "strikeout"},
+	{opentype.FsBold, "bold"},
+	{opentype.FsRegular, "regular"},
+	{opentype.FsUseTypoMetrics, "use typo metrics"},
+	{opentype.FsWWS, "WWS"},
+	{opentype.FsOblique, "oblique"},
+}
+
+func (d *dumper) os2() {
+	data, ok := d.table("OS/2")
+	if !ok {
+		return
+	}
+	var os2 opentype.OS2Table
+	opentype.ParseOS2Table(data, &os2)
+
+	var sel []string
+	for _, fs := range fsSelectionNames {
+		if os2.FsSelection&fs.flag != 0 {
+			sel = append(sel, fs.name)
+		}
+	}
+	bits := func(words ...uint32) string {
+		var out []string
+		for i, w := range words {
+			for j := range 32 {
+				if w&(1<<j) != 0 {
+					out = append(out, strconv.Itoa(i*32+j))
+				}
+			}
+		}
+		return strings.Join(out, " ")
+	}
+	lic := os2.EmbeddingLicense()
+
+	d.section("OS/2")
+	tw := tabwriter.NewWriter(d.w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "\tversion\t%d\n", os2.Version)
+	fmt.Fprintf(tw, "\tweight class\t%d\n", os2.UsWeightClass)
+	fmt.Fprintf(tw, "\twidth class\t%d\n", os2.UsWidthClass)
+	fmt.Fprintf(tw, "\tselection\t%s\n", strings.Join(sel, ", "))
+	fmt.Fprintf(tw, "\tfamily class\t%d, subclass %d\n", os2.SFamilyClass>>8, os2.SFamilyClass&0xFF)
+	fmt.Fprintf(tw, "\tPANOSE\t%v\n", os2.PANOSE)
+	fmt.Fprintf(tw, "\tvendor\t%q\n", os2.AchVendID)
+	fmt.Fprintf(tw, "\tUnicode ranges\t%s\n", bits(os2.UlUnicodeRange[:]...))
+	if os2.Version >= 1 {
+		fmt.Fprintf(tw, "\tcode pages\t%s\n", bits(os2.UlCodePageRange1[:]...))
+	}
+	fmt.Fprintf(tw, "\tembedding\tpermissions %d, no subsetting %t, bitmaps only %t\n",
+		lic.Permissions, lic.NoSubsetting, lic.BitmapEmbeddingOnly)
+	if os2.Version >= 5 {
+		fmt.Fprintf(tw, "\toptical sizes\t%g..%g pt\n",
+			float64(os2.UsLowerOpticalPointSize)/20, float64(os2.UsUpperOpticalPointSize)/20)
+	}
+	tw.Flush()
+}
+
+func (d *dumper) cmap() {
+	data, ok := d.table("cmap")
+	if !ok {
+		return
+	}
+	var cmap opentype.CmapTable
+	opentype.ParseCmapTable(data, &cmap)
+	selected, _ := cmap.SelectEncoding()
+
+	d.section("Character maps (cmap)")
+	tw := tabwriter.NewWriter(d.w, 0, 8, 2, ' ', 0)
+	for _, rec := range cmap.EncodingRecords() {
+		var sub opentype.CmapSubtable
+		format := "invalid subtable"
+		if rec.Subtable(&sub) {
+			format = fmt.Sprintf("format %d", sub.Format)
+		}
+		note := ""
+		if rec.PlatformID == selected.PlatformID && rec.EncodingID == selected.EncodingID {
+			note = "selected"
+		}
+		fmt.Fprintf(tw, "\tplatform %d\tencoding %d\t%s\t%s\n", rec.PlatformID, rec.EncodingID, format, note)
+	}
+	tw.Flush()
+
+	switch d.f.Cmap.Format {
+	case 4, 12, 13:
+	default:
+		fmt.Fprintf(d.w, "coverage of format %d subtables isn't supported\n", d.f.Cmap.Format)
+		return
+	}
+	type runeRange struct{ lo, hi rune }
+	var ranges []runeRange
+	var count int
+	for r := range d.f.Cmap.Mappings() {
+		count++
+		if n := len(ranges); n > 0 && ranges[n-1].hi+1 == r {
+			ranges[n-1].hi = r
+		} else {
+			ranges = append(ranges, runeRange{r, r})
+		}
+	}
+	d.section(fmt.Sprintf("Coverage (%d runes in %d ranges)", count, len(ranges)))
+	for _, rng := range ranges {
+		if rng.lo == rng.hi {
+			fmt.Fprintf(d.w, "  U+%04X\n", rng.lo)
+		} else {
+			fmt.Fprintf(d.w, "  U+%04X–U+%04X (%d)\n", rng.lo, rng.hi, rng.hi-rng.lo+1)
+		}
+	}
+}
+
+// layout prints the scripts, language systems and features of the GSUB or
+// GPOS table.
+func (d *dumper) layout(tag opentype.Tag) {
+	data, ok := d.table(tag)
+	if !ok {
+		return
+	}
+	var (
+		scripts  opentype.ScriptListTable
+		features opentype.FeatureListTable
+	)
+	if tag == "GSUB" {
+		var gsub opentype.GSUBTable
+		opentype.ParseGSUBTable(data, &gsub)
+		if !gsub.ScriptList(&scripts) || !gsub.FeatureList(&features) {
+			return
+		}
+	} else {
+		var gpos opentype.GPOSTable
+		opentype.ParseGPOSTable(data, &gpos)
+		if !gpos.ScriptList(&scripts) || !gpos.FeatureList(&features) {
+			return
+		}
+	}
+
+	featureTags := func(ls *opentype.LangSysTable) string {
+		var out []string
+		if idx := int(ls.RequiredFeatureIndex); idx != 0xFFFF && idx < features.NumFeatureRecords() {
+			out = append(out, string(features.FeatureRecord(idx).FeatureTag)+" (required)")
+		}
+		for _, idx := range ls.FeatureIndices() {
+			if int(idx) < features.NumFeatureRecords() {
+				out = append(out, string(features.FeatureRecord(int(idx)).FeatureTag))
+			}
+		}
+		return strings.Join(out, " ")
+	}
+
+	d.section(fmt.Sprintf("Scripts and languages (%s)", tag))
+	tw := tabwriter.NewWriter(d.w, 0, 8, 2, ' ', 0)
+	for _, rec := range scripts.ScriptRecords() {
+		var script opentype.ScriptTable
+		if !rec.Script(&script) {
+			continue
+		}
+		var ls opentype.LangSysTable
+		if script.DefaultLangSys(&ls) {
+			fmt.Fprintf(tw, "\t%q\tdefault\t%s\n", rec.ScriptTag, featureTags(&ls))
+		}
+		for _, lrec := range script.LangSysRecords() {
+			if lrec.LangSys(&ls) {
+				fmt.Fprintf(tw, "\t%q\t%q\t%s\n", rec.ScriptTag, lrec.LangSysTag, featureTags(&ls))
+			}
+		}
+	}
+	tw.Flush()
+
+	var all []string
+	for _, rec := range features.FeatureRecords() {
+		if !slices.Contains(all, string(rec.FeatureTag)) {
+			all = append(all, string(rec.FeatureTag))
+		}
+	}
+	slices.Sort(all)
+	d.section(fmt.Sprintf("Features (%s)", tag))
+	fmt.Fprintf(d.w, "  %s\n", strings.Join(all, " "))
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Command lottie-check reports the parts of Lottie animations that gutter
+// doesn't support.
+//
+// Usage:
+//
+//	lottie-check [-images dir] file...
+//
+// For each unsupported feature, such as an unsupported layer type, shape or
+// effect, lottie-check prints the JSON pointer to the feature, the names of
+// the layers and shapes that contain it, and a short description. Such
+// features are ignored or approximated when rendering the animation.
+//
+// Images that aren't embedded in an animation are loaded from the directory
+// given by -images. Without it, they are loaded relative to the animation's
+// file.
+//
+// The exit status is 1 if any file uses unsupported features or fails to
+// load.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"honnef.co/go/gutter/lottie/lottie_converter"
+	"honnef.co/go/gutter/lottie/lottie_encoding"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: lottie-check [-images dir] file...")
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("lottie-check: ")
+	images := flag.String("images", "", "load external images from `dir`")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+	}
+
+	failed := false
+	for _, path := range flag.Args() {
+		n, err := check(os.Stdout, path, *images)
+		if err != nil {
+			log.Print(err)
+			failed = true
+		} else if n > 0 {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// check prints the unsupported features of the animation in path and returns
+// their number.
+func check(w io.Writer, path string, images string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	anim, err := lottie_encoding.Parse(data)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+	// We also decode the animation generically, to look up the names of
+	// the objects that contain unsupported features.
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if images == "" {
+		images = filepath.Dir(path)
+	}
+	var reports []lottie_converter.Unsupported
+	_, err = lottie_converter.ConvertAnimation(anim, lottie_converter.Options{
+		ResolveImage: lottie_converter.FSImageResolver(os.DirFS(images)),
+		Report: func(u lottie_converter.Unsupported) {
+			reports = append(reports, u)
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for _, u := range reports {
+		fmt.Fprintf(w, "%s: %s", path, u.Path)
+		if names := namesAlong(doc, u.Path); len(names) > 0 {
+			fmt.Fprintf(w, " (%s)", strings.Join(names, " > "))
+		}
+		fmt.Fprintf(w, ": %s", u.Feature)
+		if u.Err != nil {
+			fmt.Fprintf(w, ": %s", u.Err)
+		}
+		fmt.Fprintln(w)
+	}
+	return len(reports), nil
+}
+
+// namesAlong returns the quoted names ("nm") of the objects along a JSON
+// pointer, which are the names that designers see in their tools.
+func namesAlong(doc any, pointer string) []string {
+	var names []string
+	v := doc
+	for tok := range strings.SplitSeq(strings.TrimPrefix(pointer, "/"), "/") {
+		tok = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		switch x := v.(type) {
+		case map[string]any:
+			v = x[tok]
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(x) {
+				return names
+			}
+			v = x[i]
+		default:
+			return names
+		}
+		if obj, ok := v.(map[string]any); ok {
+			if name, ok := obj["nm"].(string); ok && name != "" {
+				names = append(names, strconv.Quote(name))
+			}
+		}
+	}
+	return names
+}
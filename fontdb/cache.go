@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package fontdb
+
+import (
+	"encoding/gob"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"honnef.co/go/gutter/opentype"
+)
+
+// cacheVersion has to be incremented whenever the format of the cache, or
+// the information we extract from fonts, changes.
+const cacheVersion = 2
+
+// fontCache stores the information we extract from font files, so that we
+// don't have to parse all fonts every time the process starts. Entries are
+// keyed by path and are only valid as long as the file's size and
+// modification time don't change.
+type fontCache struct {
+	Version int
+	Files   map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	Path    string
+	Size    int64
+	ModTime int64
+	// All faces in the file that we support. Files that we can't parse
+	// have no faces but are still cached, so that we don't try to parse
+	// them again.
+	Faces []cachedFace
+}
+
+type cachedFace struct {
+	Family string
+	Face   *Face
+}
+
+func cachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gutter", "fontdb.gob"), nil
+}
+
+// loadCache loads the font cache. It returns an empty cache if there is no
+// valid cache.
+func loadCache() *fontCache {
+	empty := &fontCache{Version: cacheVersion}
+	path, err := cachePath()
+	if err != nil {
+		return empty
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Info("couldn't open font cache", "file", path, "err", err)
+		}
+		return empty
+	}
+	defer f.Close()
+
+	var c fontCache
+	if err := gob.NewDecoder(f).Decode(&c); err != nil {
+		slog.Info("couldn't read font cache", "file", path, "err", err)
+		return empty
+	}
+	if c.Version != cacheVersion {
+		return empty
+	}
+	return &c
+}
+
+// save atomically replaces the cache file.
+func (c *fontCache) save() error {
+	path, err := cachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "fontdb-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := gob.NewEncoder(f).Encode(c); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// loadFile parses all faces in a font file. It returns nil if the file
+// couldn't be read.
+func loadFile(path string) *cacheEntry {
+	fd, err := os.Open(path)
+	if err != nil {
+		slog.Info("couldn't open font", "file", path, "err", err)
+		return nil
+	}
+	defer fd.Close()
+	info, err := fd.Stat()
+	if err != nil {
+		slog.Info("couldn't stat font", "file", path, "err", err)
+		return nil
+	}
+	entry := &cacheEntry{
+		Path:    path,
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+	}
+	off, err := fd.Seek(0, io.SeekEnd)
+	if err != nil {
+		slog.Info("couldn't seek font", "file", path, "err", err)
+		return nil
+	}
+	if off == 0 {
+		return entry
+	}
+	data, err := syscall.Mmap(int(fd.Fd()), 0, int(off), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		slog.Info("couldn't mmap font", "file", path, "err", err)
+		return nil
+	}
+	// Nothing we extract from the font refers to its data.
+	defer syscall.Munmap(data)
+
+	// Font collections contain multiple faces, each of which may belong to a
+	// different family.
+	for i := range opentype.NumFonts(data) {
+		fam, face, err := loadFace(path, data, i)
+		if err != nil {
+			slog.Info("couldn't parse font", "file", path, "index", i, "err", err)
+			continue
+		}
+		if face != nil {
+			entry.Faces = append(entry.Faces, cachedFace{fam, face})
+		}
+	}
+	return entry
+}
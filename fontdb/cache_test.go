@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package fontdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// tempCache points the font cache at a temporary directory and returns the
+// path of the cache file.
+func tempCache(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+	path, err := cachePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeFont writes the Go font to a temporary file.
+func writeFont(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Go-Regular.ttf")
+	if err := os.WriteFile(path, goregular.TTF, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// renameCached renames the family of all cached faces, which lets us tell
+// whether a database was built from the cache or by parsing the fonts.
+func renameCached(t *testing.T, family string) {
+	t.Helper()
+	c := loadCache()
+	if len(c.Files) == 0 {
+		t.Fatal("cache is empty")
+	}
+	for _, e := range c.Files {
+		for i := range e.Faces {
+			e.Faces[i].Family = family
+		}
+	}
+	if err := c.save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	tempCache(t)
+	path := writeFont(t)
+
+	f := newFromFiles([]string{path})
+	if _, ok := f.Faces["Go"]; !ok {
+		t.Fatalf("got families %v, want Go", f.Faces)
+	}
+	c := loadCache()
+	e, ok := c.Files[path]
+	if !ok {
+		t.Fatal("font wasn't cached")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Size != info.Size() || e.ModTime != info.ModTime().UnixNano() {
+		t.Errorf("cached size %d and time %d, want %d and %d", e.Size, e.ModTime, info.Size(), info.ModTime().UnixNano())
+	}
+	if len(e.Faces) != 1 || e.Faces[0].Family != "Go" || e.Faces[0].Face.Path != path {
+		t.Errorf("got cached faces %v, want the Go font", e.Faces)
+	}
+
+	// Unchanged files are loaded from the cache.
+	renameCached(t, "Cached")
+	f = newFromFiles([]string{path})
+	if _, ok := f.Faces["Cached"]; !ok {
+		t.Errorf("got families %v, want the cached family", f.Faces)
+	}
+
+	// Files that no longer exist are dropped from the cache.
+	newFromFiles(nil)
+	if c := loadCache(); len(c.Files) != 0 {
+		t.Errorf("cache still contains %v", c.Files)
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	tempCache(t)
+	path := writeFont(t)
+	newFromFiles([]string{path})
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing the modification time invalidates the entry.
+	renameCached(t, "Cached")
+	mtime := info.ModTime().Add(time.Second)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if f := newFromFiles([]string{path}); f.Faces["Go"] == nil {
+		t.Errorf("got families %v after changing the modification time, want Go", f.Faces)
+	}
+
+	// So does changing the size, even if the modification time stays the
+	// same.
+	renameCached(t, "Cached")
+	if err := os.WriteFile(path, append(goregular.TTF, 0, 0, 0, 0), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if f := newFromFiles([]string{path}); f.Faces["Go"] == nil {
+		t.Errorf("got families %v after changing the size, want Go", f.Faces)
+	}
+	if e := loadCache().Files[path]; e == nil || e.Size != int64(len(goregular.TTF)+4) {
+		t.Errorf("got cache entry %v, want one for the new size", e)
+	}
+}
+
+func TestCacheCorrupt(t *testing.T) {
+	cache := tempCache(t)
+	path := writeFont(t)
+	newFromFiles([]string{path})
+	valid, err := os.ReadFile(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"garbage", []byte("not a gob stream")},
+		{"truncated", valid[:len(valid)/2]},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(cache, tt.data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if c := loadCache(); c.Version != cacheVersion || len(c.Files) != 0 {
+			t.Errorf("%s: got cache %v, want an empty one", tt.name, c)
+		}
+		// The database is built from the fonts and the cache is rewritten.
+		if f := newFromFiles([]string{path}); f.Faces["Go"] == nil {
+			t.Errorf("%s: got families %v, want Go", tt.name, f.Faces)
+		}
+		if c := loadCache(); c.Files[path] == nil {
+			t.Errorf("%s: cache wasn't rewritten", tt.name)
+		}
+	}
+
+	// Caches written by other versions are ignored.
+	c := loadCache()
+	c.Version = cacheVersion - 1
+	if err := c.save(); err != nil {
+		t.Fatal(err)
+	}
+	if c := loadCache(); c.Version != cacheVersion || len(c.Files) != 0 {
+		t.Errorf("got cache %v from a different version, want an empty one", c)
+	}
+}
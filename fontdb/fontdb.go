@@ -6,33 +6,50 @@ package fontdb
 
 import (
 	"cmp"
-	"io"
 	"io/fs"
 	"log/slog"
+	"maps"
 	"math"
 	"os"
 	"path/filepath"
 	"runtime"
 	"slices"
-	"strings"
-	"syscall"
 
 	"honnef.co/go/gutter/opentype"
 	"honnef.co/go/gutter/opentype/opentypehl"
 	"honnef.co/go/stuff/syncutil"
 
 	"github.com/go-text/typesetting/fontscan"
+	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
 )
 
 type Faces struct {
 	// All fonts grouped by typographic family name
 	Faces map[string][]*Face
+
+	// All fonts, keyed by all of their family names in all languages,
+	// normalized with familyKey.
+	names map[string][]*Face
 }
 
 type Face struct {
 	Path string
-	Axes map[opentype.Tag]Axis
+	// The index of the face in a font collection, or 0 for files containing
+	// a single face.
+	Index int
+	// The contents of the font file, for faces added with Faces.AddData or
+	// Faces.AddFS. Path is then merely the name of the font.
+	Data []byte
+	// All of the face's family names, in all languages. This includes
+	// typographic, WWS and legacy family names.
+	Families []string
+	Axes     map[opentype.Tag]Axis
+	// The Unicode blocks that the face claims to support, as stored in the
+	// OS/2 table's ulUnicodeRange fields. This is merely a summary, fonts
+	// aren't required to support every character in these blocks.
+	UnicodeRanges [4]uint32
 }
 
 type Axis struct {
@@ -45,153 +62,246 @@ type FontVariation struct {
 	AxisValues map[opentype.Tag]float64
 }
 
+// New returns a database of all fonts installed on the system.
 func New() *Faces {
-	type font struct {
-		family string
-		font   *Face
+	return newFromFiles(fontFiles())
+}
+
+// newFromFiles returns a database of the fonts in files, using and updating
+// the font cache.
+func newFromFiles(files []string) *Faces {
+	cache := loadCache()
+
+	// Only parse files that are new or have changed since we last cached
+	// them.
+	entries := make(map[string]*cacheEntry, len(files))
+	var stale []string
+	for _, f := range files {
+		if _, ok := entries[f]; ok {
+			// The same file may be reachable via multiple font directories.
+			continue
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			slog.Info("couldn't stat font", "file", f, "err", err)
+			continue
+		}
+		if e, ok := cache.Files[f]; ok && e.Size == info.Size() && e.ModTime == info.ModTime().UnixNano() {
+			entries[f] = e
+			continue
+		}
+		entries[f] = nil
+		stale = append(stale, f)
 	}
 
-	en := language.MustParseBase("en")
-	ret, _ := dmap(fontFiles(), 8, nil, func(subitems []string) ([]font, error) {
-		var out []font
+	ret, _ := dmap(stale, 8, nil, func(subitems []string) ([]*cacheEntry, error) {
+		out := make([]*cacheEntry, 0, len(subitems))
 		for _, f := range subitems {
-			func() {
-				if strings.HasSuffix(f, ".ttc") {
-					// XXX support collections
-					return
-				}
-
-				fd, err := os.Open(f)
-				if err != nil {
-					slog.Info("couldn't open font", "file", f, "err", err)
-					return
-				}
-				defer fd.Close()
-				off, err := fd.Seek(0, io.SeekEnd)
-				if err != nil {
-					slog.Info("couldn't seek font", "file", f, "err", err)
-					return
-				}
-				data, err := syscall.Mmap(int(fd.Fd()), 0, int(off), syscall.PROT_READ, syscall.MAP_SHARED)
-				if err != nil {
-					slog.Info("couldn't mmap font", "file", f, "err", err)
-					return
-				}
-				fd.Close()
+			out = append(out, loadFile(f))
+		}
+		return out, nil
+	})
+	for _, grp := range ret {
+		for _, e := range grp {
+			if e != nil {
+				entries[e.Path] = e
+			}
+		}
+	}
 
-				fnt, err := opentypehl.NewFile(data)
-				if err != nil {
-					slog.Info("couldn't parse font", "file", f, "err", err)
-					return
-				}
+	fonts := make(map[string][]*Face)
+	changed := len(stale) > 0
+	for f, e := range entries {
+		if e == nil {
+			// We failed to load the file this time around. Try again next
+			// time.
+			delete(entries, f)
+			changed = true
+			continue
+		}
+		for _, face := range e.Faces {
+			fonts[face.Family] = append(fonts[face.Family], face.Face)
+		}
+	}
+	if changed || len(entries) != len(cache.Files) {
+		cache.Files = entries
+		if err := cache.save(); err != nil {
+			slog.Info("couldn't write font cache", "err", err)
+		}
+	}
+	for _, faces := range fonts {
+		// Sort for deterministic matching, independent of the order in
+		// which files were scanned.
+		slices.SortFunc(faces, func(a, b *Face) int {
+			if c := cmp.Compare(a.Path, b.Path); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.Index, b.Index)
+		})
+	}
 
-				var fam string
-			nameLoop:
-				for name := range fnt.Names().All() {
-					switch name.ID() {
-					case opentype.NameTypographicFamilyName:
-					case opentype.NameWWSFamilyName:
-					case opentype.NameFontFamilyName:
-					default:
-						continue
-					}
-
-					// TODO(dh): collect family names in all languages
-					if lang, _ := name.Language().Base(); lang != en {
-						continue
-					}
-					switch name.ID() {
-					case opentype.NameTypographicFamilyName:
-						fam = name.String()
-						break nameLoop
-					case opentype.NameWWSFamilyName:
-						fam = name.String()
-					case opentype.NameFontFamilyName:
-						if fam == "" {
-							fam = name.String()
-						}
-					}
-				}
+	if len(fonts) == 0 {
+		slog.Warn("couldn't find any fonts")
+	}
 
-				axes := make(map[opentype.Tag]Axis)
-				os2Raw, ok := fnt.Raw().FindTable("OS/2")
-				if !ok {
-					return
-				}
-				var os2 opentype.OS2Table
-				opentype.ParseOS2Table(os2Raw.Data(), &os2)
-
-				wght := float64(os2.UsWeightClass)
-				axes["wght"] = Axis{"wght", wght, wght, wght}
-
-				var wdth float64
-				switch os2.UsWidthClass {
-				case opentype.WidthUltraCondensed:
-					wdth = 50
-				case opentype.WidthExtraCondensed:
-					wdth = 62.5
-				case opentype.WidthCondensed:
-					wdth = 75
-				case opentype.WidthSemiCondensed:
-					wdth = 87.5
-				case opentype.WidthMedium:
-					wdth = 100
-				case opentype.WidthSemiExpanded:
-					wdth = 112.5
-				case opentype.WidthExpanded:
-					wdth = 125
-				case opentype.WidthExtraExpanded:
-					wdth = 150
-				case opentype.WidthUltraExpanded:
-					wdth = 200
-				default:
-					// Invalid width class, fall back to default
-					wdth = 100
-				}
-				axes["wdth"] = Axis{"wdth", wdth, wdth, wdth}
+	out := &Faces{Faces: fonts}
+	for _, fam := range slices.Sorted(maps.Keys(fonts)) {
+		for _, face := range fonts[fam] {
+			out.index(face)
+		}
+	}
+	return out
+}
 
-				fss := os2.FsSelection
-				if fss&opentype.FsItalic != 0 {
-					axes["ital"] = Axis{"ital", 1, 1, 1}
-				} else {
-					axes["ital"] = Axis{"ital", 0, 0, 0}
-				}
+// familyKey normalizes a family name for case-insensitive matching that
+// ignores differences such as full-width and half-width forms.
+func familyKey(name string) string {
+	// cases.Caser isn't safe for concurrent use.
+	return norm.NFKC.String(cases.Fold().String(norm.NFKC.String(name)))
+}
 
-				// Overwrite computed axes with any real axes provided by the font
-				if fvarRaw, ok := fnt.Raw().FindTable("fvar"); ok {
-					var fvar opentype.FvarTable
-					opentype.ParseFvarTable(fvarRaw.Data(), &fvar)
-					for _, axis := range fvar.Axes() {
-						axes[axis.Tag] = Axis{
-							axis.Tag,
-							axis.MinValue.Float(),
-							axis.DefaultValue.Float(),
-							axis.MaxValue.Float(),
-						}
-					}
-				}
+// add adds a face to the database, grouped under the primary family name
+// fam.
+func (f *Faces) add(fam string, face *Face) {
+	if f.Faces == nil {
+		f.Faces = make(map[string][]*Face)
+	}
+	f.Faces[fam] = append(f.Faces[fam], face)
+	f.index(face)
+}
 
-				out = append(out, font{fam, &Face{
-					Path: f,
-					Axes: axes,
-				}})
-			}()
+func (f *Faces) index(face *Face) {
+	if f.names == nil {
+		f.names = make(map[string][]*Face)
+	}
+	var seen []string
+	for _, name := range face.Families {
+		key := familyKey(name)
+		if slices.Contains(seen, key) {
+			continue
 		}
-		return out, nil
-	})
+		seen = append(seen, key)
+		f.names[key] = append(f.names[key], face)
+	}
+}
 
-	fonts := make(map[string][]*Face)
-	for _, grp := range ret {
-		for _, font := range grp {
-			fonts[font.family] = append(fonts[font.family], font.font)
+// lookup returns all faces with the family name.
+func (f *Faces) lookup(family string) []*Face {
+	if faces, ok := f.names[familyKey(family)]; ok {
+		return faces
+	}
+	// Faces may have been added to the Faces field directly.
+	return f.Faces[family]
+}
+
+var en = language.MustParseBase("en")
+
+// loadFace loads the face with the given index from a font file. It returns
+// a nil face for fonts we don't support.
+func loadFace(path string, data []byte, index int) (string, *Face, error) {
+	fnt, err := opentypehl.NewFileIndex(data, index)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The primary family name is the English typographic family name,
+	// falling back to the WWS and legacy family names.
+	var fam string
+	famPriority := 0
+	var families []string
+	for name := range fnt.Names().All() {
+		var priority int
+		switch name.ID() {
+		case opentype.NameTypographicFamilyName:
+			priority = 3
+		case opentype.NameWWSFamilyName:
+			priority = 2
+		case opentype.NameFontFamilyName:
+			priority = 1
+		default:
+			continue
+		}
+		str := name.String()
+		if str == "" {
+			continue
+		}
+		if !slices.Contains(families, str) {
+			families = append(families, str)
 		}
+		if lang, _ := name.Language().Base(); lang == en && priority > famPriority {
+			fam = str
+			famPriority = priority
+		}
+	}
+	if fam == "" && len(families) > 0 {
+		fam = families[0]
 	}
 
-	if len(fonts) == 0 {
-		slog.Warn("couldn't find any fonts")
+	axes := make(map[opentype.Tag]Axis)
+	os2Raw, ok := fnt.Raw().FindTable("OS/2")
+	if !ok {
+		return "", nil, nil
+	}
+	var os2 opentype.OS2Table
+	opentype.ParseOS2Table(os2Raw.Data(), &os2)
+
+	wght := float64(os2.UsWeightClass)
+	axes["wght"] = Axis{"wght", wght, wght, wght}
+
+	var wdth float64
+	switch os2.UsWidthClass {
+	case opentype.WidthUltraCondensed:
+		wdth = 50
+	case opentype.WidthExtraCondensed:
+		wdth = 62.5
+	case opentype.WidthCondensed:
+		wdth = 75
+	case opentype.WidthSemiCondensed:
+		wdth = 87.5
+	case opentype.WidthMedium:
+		wdth = 100
+	case opentype.WidthSemiExpanded:
+		wdth = 112.5
+	case opentype.WidthExpanded:
+		wdth = 125
+	case opentype.WidthExtraExpanded:
+		wdth = 150
+	case opentype.WidthUltraExpanded:
+		wdth = 200
+	default:
+		// Invalid width class, fall back to default
+		wdth = 100
+	}
+	axes["wdth"] = Axis{"wdth", wdth, wdth, wdth}
+
+	fss := os2.FsSelection
+	if fss&opentype.FsItalic != 0 {
+		axes["ital"] = Axis{"ital", 1, 1, 1}
+	} else {
+		axes["ital"] = Axis{"ital", 0, 0, 0}
 	}
 
-	return &Faces{Faces: fonts}
+	// Overwrite computed axes with any real axes provided by the font
+	if fvarRaw, ok := fnt.Raw().FindTable("fvar"); ok {
+		var fvar opentype.FvarTable
+		opentype.ParseFvarTable(fvarRaw.Data(), &fvar)
+		for _, axis := range fvar.Axes() {
+			axes[axis.Tag] = Axis{
+				axis.Tag,
+				axis.MinValue.Float(),
+				axis.DefaultValue.Float(),
+				axis.MaxValue.Float(),
+			}
+		}
+	}
+
+	return fam, &Face{
+		Path:          path,
+		Index:         index,
+		Families:      families,
+		Axes:          axes,
+		UnicodeRanges: os2.UlUnicodeRange,
+	}, nil
 }
 
 type nilLogger struct{}
@@ -320,33 +430,92 @@ func dmap[S ~[]E, E, R any](items S, limit int, out []R, fn func(subitems S) (R,
 	return out, err
 }
 
+// Match returns the face of the family that best matches the axis values in
+// query, and the axis values to use for it. Family names are matched
+// case-insensitively and in all languages.
 func (f *Faces) Match(family string, query map[opentype.Tag]float64) (*FontVariation, bool) {
-	candidates := f.Faces[family]
-	if len(candidates) == 0 {
+	ranked := f.rank(family, query)
+	if len(ranked) == 0 {
 		return nil, false
 	}
+	return variation(ranked[0].Face, query), true
+}
 
-	type distance struct {
-		idx      int
-		distance float64
-		dot      float64
+// MatchAll is like Match but returns all faces of the family, ranked by how
+// well they match the query, best match first.
+func (f *Faces) MatchAll(family string, query map[opentype.Tag]float64) []*FontVariation {
+	ranked := f.rank(family, query)
+	out := make([]*FontVariation, len(ranked))
+	for i, c := range ranked {
+		out[i] = variation(c.Face, query)
+	}
+	return out
+}
+
+// Candidate is a face considered by Match, together with the values it was
+// ranked by.
+type Candidate struct {
+	Face *Face
+	// Distance is the weighted distance between the query and the closest
+	// point in the face's design space. Smaller distances rank higher.
+	Distance float64
+	// Dot is the dot product of the query and that point. It breaks ties
+	// between faces at the same distance, with smaller values ranking higher.
+	Dot float64
+}
+
+// Candidates is like MatchAll but returns the values that the faces were
+// ranked by, which explains why Match chose a face.
+func (f *Faces) Candidates(family string, query map[opentype.Tag]float64) []Candidate {
+	return f.rank(family, query)
+}
+
+func (f *Faces) rank(family string, query map[opentype.Tag]float64) []Candidate {
+	candidates := f.lookup(family)
+	if len(candidates) == 0 {
+		return nil
 	}
-	distances := make([]distance, len(candidates))
+
+	out := make([]Candidate, len(candidates))
 	for i, candidate := range candidates {
 		dist, dot := vectorDistance(query, candidate.Axes)
-		distances[i] = distance{i, dist, dot}
+		out[i] = Candidate{candidate, dist, dot}
 	}
-	slices.SortStableFunc(distances, func(a, b distance) int {
-		if c := cmp.Compare(a.distance, b.distance); c != 0 {
+	slices.SortStableFunc(out, func(a, b Candidate) int {
+		if c := cmp.Compare(a.Distance, b.Distance); c != 0 {
 			return c
 		}
-		return cmp.Compare(a.dot, b.dot)
+		return cmp.Compare(a.Dot, b.Dot)
 	})
-	font := candidates[distances[0].idx]
+	return out
+}
+
+// The slant to use when substituting an oblique for an italic, as in CSS.
+const obliqueSlant = -14
+
+// obliqueForItalic returns the value of the slnt axis to use if the query
+// asks for an italic that the face doesn't have, but the face can be slanted
+// instead.
+func obliqueForItalic(query map[opentype.Tag]float64, axes map[opentype.Tag]Axis) (float64, bool) {
+	if query["ital"] < 1 || axes["ital"].Max >= 1 {
+		return 0, false
+	}
+	if _, ok := query["slnt"]; ok {
+		return 0, false
+	}
+	slnt, ok := axes["slnt"]
+	if !ok || slnt.Min >= 0 {
+		return 0, false
+	}
+	return max(obliqueSlant, slnt.Min), true
+}
+
+func variation(font *Face, query map[opentype.Tag]float64) *FontVariation {
 	v := &FontVariation{
 		Font:       font,
 		AxisValues: make(map[opentype.Tag]float64),
 	}
+	oblique, useOblique := obliqueForItalic(query, font.Axes)
 	for axis, rng := range font.Axes {
 		if rng.Min == rng.Max {
 			v.AxisValues[axis] = rng.Min
@@ -359,15 +528,24 @@ func (f *Faces) Match(family string, query map[opentype.Tag]float64) (*FontVaria
 			} else {
 				v.AxisValues[axis] = rng.Max
 			}
+		} else if axis == "slnt" && useOblique {
+			v.AxisValues[axis] = oblique
 		} else {
 			v.AxisValues[axis] = rng.Default
 		}
 	}
-	return v, true
+	return v
 }
 
 func vectorDistance(query map[opentype.Tag]float64, candidate map[opentype.Tag]Axis) (dist, dot float64) {
-	// TODO(dh): allow non-zero slnt to satisfy ital?
+	// Faces that can be slanted are a substitute for missing italics, but
+	// true italics are still preferred.
+	const obliquePenalty = 0.2
+	oblique, useOblique := obliqueForItalic(query, candidate)
+	if useOblique {
+		query = maps.Clone(query)
+		query["slnt"] = oblique
+	}
 
 	seen := make(map[opentype.Tag]struct{})
 	var sum float64
@@ -415,6 +593,10 @@ func vectorDistance(query map[opentype.Tag]float64, candidate map[opentype.Tag]A
 			cv = standard
 		}
 
+		if k == "ital" && useOblique {
+			cv = qv - obliquePenalty
+		}
+
 		dot += qv * cv
 		d := ((qv - standard) * multiplier) - ((cv - standard) * multiplier)
 		sum += d * d
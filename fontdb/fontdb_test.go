@@ -0,0 +1,353 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package fontdb
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+	"unicode/utf16"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"honnef.co/go/gutter/opentype"
+)
+
+// fontName is a record of a font's name table, using Windows language IDs.
+type fontName struct {
+	id   opentype.NameID
+	lang uint16
+	name string
+}
+
+const (
+	langEnglish  = 0x0409
+	langGerman   = 0x0407
+	langJapanese = 0x0411
+)
+
+// testFont describes a font built from the Go font's outlines.
+type testFont struct {
+	names  []fontName
+	weight uint16
+	italic bool
+	axes   []Axis
+}
+
+// build encodes the font, replacing the Go font's name table, weight and
+// style, and adding an fvar table if the font has axes.
+func (tf testFont) build(t *testing.T) []byte {
+	t.Helper()
+	be := binary.BigEndian
+
+	var dir opentype.TableDirectory
+	if err := opentype.ParseFont(goregular.TTF, 0, &dir); err != nil {
+		t.Fatal(err)
+	}
+	var tables []opentype.FontTable
+	for _, rec := range dir.TableRecords() {
+		data := rec.Data()
+		switch rec.Tag {
+		case "name":
+			continue
+		case "OS/2":
+			data = slices.Clone(data)
+			be.PutUint16(data[4:], tf.weight)
+			fss := be.Uint16(data[62:]) &^ uint16(opentype.FsItalic)
+			if tf.italic {
+				fss |= uint16(opentype.FsItalic)
+			}
+			be.PutUint16(data[62:], fss)
+		}
+		tables = append(tables, opentype.FontTable{Tag: rec.Tag, Data: data})
+	}
+
+	var records, strs []byte
+	records = be.AppendUint16(records, 0)
+	records = be.AppendUint16(records, uint16(len(tf.names)))
+	records = be.AppendUint16(records, uint16(6+12*len(tf.names)))
+	for _, n := range tf.names {
+		var str []byte
+		for _, u := range utf16.Encode([]rune(n.name)) {
+			str = be.AppendUint16(str, u)
+		}
+		records = be.AppendUint16(records, uint16(opentype.PlatformWindows))
+		records = be.AppendUint16(records, uint16(opentype.EncodingWindowsUnicodeBMP))
+		records = be.AppendUint16(records, n.lang)
+		records = be.AppendUint16(records, uint16(n.id))
+		records = be.AppendUint16(records, uint16(len(str)))
+		records = be.AppendUint16(records, uint16(len(strs)))
+		strs = append(strs, str...)
+	}
+	tables = append(tables, opentype.FontTable{Tag: "name", Data: append(records, strs...)})
+
+	if len(tf.axes) > 0 {
+		fixed := func(b []byte, v float64) []byte { return be.AppendUint32(b, uint32(int32(v*65536))) }
+		var fvar []byte
+		fvar = be.AppendUint16(fvar, 1)
+		fvar = be.AppendUint16(fvar, 0)
+		fvar = be.AppendUint16(fvar, 16)
+		fvar = be.AppendUint16(fvar, 2)
+		fvar = be.AppendUint16(fvar, uint16(len(tf.axes)))
+		fvar = be.AppendUint16(fvar, 20)
+		fvar = be.AppendUint16(fvar, 0)
+		fvar = be.AppendUint16(fvar, uint16(4+4*len(tf.axes)))
+		for _, axis := range tf.axes {
+			fvar = append(fvar, axis.Tag...)
+			fvar = fixed(fvar, axis.Min)
+			fvar = fixed(fvar, axis.Default)
+			fvar = fixed(fvar, axis.Max)
+			fvar = be.AppendUint16(fvar, 0)
+			fvar = be.AppendUint16(fvar, 256)
+		}
+		tables = append(tables, opentype.FontTable{Tag: "fvar", Data: fvar})
+	}
+
+	data, err := opentype.EncodeFont(opentype.SfntVersionTrueType, tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// add adds fonts to a new database, using their index as their path.
+func add(t *testing.T, fonts ...[]byte) *Faces {
+	t.Helper()
+	f := NewEmpty()
+	for i, data := range fonts {
+		if err := f.AddData(string(rune('0'+i)), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return f
+}
+
+// paths returns the paths of the fonts of variations.
+func paths(vs []*FontVariation) []string {
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = v.Font.Path
+	}
+	return out
+}
+
+func TestFamilyKey(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"Go Mono", "go mono"},
+		{"ＧＯ　ＭＯＮＯ", "Go Mono"},
+		{"Straße", "STRASSE"},
+		{"ｶﾞ", "ガ"},
+	}
+	for _, tt := range tests {
+		if a, b := familyKey(tt.a), familyKey(tt.b); a != b {
+			t.Errorf("%q has key %q, but %q has key %q", tt.a, a, tt.b, b)
+		}
+	}
+	if familyKey("Go") == familyKey("Go Mono") {
+		t.Error("different families have the same key")
+	}
+}
+
+func TestMatchFamilyNames(t *testing.T) {
+	data := testFont{
+		names: []fontName{
+			{opentype.NameFontFamilyName, langJapanese, "ソース サンズ"},
+			{opentype.NameFontFamilyName, langEnglish, "Source Sans Light"},
+			{opentype.NameWWSFamilyName, langEnglish, "Source Sans WWS"},
+			{opentype.NameTypographicFamilyName, langGerman, "Quelle Sans"},
+			{opentype.NameTypographicFamilyName, langEnglish, "Source Sans"},
+		},
+		weight: 300,
+	}.build(t)
+	f := add(t, data)
+
+	// The primary family is the English typographic family name, even if
+	// other names come first.
+	if faces := f.Faces["Source Sans"]; len(faces) != 1 {
+		t.Errorf("got families %v, want Source Sans", f.Faces)
+	}
+	for _, family := range []string{
+		"Source Sans",
+		"source sans",
+		"ＳＯＵＲＣＥ　ＳＡＮＳ",
+		"Source Sans Light",
+		"Source Sans WWS",
+		"Quelle Sans",
+		"ソース サンズ",
+		"ｿｰｽ ｻﾝｽﾞ",
+	} {
+		if _, ok := f.Match(family, nil); !ok {
+			t.Errorf("family %q didn't match", family)
+		}
+	}
+	if _, ok := f.Match("Source", nil); ok {
+		t.Error("partial family name matched")
+	}
+
+	// Without an English name, the first family name is the primary one.
+	data = testFont{
+		names:  []fontName{{opentype.NameFontFamilyName, langGerman, "Quelle"}},
+		weight: 400,
+	}.build(t)
+	f = add(t, data)
+	if faces := f.Faces["Quelle"]; len(faces) != 1 {
+		t.Errorf("got families %v, want Quelle", f.Faces)
+	}
+}
+
+func TestMatchRanking(t *testing.T) {
+	// The Go fonts have weights of 400 and 600.
+	f := add(t, goregular.TTF, gobold.TTF, goitalic.TTF)
+	const regular, bold, italic = "0", "1", "2"
+
+	tests := []struct {
+		query map[opentype.Tag]float64
+		want  []string
+	}{
+		{nil, []string{regular, bold, italic}},
+		{map[opentype.Tag]float64{"wght": 700}, []string{bold, regular, italic}},
+		{map[opentype.Tag]float64{"ital": 1}, []string{italic, regular, bold}},
+		{map[opentype.Tag]float64{"wght": 600, "ital": 1}, []string{italic, bold, regular}},
+		// Regular and bold are equally far away. The lighter face has the
+		// smaller dot product.
+		{map[opentype.Tag]float64{"wght": 500}, []string{regular, bold, italic}},
+	}
+	for _, tt := range tests {
+		candidates := f.Candidates("GO", tt.query)
+		var got []string
+		for i, c := range candidates {
+			got = append(got, c.Face.Path)
+			if i > 0 {
+				prev := candidates[i-1]
+				if c.Distance < prev.Distance || (c.Distance == prev.Distance && c.Dot < prev.Dot) {
+					t.Errorf("%v: candidate %d (%+v) ranks below %+v", tt.query, i, c, prev)
+				}
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%v: got candidates %v, want %v", tt.query, got, tt.want)
+		}
+		if got := paths(f.MatchAll("go", tt.query)); !slices.Equal(got, tt.want) {
+			t.Errorf("%v: MatchAll returned %v, want %v", tt.query, got, tt.want)
+		}
+		v, ok := f.Match("Go", tt.query)
+		if !ok || v.Font.Path != tt.want[0] {
+			t.Errorf("%v: Match returned %v, want %s", tt.query, v, tt.want[0])
+		}
+	}
+
+	candidates := f.Candidates("Go", map[opentype.Tag]float64{"wght": 500})
+	if candidates[0].Distance != candidates[1].Distance || candidates[0].Dot >= candidates[1].Dot {
+		t.Errorf("tie wasn't broken by the dot product: %+v", candidates[:2])
+	}
+
+	if len(f.Candidates("Go Mono", nil)) != 0 || len(f.MatchAll("Go Mono", nil)) != 0 {
+		t.Error("unknown family has candidates")
+	}
+	if _, ok := f.Match("Go Mono", nil); ok {
+		t.Error("unknown family matched")
+	}
+}
+
+func TestMatchAxisValues(t *testing.T) {
+	data := testFont{
+		names:  []fontName{{opentype.NameFontFamilyName, langEnglish, "Variable"}},
+		weight: 400,
+		axes: []Axis{
+			{"wght", 100, 400, 900},
+			{"opsz", 8, 12, 72},
+		},
+	}.build(t)
+	f := add(t, data)
+
+	tests := []struct {
+		query map[opentype.Tag]float64
+		want  map[opentype.Tag]float64
+	}{
+		{nil, map[opentype.Tag]float64{"wght": 400, "opsz": 12, "wdth": 100, "ital": 0}},
+		{map[opentype.Tag]float64{"wght": 650, "opsz": 24}, map[opentype.Tag]float64{"wght": 650, "opsz": 24, "wdth": 100, "ital": 0}},
+		// Values outside of the axes' ranges are clamped.
+		{map[opentype.Tag]float64{"wght": 1000, "opsz": 4, "wdth": 50}, map[opentype.Tag]float64{"wght": 900, "opsz": 8, "wdth": 100, "ital": 0}},
+	}
+	for _, tt := range tests {
+		v, ok := f.Match("Variable", tt.query)
+		if !ok {
+			t.Fatal("family didn't match")
+		}
+		for tag, want := range tt.want {
+			if got := v.AxisValues[tag]; got != want {
+				t.Errorf("%v: got %s %g, want %g", tt.query, tag, got, want)
+			}
+		}
+	}
+}
+
+func TestObliqueForItalic(t *testing.T) {
+	upright := map[opentype.Tag]Axis{"ital": {"ital", 0, 0, 0}}
+	slanted := map[opentype.Tag]Axis{"ital": {"ital", 0, 0, 0}, "slnt": {"slnt", -20, 0, 0}}
+	slightlySlanted := map[opentype.Tag]Axis{"ital": {"ital", 0, 0, 0}, "slnt": {"slnt", -10, 0, 0}}
+	backslanted := map[opentype.Tag]Axis{"ital": {"ital", 0, 0, 0}, "slnt": {"slnt", 0, 0, 20}}
+	italic := map[opentype.Tag]Axis{"ital": {"ital", 0, 0, 1}, "slnt": {"slnt", -20, 0, 0}}
+
+	tests := []struct {
+		name  string
+		query map[opentype.Tag]float64
+		axes  map[opentype.Tag]Axis
+		slnt  float64
+		ok    bool
+	}{
+		{"slanted", map[opentype.Tag]float64{"ital": 1}, slanted, obliqueSlant, true},
+		{"slightly slanted", map[opentype.Tag]float64{"ital": 1}, slightlySlanted, -10, true},
+		{"not italic", map[opentype.Tag]float64{"ital": 0}, slanted, 0, false},
+		{"no query", nil, slanted, 0, false},
+		{"explicit slant", map[opentype.Tag]float64{"ital": 1, "slnt": -5}, slanted, 0, false},
+		{"upright", map[opentype.Tag]float64{"ital": 1}, upright, 0, false},
+		{"backslanted", map[opentype.Tag]float64{"ital": 1}, backslanted, 0, false},
+		{"italic", map[opentype.Tag]float64{"ital": 1}, italic, 0, false},
+	}
+	for _, tt := range tests {
+		slnt, ok := obliqueForItalic(tt.query, tt.axes)
+		if slnt != tt.slnt || ok != tt.ok {
+			t.Errorf("%s: got (%g, %t), want (%g, %t)", tt.name, slnt, ok, tt.slnt, tt.ok)
+		}
+	}
+}
+
+func TestMatchObliqueForItalic(t *testing.T) {
+	names := []fontName{{opentype.NameFontFamilyName, langEnglish, "Slanted"}}
+	slanted := testFont{names: names, weight: 400, axes: []Axis{{"slnt", -20, 0, 0}}}.build(t)
+	upright := testFont{names: names, weight: 400}.build(t)
+	italic := testFont{names: names, weight: 400, italic: true}.build(t)
+
+	// Faces that can be slanted rank above faces that can't, but below true
+	// italics.
+	f := add(t, upright, slanted, italic)
+	query := map[opentype.Tag]float64{"ital": 1}
+	if got, want := paths(f.MatchAll("Slanted", query)), []string{"2", "1", "0"}; !slices.Equal(got, want) {
+		t.Errorf("got faces %v, want %v", got, want)
+	}
+
+	f = add(t, upright, slanted)
+	v, ok := f.Match("Slanted", query)
+	if !ok || v.Font.Path != "1" {
+		t.Fatalf("got face %v, want the slanted face", v)
+	}
+	if got := v.AxisValues["slnt"]; got != obliqueSlant {
+		t.Errorf("got slant %g, want %d", got, obliqueSlant)
+	}
+
+	// Explicit slants and upright queries aren't overridden.
+	v, _ = f.Match("Slanted", map[opentype.Tag]float64{"ital": 1, "slnt": -5})
+	if got := v.AxisValues["slnt"]; got != -5 {
+		t.Errorf("got slant %g, want -5", got)
+	}
+	v, _ = f.Match("Slanted", nil)
+	if got := v.AxisValues["slnt"]; got != 0 {
+		t.Errorf("got slant %g for an upright query, want 0", got)
+	}
+}
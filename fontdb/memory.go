@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package fontdb
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"honnef.co/go/gutter/opentype"
+)
+
+// NewEmpty returns a database without any fonts. It doesn't scan the
+// system's fonts, which is useful for applications that only use fonts they
+// bundle, and for tests that mustn't depend on the system.
+func NewEmpty() *Faces {
+	return &Faces{Faces: make(map[string][]*Face)}
+}
+
+// AddData adds all faces in data, which may contain a single font or a font
+// collection. Name identifies the font in Face.Path. The data is retained
+// and must not be modified.
+//
+// Faces that fail to load are skipped, so that one bad face in a collection
+// doesn't prevent the others from being added. AddData returns an error
+// describing the skipped faces, even if it added others.
+func (f *Faces) AddData(name string, data []byte) error {
+	n := opentype.NumFonts(data)
+	if n == 0 {
+		return fmt.Errorf("%s: not a font", name)
+	}
+	var errs []error
+	var added int
+	for i := range n {
+		fam, face, err := loadFace(name, data, i)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: face %d: %w", name, i, err))
+			continue
+		}
+		if face == nil {
+			continue
+		}
+		face.Data = data
+		f.add(fam, face)
+		added++
+	}
+	if added == 0 && len(errs) == 0 {
+		return fmt.Errorf("%s: no supported faces", name)
+	}
+	return errors.Join(errs...)
+}
+
+// AddFS adds all font files in fsys, such as an embed.FS, recursively. Font
+// files are recognized by their extensions, .ttf, .otf, .ttc and .otc.
+func (f *Faces) AddFS(fsys fs.FS) error {
+	var errs []error
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(path.Ext(p)) {
+		case ".ttf", ".otf", ".ttc", ".otc":
+		default:
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		if err := f.AddData(p, data); err != nil {
+			// Keep going, so that one bad file doesn't prevent all other
+			// fonts from being added.
+			errs = append(errs, err)
+		}
+		return nil
+	})
+	return errors.Join(append(errs, err)...)
+}
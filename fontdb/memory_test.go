@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package fontdb
+
+import (
+	"encoding/binary"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// collection returns a font collection whose first face is broken and whose
+// second face is the Go font.
+func collection() []byte {
+	const hdrSize = 12 + 2*4
+	// The broken face claims to have tables that are out of bounds.
+	broken := []byte{0, 1, 0, 0, 0, 10, 0, 0, 0, 0, 0, 0}
+
+	be := binary.BigEndian
+	var out []byte
+	out = append(out, "ttcf"...)
+	out = be.AppendUint32(out, 0x00010000)
+	out = be.AppendUint32(out, 2)
+	out = be.AppendUint32(out, hdrSize)
+	out = be.AppendUint32(out, hdrSize+uint32(len(broken)))
+	out = append(out, broken...)
+
+	// Table offsets are relative to the start of the collection, so we have
+	// to move the Go font's tables.
+	off := uint32(len(out))
+	out = append(out, goregular.TTF...)
+	face := out[off:]
+	numTables := int(be.Uint16(face[4:]))
+	for i := range numTables {
+		rec := face[12+16*i:]
+		be.PutUint32(rec[8:], be.Uint32(rec[8:])+off)
+	}
+	return out
+}
+
+func TestAddDataSkipsBrokenFaces(t *testing.T) {
+	f := NewEmpty()
+	err := f.AddData("fonts.ttc", collection())
+	if err == nil {
+		t.Error("broken face wasn't reported")
+	}
+	face, ok := f.Match("Go", nil)
+	if !ok {
+		t.Fatal("face after the broken face wasn't added")
+	}
+	if face.Font.Index != 1 {
+		t.Errorf("got face %d, want 1", face.Font.Index)
+	}
+
+	if err := f.AddData("Go-Regular.ttf", goregular.TTF); err != nil {
+		t.Errorf("valid font caused error %q", err)
+	}
+	if err := f.AddData("empty.ttf", nil); err == nil {
+		t.Error("empty data didn't cause an error")
+	}
+}
+
+func TestAddDataMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"junk.ttf", []byte("\x00\x01\x00\x00\xff\xffjunkjunkjunkjunk")},
+		{"short.ttf", []byte("\x00\x01\x00\x00")},
+		{"truncated.ttf", goregular.TTF[:len(goregular.TTF)/2]},
+		{"junk.ttc", []byte("ttcf\x00\x01\x00\x00\xff\xff\xff\xff")},
+		{"truncated.ttc", collection()[:100]},
+	}
+	for _, tt := range tests {
+		f := NewEmpty()
+		if err := f.AddData(tt.name, tt.data); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+		if len(f.Faces) != 0 {
+			t.Errorf("%s: added faces %v", tt.name, f.Faces)
+		}
+	}
+}
+
+func TestAddFSMalformed(t *testing.T) {
+	fsys := fstest.MapFS{
+		"junk.ttf":            {Data: []byte("\x00\x01\x00\x00\xff\xffjunkjunkjunkjunk")},
+		"fonts/Go.ttf":        {Data: goregular.TTF},
+		"fonts/truncated.ttf": {Data: goregular.TTF[:100]},
+	}
+	f := NewEmpty()
+	if err := f.AddFS(fsys); err == nil {
+		t.Error("malformed fonts weren't reported")
+	}
+	face, ok := f.Match("Go", nil)
+	if !ok {
+		t.Fatal("valid font wasn't added")
+	}
+	if face.Font.Path != "fonts/Go.ttf" {
+		t.Errorf("got face from %s, want fonts/Go.ttf", face.Font.Path)
+	}
+}
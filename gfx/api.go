@@ -183,4 +183,11 @@ type Layer struct {
 	BlendMode BlendMode
 	Opacity   float32
 	Clip      Shape
+	// LuminanceToAlpha turns the layer into a luminance mask. Before the layer
+	// is composited, the alpha of each of its pixels is replaced by the pixel's
+	// luminance and its color by black. Transparent pixels count as black.
+	//
+	// Combined with [ComposeSrcIn] or [ComposeDestIn], this can be used to mask
+	// content by the brightness of other content.
+	LuminanceToAlpha bool
 }
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package gfx
+
+import "image"
+
+var _ Paint = (*Image)(nil)
+
+// Image paints an image. The image's top-left pixel covers the unit square at
+// the origin of user space, and every pixel covers one unit. Outside of the
+// image, the paint is transparent. To draw an image at a different position
+// or size, transform it.
+//
+// The image's colors are interpreted as sRGB.
+type Image struct {
+	Image image.Image
+	// Filter selects how the image is sampled when it isn't drawn at its
+	// natural size.
+	Filter ImageFilter
+}
+
+func (*Image) isPaint() {}
+
+type ImageFilter uint8
+
+const (
+	// Interpolate linearly between the four nearest pixels.
+	ImageFilterLinear ImageFilter = iota
+	// Use the nearest pixel.
+	ImageFilterNearest
+)
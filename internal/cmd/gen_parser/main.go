@@ -30,7 +30,7 @@ import (
 func main() {
 	cfg := &packages.Config{
 		// We need syntax to get access to unexported types
-		Mode: packages.NeedTypes | packages.NeedSyntax,
+		Mode: packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps,
 	}
 	pkgs, err := packages.Load(cfg, "honnef.co/go/gutter/opentype/internal/tables")
 	if err != nil {
@@ -25,6 +25,17 @@ func NewBlobFromFile(path string) *Blob {
 	return safeish.Cast[*Blob](C.hb_blob_create_from_file(cstr))
 }
 
+// NewBlob returns a blob containing a copy of data.
+func NewBlob(data []byte) *Blob {
+	return safeish.Cast[*Blob](C.hb_blob_create(
+		(*C.char)(unsafe.Pointer(unsafe.SliceData(data))),
+		C.uint(len(data)),
+		C.HB_MEMORY_MODE_DUPLICATE,
+		nil,
+		nil,
+	))
+}
+
 func (b *Blob) SubBlob(offset, length int) *Blob {
 	return safeish.Cast[*Blob](C.hb_blob_create_sub_blob(&b.c, C.uint(offset), C.uint(length)))
 }
@@ -5,6 +5,7 @@
 package harfbuzz
 
 // #include <harfbuzz/hb.h>
+// #include <harfbuzz/hb-ot.h>
 // #include "./draw.h"
 // #include "./paint.h"
 // #cgo noescape hb_font_get_glyph_extents
@@ -107,6 +108,18 @@ func (f *Font) GlyphExtents(glyph int32) (GlyphExtents, bool) {
 	return extents, b != 0
 }
 
+// NominalGlyph returns the glyph that the font's cmap maps r to, if any.
+func (f *Font) NominalGlyph(r rune) (int32, bool) {
+	var glyph C.hb_codepoint_t
+	b := C.hb_font_get_nominal_glyph(&f.c, C.hb_codepoint_t(r), &glyph)
+	return int32(glyph), b != 0
+}
+
+// GlyphHAdvance returns the horizontal advance of the glyph, in font units.
+func (f *Font) GlyphHAdvance(glyph int32) int32 {
+	return int32(C.hb_font_get_glyph_h_advance(&f.c, C.hb_codepoint_t(glyph)))
+}
+
 type FontExtents struct {
 	structs.HostLayout
 
@@ -122,3 +135,23 @@ func (f *Font) HorizontalExtents() (FontExtents, bool) {
 	b := C.hb_font_get_h_extents(&f.c, safeish.Cast[*C.hb_font_extents_t](&extents))
 	return extents, b != 0
 }
+
+type MetricsTag uint32
+
+const (
+	MetricsUnderlineSize      MetricsTag = C.HB_OT_METRICS_TAG_UNDERLINE_SIZE
+	MetricsUnderlineOffset    MetricsTag = C.HB_OT_METRICS_TAG_UNDERLINE_OFFSET
+	MetricsStrikeoutSize      MetricsTag = C.HB_OT_METRICS_TAG_STRIKEOUT_SIZE
+	MetricsStrikeoutOffset    MetricsTag = C.HB_OT_METRICS_TAG_STRIKEOUT_OFFSET
+	MetricsXHeight            MetricsTag = C.HB_OT_METRICS_TAG_X_HEIGHT
+	MetricsCapHeight          MetricsTag = C.HB_OT_METRICS_TAG_CAP_HEIGHT
+	MetricsHorizontalAscender MetricsTag = C.HB_OT_METRICS_TAG_HORIZONTAL_ASCENDER
+)
+
+// Metric returns the value of a font-wide metric, in font units. If the font
+// doesn't provide the metric, HarfBuzz synthesizes a value.
+func (f *Font) Metric(tag MetricsTag) int32 {
+	var pos C.hb_position_t
+	C.hb_ot_metrics_get_position_with_fallback(&f.c, C.hb_ot_metrics_tag_t(tag), &pos)
+	return int32(pos)
+}
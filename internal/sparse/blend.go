@@ -58,6 +58,22 @@ func mixSoftLight(dst, src float32) float32 {
 	}
 }
 
+// luminanceToAlpha returns black with an alpha equal to c's luminance. Because
+// c is premultiplied, transparent colors have no luminance.
+func luminanceToAlpha(c gfx.PlainColor) gfx.PlainColor {
+	// These are the coefficients used by SVG's luminanceToAlpha, which operates
+	// on linear RGB, like we do.
+	return gfx.PlainColor{0, 0, 0, 0.2125*c[0] + 0.7154*c[1] + 0.0721*c[2]}
+}
+
+func luminanceToAlphaColumns(cols [][stripHeight]gfx.PlainColor) {
+	for i := range cols {
+		for j := range stripHeight {
+			cols[i][j] = luminanceToAlpha(cols[i][j])
+		}
+	}
+}
+
 func blendComplexComplex(
 	dst [][stripHeight]gfx.PlainColor,
 	tos [][stripHeight]gfx.PlainColor,
@@ -168,10 +168,10 @@ func (f *fine) runCmd(cmd cmd) {
 	case cmdBlend:
 		// OPT(dh): we should probably just pass *cmd to blend and alphaBlend
 		args := f.tile.blendArgs[cmd.args]
-		f.blend(int(args.x), int(args.width), args.blend, args.opacity)
+		f.blend(int(args.x), int(args.width), args.blend, args.opacity, args.luminance)
 	case cmdAlphaBlend:
 		args := f.tile.alphaBlendArgs[cmd.args]
-		f.alphaBlend(int(args.x), int(args.width), args.alphas, args.blend, args.opacity)
+		f.alphaBlend(int(args.x), int(args.width), args.alphas, args.blend, args.opacity, args.luminance)
 	case cmdClear:
 		args := f.tile.fillArgs[cmd.args]
 		if p, ok := args.paint.(encodedColor); ok {
@@ -276,7 +276,7 @@ func (f *fine) fill(x, width int, paint encodedPaint) {
 	}
 }
 
-func (f *fine) blend(x, width int, blend gfx.BlendMode, opacity float32) {
+func (f *fine) blend(x, width int, blend gfx.BlendMode, opacity float32, luminance bool) {
 	if n := len(f.layers); n < 2 {
 		panic(fmt.Sprintf("internal error: trying to clipFill but we only have %d layers", n))
 	}
@@ -296,6 +296,9 @@ func (f *fine) blend(x, width int, blend gfx.BlendMode, opacity float32) {
 	src := tos.scratch[x : x+width]
 	if !nosComplex && !tos.complex {
 		c := tos.singleColor
+		if luminance {
+			c = luminanceToAlpha(c)
+		}
 
 		c[0] *= opacity
 		c[1] *= opacity
@@ -304,6 +307,9 @@ func (f *fine) blend(x, width int, blend gfx.BlendMode, opacity float32) {
 		blendSimpleSimple(dst, nos.singleColor, c, blend)
 	} else {
 		f.materialize(tos)
+		if luminance {
+			luminanceToAlphaColumns(src)
+		}
 		blendComplexComplex(dst, src, nil, blend, opacity)
 	}
 }
@@ -314,6 +320,7 @@ func (f *fine) alphaBlend(
 	alphas [][stripHeight]uint8,
 	blend gfx.BlendMode,
 	opacity float32,
+	luminance bool,
 ) {
 	tos := &f.layers[len(f.layers)-1]
 	nos := &f.layers[len(f.layers)-2]
@@ -324,6 +331,9 @@ func (f *fine) alphaBlend(
 
 	dst := nos.scratch[x : x+width]
 	src := tos.scratch[x : x+width]
+	if luminance {
+		luminanceToAlphaColumns(src)
+	}
 	blendComplexComplex(dst, src, alphas, blend, opacity)
 }
 
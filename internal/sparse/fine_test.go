@@ -9,7 +9,9 @@ import (
 	"flag"
 	"fmt"
 	"image"
+	stdcolor "image/color"
 	"image/png"
+	"math"
 	"os"
 	"testing"
 
@@ -518,6 +520,73 @@ func Test50pctGrey(t *testing.T) {
 	})
 }
 
+func TestLuminanceToAlpha(t *testing.T) {
+	ctx := getCtx(32, 32, true)
+	full := curve.NewRectFromOrigin(curve.Pt(0, 0), curve.Sz(32, 32))
+	left := curve.NewRectFromOrigin(curve.Pt(0, 0), curve.Sz(16, 32))
+
+	// Mask red by a luminance mask that is 50% grey on the left and empty on
+	// the right.
+	ctx.PushLayer(Layer{Opacity: 1})
+	ctx.PushLayer(Layer{Opacity: 1, LuminanceToAlpha: true})
+	ctx.Fill(left, curve.Identity, gfx.NonZero, gfx.Solid(color.Make(color.LinearSRGB, 0.5, 0.5, 0.5, 1)))
+	ctx.PopLayer()
+	ctx.PushLayer(Layer{BlendMode: gfx.BlendMode{Compose: gfx.ComposeSrcIn}, Opacity: 1})
+	ctx.Fill(full, curve.Identity, gfx.NonZero, gfx.Solid(color.Make(color.LinearSRGB, 1, 0, 0, 1)))
+	ctx.PopLayer()
+	ctx.PopLayer()
+
+	pixmap := render(ctx)
+	for _, tt := range []struct {
+		x    int
+		want gfx.PlainColor
+	}{
+		{0, gfx.PlainColor{0.5, 0, 0, 0.5}},
+		{31, gfx.PlainColor{0, 0, 0, 0}},
+	} {
+		got := pixmap[16*32+tt.x]
+		for i := range got {
+			if math.Abs(float64(got[i]-tt.want[i])) > 1e-3 {
+				t.Errorf("pixel at x=%d is %v, want %v", tt.x, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestImage(t *testing.T) {
+	ctx := getCtx(32, 32, true)
+	// A 2x2 image with an opaque red, a translucent green, an opaque white
+	// and a transparent pixel, scaled up to 16x16 pixels.
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, stdcolor.NRGBA{255, 0, 0, 255})
+	img.Set(1, 0, stdcolor.NRGBA{0, 255, 0, 128})
+	img.Set(0, 1, stdcolor.NRGBA{255, 255, 255, 255})
+	full := curve.NewRectFromOrigin(curve.Pt(0, 0), curve.Sz(32, 32))
+	ctx.Fill(full, curve.Scale(8, 8), gfx.NonZero, &gfx.Image{Image: img, Filter: gfx.ImageFilterNearest})
+
+	pixmap := render(ctx)
+	for _, tt := range []struct {
+		x, y int
+		want gfx.PlainColor
+	}{
+		{4, 4, gfx.PlainColor{1, 0, 0, 1}},
+		{12, 4, gfx.PlainColor{0, 128.0 / 255, 0, 128.0 / 255}},
+		{4, 12, gfx.PlainColor{1, 1, 1, 1}},
+		{12, 12, gfx.PlainColor{}},
+		// Outside of the image.
+		{20, 20, gfx.PlainColor{}},
+	} {
+		got := pixmap[tt.y*32+tt.x]
+		for i := range got {
+			if math.Abs(float64(got[i]-tt.want[i])) > 1e-3 {
+				t.Errorf("pixel at (%d, %d) is %v, want %v", tt.x, tt.y, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
 func writeF32AsU16(in []gfx.PlainColor, out [][8]uint8) {
 	_ = out[len(in)]
 	for i := range in {
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package sparse
+
+import (
+	"image"
+	"math"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/gfx"
+)
+
+func encodeImage(img *gfx.Image, transform curve.Affine) *encodedImage {
+	// Like for gradients, we sample at the center of pixels and map pixels to
+	// the image's space by applying the inverse transform.
+	transform = transform.Invert().Mul(curve.Translate(curve.Vec(0.5, 0.5)))
+	xAdvance, yAdvance := xyAdvances(transform)
+	return &encodedImage{
+		img:       img.Image,
+		bounds:    img.Image.Bounds(),
+		filter:    img.Filter,
+		transform: transform,
+		xAdvance:  xAdvance,
+		yAdvance:  yAdvance,
+	}
+}
+
+var _ encodedPaint = (*encodedImage)(nil)
+
+type encodedImage struct {
+	img    image.Image
+	bounds image.Rectangle
+	filter gfx.ImageFilter
+	// A transform that needs to be applied to the position of the first
+	// processed pixel.
+	transform curve.Affine
+	// How much to advance into the x/y direction for one step in the x
+	// direction.
+	xAdvance curve.Vec2
+	// How much to advance into the x/y direction for one step in the y
+	// direction.
+	yAdvance curve.Vec2
+}
+
+// Opaque implements [encodedPaint].
+func (e *encodedImage) Opaque() bool {
+	// The image is transparent outside of its bounds, and the path we're
+	// filling may extend beyond them.
+	return false
+}
+
+// isEncodedPaint implements [encodedPaint].
+func (e *encodedImage) isEncodedPaint() {}
+
+func (*encodedImage) String() string { return "Image" }
+
+type imageFiller struct {
+	curPos curve.Point
+	image  *encodedImage
+}
+
+func (e *encodedImage) filler(startX, startY uint16) paintFiller {
+	return &imageFiller{
+		curPos: curve.Pt(float64(startX), float64(startY)).Transform(e.transform),
+		image:  e,
+	}
+}
+
+func (f *imageFiller) reset(startX, startY uint16) {
+	f.curPos = curve.Pt(float64(startX), float64(startY)).Transform(f.image.transform)
+}
+
+func (f *imageFiller) fill(dst [][stripHeight]gfx.PlainColor) {
+	img := f.image
+	for x := range dst {
+		col := &dst[x]
+		pos := f.curPos
+		for y := range col {
+			switch img.filter {
+			case gfx.ImageFilterNearest:
+				col[y] = img.texel(int(math.Floor(pos.X)), int(math.Floor(pos.Y)))
+			default:
+				col[y] = img.sampleLinear(pos)
+			}
+			pos = pos.Translate(img.yAdvance)
+		}
+		f.curPos = f.curPos.Translate(img.xAdvance)
+	}
+}
+
+// sampleLinear interpolates between the four texels closest to pos.
+func (e *encodedImage) sampleLinear(pos curve.Point) gfx.PlainColor {
+	// Texel centers are at half-integer coordinates.
+	u := pos.X - 0.5
+	v := pos.Y - 0.5
+	x0 := math.Floor(u)
+	y0 := math.Floor(v)
+	fx := float32(u - x0)
+	fy := float32(v - y0)
+	ix := int(x0)
+	iy := int(y0)
+
+	c00 := e.texel(ix, iy)
+	c10 := e.texel(ix+1, iy)
+	c01 := e.texel(ix, iy+1)
+	c11 := e.texel(ix+1, iy+1)
+	var out gfx.PlainColor
+	for i := range out {
+		top := c00[i] + (c10[i]-c00[i])*fx
+		bottom := c01[i] + (c11[i]-c01[i])*fx
+		out[i] = top + (bottom-top)*fy
+	}
+	return out
+}
+
+// texel returns the premultiplied, linear color of the image's pixel at (x,
+// y), relative to the image's top-left corner.
+func (e *encodedImage) texel(x, y int) gfx.PlainColor {
+	x += e.bounds.Min.X
+	y += e.bounds.Min.Y
+	if !(image.Point{x, y}.In(e.bounds)) {
+		return gfx.PlainColor{}
+	}
+
+	// OPT(dh): converting pixels on every access is wasteful when the same
+	// image is drawn many times, or scaled up. Consider caching the converted
+	// image.
+	switch img := e.img.(type) {
+	case *image.NRGBA:
+		i := img.PixOffset(x, y)
+		p := img.Pix[i : i+4 : i+4]
+		return straightToInternal(p[0], p[1], p[2], p[3])
+	case *image.RGBA:
+		i := img.PixOffset(x, y)
+		p := img.Pix[i : i+4 : i+4]
+		return premultipliedToInternal(p[0], p[1], p[2], p[3])
+	default:
+		r, g, b, a := img.At(x, y).RGBA()
+		return premultipliedToInternal(uint8(r>>8), uint8(g>>8), uint8(b>>8), uint8(a>>8))
+	}
+}
+
+// premultipliedToInternal converts an 8-bit, premultiplied sRGB color.
+func premultipliedToInternal(r, g, b, a uint8) gfx.PlainColor {
+	switch a {
+	case 0:
+		return gfx.PlainColor{}
+	case 255:
+		return straightToInternal(r, g, b, a)
+	}
+	// Colors have to be unpremultiplied before they can be linearized.
+	unpremul := func(c uint8) uint8 {
+		return uint8(min(255, (uint32(c)*255+uint32(a)/2)/uint32(a)))
+	}
+	return straightToInternal(unpremul(r), unpremul(g), unpremul(b), a)
+}
+
+// straightToInternal converts an 8-bit, unpremultiplied sRGB color.
+func straightToInternal(r, g, b, a uint8) gfx.PlainColor {
+	fa := float32(a) * (1.0 / 255.0)
+	return gfx.PlainColor{
+		srgbToLinearTable[r] * fa,
+		srgbToLinearTable[g] * fa,
+		srgbToLinearTable[b] * fa,
+		fa,
+	}
+}
+
+var srgbToLinearTable = func() (table [256]float32) {
+	for i := range table {
+		c := float64(i) / 255
+		if c <= 0.04045 {
+			c /= 12.92
+		} else {
+			c = math.Pow((c+0.055)/1.055, 2.4)
+		}
+		table[i] = float32(c)
+	}
+	return table
+}()
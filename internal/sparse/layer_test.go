@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package sparse
+
+import (
+	"math"
+	"testing"
+
+	"honnef.co/go/color"
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/gfx"
+)
+
+// checkPixels checks that pixels in the columns [x0, x1) have the color want
+// and all other pixels are transparent.
+func checkPixels(t *testing.T, ctx *Renderer, x0, x1 int, want gfx.PlainColor) {
+	t.Helper()
+	pixmap := render(ctx)
+	for i, got := range pixmap {
+		want := want
+		if x := i % int(ctx.width); x < x0 || x >= x1 {
+			want = gfx.PlainColor{}
+		}
+		for j := range got {
+			if math.Abs(float64(got[j]-want[j])) > 1e-3 {
+				t.Fatalf("pixel at (%d, %d) is %v, want %v",
+					i%int(ctx.width), i/int(ctx.width), got, want)
+			}
+		}
+	}
+}
+
+func TestClippedLayerBlends(t *testing.T) {
+	// The clip consists of two overlapping rectangles. The strips for the
+	// edges inside the overlap are fully opaque and get blended like the gaps
+	// between strips, by adjacent blend commands that have to be merged, not
+	// dropped.
+	ctx := getCtx(64, 8, true)
+	var clip curve.BezPath
+	for _, xs := range [][2]float64{{4, 40}, {20, 60}} {
+		clip.MoveTo(curve.Pt(xs[0], 0))
+		clip.LineTo(curve.Pt(xs[1], 0))
+		clip.LineTo(curve.Pt(xs[1], 8))
+		clip.LineTo(curve.Pt(xs[0], 8))
+		clip.ClosePath()
+	}
+	full := curve.NewRectFromOrigin(curve.Pt(0, 0), curve.Sz(64, 8))
+	ctx.PushLayer(Layer{Opacity: 0.5, Clip: clip, ClipTransform: curve.Identity})
+	ctx.Fill(full, curve.Identity, gfx.NonZero, gfx.Solid(color.Make(color.LinearSRGB, 1, 0, 0, 1)))
+	ctx.PopLayer()
+	checkPixels(t, ctx, 4, 60, gfx.PlainColor{0.5, 0, 0, 0.5})
+}
+
+func TestLayerInLazyLayer(t *testing.T) {
+	// The outer layer is pushed lazily, and has to be pushed before the
+	// inner layer, which isn't lazy because of its blend mode.
+	ctx := getCtx(64, 8, true)
+	full := curve.NewRectFromOrigin(curve.Pt(0, 0), curve.Sz(64, 8))
+	ctx.PushLayer(Layer{Opacity: 0.5})
+	ctx.PushLayer(Layer{BlendMode: gfx.BlendMode{Mix: gfx.MixMultiply}, Opacity: 1})
+	ctx.Fill(full, curve.Identity, gfx.NonZero, gfx.Solid(color.Make(color.LinearSRGB, 1, 0, 0, 1)))
+	ctx.PopLayer()
+	ctx.PopLayer()
+	checkPixels(t, ctx, 0, 64, gfx.PlainColor{0.5, 0, 0, 0.5})
+}
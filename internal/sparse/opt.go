@@ -295,6 +295,7 @@ type optLayer struct {
 	maybeNotTransparent optBitset
 	blend               gfx.BlendMode
 	opacity             float32
+	luminance           bool
 }
 
 func childNeedsBackdrop(layers []optLayer, l *optLayer) bool {
@@ -361,6 +362,7 @@ func optimizeCommands(tile *wideTile, cmds []cmd, stackScratch []optLayer) (newC
 					layers[top].footer = i
 					layers[top].blend = args.blend
 					layers[top].opacity = args.opacity
+					layers[top].luminance = args.luminance
 				}
 				blended := makeMask(args.x, args.width)
 				blendedNonZeroAlpha := blended
@@ -728,6 +730,7 @@ func optimizeCommands(tile *wideTile, cmds []cmd, stackScratch []optLayer) (newC
 							changed = true
 						} else if child.opacity == 1 &&
 							child.blend.Mix == gfx.MixNormal &&
+							!child.luminance &&
 							child.numAlphaBlends == 0 &&
 							!childNeedsBackdrop(layers, child) &&
 							// TODO(dh): future-proof code would look for
@@ -770,7 +773,7 @@ func optimizeCommands(tile *wideTile, cmds []cmd, stackScratch []optLayer) (newC
 						}
 
 					case gfx.ComposeCopy:
-						if child.opacity == 1 && cmds[child.push+1].typ == cmdCopyBackdrop && child.numAlphaBlends == 0 {
+						if child.opacity == 1 && !child.luminance && cmds[child.push+1].typ == cmdCopyBackdrop && child.numAlphaBlends == 0 {
 							unwrappable := true
 							for j := child.footer; j < child.pop; j++ {
 								c2 := cmds[j]
@@ -52,6 +52,7 @@ type layer struct {
 	alphas       [][stripHeight]uint8
 	opacity      float32
 	blend        gfx.BlendMode
+	luminance    bool
 	copyBackdrop bool
 	nonempty     bool
 	blackholed   int
@@ -122,7 +123,8 @@ func optimizeRecording(cmds gfx.Recording) {
 				push: i,
 				needBackdrop: cmd.Layer.BlendMode != gfx.BlendMode{} ||
 					cmd.Layer.Opacity != 1 ||
-					cmd.Layer.Clip != nil,
+					cmd.Layer.Clip != nil ||
+					cmd.Layer.LuminanceToAlpha,
 			}
 			if len(layers) > 0 && cmd.Layer.BlendMode != (gfx.BlendMode{}) {
 				layers[len(layers)-1].childNeedsBackdrop = true
@@ -210,8 +212,9 @@ func PlayRecording(cmds gfx.Recording, r *Renderer, aff curve.Affine) {
 			r.PopLayer()
 		case gfx.CommandPushLayer:
 			lc := LayerCompiled{
-				BlendMode: cmd.Layer.BlendMode,
-				Opacity:   cmd.Layer.Opacity,
+				BlendMode:        cmd.Layer.BlendMode,
+				Opacity:          cmd.Layer.Opacity,
+				LuminanceToAlpha: cmd.Layer.LuminanceToAlpha,
 			}
 			if cmd.Layer.Clip != nil {
 				lc.Clip = maybe.Some(compiled[i])
@@ -467,6 +470,7 @@ func (ctx *Renderer) popLayer() {
 					wideTileWidth,
 					lastLayer.blend,
 					lastLayer.opacity,
+					lastLayer.luminance,
 				)
 				tile.popLayer()
 			}
@@ -478,6 +482,7 @@ func (ctx *Renderer) popLayer() {
 						wideTileWidth,
 						lastLayer.blend,
 						lastLayer.opacity,
+						lastLayer.luminance,
 					)
 					ctx.tiles[tileY][tileX].popLayer()
 				}
@@ -566,13 +571,14 @@ func (ctx *Renderer) popLayer() {
 				}
 			}
 			if allOne {
-				ctx.tiles[tileY][xtile].blend(xTileRel, width, lastLayer.blend, lastLayer.opacity)
+				ctx.tiles[tileY][xtile].blend(xTileRel, width, lastLayer.blend, lastLayer.opacity, lastLayer.luminance)
 			} else {
 				args := alphaBlendArgs{
 					alphas: alphas[col:],
 					blendArgs: blendArgs{
-						blend:   lastLayer.blend,
-						opacity: lastLayer.opacity,
+						blend:     lastLayer.blend,
+						opacity:   lastLayer.opacity,
+						luminance: lastLayer.luminance,
 						baseArgs: baseArgs{
 							x:     xTileRel,
 							width: width,
@@ -608,7 +614,7 @@ func (ctx *Renderer) popLayer() {
 					continue
 				}
 				x += width
-				ctx.tiles[tileY][xtile].blend(xTileRel, width, lastLayer.blend, lastLayer.opacity)
+				ctx.tiles[tileY][xtile].blend(xTileRel, width, lastLayer.blend, lastLayer.opacity, lastLayer.luminance)
 				tileX = xtile
 				popPending = true
 			}
@@ -661,19 +667,21 @@ func (ctx *Renderer) Stroke(
 }
 
 type Layer struct {
-	BlendMode     gfx.BlendMode
-	Opacity       float32
-	Clip          gfx.Shape
-	ClipTransform curve.Affine
-	ClipFillRule  gfx.FillRule
-	CopyBackdrop  bool
+	BlendMode        gfx.BlendMode
+	Opacity          float32
+	Clip             gfx.Shape
+	ClipTransform    curve.Affine
+	ClipFillRule     gfx.FillRule
+	CopyBackdrop     bool
+	LuminanceToAlpha bool
 }
 
 type LayerCompiled struct {
-	BlendMode    gfx.BlendMode
-	Opacity      float32
-	Clip         maybe.Option[Path]
-	CopyBackdrop bool
+	BlendMode        gfx.BlendMode
+	Opacity          float32
+	Clip             maybe.Option[Path]
+	CopyBackdrop     bool
+	LuminanceToAlpha bool
 }
 
 // PushClip pushes a new clip to the clip stack. The provided shape gets
@@ -784,6 +792,9 @@ func (ctx *Renderer) PushLayerCompiled(l LayerCompiled) {
 		if !lazy {
 			for tileY := bbox.tileMin.tileY; tileY < bbox.tileMax.tileY; tileY++ {
 				for tileX := bbox.tileMin.tileX; tileX < bbox.tileMax.tileX; tileX++ {
+					// Lazy parent layers have to be pushed before we can push
+					// this layer.
+					ctx.ensureLayerForTile(tileX, tileY)
 					ctx.tiles[tileY][tileX].pushLayer()
 					if l.CopyBackdrop {
 						ctx.tiles[tileY][tileX].copyBackdrop()
@@ -796,6 +807,7 @@ func (ctx *Renderer) PushLayerCompiled(l LayerCompiled) {
 			strips:       nil,
 			opacity:      l.Opacity,
 			blend:        l.BlendMode,
+			luminance:    l.LuminanceToAlpha,
 			alphas:       nil,
 			copyBackdrop: l.CopyBackdrop,
 			nonempty:     l.CopyBackdrop && topLayer.nonempty,
@@ -903,6 +915,7 @@ func (ctx *Renderer) PushLayerCompiled(l LayerCompiled) {
 		strips:       strips,
 		opacity:      l.Opacity,
 		blend:        l.BlendMode,
+		luminance:    l.LuminanceToAlpha,
 		alphas:       l.Clip.Unwrap().alphas,
 		copyBackdrop: l.CopyBackdrop,
 		nonempty:     l.CopyBackdrop && topLayer.nonempty,
@@ -918,10 +931,11 @@ func (ctx *Renderer) PushLayer(l Layer) {
 	}
 
 	ctx.PushLayerCompiled(LayerCompiled{
-		BlendMode:    l.BlendMode,
-		Opacity:      l.Opacity,
-		Clip:         p,
-		CopyBackdrop: l.CopyBackdrop,
+		BlendMode:        l.BlendMode,
+		Opacity:          l.Opacity,
+		Clip:             p,
+		CopyBackdrop:     l.CopyBackdrop,
+		LuminanceToAlpha: l.LuminanceToAlpha,
 	})
 
 }
@@ -980,6 +994,8 @@ func encodePaint(p gfx.Paint, transform curve.Affine) encodedPaint {
 		return encodeSweepGradient(p, transform)
 	case *gfx.BlurredRoundedRectangle:
 		return encodeBlurredRoundedRectangle(p, transform)
+	case *gfx.Image:
+		return encodeImage(p, transform)
 	default:
 		panic(fmt.Sprintf("unexpected gfx.Paint: %#v", p))
 	}
@@ -64,6 +64,8 @@ type blendArgs struct {
 	opacity float32
 	baseArgs
 	blend gfx.BlendMode
+	// Convert the layer's luminance to alpha before blending.
+	luminance bool
 }
 
 type alphaBlendArgs struct {
@@ -94,12 +96,12 @@ func (wt *wideTile) stringifyCmd(cmd cmd) string {
 		return "CopyBackdrop()"
 	case cmdBlend:
 		args := wt.blendArgs[cmd.args]
-		return fmt.Sprintf("Blend(x=%v, width=%v, blend=%s, opacity=%v)",
-			args.x, args.width, args.blend, args.opacity)
+		return fmt.Sprintf("Blend(x=%v, width=%v, blend=%s, opacity=%v, luminance=%v)",
+			args.x, args.width, args.blend, args.opacity, args.luminance)
 	case cmdAlphaBlend:
 		args := wt.alphaBlendArgs[cmd.args]
-		return fmt.Sprintf("AlphaBlend(x=%v, width=%v, blend=%s, opacity=%v)",
-			args.x, args.width, args.blend, args.opacity)
+		return fmt.Sprintf("AlphaBlend(x=%v, width=%v, blend=%s, opacity=%v, luminance=%v)",
+			args.x, args.width, args.blend, args.opacity, args.luminance)
 	case cmdNop:
 		return "Nop()"
 	case cmdClear:
@@ -206,6 +208,7 @@ func (wt *wideTile) blend(
 	width uint16,
 	blend gfx.BlendMode,
 	opacity float32,
+	luminance bool,
 ) {
 	if wt.isZeroClip() {
 		return
@@ -226,15 +229,16 @@ func (wt *wideTile) blend(
 	// generation time, an uninterrupted run of blends is only possible while
 	// popping a layer.
 	if prevCmd.typ == cmdBlend {
-		prevArgs := wt.blendArgs[prevCmd.args]
+		prevArgs := &wt.blendArgs[prevCmd.args]
 		if !disableWideTileOpts && x == prevArgs.x+prevArgs.width {
 			prevArgs.width += width
 			return
 		}
 	}
 	wt.blendArgs = append(wt.blendArgs, blendArgs{
-		blend:   blend,
-		opacity: opacity,
+		blend:     blend,
+		opacity:   opacity,
+		luminance: luminance,
 		baseArgs: baseArgs{
 			x:     x,
 			width: width,
@@ -22,6 +22,8 @@ func BenchmarkConvert(b *testing.B) {
 	b.ResetTimer()
 
 	for range b.N {
-		ConvertAnimation(comp)
+		if _, err := ConvertAnimation(comp, Options{}); err != nil {
+			b.Fatal(err)
+		}
 	}
 }
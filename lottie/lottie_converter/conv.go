@@ -6,9 +6,17 @@
 package lottie_converter
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
 	"math"
+	"path"
 	"slices"
+	"strings"
 
 	"honnef.co/go/color"
 	"honnef.co/go/curve"
@@ -18,9 +26,45 @@ import (
 	model "honnef.co/go/gutter/lottie/lottie_model"
 	"honnef.co/go/stuff/container/maybe"
 	"honnef.co/go/stuff/math/mathutil"
+
+	_ "golang.org/x/image/webp"
 )
 
-func ConvertAnimation(source *encoding.Animation) *model.Composition {
+// Options configures [ConvertAnimation].
+type Options struct {
+	// ResolveImage loads the images of image assets that aren't embedded in
+	// the animation. dir and name are the asset's directory and file name, as
+	// stored in the animation. If ResolveImage is nil, such images aren't
+	// loaded and layers using them don't render.
+	ResolveImage func(dir, name string) (image.Image, error)
+	// Report, if not nil, is called for each part of the animation that
+	// isn't supported or that failed to load. Such parts are ignored or
+	// approximated, and the rest of the animation is converted. If Report is
+	// nil, unsupported features are ignored silently, but parts that failed
+	// to load cause [ConvertAnimation] to fail.
+	Report func(Unsupported)
+}
+
+// FSImageResolver returns a function for [Options.ResolveImage] that loads
+// images from a file system. Images can be in the PNG, JPEG, or WebP formats.
+func FSImageResolver(fsys fs.FS) func(dir, name string) (image.Image, error) {
+	return func(dir, name string) (image.Image, error) {
+		// Animations often use absolute paths, such as "/images/".
+		p := strings.TrimPrefix(path.Join(dir, name), "/")
+		f, err := fsys.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		img, _, err := image.Decode(f)
+		return img, err
+	}
+}
+
+// ConvertAnimation converts a parsed animation to a composition that can be
+// rendered. Without [Options.Report], it returns an error if parts of the
+// animation, such as images, fail to load.
+func ConvertAnimation(source *encoding.Animation, opts Options) (*model.Composition, error) {
 	target := &model.Composition{
 		FirstFrame: source.InPoint,
 		LastFrame:  source.OutPoint,
@@ -28,58 +72,132 @@ func ConvertAnimation(source *encoding.Animation) *model.Composition {
 		Width:      source.Width,
 		Height:     source.Height,
 		Assets:     make(map[string][]model.Layer),
+		Images:     make(map[string]model.Image),
+		Slots:      make(map[string]*model.Slot),
+	}
+	sc := scope{
+		fn:        opts.Report,
+		framerate: source.Framerate,
+		slots: &slots{
+			source: source.Slots,
+			target: target.Slots,
+		},
+	}
+	for _, m := range source.Markers {
+		target.Markers = append(target.Markers, model.Marker{
+			Name:     m.Comment,
+			Frame:    m.Time,
+			Duration: m.Duration,
+		})
 	}
 
 	// Collect assets and layers
+	var errs []error
 	idmap := map[int]int{}
-	for _, asset := range source.Assets {
+	for i, asset := range source.Assets {
 		switch asset := asset.(type) {
 		case encoding.Precomposition:
 			clear(idmap)
-			var layers []model.Layer
-			var maskLayer maybe.Option[int]
-			for _, layer := range asset.Layers {
-				idx := len(layers)
-				if layer, id, maskBlend, ok := convertLayer(layer); ok {
-					if maskBlend, ok := maskBlend.Get(); ok {
-						if maskLayer, ok := maskLayer.Take().Get(); ok {
-							layer.MaskLayerMode = maybe.Some(maskBlend)
-							layer.MaskLayerID = maybe.Some(maskLayer)
-						}
-					}
-					if layer.IsMask {
-						maskLayer = maybe.Some(idx)
-					}
-					idmap[id] = idx
-					layers = append(layers, layer)
-				}
-			}
-			for i := range layers {
-				layer := &layers[i]
-				if parent, ok := layer.Parent.Get(); ok {
-					layer.Parent = maybe.Some(idmap[parent])
+			target.Assets[asset.ID] = convertLayers(sc.at("assets", i), asset.Layers, idmap)
+		case encoding.Image:
+			img, err := convertImage(asset, &opts)
+			if err != nil {
+				// Layers using the image don't render.
+				isc := sc.at("assets", i)
+				if opts.Report == nil {
+					errs = append(errs, fmt.Errorf("%s: image asset: %w", isc.path, err))
+				} else {
+					isc.report("image asset", err)
 				}
 			}
-			target.Assets[asset.ID] = layers
+			target.Images[asset.ID] = img
+		case nil:
+			// Assets we don't know about, such as sounds, which don't affect
+			// rendering.
 		default:
-			panic(fmt.Sprintf("asset type %T not yet supported", asset))
+			sc.at("assets", i).unsupported(fmt.Sprintf("asset type %T", asset))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if fonts, ok := source.Fonts.Get(); ok {
+		target.Fonts = make(map[string]model.Font, len(fonts.List))
+		for _, font := range fonts.List {
+			target.Fonts[font.Name] = model.Font{
+				Family: font.Family,
+				Style:  font.Style,
+				Ascent: font.Ascent.UnwrapOr(0) / 100,
+			}
+		}
+	}
+	if len(source.Chars) > 0 {
+		target.Glyphs = make(map[model.GlyphKey]model.Glyph, len(source.Chars))
+		for i, char := range source.Chars {
+			target.Glyphs[model.GlyphKey{
+				Character: char.Character,
+				Family:    char.Family,
+				Style:     char.Style,
+			}] = convertGlyph(sc.at("chars", i), char)
 		}
 	}
 
 	clear(idmap)
+	target.Layers = convertLayers(sc, source.Layers, idmap)
+	return target, nil
+}
+
+func convertImage(source encoding.Image, opts *Options) (model.Image, error) {
+	target := model.Image{
+		Width:  source.Width,
+		Height: source.Height,
+	}
+	// Embedded images are stored as data URLs. Not all exporters set the
+	// embedded flag, so we look at the file name, too.
+	if bool(source.Embedded) || strings.HasPrefix(source.FileName, "data:") {
+		_, data, err := encoding.DataURL(source.FileName).Decode()
+		if err != nil {
+			return target, err
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return target, err
+		}
+		target.Image = img
+	} else if opts.ResolveImage != nil {
+		img, err := opts.ResolveImage(source.FilePath, source.FileName)
+		if err != nil {
+			return target, err
+		}
+		target.Image = img
+	}
+	return target, nil
+}
+
+func convertLayers(sc scope, source []encoding.AnyLayer, idmap map[int]int) []model.Layer {
 	var layers []model.Layer
-	var maskLayer maybe.Option[int]
-	for _, layer := range source.Layers {
+	// Maps indices of matted layers to the IDs of their matte layers, for
+	// layers that explicitly specify their matte layer.
+	matteParents := map[int]int{}
+	var matteLayer maybe.Option[int]
+	for i, layer := range source {
 		idx := len(layers)
-		if layer, id, maskBlend, ok := convertLayer(layer); ok {
-			if maskBlend, ok := maskBlend.Get(); ok {
-				if maskLayer, ok := maskLayer.Take().Get(); ok {
-					layer.MaskLayerID = maybe.Some(maskLayer)
-					layer.MaskLayerMode = maybe.Some(maskBlend)
+		if layer, id, matteParent, ok := convertLayer(sc.at("layers", i), layer); ok {
+			if matte, ok := layer.Matte.Get(); ok {
+				// Without an explicit matte layer, the matte is the closest
+				// preceding matte layer.
+				if parent, ok := matteParent.Get(); ok {
+					matteParents[idx] = parent
+				} else if matteLayer, ok := matteLayer.Take().Get(); ok {
+					matte.Layer = matteLayer
+					layer.Matte = maybe.Some(matte)
+				} else {
+					layer.Matte = maybe.Option[model.Matte]{}
 				}
 			}
 			if layer.IsMask {
-				maskLayer = maybe.Some(idx)
+				matteLayer = maybe.Some(idx)
 			}
 			idmap[id] = idx
 			layers = append(layers, layer)
@@ -90,31 +208,51 @@ func ConvertAnimation(source *encoding.Animation) *model.Composition {
 		if parent, ok := layer.Parent.Get(); ok {
 			layer.Parent = maybe.Some(idmap[parent])
 		}
+		if parent, ok := matteParents[i]; ok {
+			if idx, ok := idmap[parent]; ok {
+				matte := layer.Matte.Unwrap()
+				matte.Layer = idx
+				layer.Matte = maybe.Some(matte)
+			} else {
+				layer.Matte = maybe.Option[model.Matte]{}
+			}
+		}
 	}
-	target.Layers = layers
-	return target
+	return layers
 }
 
-func convertLayer(source encoding.AnyLayer) (model.Layer, int, maybe.Option[gfx.BlendMode], bool) {
+// layerNames are the names of the layer types that we don't support, for
+// reporting them.
+var layerNames = map[int]string{
+	7:  "video placeholder",
+	8:  "image sequence",
+	9:  "video layer",
+	10: "image placeholder",
+	12: "adjustment layer",
+	13: "camera layer",
+	14: "light layer",
+}
+
+func convertLayer(sc scope, source encoding.AnyLayer) (model.Layer, int, maybe.Option[int], bool) {
 	var layer model.Layer
-	var none maybe.Option[gfx.BlendMode]
+	var none maybe.Option[int]
 
 	var id int
-	var matteMode maybe.Option[gfx.BlendMode]
+	var matteParent maybe.Option[int]
 	switch l := source.(type) {
 	case encoding.NullLayer:
 		if l.Hidden {
 			return model.Layer{}, 0, none, false
 		}
-		id, matteMode = setupLayerBase(l.VisualLayer, &layer)
+		id, matteParent = setupLayerBase(sc, l.VisualLayer, &layer)
 
 	case encoding.PrecompositionLayer:
 		if l.Hidden {
 			return model.Layer{}, 0, none, false
 		}
-		id, matteMode = setupPrecompLayer(l, &layer)
+		id, matteParent = setupPrecompLayer(sc, l, &layer)
 		name := l.ReferenceID
-		timeRemap := maybe.Map(l.TimeRemap, convertScalar)
+		timeRemap := maybe.Map(l.TimeRemap, sc.scalar)
 		layer.Content = model.Content{
 			Kind: model.ContentKindInstance,
 			Instance: struct {
@@ -127,10 +265,10 @@ func convertLayer(source encoding.AnyLayer) (model.Layer, int, maybe.Option[gfx.
 		if l.Hidden {
 			return model.Layer{}, 0, none, false
 		}
-		id, matteMode = setupShapeLayer(l, &layer)
+		id, matteParent = setupShapeLayer(sc, l, &layer)
 		var shapes []model.Shape
-		for _, shape := range l.Shapes {
-			if shape, ok := convertShape(shape); ok {
+		for i, shape := range l.Shapes {
+			if shape, ok := convertShape(sc.at("shapes", i), shape); ok {
 				shapes = append(shapes, shape)
 			}
 		}
@@ -139,44 +277,91 @@ func convertLayer(source encoding.AnyLayer) (model.Layer, int, maybe.Option[gfx.
 			Shapes: shapes,
 		}
 
+	case encoding.ImageLayer:
+		if l.Hidden {
+			return model.Layer{}, 0, none, false
+		}
+		id, matteParent = setupLayerBase(sc, l.VisualLayer, &layer)
+		layer.Content = model.Content{
+			Kind:  model.ContentKindImage,
+			Image: l.ReferenceID,
+		}
+
+	case encoding.TextLayer:
+		if l.Hidden {
+			return model.Layer{}, 0, none, false
+		}
+		id, matteParent = setupLayerBase(sc, l.VisualLayer, &layer)
+		layer.Content = model.Content{
+			Kind: model.ContentKindText,
+			Text: convertText(sc.at("t"), l.Data),
+		}
+
 	case encoding.SolidLayer:
 		if l.Hidden {
 			return model.Layer{}, 0, none, false
 		}
-		id, matteMode = setupLayerBase(l.VisualLayer, &layer)
+		// The layer can still be used as a parent or a matte.
+		sc.unsupported("solid layer")
+		id, matteParent = setupLayerBase(sc, l.VisualLayer, &layer)
+
+	case encoding.UnknownLayer:
+		switch l.Type {
+		case 6, 11, 15:
+			// Audio, guide, and data layers don't affect rendering.
+		default:
+			name, ok := layerNames[l.Type]
+			if !ok {
+				name = fmt.Sprintf("layer type %d", l.Type)
+			}
+			sc.unsupported(name)
+		}
+		return model.Layer{}, 0, none, false
 	}
 
-	return layer, id, matteMode, true
+	return layer, id, matteParent, true
 }
 
-func setupLayerBase(source encoding.VisualLayer, target *model.Layer) (int, maybe.Option[gfx.BlendMode]) {
+// setupLayerBase returns the layer's ID and the ID of its matte layer, if
+// specified explicitly.
+func setupLayerBase(sc scope, source encoding.VisualLayer, target *model.Layer) (int, maybe.Option[int]) {
 	target.Name = source.Name
 	target.Parent = source.ParentIndex
-	transform, opacity := convertTransform(&source.Transform)
+	transform, opacity := convertTransform(sc.at("ks"), &source.Transform)
 	target.Transform = transform
 	target.Opacity = opacity
 	target.IsMask = bool(source.MatteTarget)
 
-	matteMode := maybe.Map(source.MatteMode, func(v encoding.MatteMode) gfx.BlendMode {
-		switch v {
-		case encoding.MatteModeNormal:
-			return gfx.BlendMode{
-				Mix: gfx.MixNormal,
-			}
-		case encoding.MatteModeAlpha, encoding.MatteModeLuma:
-			return gfx.BlendMode{
-				Compose: gfx.ComposeSrcIn,
-			}
-		case encoding.MatteModeInvertedAlpha, encoding.MatteModeInvertedLuma:
-			return gfx.BlendMode{
-				Compose: gfx.ComposeSrcOut,
-			}
-		default:
-			return gfx.BlendMode{}
+	if mode, ok := source.MatteMode.Get(); ok {
+		var matte model.Matte
+		switch mode {
+		case encoding.MatteModeAlpha:
+			matte.Mode = model.MatteModeAlpha
+		case encoding.MatteModeInvertedAlpha:
+			matte.Mode = model.MatteModeInvertedAlpha
+		case encoding.MatteModeLuma:
+			matte.Mode = model.MatteModeLuma
+		case encoding.MatteModeInvertedLuma:
+			matte.Mode = model.MatteModeInvertedLuma
+		}
+		if mode != encoding.MatteModeNormal {
+			target.Matte = maybe.Some(matte)
+		}
+	}
+
+	if source.ThreeDimensional {
+		sc.at("ddd").unsupported("3D layer")
+	}
+	if source.AutoOrient {
+		sc.at("ao").unsupported("auto-orient")
+	}
+	for i, effect := range source.Effects {
+		if effect.Enabled.UnwrapOr(true) {
+			sc.at("ef", i).unsupported(fmt.Sprintf("effect %q", effect.Name))
 		}
-	})
+	}
 
-	target.BlendMode = convertBlendMode(source.BlendMode)
+	target.BlendMode = convertBlendMode(sc.at("bm"), source.BlendMode)
 	// TODO: Why do we do this next part?
 	if target.BlendMode == maybe.Some(gfx.BlendMode{}) {
 		target.BlendMode = maybe.Option[gfx.BlendMode]{}
@@ -186,58 +371,65 @@ func setupLayerBase(source encoding.VisualLayer, target *model.Layer) (int, mayb
 	target.LastFrame = source.OutPoint
 	target.StartFrame = source.StartTime
 
-	for _, maskSource := range source.MasksProperties {
+	for i, maskSource := range source.MasksProperties {
 		// TODO(dh): what does a mask without a shape do?
 		if shape, ok := maskSource.Shape.Get(); ok {
-			if geometry, ok := convertShapeGeometry(shape); ok {
-				// TODO(dh): correctly implement masks. Compose together
-				// multiple masks into a single mask, and propagate normal
-				// layer's blend mode through the final mask layer, so that its
-				// isolated blend group doesn't cause problems.
-				//
-				// We could either draw actual mask layers, filling the
-				// geometry, then compose the layers together. However, it'd be
-				// preferable to do boolean math directly on the geometry and
-				// generate a clip instead of a mask layer. That way we can
-				// avoid a lot blending. Not all mask modes can be implemented
-				// that way (lighten, darken, possibly difference), but we can
-				// use both boolean math and fills on a single stack of masks.
-				var mode gfx.BlendMode
+			if geometry, ok := convertShapeGeometry(sc.at("masksProperties", i), shape); ok {
+				var mode model.MaskMode
 				switch maskSource.Mode {
 				case encoding.MaskModeNone:
+					// The mask is disabled.
+					continue
 				case encoding.MaskModeAdd:
+					mode = model.MaskModeAdd
 				case encoding.MaskModeSubtract:
+					mode = model.MaskModeSubtract
 				case encoding.MaskModeIntersect:
+					mode = model.MaskModeIntersect
 				case encoding.MaskModeLighten:
+					mode = model.MaskModeLighten
 				case encoding.MaskModeDarken:
+					mode = model.MaskModeDarken
 				case encoding.MaskModeDifference:
+					mode = model.MaskModeDifference
 				default:
+					mode = model.MaskModeIntersect
 				}
 				oneHundred := encoding.ScalarProperty{
 					AnimatableProperty: encoding.AnimatableProperty[float64, encoding.SimpleKeyframe[[]float64]]{
 						Value: 100,
 					},
 				}
-				opacity := convertScalar(maskSource.Opacity.UnwrapOr(oneHundred))
-				target.Masks = append(target.Masks, model.Mask{
+				opacity := sc.scalar(maskSource.Opacity.UnwrapOr(oneHundred))
+				mask := model.Mask{
 					Mode:     mode,
+					Inverted: maskSource.Inverted,
 					Geometry: geometry,
 					Opacity:  opacity,
-				})
+				}
+				if feather, ok := maskSource.Feather.Get(); ok {
+					mask.Feather = convertVec2(sc, feather)
+				}
+				if expansion, ok := maskSource.Expansion.Get(); ok {
+					mask.Expansion = sc.scalar(expansion)
+				}
+				target.Masks = append(target.Masks, mask)
 			}
 		}
 	}
 
-	return source.Index, matteMode
+	return source.Index, source.MatteParent
 }
 
-func setupPrecompLayer(source encoding.PrecompositionLayer, target *model.Layer) (int, maybe.Option[gfx.BlendMode]) {
+func setupPrecompLayer(sc scope, source encoding.PrecompositionLayer, target *model.Layer) (int, maybe.Option[int]) {
 	target.Width = source.Width
 	target.Height = source.Height
-	return setupLayerBase(source.VisualLayer, target)
+	return setupLayerBase(sc, source.VisualLayer, target)
 }
 
-func convertBlendMode(value encoding.BlendMode) maybe.Option[gfx.BlendMode] {
+// convertBlendMode converts a blend mode. Unsupported blend modes are
+// reported and use normal blending.
+func convertBlendMode(sc scope, value encoding.BlendMode) maybe.Option[gfx.BlendMode] {
 	switch value {
 	case encoding.BlendModeNormal:
 		return maybe.Option[gfx.BlendMode]{}
@@ -263,68 +455,47 @@ func convertBlendMode(value encoding.BlendMode) maybe.Option[gfx.BlendMode] {
 		return maybe.Some(gfx.BlendMode{Mix: gfx.MixDifference})
 	case encoding.BlendModeExclusion:
 		return maybe.Some(gfx.BlendMode{Mix: gfx.MixExclusion})
+	case encoding.BlendModeAdd:
+		return maybe.Some(gfx.BlendMode{Compose: gfx.ComposePlus})
 	case encoding.BlendModeHue:
-		// return maybe.Some(gfx.BlendMode{Mix: gfx.MixHue})
 		// XXX add support
-		panic("unimplemented")
+		sc.unsupported("blend mode hue")
 	case encoding.BlendModeSaturation:
-		// return maybe.Some(gfx.BlendMode{Mix: gfx.MixSaturation})
 		// XXX add support
-		panic("unimplemented")
+		sc.unsupported("blend mode saturation")
 	case encoding.BlendModeColor:
-		// return maybe.Some(gfx.BlendMode{Mix: gfx.MixColor})
 		// XXX add support
-		panic("unimplemented")
+		sc.unsupported("blend mode color")
 	case encoding.BlendModeLuminosity:
-		// return maybe.Some(gfx.BlendMode{Mix: gfx.MixLuminosity})
-		// XXX add support
-		panic("unimplemented")
-	case encoding.BlendModeAdd:
 		// XXX add support
-		panic("unimplemented")
+		sc.unsupported("blend mode luminosity")
 	case encoding.BlendModeHardMix:
 		// XXX add support
-		panic("unimplemented")
+		sc.unsupported("blend mode hard mix")
 	default:
-		panic("unimplemented")
-		return maybe.Option[gfx.BlendMode]{}
+		sc.unsupported(fmt.Sprintf("blend mode %d", value))
 	}
+	return maybe.Option[gfx.BlendMode]{}
 }
 
-func convertTransform(value *encoding.Transform) (animation.KeyframedTransform, animation.Keyframes[float64]) {
-	var position animation.KeyframedPoint
+func convertTransform(sc scope, value *encoding.Transform) (animation.KeyframedTransform, animation.Keyframes[float64]) {
+	position := convertSplittablePos(sc, value.Position)
 
-	if value.Position.Split {
-		position = animation.KeyframedPoint{
-			X: convertScalar(value.Position.X),
-			Y: convertScalar(value.Position.Y),
-		}
-	} else {
-		position = convertPos(value.Position.PositionProperty)
-	}
-
-	one := encoding.VectorProperty{
+	// Scale is a percentage.
+	oneHundredPercent := encoding.VectorProperty{
 		AnimatableProperty: encoding.AnimatableProperty[encoding.Vec2, encoding.SimpleKeyframe[encoding.Vec2]]{
-			Value: encoding.Vec2{1, 1},
+			Value: encoding.Vec2{100, 100},
 		},
 	}
 	transform := animation.KeyframedTransform{
-		Anchor:    convertPos(value.AnchorPoint),
-		Position:  position,
-		Scale:     convertVec2(value.Scale.UnwrapOr(one)),
-		Rotation:  convertScalar(value.Rotation),
-		Skew:      convertScalar(value.Skew),
-		SkewAngle: convertScalar(value.SkewAxis),
-	}
-	for i, v := range transform.Rotation.Values {
-		transform.Rotation.Values[i] = toRadians(v)
-	}
-	for i, v := range transform.Skew.Values {
-		v = mathutil.Clamp(-v, -85, 85)
-		transform.Skew.Values[i] = toRadians(v)
-	}
-	for i, v := range transform.SkewAngle.Values {
-		transform.SkewAngle.Values[i] = toRadians(v)
+		Anchor:   convertPos(sc, value.AnchorPoint),
+		Position: position,
+		Scale:    convertVec2(sc, value.Scale.UnwrapOr(oneHundredPercent)),
+		Rotation: sc.scalarFunc(value.Rotation, toRadians),
+		Skew: sc.scalarFunc(value.Skew, func(v float64) float64 {
+			return toRadians(mathutil.Clamp(-v, -85, 85))
+		}),
+		SkewAngle: sc.scalarFunc(value.SkewAxis, toRadians),
 	}
 
 	oneHundred := encoding.ScalarProperty{
@@ -332,7 +503,7 @@ func convertTransform(value *encoding.Transform) (animation.KeyframedTransform,
 			Value: 100,
 		},
 	}
-	opacity := convertScalar(value.Opacity.UnwrapOr(oneHundred))
+	opacity := sc.scalar(value.Opacity.UnwrapOr(oneHundred))
 
 	return transform, opacity
 }
@@ -346,7 +517,9 @@ func convertScalar(floatValue encoding.ScalarProperty) animation.Keyframes[float
 
 		for i, keyframe := range floatValue.Keyframes {
 			frames[i], easings[i] = convertKeyframe(keyframe.BaseKeyframe)
-			values[i] = keyframe.Value[0]
+			if len(keyframe.Value) > 0 {
+				values[i] = keyframe.Value[0]
+			}
 		}
 		return animation.Keyframes[float64]{
 			Frames: frames,
@@ -359,10 +532,14 @@ func convertScalar(floatValue encoding.ScalarProperty) animation.Keyframes[float
 }
 
 func convertKeyframeHandle(handle encoding.EasingHandle) curve.Point {
-	// XXX validate length
-	x := handle.X[0]
-	y := handle.Y[0]
-	return curve.Point{X: x, Y: y}
+	var pt curve.Point
+	if len(handle.X) > 0 {
+		pt.X = handle.X[0]
+	}
+	if len(handle.Y) > 0 {
+		pt.Y = handle.Y[0]
+	}
+	return pt
 }
 
 func convertMultiKeyframes[T ~[]float64 | ~[2]float64 | ~[3]float64](keyframes []encoding.SimpleKeyframe[T], numItems int) []animation.Keyframes[float64] {
@@ -410,7 +587,11 @@ func convertMultiKeyframes[T ~[]float64 | ~[2]float64 | ~[3]float64](keyframes [
 				} else {
 					easings[j] = append(easings[j], animation.CurveIdentity)
 				}
-				values[j] = append(values[j], keyframe.Value[j])
+				if j < len(keyframe.Value) {
+					values[j] = append(values[j], keyframe.Value[j])
+				} else {
+					values[j] = append(values[j], 0)
+				}
 			}
 		} else {
 			for j := range numItems {
@@ -444,60 +625,78 @@ func convertMultiKeyframes[T ~[]float64 | ~[2]float64 | ~[3]float64](keyframes [
 	return out
 }
 
-func convertPos(pos encoding.PositionProperty) animation.KeyframedPoint {
+func convertPos(sc scope, pos encoding.PositionProperty) animation.KeyframedPoint {
+	var p animation.KeyframedPoint
 	if pos.Animated {
 		// TODO: Are we using PositionKeyframes here how we're supposed to?
 		// there are in_tangents and out_tangents in addition to the keyframes.
 		// conv_keyframes(pos_keyframes.iter().map(|pk| &pk.keyframe), |k| f(&k.value))
 		xy := convertMultiKeyframes(pos.Keyframes, 2)
-		return animation.KeyframedPoint{
+		p = animation.KeyframedPoint{
 			X: xy[0],
 			Y: xy[1],
 		}
 	} else {
-		return animation.KeyframedPoint{
+		p = animation.KeyframedPoint{
 			X: fixedValue(pos.Value[0]),
 			Y: fixedValue(pos.Value[1]),
 		}
 	}
+	sc.expression(pos.Expression, nil, &p.X, &p.Y)
+	return p
 }
 
-func convert2D(v encoding.VectorProperty) []animation.Keyframes[float64] {
+func convertSplittablePos(sc scope, pos encoding.SplittablePositionProperty) animation.KeyframedPoint {
+	if pos.Split {
+		return animation.KeyframedPoint{
+			X: sc.scalar(pos.X),
+			Y: sc.scalar(pos.Y),
+		}
+	} else {
+		return convertPos(sc, pos.PositionProperty)
+	}
+}
+
+func convert2D(sc scope, v encoding.VectorProperty) []animation.Keyframes[float64] {
+	var kfs []animation.Keyframes[float64]
 	if v.Animated {
-		return convertMultiKeyframes(v.Keyframes, 2)
+		kfs = convertMultiKeyframes(v.Keyframes, 2)
 	} else {
-		return []animation.Keyframes[float64]{
+		kfs = []animation.Keyframes[float64]{
 			fixedValue(v.Value[0]),
 			fixedValue(v.Value[1]),
 		}
 	}
+	sc.expression(v.Expression, nil, &kfs[0], &kfs[1])
+	return kfs
 }
 
-func convertVec2(value encoding.VectorProperty) animation.KeyframedVec2 {
-	xy := convert2D(value)
+func convertVec2(sc scope, value encoding.VectorProperty) animation.KeyframedVec2 {
+	xy := convert2D(sc, value)
 	return animation.KeyframedVec2{
 		X: xy[0],
 		Y: xy[1],
 	}
 }
 
-func convertSize(value encoding.VectorProperty) animation.KeyframedSize {
-	wh := convert2D(value)
+func convertSize(sc scope, value encoding.VectorProperty) animation.KeyframedSize {
+	wh := convert2D(sc, value)
 	return animation.KeyframedSize{
 		Width:  wh[0],
 		Height: wh[1],
 	}
 }
 
-func convertPoint(value encoding.PositionProperty) animation.KeyframedPoint {
-	xy := convert2D(value)
+func convertPoint(sc scope, value encoding.PositionProperty) animation.KeyframedPoint {
+	xy := convert2D(sc, value)
 	return animation.KeyframedPoint{
 		X: xy[0],
 		Y: xy[1],
 	}
 }
 
-func convertShapeGeometry(value encoding.BezierProperty) (model.Geometry, bool) {
+func convertShapeGeometry(sc scope, value encoding.BezierProperty) (model.Geometry, bool) {
+	sc.unsupportedExpression(value.Expression, "path")
 	if value.Animated {
 		var isClosed bool
 		n := len(value.Keyframes)
@@ -560,13 +759,25 @@ func convertSpline(value encoding.Bezier) ([]curve.Point, bool) {
 	return points, isClosed
 }
 
-func convertShape(value encoding.AnyGraphicElement) (model.Shape, bool) {
-	if draw, ok := convertDraw(value); ok {
+// shapeNames are the names of the shapes that we don't support, for reporting
+// them.
+var shapeNames = map[string]string{
+	"mm": "merge paths",
+	"rd": "rounded corners",
+	"op": "offset path",
+	"pb": "pucker/bloat",
+	"tw": "twist",
+	"zz": "zig zag",
+	"no": "no style",
+}
+
+func convertShape(sc scope, value encoding.AnyGraphicElement) (model.Shape, bool) {
+	if draw, ok := convertDraw(sc, value); ok {
 		return model.Shape{
 			Kind: model.ShapeKindDraw,
 			Draw: draw,
 		}, true
-	} else if geometry, ok := convertGeometry(value); ok {
+	} else if geometry, ok := convertGeometry(sc, value); ok {
 		return model.Shape{
 			Kind:     model.ShapeKindGeometry,
 			Geometry: geometry,
@@ -577,12 +788,12 @@ func convertShape(value encoding.AnyGraphicElement) (model.Shape, bool) {
 	case encoding.Group:
 		var shapes []model.Shape
 		var groupTransform maybe.Option[model.GroupTransform]
-		for _, item := range value.Shapes {
+		for i, item := range value.Shapes {
 			switch item := item.(type) {
 			case encoding.TransformShape:
-				groupTransform = maybe.Some(convertShapeTransform(item))
+				groupTransform = maybe.Some(convertShapeTransform(sc.at("it", i), item))
 			default:
-				if shape, ok := convertShape(item); ok {
+				if shape, ok := convertShape(sc.at("it", i), item); ok {
 					shapes = append(shapes, shape)
 				}
 			}
@@ -596,50 +807,161 @@ func convertShape(value encoding.AnyGraphicElement) (model.Shape, bool) {
 		} else {
 			return model.Shape{}, false
 		}
+	case encoding.TrimPath:
+		if value.Hidden {
+			return model.Shape{}, false
+		}
+		return model.Shape{
+			Kind: model.ShapeKindTrim,
+			Trim: convertTrim(sc, value),
+		}, true
+	case encoding.Repeater:
+		if value.Hidden {
+			return model.Shape{}, false
+		}
+		return model.Shape{
+			Kind:     model.ShapeKindRepeater,
+			Repeater: convertRepeater(sc, value),
+		}, true
+	case encoding.UnknownShape:
+		name, ok := shapeNames[value.Type]
+		if !ok {
+			name = fmt.Sprintf("shape type %q", value.Type)
+		}
+		sc.unsupported(name)
+		return model.Shape{}, false
 	default:
-		// TODO: support repeater shape
 		return model.Shape{}, false
 	}
 }
 
-func convertGeometry(value encoding.AnyGraphicElement) (model.Geometry, bool) {
+func convertRepeater(sc scope, value encoding.Repeater) model.Repeater {
+	composite := model.RepeaterCompositeAbove
+	if value.Composite == encoding.RepeaterCompositeBelow {
+		composite = model.RepeaterCompositeBelow
+	}
+
+	tr := &value.Transform
+	position := convertSplittablePos(sc, tr.Position)
+	oneHundred := encoding.ScalarProperty{
+		AnimatableProperty: encoding.AnimatableProperty[float64, encoding.SimpleKeyframe[[]float64]]{
+			Value: 100,
+		},
+	}
+	oneHundredPercent := encoding.VectorProperty{
+		AnimatableProperty: encoding.AnimatableProperty[encoding.Vec2, encoding.SimpleKeyframe[encoding.Vec2]]{
+			Value: encoding.Vec2{100, 100},
+		},
+	}
+	// Unlike the transforms of layers and groups, the repeater's transform
+	// is applied per copy by [model.FixedRepeater.Transform], which expects
+	// Lottie's units: degrees and percentages.
+	return model.Repeater{
+		Composite:    composite,
+		Copies:       sc.scalar(value.Copies),
+		Offset:       sc.scalar(value.Offset.UnwrapOr(encoding.ScalarProperty{})),
+		AnchorPoint:  convertPos(sc, tr.AnchorPoint),
+		Position:     position,
+		Rotation:     sc.scalar(tr.Rotation),
+		Scale:        convertVec2(sc, tr.Scale.UnwrapOr(oneHundredPercent)),
+		StartOpacity: sc.scalar(tr.StartOpacity.UnwrapOr(oneHundred)),
+		EndOpacity:   sc.scalar(tr.EndOpacity.UnwrapOr(oneHundred)),
+	}
+}
+
+func convertTrim(sc scope, value encoding.TrimPath) model.Trim {
+	mode := model.TrimModeSimultaneous
+	if value.Multiple == encoding.TrimMultipleShapesSequential {
+		mode = model.TrimModeIndividual
+	}
+	// Start and end are percentages and the offset is in degrees.
+	percent := func(v float64) float64 { return v / 100 }
+	return model.Trim{
+		Mode:   mode,
+		Start:  sc.scalarFunc(value.Start, percent),
+		End:    sc.scalarFunc(value.End, percent),
+		Offset: sc.scalarFunc(value.Offset, func(v float64) float64 { return v / 360 }),
+	}
+}
+
+func convertDash(sc scope, dashes []encoding.StrokeDash) maybe.Option[model.Dash] {
+	if len(dashes) == 0 {
+		return maybe.Option[model.Dash]{}
+	}
+	dash := model.Dash{
+		Offset: fixedValue(0.0),
+	}
+	for _, d := range dashes {
+		length := maybe.Map(d.Length, sc.scalar).UnwrapOr(fixedValue(0.0))
+		switch d.DashType {
+		case encoding.StrokeDashTypeOffset:
+			dash.Offset = length
+		default:
+			// Dashes and gaps alternate, so we don't need to distinguish
+			// them.
+			dash.Pattern = append(dash.Pattern, length)
+		}
+	}
+	if len(dash.Pattern) == 0 {
+		return maybe.Option[model.Dash]{}
+	}
+	return maybe.Some(dash)
+}
+
+func convertGeometry(sc scope, value encoding.AnyGraphicElement) (model.Geometry, bool) {
 	switch value := value.(type) {
 	case encoding.Ellipse:
 		return model.Geometry{
 			Kind: model.GeometryKindEllipse,
 			Ellipse: animation.KeyframedEllipse{
-				Position: convertPos(value.Position),
-				Size:     convertSize(value.Size),
+				Position: convertPos(sc, value.Position),
+				Size:     convertSize(sc, value.Size),
 			},
 		}, true
 	case encoding.Rectangle:
 		return model.Geometry{
 			Kind: model.GeometryKindRect,
 			Rect: animation.KeyframedRoundedRect{
-				Position:     convertPos(value.Position),
-				Size:         convertSize(value.Size),
-				CornerRadius: convertScalar(value.Rounded),
+				Position:     convertPos(sc, value.Position),
+				Size:         convertSize(sc, value.Size),
+				CornerRadius: sc.scalar(value.Rounded),
 			},
 		}, true
 	case encoding.Path:
-		return convertShapeGeometry(value.Shape)
+		return convertShapeGeometry(sc, value.Shape)
 	case encoding.Polystar:
-		// TODO support shape
-		return model.Geometry{}, false
+		direction := 1.0
+		if value.Direction == encoding.ShapeDirectionReversed {
+			direction = -1
+		}
+		return model.Geometry{
+			Kind: model.GeometryKindStar,
+			Star: model.Star{
+				IsPolygon:      value.StarType == encoding.StarTypePolygon,
+				Direction:      direction,
+				Position:       convertPos(sc, value.Position),
+				InnerRadius:    sc.scalar(value.InnerRadius.UnwrapOr(encoding.ScalarProperty{})),
+				InnerRoundness: sc.scalar(value.InnerRoundness.UnwrapOr(encoding.ScalarProperty{})),
+				OuterRadius:    sc.scalar(value.OuterRadius),
+				OuterRoundness: sc.scalar(value.OuterRoundness),
+				Rotation:       sc.scalar(value.Rotation),
+				Points:         sc.scalar(value.Points),
+			},
+		}, true
 	default:
 		return model.Geometry{}, false
 	}
 }
 
-func convertShapeTransform(value encoding.TransformShape) model.GroupTransform {
-	transform, opacity := convertTransform(&value.Transform)
+func convertShapeTransform(sc scope, value encoding.TransformShape) model.GroupTransform {
+	transform, opacity := convertTransform(sc, &value.Transform)
 	return model.GroupTransform{
 		Transform: transform,
 		Opacity:   opacity,
 	}
 }
 
-func convertDraw(value encoding.AnyGraphicElement) (model.Draw, bool) {
+func convertDraw(sc scope, value encoding.AnyGraphicElement) (model.Draw, bool) {
 	if value, ok := value.(interface{ IsHidden() bool }); ok && value.IsHidden() {
 		return model.Draw{}, false
 	}
@@ -650,15 +972,16 @@ func convertDraw(value encoding.AnyGraphicElement) (model.Draw, bool) {
 	}
 	switch value := value.(type) {
 	case encoding.Fill:
-		color := convertColor(value.Color)
+		color := sc.color(value.Color)
 		brush := model.Brush{
 			Kind:  model.BrushKindSolid,
 			Solid: color,
 		}
-		opacity := convertScalar(value.Opacity.UnwrapOr(oneHundred))
+		opacity := sc.scalar(value.Opacity.UnwrapOr(oneHundred))
 		return model.Draw{
-			Brush:   brush,
-			Opacity: opacity,
+			Brush:    brush,
+			Opacity:  opacity,
+			FillRule: convertFillRule(value.FillRule),
 		}, true
 	case encoding.Stroke:
 		var join curve.Join
@@ -685,32 +1008,33 @@ func convertDraw(value encoding.AnyGraphicElement) (model.Draw, bool) {
 			cap = curve.ButtCap
 		}
 		stroke := animation.KeyframedStroke{
-			Width:      convertScalar(value.StrokeWidth),
+			Width:      sc.scalar(value.StrokeWidth),
 			Join:       join,
 			MiterLimit: maybe.Some(value.MiterLimit),
 			Cap:        cap,
 		}
-		color := convertColor(value.Color)
+		color := sc.color(value.Color)
 		brush := model.Brush{
 			Kind:  model.BrushKindSolid,
 			Solid: color,
 		}
-		opacity := convertScalar(value.Opacity.UnwrapOr(oneHundred))
+		opacity := sc.scalar(value.Opacity.UnwrapOr(oneHundred))
 		return model.Draw{
 			Stroke:  maybe.Some(stroke),
+			Dash:    convertDash(sc.at("d"), value.Dashes),
 			Brush:   brush,
 			Opacity: opacity,
 		}, true
 
 	case encoding.GradientFill:
 		isRadial := value.GradientType == encoding.GradientTypeRadial
-		startPoint := convertPoint(value.StartPoint)
-		endPoint := convertPoint(value.EndPoint)
+		startPoint := convertPoint(sc, value.StartPoint)
+		endPoint := convertPoint(sc, value.EndPoint)
 		gradient := animation.KeyframedGradient{
 			IsRadial:   isRadial,
 			StartPoint: startPoint,
 			EndPoint:   endPoint,
-			Stops:      convertGradientColors(value.Colors),
+			Stops:      convertGradientColors(sc, value.Colors),
 			ColorSpace: model.WorkingColorSpace,
 		}
 		brush := model.Brush{
@@ -718,8 +1042,9 @@ func convertDraw(value encoding.AnyGraphicElement) (model.Draw, bool) {
 			Gradient: gradient,
 		}
 		return model.Draw{
-			Brush:   brush,
-			Opacity: fixedValue(100.0),
+			Brush:    brush,
+			Opacity:  fixedValue(100.0),
+			FillRule: convertFillRule(value.FillRule),
 		}, true
 	case encoding.GradientStroke:
 		var join curve.Join
@@ -746,19 +1071,19 @@ func convertDraw(value encoding.AnyGraphicElement) (model.Draw, bool) {
 			cap = curve.RoundCap
 		}
 		stroke := animation.KeyframedStroke{
-			Width:      convertScalar(value.StrokeWidth),
+			Width:      sc.scalar(value.StrokeWidth),
 			Join:       join,
 			MiterLimit: maybe.Some(value.MiterLimit),
 			Cap:        cap,
 		}
 		isRadial := value.GradientType == encoding.GradientTypeRadial
-		startPoint := convertPoint(value.StartPoint)
-		endPoint := convertPoint(value.EndPoint)
+		startPoint := convertPoint(sc, value.StartPoint)
+		endPoint := convertPoint(sc, value.EndPoint)
 		gradient := animation.KeyframedGradient{
 			IsRadial:   isRadial,
 			StartPoint: startPoint,
 			EndPoint:   endPoint,
-			Stops:      convertGradientColors(value.Colors),
+			Stops:      convertGradientColors(sc, value.Colors),
 			ColorSpace: model.WorkingColorSpace,
 		}
 		brush := model.Brush{
@@ -767,6 +1092,7 @@ func convertDraw(value encoding.AnyGraphicElement) (model.Draw, bool) {
 		}
 		return model.Draw{
 			Stroke:  maybe.Some(stroke),
+			Dash:    convertDash(sc.at("d"), value.Dashes),
 			Brush:   brush,
 			Opacity: fixedValue(100.0),
 		}, true
@@ -775,7 +1101,15 @@ func convertDraw(value encoding.AnyGraphicElement) (model.Draw, bool) {
 	}
 }
 
-func convertGradientColors(value encoding.GradientProperty) animation.KeyframedColorStops {
+func convertFillRule(rule encoding.FillRule) gfx.FillRule {
+	if rule == encoding.FillRuleEvenOdd {
+		return gfx.EvenOdd
+	}
+	return gfx.NonZero
+}
+
+func convertGradientColors(sc scope, value encoding.GradientProperty) animation.KeyframedColorStops {
+	sc.unsupportedExpression(value.Value.Expression, "gradient colors")
 	count := value.NumColorStops
 	if value.Value.Animated {
 		n := len(value.Value.Keyframes)
@@ -889,8 +1223,8 @@ func convertColor(color encoding.ColorProperty) [3]animation.Keyframes[float64]
 	}
 }
 
-func setupShapeLayer(source encoding.ShapeLayer, target *model.Layer) (int, maybe.Option[gfx.BlendMode]) {
-	return setupLayerBase(source.VisualLayer, target)
+func setupShapeLayer(sc scope, source encoding.ShapeLayer, target *model.Layer) (int, maybe.Option[int]) {
+	return setupLayerBase(sc, source.VisualLayer, target)
 }
 
 func fixedValue[T any](v T) animation.Keyframes[T] {
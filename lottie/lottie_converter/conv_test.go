@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_converter
+
+import (
+	"testing"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/lottie/lottie_encoding"
+)
+
+func TestDefaultTransform(t *testing.T) {
+	// Neither the layer's nor the group's transform specify any of their
+	// properties.
+	const data = `{
+		"v": "5.8.1", "fr": 10, "ip": 0, "op": 10, "w": 100, "h": 100,
+		"layers": [
+			{"ty": 4, "ind": 1, "ip": 0, "op": 10, "st": 0, "sr": 1, "ks": {},
+				"shapes": [
+					{"ty": "gr", "it": [
+						{"ty": "rc", "p": {"a": 0, "k": [0, 0]}, "s": {"a": 0, "k": [10, 10]}, "r": {"a": 0, "k": 0}},
+						{"ty": "tr"}
+					]}
+				]}
+		]
+	}`
+	anim, err := lottie_encoding.Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	comp, err := ConvertAnimation(anim, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layer := comp.Layers[0]
+	if tr := layer.Transform.Evaluate(0); tr != curve.Identity {
+		t.Errorf("layer has transform %v, want identity", tr)
+	}
+	if o := layer.Opacity.Evaluate(0); o != 100 {
+		t.Errorf("layer has opacity %g, want 100", o)
+	}
+	group, ok := layer.Content.Shapes[0].GroupTransform.Get()
+	if !ok {
+		t.Fatal("group has no transform")
+	}
+	if tr := group.Transform.Evaluate(0); tr != curve.Identity {
+		t.Errorf("group has transform %v, want identity", tr)
+	}
+}
@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_converter
+
+import (
+	"hash/fnv"
+
+	"honnef.co/go/gutter/animation"
+	"honnef.co/go/gutter/lottie/lottie_expression"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+)
+
+// expression binds the expression src, if any, to the components of a
+// property. fn, which may be nil, converts the expression's results from the
+// units of Lottie files. It reports whether it bound the expression, which it
+// doesn't do for unsupported expressions.
+func (sc scope) expression(src string, fn func(float64) float64, components ...*animation.Keyframes[float64]) bool {
+	if src == "" {
+		return false
+	}
+	prog, err := lottie_expression.Parse(src)
+	if err != nil {
+		sc.report("expression", err)
+		return false
+	}
+	// Derive the seed from the property's position so that different
+	// properties wiggle differently, but the same way every time.
+	h := fnv.New64a()
+	h.Write([]byte(sc.path))
+	h.Write([]byte(src))
+	model.BindExpression(prog, sc.framerate, h.Sum64(), fn, components...)
+	return true
+}
+
+// unsupportedExpression reports the expression src, if any, of a property
+// that doesn't support expressions.
+func (sc scope) unsupportedExpression(src string, property string) {
+	if src != "" {
+		sc.unsupported("expression on " + property)
+	}
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_converter
+
+import (
+	"math"
+	"testing"
+
+	"honnef.co/go/gutter/lottie/lottie_encoding"
+)
+
+const expressionJSON = `{
+	"v": "5.8.1", "fr": 10, "ip": 0, "op": 30, "w": 100, "h": 100,
+	"layers": [
+		{"ty": 3, "ind": 1, "ip": 0, "op": 30, "st": 0, "sr": 1,
+			"ks": {
+				"r": {"a": 1, "k": [
+					{"t": 0, "s": [0], "o": {"x": [0], "y": [0]}, "i": {"x": [1], "y": [1]}},
+					{"t": 10, "s": [90]}
+				], "x": "var $bm_rt;\n$bm_rt = loopOut('cycle');"},
+				"p": {"a": 0, "k": [50, 50], "x": "wiggle(2, 5)"},
+				"o": {"a": 0, "k": 100, "x": "thisComp.layer('Control').transform.opacity"}
+			}}
+	]
+}`
+
+func TestExpressions(t *testing.T) {
+	anim, err := lottie_encoding.Parse([]byte(expressionJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reports []Unsupported
+	comp, err := ConvertAnimation(anim, Options{
+		Report: func(u Unsupported) { reports = append(reports, u) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Feature != "expression" || reports[0].Err == nil {
+		t.Errorf("got reports %v, want one unsupported expression", reports)
+	}
+
+	layer := &comp.Layers[0]
+	tr := &layer.Transform
+	// The rotation repeats after frame 10 and gets converted to radians.
+	if got, want := tr.Rotation.Evaluate(15), math.Pi/4; math.Abs(got-want) > 1e-9 {
+		t.Errorf("got rotation %v at frame 15, want %v", got, want)
+	}
+	if tr.IsStatic() {
+		t.Error("transform with expressions is static")
+	}
+	moved := false
+	for frame := 0.0; frame < 30; frame++ {
+		pos := tr.Position.Evaluate(frame)
+		if math.Abs(pos.X-50) > 5 || math.Abs(pos.Y-50) > 5 {
+			t.Errorf("frame %v: position %v exceeds wiggle amplitude", frame, pos)
+		}
+		if pos.X != 50 {
+			moved = true
+		}
+	}
+	if !moved {
+		t.Error("position didn't wiggle")
+	}
+	// The unsupported expression leaves the property at its value.
+	if got := layer.Opacity.Evaluate(15); got != 100 {
+		t.Errorf("got opacity %v, want 100", got)
+	}
+}
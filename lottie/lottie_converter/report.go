@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_converter
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Unsupported describes a part of an animation that the converter ignored or
+// approximated.
+type Unsupported struct {
+	// Path is a JSON pointer to the part of the animation, such as
+	// "/layers/3/shapes/0/it/2".
+	Path string
+	// Feature is a short description of the feature, such as "merge paths"
+	// or "blend mode hue".
+	Feature string
+	// Err is the error that caused the part to be ignored, for parts that
+	// failed to load, such as images. It is nil for features that aren't
+	// implemented.
+	Err error
+}
+
+func (u Unsupported) String() string {
+	if u.Err != nil {
+		return fmt.Sprintf("%s: %s: %s", u.Path, u.Feature, u.Err)
+	}
+	return fmt.Sprintf("%s: %s", u.Path, u.Feature)
+}
+
+// scope is a position in the animation's JSON. It reports unsupported
+// features at that position and binds the properties converted there to
+// their slots and expressions.
+type scope struct {
+	fn        func(Unsupported)
+	path      string
+	slots     *slots
+	framerate float64
+}
+
+// at returns a scope for a child of the current position. Elements are
+// object keys or array indices.
+func (sc scope) at(elems ...any) scope {
+	for _, el := range elems {
+		switch el := el.(type) {
+		case string:
+			sc.path += "/" + el
+		case int:
+			sc.path += "/" + strconv.Itoa(el)
+		default:
+			panic(fmt.Sprintf("internal error: unexpected path element %T", el))
+		}
+	}
+	return sc
+}
+
+func (sc scope) unsupported(feature string) {
+	sc.report(feature, nil)
+}
+
+func (sc scope) report(feature string, err error) {
+	if sc.fn == nil {
+		return
+	}
+	sc.fn(Unsupported{Path: sc.path, Feature: feature, Err: err})
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_converter
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"honnef.co/go/gutter/lottie/lottie_encoding"
+)
+
+const unsupportedJSON = `{
+	"v": "5.8.1", "fr": 10, "ip": 0, "op": 10, "w": 100, "h": 100,
+	"assets": [
+		{"id": "broken", "w": 10, "h": 10, "u": "", "p": "data:image/png;base64,AAAA", "e": 1}
+	],
+	"layers": [
+		{"ty": 4, "ind": 1, "ip": 0, "op": 10, "st": 0, "sr": 1, "bm": 12, "ks": {},
+			"ef": [{"ty": 29, "nm": "Gaussian Blur", "en": 1}, {"ty": 29, "nm": "Disabled", "en": 0}],
+			"shapes": [
+				{"ty": "gr", "it": [
+					{"ty": "rc", "p": {"a": 0, "k": [0, 0]}, "s": {"a": 0, "k": [10, 10]}, "r": {"a": 0, "k": 0}},
+					{"ty": "mm", "mm": 1},
+					{"ty": "fl", "c": {"a": 0, "k": [1, 0, 0]}, "o": {"a": 0, "k": 100}}
+				]}
+			]},
+		{"ty": 13, "ind": 2, "ip": 0, "op": 10},
+		{"ty": 6, "ind": 3, "ip": 0, "op": 10},
+		{"ty": 2, "ind": 4, "ip": 0, "op": 10, "st": 0, "sr": 1, "refId": "broken", "ks": {}, "bm": 16}
+	]
+}`
+
+func TestReportUnsupported(t *testing.T) {
+	anim, err := lottie_encoding.Parse([]byte(unsupportedJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Unsupported
+	comp, err := ConvertAnimation(anim, Options{
+		Report: func(u Unsupported) { got = append(got, u) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Unsupported{
+		{Path: "/assets/0", Feature: "image asset"},
+		{Path: "/layers/0/ef/0", Feature: `effect "Gaussian Blur"`},
+		{Path: "/layers/0/bm", Feature: "blend mode hue"},
+		{Path: "/layers/0/shapes/0/it/1", Feature: "merge paths"},
+		{Path: "/layers/1", Feature: "camera layer"},
+	}
+	eq := func(a, b Unsupported) bool {
+		return a.Path == b.Path && a.Feature == b.Feature
+	}
+	if !slices.EqualFunc(got, want, eq) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if len(got) > 0 && got[0].Err == nil {
+		t.Error("broken image was reported without an error")
+	}
+
+	// Everything else still gets converted. The add blend mode is supported.
+	if len(comp.Layers) != 2 {
+		t.Fatalf("got %d layers, want 2", len(comp.Layers))
+	}
+	if len(comp.Layers[0].Content.Shapes) != 1 || len(comp.Layers[0].Content.Shapes[0].GroupShapes) != 2 {
+		t.Errorf("group wasn't converted without the merge paths")
+	}
+	if !comp.Layers[1].BlendMode.Set() {
+		t.Error("add blend mode wasn't converted")
+	}
+}
+
+func TestBrokenImageError(t *testing.T) {
+	anim, err := lottie_encoding.Parse([]byte(unsupportedJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without a Report function, images that fail to load are errors, but
+	// unsupported features aren't.
+	if _, err := ConvertAnimation(anim, Options{}); err == nil {
+		t.Fatal("broken image didn't cause an error")
+	} else if !strings.HasPrefix(err.Error(), "/assets/0: image asset: ") {
+		t.Errorf("got error %q, want it to describe the image asset", err)
+	}
+
+	// Images that aren't embedded don't load without a resolver, which isn't
+	// an error.
+	anim.Assets = []lottie_encoding.AnyAsset{lottie_encoding.Image{FileName: "img.png"}}
+	if _, err := ConvertAnimation(anim, Options{}); err != nil {
+		t.Errorf("unresolved image caused error %q", err)
+	}
+	if _, err := ConvertAnimation(anim, Options{ResolveImage: FSImageResolver(fstest.MapFS{})}); err == nil {
+		t.Error("missing image file didn't cause an error")
+	}
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_converter
+
+import (
+	"fmt"
+
+	"honnef.co/go/gutter/animation"
+	encoding "honnef.co/go/gutter/lottie/lottie_encoding"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+
+	"github.com/go-json-experiment/json"
+)
+
+// slots tracks the slots of the animation being converted.
+type slots struct {
+	// The values that the animation assigns to slots, which replace the
+	// values of the properties using them.
+	source map[string]encoding.Slot
+	target map[string]*model.Slot
+}
+
+// slot returns the slot with the given ID, creating it if necessary. It
+// returns nil if properties of different kinds use the slot.
+func (sc scope) slot(id string, kind model.SlotKind) *model.Slot {
+	s, ok := sc.slots.target[id]
+	if !ok {
+		s = &model.Slot{Kind: kind}
+		sc.slots.target[id] = s
+	}
+	if s.Kind != kind {
+		sc.unsupported(fmt.Sprintf("slot %q shared by different kinds of properties", id))
+		return nil
+	}
+	return s
+}
+
+// resolveSlot returns the value that the animation assigns to the slot with
+// the given ID, or prop if the property doesn't use a slot.
+func resolveSlot[T any](sc scope, id string, prop T) T {
+	if id == "" {
+		return prop
+	}
+	slot, ok := sc.slots.source[id]
+	if !ok || len(slot.Property) == 0 {
+		return prop
+	}
+	var v T
+	if err := json.Unmarshal(slot.Property, &v); err != nil {
+		sc.report(fmt.Sprintf("slot %q", id), err)
+		return prop
+	}
+	return v
+}
+
+func (sc scope) scalar(prop encoding.ScalarProperty) animation.Keyframes[float64] {
+	return sc.scalarFunc(prop, nil)
+}
+
+// scalarFunc converts a scalar property, applying fn, which may be nil, to its
+// values.
+func (sc scope) scalarFunc(prop encoding.ScalarProperty, fn func(float64) float64) animation.Keyframes[float64] {
+	id := prop.SlotID
+	prop = resolveSlot(sc, id, prop)
+	kfs := convertScalar(prop)
+	if sc.expression(prop.Expression, fn, &kfs) {
+		// The expression applies fn to its results, which means that the
+		// keyframes have to keep the units of Lottie files.
+		fn = nil
+	}
+	if fn != nil {
+		for i, v := range kfs.Values {
+			kfs.Values[i] = fn(v)
+		}
+	}
+	if id != "" {
+		if slot := sc.slot(id, model.SlotKindScalar); slot != nil {
+			slot.BindScalar(kfs.Values, fn)
+		}
+	}
+	return kfs
+}
+
+func (sc scope) color(prop encoding.ColorProperty) [3]animation.Keyframes[float64] {
+	id := prop.SlotID
+	prop = resolveSlot(sc, id, prop)
+	kfs := convertColor(prop)
+	sc.expression(prop.Expression, nil, &kfs[0], &kfs[1], &kfs[2])
+	if id != "" {
+		if slot := sc.slot(id, model.SlotKindColor); slot != nil {
+			slot.BindColor([3][]float64{kfs[0].Values, kfs[1].Values, kfs[2].Values})
+		}
+	}
+	return kfs
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_converter
+
+import (
+	"testing"
+
+	"honnef.co/go/color"
+	"honnef.co/go/gutter/lottie/lottie_encoding"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+)
+
+const slotsJSON = `{
+	"v": "5.8.1", "fr": 10, "ip": 0, "op": 30, "w": 100, "h": 100,
+	"markers": [
+		{"cm": "intro", "tm": 0, "dr": 10},
+		{"cm": "loop", "tm": 10, "dr": 20}
+	],
+	"slots": {
+		"background": {"p": {"a": 0, "k": [0, 0, 1]}}
+	},
+	"layers": [
+		{"ty": 4, "ind": 1, "ip": 0, "op": 30, "st": 0, "sr": 1, "ks": {},
+			"shapes": [
+				{"ty": "rc", "p": {"a": 0, "k": [0, 0]}, "s": {"a": 0, "k": [10, 10]}, "r": {"a": 0, "k": 0}},
+				{"ty": "tm", "s": {"a": 0, "k": 0}, "e": {"a": 0, "k": 50, "sid": "progress"}, "o": {"a": 0, "k": 0}, "m": 1},
+				{"ty": "fl", "c": {"a": 0, "k": [1, 0, 0], "sid": "background"}, "o": {"a": 0, "k": 100}}
+			]}
+	]
+}`
+
+func TestMarkersAndSlots(t *testing.T) {
+	anim, err := lottie_encoding.Parse([]byte(slotsJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reports []Unsupported
+	comp, err := ConvertAnimation(anim, Options{
+		Report: func(u Unsupported) { reports = append(reports, u) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) > 0 {
+		t.Errorf("unexpected reports: %v", reports)
+	}
+
+	if m, ok := comp.Marker("loop"); !ok || m.Frame != 10 || m.End() != 30 {
+		t.Errorf("got marker %v, %t, want loop from 10 to 30", m, ok)
+	}
+	if _, ok := comp.Marker("outro"); ok {
+		t.Error("found nonexistent marker")
+	}
+
+	shapes := comp.Layers[0].Content.Shapes
+	end := shapes[1].Trim.End.Values
+	fill := shapes[2].Draw.Brush.Solid
+	rgb := func() [3]float64 {
+		return [3]float64{fill[0].Values[0], fill[1].Values[0], fill[2].Values[0]}
+	}
+	// The animation's slot value replaces the property's value.
+	if got, want := rgb(), [3]float64{0, 0, 1}; got != want {
+		t.Errorf("got color %v, want %v", got, want)
+	}
+
+	bg, progress := comp.Slots["background"], comp.Slots["progress"]
+	if bg == nil || bg.Kind != model.SlotKindColor {
+		t.Fatalf("got background slot %v, want color slot", bg)
+	}
+	if progress == nil || progress.Kind != model.SlotKindScalar {
+		t.Fatalf("got progress slot %v, want scalar slot", progress)
+	}
+
+	bg.Set(model.ColorValue(color.Make(color.SRGB, 0, 1, 0, 1)))
+	// Values of the wrong kind are ignored.
+	bg.Set(model.ScalarValue(1))
+	progress.Set(model.ScalarValue(75))
+	if got, want := rgb(), [3]float64{0, 1, 0}; got != want {
+		t.Errorf("got color %v after setting slot, want %v", got, want)
+	}
+	// Slot values use Lottie's units, which the converter turns into
+	// fractions for trims.
+	if end[0] != 0.75 {
+		t.Errorf("got trim end %v after setting slot, want 0.75", end[0])
+	}
+	if v, ok := progress.Value(); !ok || v != model.ScalarValue(75) {
+		t.Errorf("got slot value %v, %t, want 75", v, ok)
+	}
+
+	bg.Reset()
+	progress.Reset()
+	if got, want := rgb(), [3]float64{0, 0, 1}; got != want {
+		t.Errorf("got color %v after resetting slot, want %v", got, want)
+	}
+	if end[0] != 0.5 {
+		t.Errorf("got trim end %v after resetting slot, want 0.5", end[0])
+	}
+	if _, ok := progress.Value(); ok {
+		t.Error("reset slot still has a value")
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_converter
+
+import (
+	"strings"
+
+	"honnef.co/go/color"
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/animation"
+	encoding "honnef.co/go/gutter/lottie/lottie_encoding"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+	"honnef.co/go/stuff/container/maybe"
+)
+
+func convertGlyph(sc scope, source encoding.CharacterData) model.Glyph {
+	glyph := model.Glyph{Width: source.Width}
+	for i, shape := range source.Data.Shapes {
+		if shape, ok := convertShape(sc.at("data", "shapes", i), shape); ok {
+			glyph.Shapes = append(glyph.Shapes, shape)
+		}
+	}
+	return glyph
+}
+
+func convertText(sc scope, source encoding.TextData) model.Text {
+	var text model.Text
+	id := source.Document.SlotID
+	doc := resolveSlot(sc, id, source.Document)
+	for _, kf := range doc.Keyframes {
+		text.Documents = append(text.Documents, model.TextDocumentKeyframe{
+			Frame:    kf.Time,
+			Document: convertTextDocument(kf.Start),
+		})
+	}
+	if id != "" {
+		if slot := sc.slot(id, model.SlotKindText); slot != nil {
+			slot.BindText(text.Documents)
+		}
+	}
+	for i, r := range source.Ranges {
+		text.Ranges = append(text.Ranges, convertTextRange(sc.at("a", i), r))
+	}
+	return text
+}
+
+// lineSeparators normalizes the different line separators used by
+// exporters to line feeds.
+var lineSeparators = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\u0003", "\n")
+
+func convertTextDocument(source encoding.TextDocument) model.TextDocument {
+	toColor := func(c encoding.Color) color.Color {
+		return color.Make(model.ParsedColorSpace, c[0], c[1], c[2], 1)
+	}
+	doc := model.TextDocument{
+		Font:           source.FontFamily,
+		Size:           source.Size,
+		Text:           lineSeparators.Replace(source.Text),
+		Fill:           maybe.Map(source.FillColor, toColor),
+		Stroke:         maybe.Map(source.StrokeColor, toColor),
+		StrokeWidth:    source.StrokeWidth,
+		StrokeOverFill: source.StrokeOverFill,
+		// After Effects defaults to a line height of 120% of the font size.
+		LineHeight:    source.LineHeight.UnwrapOr(source.Size * 1.2),
+		Tracking:      source.Tracking,
+		BaselineShift: source.BaselineShift,
+	}
+	if size, ok := source.BoxSize.Get(); ok {
+		pos := source.BoxPosition.UnwrapOr(encoding.Vec2{})
+		doc.Box = maybe.Some(curve.NewRectFromOrigin(curve.Pt(pos[0], pos[1]), curve.Sz(size[0], size[1])))
+	}
+	switch source.Justify {
+	case encoding.TextJustifyRight:
+		doc.Justify = model.TextJustifyRight
+	case encoding.TextJustifyCenter:
+		doc.Justify = model.TextJustifyCenter
+	case encoding.TextJustifyLastLineLeft:
+		doc.Justify = model.TextJustifyLastLineLeft
+	case encoding.TextJustifyLastLineRight:
+		doc.Justify = model.TextJustifyLastLineRight
+	case encoding.TextJustifyLastLineCenter:
+		doc.Justify = model.TextJustifyLastLineCenter
+	case encoding.TextJustifyLastLineFull:
+		doc.Justify = model.TextJustifyLastLineFull
+	default:
+		doc.Justify = model.TextJustifyLeft
+	}
+	switch source.Caps {
+	case encoding.TextCapsAllCaps:
+		doc.Caps = model.TextCapsAllCaps
+	case encoding.TextCapsSmallCaps:
+		doc.Caps = model.TextCapsSmallCaps
+	default:
+		doc.Caps = model.TextCapsRegular
+	}
+	return doc
+}
+
+func convertTextRange(sc scope, source encoding.TextRange) model.TextRange {
+	// A range without a selector affects all of the text.
+	sel := source.Selector.UnwrapOr(encoding.TextRangeSelector{})
+	scalar := func(prop maybe.Option[encoding.ScalarProperty], def float64) animation.Keyframes[float64] {
+		if prop, ok := prop.Get(); ok {
+			return sc.scalar(prop)
+		}
+		return fixedValue(def)
+	}
+	r := model.TextRange{
+		Indices:   sel.Units == encoding.TextRangeUnitsIndex,
+		Randomize: bool(sel.Randomize),
+		Start:     scalar(sel.Start, 0),
+		End:       scalar(sel.End, 100),
+		Offset:    scalar(sel.Offset, 0),
+		Amount:    scalar(sel.Amount, 100),
+		MinEase:   scalar(sel.MinEase, 0),
+		MaxEase:   scalar(sel.MaxEase, 0),
+	}
+	if r.Indices && !sel.End.Set() {
+		// There is no sensible default in units, but files always specify
+		// the end when using indices.
+		r.End = fixedValue(0.0)
+	}
+	switch sel.BasedOn {
+	case encoding.TextBasedCharactersExcludingSpaces:
+		r.Basis = model.TextBasisCharactersExcludingSpaces
+	case encoding.TextBasedWords:
+		r.Basis = model.TextBasisWords
+	case encoding.TextBasedLines:
+		r.Basis = model.TextBasisLines
+	default:
+		r.Basis = model.TextBasisCharacters
+	}
+	switch sel.Shape {
+	case encoding.TextShapeRampUp:
+		r.Shape = model.TextRangeShapeRampUp
+	case encoding.TextShapeRampDown:
+		r.Shape = model.TextRangeShapeRampDown
+	case encoding.TextShapeTriangle:
+		r.Shape = model.TextRangeShapeTriangle
+	case encoding.TextShapeRound:
+		r.Shape = model.TextRangeShapeRound
+	case encoding.TextShapeSmooth:
+		r.Shape = model.TextRangeShapeSmooth
+	default:
+		r.Shape = model.TextRangeShapeSquare
+	}
+
+	style := &source.Style
+	r.Style = model.TextRangeStyle{
+		Anchor:        convertPos(sc, style.AnchorPoint),
+		Position:      convertSplittablePos(sc, style.Position),
+		Scale:         maybe.Map(style.Scale, func(v encoding.VectorProperty) animation.KeyframedVec2 { return convertVec2(sc, v) }),
+		Rotation:      sc.scalar(style.Rotation),
+		Opacity:       maybe.Map(style.Opacity, sc.scalar),
+		FillColor:     maybe.Map(style.FillColor, sc.color),
+		StrokeColor:   maybe.Map(style.StrokeColor, sc.color),
+		StrokeWidth:   maybe.Map(style.StrokeWidth, sc.scalar),
+		FillOpacity:   maybe.Map(style.FillOpacity, sc.scalar),
+		StrokeOpacity: maybe.Map(style.StrokeOpacity, sc.scalar),
+		Tracking:      maybe.Map(style.Tracking, sc.scalar),
+	}
+	return r
+}
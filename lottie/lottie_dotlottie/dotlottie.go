@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package lottie_dotlottie reads dotLottie files, which are ZIP archives that
+// bundle one or more Lottie animations with their images and themes.
+//
+// Both versions of the format are supported. Version 1 stores animations in
+// "animations/" and images in "images/", version 2 uses "a/", "i/", and
+// stores themes in "t/".
+package lottie_dotlottie
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"path"
+
+	"honnef.co/go/gutter/lottie/lottie_converter"
+	"honnef.co/go/gutter/lottie/lottie_encoding"
+
+	"github.com/go-json-experiment/json"
+)
+
+// Archive is a dotLottie file.
+type Archive struct {
+	Manifest Manifest
+
+	zr *zip.Reader
+}
+
+type Manifest struct {
+	Version    string              `json:"version"`
+	Generator  string              `json:"generator"`
+	Animations []ManifestAnimation `json:"animations"`
+	Themes     []ManifestTheme     `json:"themes"`
+}
+
+type ManifestAnimation struct {
+	ID string `json:"id"`
+	// The ID of the theme to apply by default, if any.
+	InitialTheme string `json:"initialTheme"`
+	// The IDs of the themes that apply to the animation.
+	Themes []string `json:"themes"`
+}
+
+type ManifestTheme struct {
+	ID string `json:"id"`
+}
+
+// The directories that hold the different kinds of files, for each version
+// of the format.
+var (
+	animationDirs = []string{"a", "animations"}
+	imageDirs     = []string{"i", "images"}
+	themeDirs     = []string{"t", "themes"}
+)
+
+// Parse parses a dotLottie file and its manifest. Animations and themes are
+// parsed on demand.
+func Parse(b []byte) (*Archive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+	a := &Archive{zr: zr}
+	data, err := fs.ReadFile(zr, "manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &a.Manifest); err != nil {
+		return nil, fmt.Errorf("manifest.json: %w", err)
+	}
+	return a, nil
+}
+
+// readFile reads the JSON file with the given ID from the first of dirs that
+// contains it.
+func (a *Archive) readFile(dirs []string, id string) ([]byte, string, error) {
+	for _, dir := range dirs {
+		name := path.Join(dir, id+".json")
+		data, err := fs.ReadFile(a.zr, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return data, name, err
+	}
+	return nil, "", fmt.Errorf("%q: %w", id, fs.ErrNotExist)
+}
+
+// Animation parses the animation with the given ID. Use [Archive.ResolveImage]
+// to load its images when converting it with
+// [lottie_converter.ConvertAnimation].
+func (a *Archive) Animation(id string) (*lottie_encoding.Animation, error) {
+	data, name, err := a.readFile(animationDirs, id)
+	if err != nil {
+		return nil, err
+	}
+	anim, err := lottie_encoding.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return anim, nil
+}
+
+// ResolveImage loads images from the archive. It implements
+// [lottie_converter.Options.ResolveImage].
+func (a *Archive) ResolveImage(dir, name string) (image.Image, error) {
+	resolve := lottie_converter.FSImageResolver(a.zr)
+	img, err := resolve(dir, name)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return img, err
+	}
+	// Exporters don't agree on the paths they store in image assets, so
+	// we also look in the archive's image directories.
+	for _, dir := range imageDirs {
+		img, err := resolve(dir, name)
+		if !errors.Is(err, fs.ErrNotExist) {
+			return img, err
+		}
+	}
+	return nil, err
+}
+
+// Theme parses the theme with the given ID.
+func (a *Archive) Theme(id string) (*Theme, error) {
+	data, name, err := a.readFile(themeDirs, id)
+	if err != nil {
+		return nil, err
+	}
+	var theme Theme
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &theme, nil
+}
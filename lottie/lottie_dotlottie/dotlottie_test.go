@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_dotlottie
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+
+	"honnef.co/go/gutter/lottie/lottie_converter"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+)
+
+func parse(t *testing.T, name string) *Archive {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func convert(t *testing.T, a *Archive, id string) *model.Composition {
+	t.Helper()
+	anim, err := a.Animation(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	comp, err := lottie_converter.ConvertAnimation(anim, lottie_converter.Options{
+		ResolveImage: a.ResolveImage,
+		Report:       func(u lottie_converter.Unsupported) { t.Errorf("%s: unexpected report: %s", id, u) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img := comp.Images["dot"].Image; img == nil {
+		t.Errorf("%s: image wasn't loaded from the archive", id)
+	}
+	return comp
+}
+
+func TestVersion1(t *testing.T) {
+	a := parse(t, "testdata/v1.lottie")
+	if len(a.Manifest.Animations) != 1 || a.Manifest.Animations[0].ID != "spinner" {
+		t.Fatalf("got animations %v, want spinner", a.Manifest.Animations)
+	}
+	convert(t, a, "spinner")
+}
+
+func TestVersion2(t *testing.T) {
+	a := parse(t, "testdata/v2.lottie")
+	var ids []string
+	for _, anim := range a.Manifest.Animations {
+		ids = append(ids, anim.ID)
+	}
+	if len(ids) != 2 || ids[0] != "spinner" || ids[1] != "badge" {
+		t.Fatalf("got animations %v, want [spinner badge]", ids)
+	}
+	if _, err := a.Animation("missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v for missing animation, want ErrNotExist", err)
+	}
+
+	theme, err := a.Theme(a.Manifest.Animations[0].InitialTheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids {
+		comp := convert(t, a, id)
+		if err := theme.Apply(comp, id); err != nil {
+			t.Fatal(err)
+		}
+		fill := comp.Layers[1].Content.Shapes[1].Draw
+		rgb := [3]float64{
+			fill.Brush.Solid[0].Values[0],
+			fill.Brush.Solid[1].Values[0],
+			fill.Brush.Solid[2].Values[0],
+		}
+		if want := [3]float64{0.1, 0.2, 0.3}; rgb != want {
+			t.Errorf("%s: got color %v, want %v", id, rgb, want)
+		}
+		// The opacity rule only applies to the badge.
+		want := 100.0
+		if id == "badge" {
+			want = 50
+		}
+		if got := fill.Opacity.Values[0]; got != want {
+			t.Errorf("%s: got opacity %v, want %v", id, got, want)
+		}
+	}
+}
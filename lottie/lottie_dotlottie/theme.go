@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_dotlottie
+
+import (
+	"fmt"
+	"slices"
+
+	"honnef.co/go/color"
+	"honnef.co/go/gutter/lottie/lottie_encoding"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// Theme overrides the values of slots, for example to adapt an animation to
+// a dark color scheme.
+type Theme struct {
+	Rules []ThemeRule `json:"rules"`
+}
+
+type RuleType string
+
+const (
+	RuleTypeColor    RuleType = "Color"
+	RuleTypeScalar   RuleType = "Scalar"
+	RuleTypePosition RuleType = "Position"
+	RuleTypeVector   RuleType = "Vector"
+	RuleTypeGradient RuleType = "Gradient"
+	RuleTypeImage    RuleType = "Image"
+	RuleTypeText     RuleType = "Text"
+)
+
+// ThemeRule assigns a value to a slot.
+type ThemeRule struct {
+	// The ID of the slot.
+	ID   string   `json:"id"`
+	Type RuleType `json:"type"`
+	// The IDs of the animations that the rule applies to. The rule applies to
+	// all animations if this is empty.
+	Animations []string       `json:"animations"`
+	Value      jsontext.Value `json:"value"`
+	Keyframes  jsontext.Value `json:"keyframes"`
+}
+
+// Slots returns the values that the theme assigns to the slots of an
+// animation, which can be used with [model.Slot.Set] or the Slots field of
+// the Lottie widget.
+//
+// Only colors, scalars, and the text of text documents are supported. Other
+// rules, as well as rules that animate their values, are ignored.
+func (t *Theme) Slots(animation string) (map[string]model.SlotValue, error) {
+	out := make(map[string]model.SlotValue)
+	for _, rule := range t.Rules {
+		if len(rule.Animations) > 0 && !slices.Contains(rule.Animations, animation) {
+			continue
+		}
+		if len(rule.Value) == 0 {
+			// XXX support animated rules, once slots can be animated.
+			continue
+		}
+		var v model.SlotValue
+		switch rule.Type {
+		case RuleTypeColor:
+			var c []float64
+			if err := json.Unmarshal(rule.Value, &c); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", rule.ID, err)
+			}
+			if len(c) < 3 {
+				return nil, fmt.Errorf("rule %q: color has %d components, want 3 or 4", rule.ID, len(c))
+			}
+			alpha := 1.0
+			if len(c) > 3 {
+				alpha = c[3]
+			}
+			v = model.ColorValue(color.Make(model.ParsedColorSpace, c[0], c[1], c[2], alpha))
+		case RuleTypeScalar:
+			var x float64
+			if err := json.Unmarshal(rule.Value, &x); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", rule.ID, err)
+			}
+			v = model.ScalarValue(x)
+		case RuleTypeText:
+			var doc lottie_encoding.TextDocument
+			if err := json.Unmarshal(rule.Value, &doc); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", rule.ID, err)
+			}
+			v = model.TextValue(doc.Text)
+		default:
+			continue
+		}
+		out[rule.ID] = v
+	}
+	return out, nil
+}
+
+// Apply sets the slots of a composition to the values the theme assigns to
+// them for an animation. Use [model.Slot.Reset] to undo this.
+func (t *Theme) Apply(comp *model.Composition, animation string) error {
+	values, err := t.Slots(animation)
+	if err != nil {
+		return err
+	}
+	for id, v := range values {
+		if slot, ok := comp.Slots[id]; ok {
+			slot.Set(v)
+		}
+	}
+	return nil
+}
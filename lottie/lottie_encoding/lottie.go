@@ -5,8 +5,12 @@
 package lottie_encoding
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"net/url"
 	"reflect"
+	"strings"
 
 	"honnef.co/go/stuff/container/maybe"
 
@@ -14,7 +18,9 @@ import (
 	"github.com/go-json-experiment/json/jsontext"
 )
 
-func decodeTyped(d *jsontext.Decoder, a *any, o json.Options, m map[any]reflect.Type) error {
+// decodeTyped decodes a value whose Go type depends on its "ty" field. Values
+// of unknown types are replaced by the result of unknown.
+func decodeTyped(d *jsontext.Decoder, a *any, o json.Options, m map[any]reflect.Type, unknown func(typ any) any) error {
 	v, err := d.ReadValue()
 	if err != nil {
 		return err
@@ -27,7 +33,7 @@ func decodeTyped(d *jsontext.Decoder, a *any, o json.Options, m map[any]reflect.
 	}
 	t, ok := m[typ.Type]
 	if !ok {
-		*a = nil
+		*a = unknown(typ.Type)
 		return nil
 	}
 	reflect.ValueOf(a).Elem().Set(reflect.New(t).Elem())
@@ -42,6 +48,7 @@ var layerTypes = map[any]reflect.Type{
 	float64(2): reflect.TypeFor[ImageLayer](),
 	float64(3): reflect.TypeFor[NullLayer](),
 	float64(4): reflect.TypeFor[ShapeLayer](),
+	float64(5): reflect.TypeFor[TextLayer](),
 }
 
 var graphicElementTypes = map[any]reflect.Type{
@@ -53,17 +60,24 @@ var graphicElementTypes = map[any]reflect.Type{
 	"sh": reflect.TypeFor[Path](),
 	"sr": reflect.TypeFor[Polystar](),
 	"rc": reflect.TypeFor[Rectangle](),
+	"rp": reflect.TypeFor[Repeater](),
 	"st": reflect.TypeFor[Stroke](),
 	"tr": reflect.TypeFor[TransformShape](),
 	"tm": reflect.TypeFor[TrimPath](),
 }
 
 var unmarshalLayer = json.UnmarshalFuncV2(func(d *jsontext.Decoder, a *AnyLayer, o json.Options) error {
-	return decodeTyped(d, (*any)(a), o, layerTypes)
+	return decodeTyped(d, (*any)(a), o, layerTypes, func(typ any) any {
+		n, _ := typ.(float64)
+		return UnknownLayer{Type: int(n)}
+	})
 })
 
 var unmarshalGraphicElement = json.UnmarshalFuncV2(func(d *jsontext.Decoder, a *AnyGraphicElement, o json.Options) error {
-	return decodeTyped(d, (*any)(a), o, graphicElementTypes)
+	return decodeTyped(d, (*any)(a), o, graphicElementTypes, func(typ any) any {
+		s, _ := typ.(string)
+		return UnknownShape{Type: s}
+	})
 })
 
 var unmarshalAsset = json.UnmarshalFuncV2(func(d *jsontext.Decoder, a *AnyAsset, o json.Options) error {
@@ -184,6 +198,13 @@ const (
 	TrimMultipleShapesSequential TrimMultipleShapes = 2
 )
 
+type RepeaterComposite int
+
+const (
+	RepeaterCompositeAbove RepeaterComposite = 1
+	RepeaterCompositeBelow RepeaterComposite = 2
+)
+
 type GradientType int
 
 const (
@@ -232,15 +253,19 @@ type AnimatableProperty[T, K any] struct {
 	// elements in the first place? Who knows... But we've seen real files where
 	// scaling in a transformation had 3 array values instead of the required 2.
 	Length maybe.Option[int]
+	// The After Effects expression that computes the property's value.
+	Expression string
 }
 
 func (prop *AnimatableProperty[T, K]) UnmarshalJSON(data []byte) error {
 	var animated struct {
-		Animated IntBoolean `json:"a"`
+		Animated   IntBoolean `json:"a"`
+		Expression string     `json:"x"`
 	}
 	if err := json.Unmarshal(data, &animated); err != nil {
 		return err
 	}
+	prop.Expression = animated.Expression
 	if animated.Animated {
 		var value struct {
 			Value  []K               `json:"k"`
@@ -273,12 +298,14 @@ type Animation struct {
 	// Frame the animation starts at
 	InPoint float64 `json:"ip"`
 	// Frame the animation stops or loops at
-	OutPoint float64    `json:"op"`
-	Width    int        `json:"w"`
-	Height   int        `json:"h"`
-	Assets   []AnyAsset `json:"assets"`
-	Markers  []Marker
-	Slots    Slot `json:"slots"`
+	OutPoint float64                `json:"op"`
+	Width    int                    `json:"w"`
+	Height   int                    `json:"h"`
+	Assets   []AnyAsset             `json:"assets"`
+	Markers  []Marker               `json:"markers"`
+	Slots    map[string]Slot        `json:"slots"`
+	Fonts    maybe.Option[FontList] `json:"fonts"`
+	Chars    []CharacterData        `json:"chars"`
 }
 
 type NullLayer struct {
@@ -287,7 +314,7 @@ type NullLayer struct {
 }
 
 type UnknownLayer struct {
-	Type int `json:"ty"` // not 0, 1, 2, 3, 4
+	Type int `json:"ty"` // not 0, 1, 2, 3, 4, 5
 }
 
 type AnyGraphicElement any
@@ -300,12 +327,13 @@ type ShapeLayer struct {
 
 type Layer struct {
 	VisualObject
-	Hidden      bool              `json:"hd"`
-	Type        int               `json:"ty"`
-	Index       int               `json:"ind"`
-	ParentIndex maybe.Option[int] `json:"parent"`
-	InPoint     float64           `json:"ip"`
-	OutPoint    float64           `json:"op"`
+	ThreeDimensional IntBoolean        `json:"ddd"`
+	Hidden           bool              `json:"hd"`
+	Type             int               `json:"ty"`
+	Index            int               `json:"ind"`
+	ParentIndex      maybe.Option[int] `json:"parent"`
+	InPoint          float64           `json:"ip"`
+	OutPoint         float64           `json:"op"`
 }
 
 type PrecompositionLayer struct {
@@ -323,6 +351,12 @@ type ImageLayer struct {
 	ReferenceID string `json:"refId"`
 }
 
+type TextLayer struct {
+	VisualLayer
+	Type int      `json:"ty"` // 5
+	Data TextData `json:"t"`
+}
+
 type AnyLayer any
 
 type SolidLayer struct {
@@ -348,6 +382,15 @@ type VisualLayer struct {
 	MatteTarget                   IntBoolean              `json:"td"`
 	StartTime                     float64                 `json:"st"` // defaults to 0
 	TimeStretch                   maybe.Option[float64]   `json:"sr"` // defaults to 1
+	Effects                       []Effect                `json:"ef"`
+}
+
+// Effect is a layer effect. We don't support any effects and only decode
+// enough to report them.
+type Effect struct {
+	VisualObject
+	Type    int                      `json:"ty"`
+	Enabled maybe.Option[IntBoolean] `json:"en"`
 }
 
 type Numbers []float64
@@ -392,6 +435,20 @@ type ScalarProperty struct {
 	AnimatableProperty[float64, SimpleKeyframe[[]float64]]
 }
 
+func (prop *ScalarProperty) UnmarshalJSON(data []byte) error {
+	return unmarshalSlottable(data, &prop.SlottableProperty, &prop.AnimatableProperty)
+}
+
+// unmarshalSlottable decodes a slottable, animatable property. The
+// UnmarshalJSON method of AnimatableProperty would otherwise be promoted and
+// ignore the slot ID.
+func unmarshalSlottable(data []byte, slot *SlottableProperty, prop interface{ UnmarshalJSON([]byte) error }) error {
+	if err := json.Unmarshal(data, slot); err != nil {
+		return err
+	}
+	return prop.UnmarshalJSON(data)
+}
+
 // XXX support position keyframe easing
 // type PositionKeyframe struct {
 // 	SimpleKeyframe[[]float64]
@@ -404,6 +461,10 @@ type ColorProperty struct {
 	AnimatableProperty[Color, SimpleKeyframe[Color]]
 }
 
+func (prop *ColorProperty) UnmarshalJSON(data []byte) error {
+	return unmarshalSlottable(data, &prop.SlottableProperty, &prop.AnimatableProperty)
+}
+
 type PositionProperty = VectorProperty
 
 type Color [3]float64
@@ -496,7 +557,49 @@ func (v *IntBoolean) UnmarshalJSON(data []byte) error {
 }
 
 type HexColor string
+
+// DataURL is a data URL as defined by RFC 2397, such as
+// "data:image/png;base64,iVBORw0KGgo…".
 type DataURL string
+
+// Decode returns the data URL's media type and data.
+func (u DataURL) Decode() (mediaType string, data []byte, err error) {
+	rest, ok := strings.CutPrefix(string(u), "data:")
+	if !ok {
+		return "", nil, errors.New("not a data URL")
+	}
+	header, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return "", nil, errors.New("malformed data URL: missing comma")
+	}
+	mediaType, isBase64 := strings.CutSuffix(header, ";base64")
+	if isBase64 {
+		// Some exporters insert line breaks or omit padding.
+		payload = strings.Map(func(r rune) rune {
+			switch r {
+			case ' ', '\t', '\r', '\n':
+				return -1
+			default:
+				return r
+			}
+		}, payload)
+		data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "="))
+		if err != nil {
+			return "", nil, fmt.Errorf("malformed data URL: %w", err)
+		}
+	} else {
+		payload, err := url.PathUnescape(payload)
+		if err != nil {
+			return "", nil, fmt.Errorf("malformed data URL: %w", err)
+		}
+		data = []byte(payload)
+	}
+	if mediaType == "" {
+		mediaType = "text/plain;charset=US-ASCII"
+	}
+	return mediaType, data, nil
+}
+
 type Gradient []float64
 
 type Bezier struct {
@@ -506,8 +609,10 @@ type Bezier struct {
 	Vertices    []Vec2 `json:"v"` // defaults to []
 }
 
+// Slot is the value that an animation assigns to a slot. Its type depends on
+// the properties that use the slot.
 type Slot struct {
-	PropertyValue any `json:"p"`
+	Property jsontext.Value `json:"p"`
 }
 
 type Transform struct {
@@ -524,9 +629,12 @@ type Transform struct {
 }
 
 type Mask struct {
-	Mode    MaskMode                     `json:"mode"` // defaults to "i"
-	Opacity maybe.Option[ScalarProperty] `json:"o"`    // defaults to 100
-	Shape   maybe.Option[BezierProperty] `json:"pt"`
+	Mode      MaskMode                     `json:"mode"` // defaults to "i"
+	Opacity   maybe.Option[ScalarProperty] `json:"o"`    // defaults to 100
+	Shape     maybe.Option[BezierProperty] `json:"pt"`
+	Inverted  bool                         `json:"inv"`
+	Feather   maybe.Option[VectorProperty] `json:"f"`
+	Expansion maybe.Option[ScalarProperty] `json:"x"`
 }
 
 type SlottableObject struct {
@@ -613,7 +721,7 @@ type StrokeDash struct {
 
 type BaseShape struct {
 	GraphicElement
-	Direction ShapeDirection
+	Direction ShapeDirection `json:"d"` // defaults to 1
 }
 
 type ShapeModifier struct {
@@ -679,6 +787,21 @@ type TrimPath struct {
 	Multiple TrimMultipleShapes `json:"m"`
 }
 
+type Repeater struct {
+	ShapeModifier
+	Type      string                       `json:"ty"` // "rp"
+	Copies    ScalarProperty               `json:"c"`
+	Offset    maybe.Option[ScalarProperty] `json:"o"`
+	Composite RepeaterComposite            `json:"m"` // defaults to 1
+	Transform RepeaterTransform            `json:"tr"`
+}
+
+type RepeaterTransform struct {
+	Transform
+	StartOpacity maybe.Option[ScalarProperty] `json:"so"` // defaults to 100
+	EndOpacity   maybe.Option[ScalarProperty] `json:"eo"` // defaults to 100
+}
+
 type UnknownShape struct {
 	Type string `json:"ty"`
 }
@@ -704,3 +827,179 @@ type Image struct {
 	FilePath string     `json:"u"`
 	Embedded IntBoolean `json:"e"`
 }
+
+type FontList struct {
+	List []Font `json:"list"`
+}
+
+type Font struct {
+	// Text size that corresponds to the font's ascent, as a percentage of the
+	// font size.
+	Ascent maybe.Option[float64] `json:"ascent"`
+	Family string                `json:"fFamily"`
+	// The name that text documents use to refer to the font.
+	Name   string     `json:"fName"`
+	Style  string     `json:"fStyle"` // e.g. "Bold Italic"
+	Path   string     `json:"fPath"`
+	Weight string     `json:"fWeight"`
+	Origin FontOrigin `json:"origin"`
+	Class  string     `json:"fClass"`
+}
+
+type FontOrigin int
+
+const (
+	FontOriginLocal     FontOrigin = 0
+	FontOriginCSSURL    FontOrigin = 1
+	FontOriginScriptURL FontOrigin = 2
+	FontOriginFontURL   FontOrigin = 3
+)
+
+// CharacterData defines the outline of a character of a font, for
+// animations that embed their glyphs.
+type CharacterData struct {
+	Character string  `json:"ch"`
+	Family    string  `json:"fFamily"`
+	Size      float64 `json:"size"`
+	Style     string  `json:"style"`
+	// The character's advance, at a font size of 100.
+	Width float64 `json:"w"`
+	// Characters defined by precompositions aren't supported.
+	Data CharacterShapes `json:"data"`
+}
+
+type CharacterShapes struct {
+	Shapes []AnyGraphicElement `json:"shapes"`
+}
+
+type TextData struct {
+	Ranges    []TextRange          `json:"a"`
+	Document  AnimatedTextDocument `json:"d"`
+	Alignment TextAlignmentOptions `json:"m"`
+}
+
+type AnimatedTextDocument struct {
+	SlottableProperty
+	Keyframes []TextDocumentKeyframe `json:"k"`
+}
+
+type TextDocumentKeyframe struct {
+	Start TextDocument `json:"s"`
+	Time  float64      `json:"t"`
+}
+
+type TextDocument struct {
+	// The name of a font in the animation's font list.
+	FontFamily     string                `json:"f"`
+	FillColor      maybe.Option[Color]   `json:"fc"`
+	StrokeColor    maybe.Option[Color]   `json:"sc"`
+	StrokeWidth    float64               `json:"sw"`
+	StrokeOverFill bool                  `json:"of"`
+	Size           float64               `json:"s"`
+	LineHeight     maybe.Option[float64] `json:"lh"`
+	BoxSize        maybe.Option[Vec2]    `json:"sz"`
+	BoxPosition    maybe.Option[Vec2]    `json:"ps"`
+	// Lines are separated by carriage returns, line feeds, or U+0003.
+	Text    string      `json:"t"`
+	Justify TextJustify `json:"j"`
+	Caps    TextCaps    `json:"ca"`
+	// Tracking, in thousandths of an em.
+	Tracking      float64 `json:"tr"`
+	BaselineShift float64 `json:"ls"`
+}
+
+type TextJustify int
+
+const (
+	TextJustifyLeft           TextJustify = 0
+	TextJustifyRight          TextJustify = 1
+	TextJustifyCenter         TextJustify = 2
+	TextJustifyLastLineLeft   TextJustify = 3
+	TextJustifyLastLineRight  TextJustify = 4
+	TextJustifyLastLineCenter TextJustify = 5
+	TextJustifyLastLineFull   TextJustify = 6
+)
+
+type TextCaps int
+
+const (
+	TextCapsRegular   TextCaps = 0
+	TextCapsAllCaps   TextCaps = 1
+	TextCapsSmallCaps TextCaps = 2
+)
+
+type TextAlignmentOptions struct {
+	// Group alignment, as a percentage of the size of the group.
+	Alignment maybe.Option[VectorProperty] `json:"a"`
+	Grouping  TextGrouping                 `json:"g"`
+}
+
+type TextGrouping int
+
+const (
+	TextGroupingCharacters TextGrouping = 1
+	TextGroupingWords      TextGrouping = 2
+	TextGroupingLines      TextGrouping = 3
+	TextGroupingAll        TextGrouping = 4
+)
+
+// TextRange animates the properties of a range of the text.
+type TextRange struct {
+	VisualObject
+	Selector maybe.Option[TextRangeSelector] `json:"s"`
+	Style    TextStyle                       `json:"a"`
+}
+
+type TextRangeSelector struct {
+	Expressible IntBoolean                   `json:"t"`
+	MaxEase     maybe.Option[ScalarProperty] `json:"xe"`
+	MinEase     maybe.Option[ScalarProperty] `json:"ne"`
+	Amount      maybe.Option[ScalarProperty] `json:"a"` // defaults to 100
+	BasedOn     TextBased                    `json:"b"`
+	Randomize   IntBoolean                   `json:"rn"`
+	Shape       TextShape                    `json:"sh"`
+	Offset      maybe.Option[ScalarProperty] `json:"o"`
+	Units       TextRangeUnits               `json:"r"`
+	Smoothness  maybe.Option[ScalarProperty] `json:"sm"`
+	Start       maybe.Option[ScalarProperty] `json:"s"`
+	End         maybe.Option[ScalarProperty] `json:"e"` // defaults to 100
+}
+
+type TextBased int
+
+const (
+	TextBasedCharacters                TextBased = 1
+	TextBasedCharactersExcludingSpaces TextBased = 2
+	TextBasedWords                     TextBased = 3
+	TextBasedLines                     TextBased = 4
+)
+
+type TextShape int
+
+const (
+	TextShapeSquare   TextShape = 1
+	TextShapeRampUp   TextShape = 2
+	TextShapeRampDown TextShape = 3
+	TextShapeTriangle TextShape = 4
+	TextShapeRound    TextShape = 5
+	TextShapeSmooth   TextShape = 6
+)
+
+type TextRangeUnits int
+
+const (
+	TextRangeUnitsPercent TextRangeUnits = 1
+	TextRangeUnitsIndex   TextRangeUnits = 2
+)
+
+// TextStyle holds the values that a text range applies to its text. Its
+// transform properties are offsets relative to each character.
+type TextStyle struct {
+	Transform
+	StrokeWidth   maybe.Option[ScalarProperty] `json:"sw"`
+	StrokeColor   maybe.Option[ColorProperty]  `json:"sc"`
+	FillColor     maybe.Option[ColorProperty]  `json:"fc"`
+	FillOpacity   maybe.Option[ScalarProperty] `json:"fo"`
+	StrokeOpacity maybe.Option[ScalarProperty] `json:"so"`
+	Tracking      maybe.Option[ScalarProperty] `json:"t"`
+}
@@ -0,0 +1,510 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_expression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/animation"
+	"honnef.co/go/stuff/math/mathutil"
+)
+
+type builtin struct {
+	// The number of arguments. maxArgs is -1 for variadic functions.
+	minArgs, maxArgs int
+	fn               func(e *evaluator, args []value) (value, error)
+}
+
+var constants = map[string]float64{
+	"Math.PI":      math.Pi,
+	"Math.E":       math.E,
+	"Math.SQRT2":   math.Sqrt2,
+	"Math.LN2":     math.Ln2,
+	"Math.LN10":    math.Ln10,
+	"Math.LOG2E":   math.Log2E,
+	"Math.LOG10E":  math.Log10E,
+	"Math.SQRT1_2": 1 / math.Sqrt2,
+}
+
+var builtins map[string]*builtin
+
+func init() {
+	builtins = map[string]*builtin{
+		"loopOut":         {0, 2, loopFunc(true, false)},
+		"loopIn":          {0, 2, loopFunc(false, false)},
+		"loopOutDuration": {0, 2, loopFunc(true, true)},
+		"loopInDuration":  {0, 2, loopFunc(false, true)},
+		"wiggle":          {2, 5, (*evaluator).wiggle},
+		"valueAtTime":     {1, 1, (*evaluator).valueAtTime},
+
+		"linear":  {3, 5, interpolateFunc(nil)},
+		"ease":    {3, 5, interpolateFunc(animation.CurveCubicBezier{P1: curve.Pt(0.33, 0), P2: curve.Pt(0.667, 1)})},
+		"easeIn":  {3, 5, interpolateFunc(animation.CurveCubicBezier{P1: curve.Pt(0.333, 0), P2: curve.Pt(1, 1)})},
+		"easeOut": {3, 5, interpolateFunc(animation.CurveCubicBezier{P1: curve.Pt(0, 0), P2: curve.Pt(0.667, 1)})},
+
+		"random":           {0, 2, (*evaluator).random},
+		"Math.random":      {0, 0, (*evaluator).random},
+		"seedRandom":       {1, 2, (*evaluator).seedRandom},
+		"clamp":            {3, 3, clamp},
+		"length":           {1, 2, length},
+		"timeToFrames":     {0, 1, (*evaluator).timeToFrames},
+		"framesToTime":     {1, 1, (*evaluator).framesToTime},
+		"add":              {2, 2, operatorFunc("+")},
+		"sub":              {2, 2, operatorFunc("-")},
+		"mul":              {2, 2, operatorFunc("*")},
+		"div":              {2, 2, operatorFunc("/")},
+		"$bm_sum":          {2, 2, operatorFunc("+")},
+		"sum":              {2, 2, operatorFunc("+")},
+		"$bm_sub":          {2, 2, operatorFunc("-")},
+		"$bm_mul":          {2, 2, operatorFunc("*")},
+		"$bm_div":          {2, 2, operatorFunc("/")},
+		"$bm_mod":          {2, 2, operatorFunc("%")},
+		"mod":              {2, 2, operatorFunc("%")},
+		"$bm_neg":          {1, 1, func(_ *evaluator, args []value) (value, error) { return neg(args[0]) }},
+		"Math.min":         {0, -1, minMax(math.Min, math.Inf(1))},
+		"Math.max":         {0, -1, minMax(math.Max, math.Inf(-1))},
+		"Math.pow":         {2, 2, math2(math.Pow)},
+		"Math.atan2":       {2, 2, math2(math.Atan2)},
+		"Math.abs":         {1, 1, math1(math.Abs)},
+		"Math.acos":        {1, 1, math1(math.Acos)},
+		"Math.asin":        {1, 1, math1(math.Asin)},
+		"Math.atan":        {1, 1, math1(math.Atan)},
+		"Math.ceil":        {1, 1, math1(math.Ceil)},
+		"Math.cos":         {1, 1, math1(math.Cos)},
+		"Math.exp":         {1, 1, math1(math.Exp)},
+		"Math.floor":       {1, 1, math1(math.Floor)},
+		"Math.log":         {1, 1, math1(math.Log)},
+		"Math.round":       {1, 1, math1(jsRound)},
+		"Math.sign":        {1, 1, math1(sign)},
+		"Math.sin":         {1, 1, math1(math.Sin)},
+		"Math.sqrt":        {1, 1, math1(math.Sqrt)},
+		"Math.tan":         {1, 1, math1(math.Tan)},
+		"degreesToRadians": {1, 1, math1(func(x float64) float64 { return x * math.Pi / 180 })},
+		"radiansToDegrees": {1, 1, math1(func(x float64) float64 { return x * 180 / math.Pi })},
+	}
+}
+
+func numberArg(args []value, i int, def float64) (float64, error) {
+	if i >= len(args) {
+		return def, nil
+	}
+	if args[i].kind != kindNumber {
+		return 0, fmt.Errorf("argument %d: got %s, want number", i+1, args[i].kind)
+	}
+	return args[i].num, nil
+}
+
+func operatorFunc(op string) func(*evaluator, []value) (value, error) {
+	return func(_ *evaluator, args []value) (value, error) {
+		return binary(op, args[0], args[1])
+	}
+}
+
+func math1(fn func(float64) float64) func(*evaluator, []value) (value, error) {
+	return func(_ *evaluator, args []value) (value, error) {
+		x, err := numberArg(args, 0, 0)
+		if err != nil {
+			return value{}, err
+		}
+		return number(fn(x)), nil
+	}
+}
+
+func math2(fn func(float64, float64) float64) func(*evaluator, []value) (value, error) {
+	return func(_ *evaluator, args []value) (value, error) {
+		x, err := numberArg(args, 0, 0)
+		if err != nil {
+			return value{}, err
+		}
+		y, err := numberArg(args, 1, 0)
+		if err != nil {
+			return value{}, err
+		}
+		return number(fn(x, y)), nil
+	}
+}
+
+func minMax(fn func(float64, float64) float64, init float64) func(*evaluator, []value) (value, error) {
+	return func(_ *evaluator, args []value) (value, error) {
+		res := init
+		for i := range args {
+			x, err := numberArg(args, i, 0)
+			if err != nil {
+				return value{}, err
+			}
+			res = fn(res, x)
+		}
+		return number(res), nil
+	}
+}
+
+// jsRound rounds like JavaScript's Math.round, which rounds halves up.
+func jsRound(x float64) float64 {
+	return math.Floor(x + 0.5)
+}
+
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		return x
+	}
+}
+
+func clamp(_ *evaluator, args []value) (value, error) {
+	if err := checkNumeric("clamp", args...); err != nil {
+		return value{}, err
+	}
+	v, lo, hi := args[0].components(), args[1].components(), args[2].components()
+	out := make([]float64, len(v))
+	for i, x := range v {
+		// Like After Effects, numeric limits apply to all components.
+		l, h := lo[min(i, len(lo)-1)], hi[min(i, len(hi)-1)]
+		out[i] = math.Min(math.Max(x, l), h)
+	}
+	if args[0].kind == kindNumber {
+		return number(out[0]), nil
+	}
+	return array(out), nil
+}
+
+func length(_ *evaluator, args []value) (value, error) {
+	if err := checkNumeric("length", args...); err != nil {
+		return value{}, err
+	}
+	v := args[0]
+	if len(args) == 2 {
+		var err error
+		v, err = binary("-", args[1], args[0])
+		if err != nil {
+			return value{}, err
+		}
+	}
+	var sum float64
+	for _, x := range v.components() {
+		sum += x * x
+	}
+	return number(math.Sqrt(sum)), nil
+}
+
+func (e *evaluator) timeToFrames(args []value) (value, error) {
+	t, err := numberArg(args, 0, e.time())
+	if err != nil {
+		return value{}, err
+	}
+	return number(t * e.env.Framerate), nil
+}
+
+func (e *evaluator) framesToTime(args []value) (value, error) {
+	f, err := numberArg(args, 0, 0)
+	if err != nil {
+		return value{}, err
+	}
+	return number(f / e.env.Framerate), nil
+}
+
+func (e *evaluator) valueAtTime(args []value) (value, error) {
+	t, err := numberArg(args, 0, 0)
+	if err != nil {
+		return value{}, err
+	}
+	return e.valueAt(t * e.env.Framerate), nil
+}
+
+// interpolateFunc returns a function like linear and ease, which map t from
+// [tMin, tMax], or [0, 1] if there are only three arguments, to a value
+// between v1 and v2, using the easing curve c.
+func interpolateFunc(c animation.Curve) func(*evaluator, []value) (value, error) {
+	return func(_ *evaluator, args []value) (value, error) {
+		t, err := numberArg(args, 0, 0)
+		if err != nil {
+			return value{}, err
+		}
+		tMin, tMax := 0.0, 1.0
+		v1, v2 := args[1], args[2]
+		switch len(args) {
+		case 4:
+			return value{}, errors.New("got 4 arguments, want 3 or 5")
+		case 5:
+			if tMin, err = numberArg(args, 1, 0); err != nil {
+				return value{}, err
+			}
+			if tMax, err = numberArg(args, 2, 0); err != nil {
+				return value{}, err
+			}
+			v1, v2 = args[3], args[4]
+		}
+		if err := checkNumeric("interpolation", v1, v2); err != nil {
+			return value{}, err
+		}
+		if tMin > tMax {
+			tMin, tMax = tMax, tMin
+			v1, v2 = v2, v1
+		}
+		var p float64
+		switch {
+		case t <= tMin:
+			p = 0
+		case t >= tMax:
+			p = 1
+		default:
+			p = (t - tMin) / (tMax - tMin)
+		}
+		if c != nil {
+			p = c.Transform(p)
+		}
+		// v1 + (v2 - v1) * p
+		d, err := binary("-", v2, v1)
+		if err != nil {
+			return value{}, err
+		}
+		d, err = binary("*", d, number(p))
+		if err != nil {
+			return value{}, err
+		}
+		return binary("+", v1, d)
+	}
+}
+
+// loopFunc returns one of loopIn, loopOut, loopInDuration, and
+// loopOutDuration, which repeat the keyframes before the first or after the
+// last keyframe. The second argument is either the number of keyframes to
+// repeat or, if duration is true, the duration in seconds. Zero means all
+// keyframes.
+func loopFunc(out, duration bool) func(*evaluator, []value) (value, error) {
+	return func(e *evaluator, args []value) (value, error) {
+		typ := "cycle"
+		if len(args) > 0 {
+			if args[0].kind != kindString {
+				return value{}, fmt.Errorf("argument 1: got %s, want string", args[0].kind)
+			}
+			typ = args[0].str
+		}
+		switch typ {
+		case "cycle", "pingpong", "offset", "continue":
+		default:
+			return value{}, fmt.Errorf("unsupported loop type %q", typ)
+		}
+		n, err := numberArg(args, 1, 0)
+		if err != nil {
+			return value{}, err
+		}
+
+		frame := e.env.Frame
+		keys := e.env.Keyframes
+		if len(keys) < 2 {
+			return e.valueAt(frame), nil
+		}
+		first, last := keys[0], keys[len(keys)-1]
+		if out {
+			if frame <= last {
+				return e.valueAt(frame), nil
+			}
+			if duration {
+				if n > 0 {
+					first = max(last-n*e.env.Framerate, first)
+				}
+			} else if n := int(n); n > 0 && n < len(keys)-1 {
+				first = keys[len(keys)-1-n]
+			}
+		} else {
+			if frame >= first {
+				return e.valueAt(frame), nil
+			}
+			if duration {
+				if n > 0 {
+					last = min(first+n*e.env.Framerate, last)
+				}
+			} else if n := int(n); n > 0 && n < len(keys)-1 {
+				last = keys[n]
+			}
+		}
+		d := last - first
+		if d <= 0 {
+			return e.valueAt(frame), nil
+		}
+
+		// The distance from the looped range, and the position within the
+		// current iteration.
+		var dist float64
+		if out {
+			dist = frame - first
+		} else {
+			dist = first - frame
+		}
+		iter := math.Floor(dist / d)
+		rem := math.Mod(dist, d)
+
+		switch typ {
+		case "cycle":
+			if out {
+				return e.valueAt(first + rem), nil
+			}
+			return e.valueAt(last - rem), nil
+		case "pingpong":
+			if int(iter)%2 == 0 {
+				return e.valueAt(first + rem), nil
+			}
+			return e.valueAt(last - rem), nil
+		case "offset":
+			// Each iteration continues where the previous one ended.
+			delta, err := binary("-", e.valueAt(last), e.valueAt(first))
+			if err != nil {
+				return value{}, err
+			}
+			if out {
+				shift, err := binary("*", delta, number(iter))
+				if err != nil {
+					return value{}, err
+				}
+				return binary("+", shift, e.valueAt(first+rem))
+			}
+			shift, err := binary("*", delta, number(iter+1))
+			if err != nil {
+				return value{}, err
+			}
+			return binary("-", e.valueAt(last-rem), shift)
+		case "continue":
+			// Extrapolate the velocity at the first or last keyframe.
+			const h = 0.001
+			edge, inner, beyond := e.valueAt(first), e.valueAt(first+h), first-frame
+			if out {
+				edge, inner, beyond = e.valueAt(last), e.valueAt(last-h), frame-last
+			}
+			delta, err := binary("-", edge, inner)
+			if err != nil {
+				return value{}, err
+			}
+			delta, err = binary("*", delta, number(beyond/h))
+			if err != nil {
+				return value{}, err
+			}
+			return binary("+", edge, delta)
+		}
+		panic("unreachable")
+	}
+}
+
+// wiggle adds noise to the property's value. Its arguments are the frequency
+// in wiggles per second, the amplitude, the number of octaves of noise, the
+// factor by which the amplitude changes per octave, and the time at which to
+// compute the noise.
+func (e *evaluator) wiggle(args []value) (value, error) {
+	freq, err := numberArg(args, 0, 0)
+	if err != nil {
+		return value{}, err
+	}
+	amp, err := numberArg(args, 1, 0)
+	if err != nil {
+		return value{}, err
+	}
+	octaves, err := numberArg(args, 2, 1)
+	if err != nil {
+		return value{}, err
+	}
+	ampMult, err := numberArg(args, 3, 0.5)
+	if err != nil {
+		return value{}, err
+	}
+	t, err := numberArg(args, 4, e.time())
+	if err != nil {
+		return value{}, err
+	}
+
+	v := e.valueAt(e.env.Frame)
+	out := append([]float64(nil), v.components()...)
+	for i := range out {
+		f, a := freq*t, amp
+		for o := range max(int(octaves), 1) {
+			out[i] += a * e.noise(uint64(i)<<8|uint64(o), f)
+			f *= 2
+			a *= ampMult
+		}
+	}
+	return propertyValue(out), nil
+}
+
+// noise returns smooth value noise in [-1, 1]. Different streams are
+// independent of each other.
+func (e *evaluator) noise(stream uint64, x float64) float64 {
+	i := math.Floor(x)
+	f := x - i
+	a := e.hash(stream, math.Float64bits(i))
+	b := e.hash(stream, math.Float64bits(i+1))
+	f = f * f * (3 - 2*f)
+	return mathutil.Lerp(a, b, f)*2 - 1
+}
+
+// hash returns a pseudorandom number in [0, 1) derived from the seed and the
+// arguments.
+func (e *evaluator) hash(a, b uint64) float64 {
+	x := splitmix(e.seed ^ splitmix(a^splitmix(b)))
+	return float64(x>>11) / (1 << 53)
+}
+
+func splitmix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// random returns a pseudorandom number, or array of numbers, in [0, 1),
+// [0, max), or [min, max). The numbers depend on the seed and, unless
+// seedRandom made them timeless, the frame.
+func (e *evaluator) random(args []value) (value, error) {
+	if err := checkNumeric("random", args...); err != nil {
+		return value{}, err
+	}
+	lo, hi := number(0), number(1)
+	switch len(args) {
+	case 1:
+		hi = args[0]
+	case 2:
+		lo, hi = args[0], args[1]
+	}
+	var frame uint64
+	if !e.timeless {
+		frame = math.Float64bits(e.env.Frame)
+	}
+	n := max(len(lo.components()), len(hi.components()))
+	out := make([]float64, n)
+	for i := range out {
+		e.draws++
+		r := e.hash(frame, e.draws)
+		l, h := lo.components(), hi.components()
+		var a, b float64
+		if i < len(l) {
+			a = l[i]
+		}
+		if i < len(h) {
+			b = h[i]
+		}
+		out[i] = a + r*(b-a)
+	}
+	if lo.kind == kindNumber && hi.kind == kindNumber {
+		return number(out[0]), nil
+	}
+	return array(out), nil
+}
+
+func (e *evaluator) seedRandom(args []value) (value, error) {
+	seed, err := numberArg(args, 0, 0)
+	if err != nil {
+		return value{}, err
+	}
+	if len(args) > 1 {
+		e.timeless = args[1].truthy()
+	}
+	e.seed = splitmix(math.Float64bits(seed))
+	e.draws = 0
+	return value{}, nil
+}
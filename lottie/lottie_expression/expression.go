@@ -0,0 +1,431 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package lottie_expression evaluates the expressions that After Effects
+// attaches to animated properties.
+//
+// Expressions are JavaScript, of which only a small subset is supported:
+// numbers, arrays, variables, arithmetic, comparisons, the conditional
+// operator, and the functions that exports commonly use, such as loopOut,
+// wiggle, and linear. Like After Effects, arithmetic works component-wise on
+// arrays. Arrays and numbers can be added, which adds the number to the
+// first component.
+package lottie_expression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Program is a parsed expression.
+type Program struct {
+	stmts []statement
+	// The number of variables.
+	numVars int
+	// The index of the variable holding the result, or -1 if the result is
+	// the value of the last statement. Exports store the result in $bm_rt.
+	result int
+}
+
+// Parse parses an expression. It returns an error if the expression uses
+// features that aren't supported.
+func Parse(src string) (*Program, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, vars: make(map[string]int)}
+	stmts, err := p.parseProgram()
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) == 0 {
+		return nil, errors.New("expression is empty")
+	}
+	prog := &Program{stmts: stmts, numVars: len(p.vars), result: -1}
+	if v, ok := p.vars["$bm_rt"]; ok {
+		prog.result = v
+	}
+	return prog, nil
+}
+
+// Env is the environment in which an expression is evaluated.
+type Env struct {
+	// The frame to evaluate the expression at.
+	Frame     float64
+	Framerate float64
+	// Value returns the value of the property at a frame, ignoring the
+	// expression. Scalar properties have one component.
+	Value func(frame float64) []float64
+	// The frames of the property's keyframes, which loopIn and loopOut
+	// repeat.
+	Keyframes []float64
+	// Seed seeds wiggle and random, unless the expression calls seedRandom.
+	Seed uint64
+}
+
+// Evaluate evaluates the expression. Scalar results have one component.
+func (prog *Program) Evaluate(env *Env) ([]float64, error) {
+	e := &evaluator{
+		env:  env,
+		vars: make([]value, prog.numVars),
+		seed: env.Seed,
+	}
+	var res value
+	for _, s := range prog.stmts {
+		v, err := e.eval(s.x)
+		if err != nil {
+			return nil, err
+		}
+		if s.variable >= 0 {
+			e.vars[s.variable] = v
+		}
+		res = v
+	}
+	if prog.result >= 0 {
+		res = e.vars[prog.result]
+	}
+	switch res.kind {
+	case kindNumber:
+		return []float64{res.num}, nil
+	case kindArray:
+		return res.arr, nil
+	default:
+		return nil, fmt.Errorf("expression evaluated to %s, not a number or array", res.kind)
+	}
+}
+
+type kind int
+
+const (
+	kindUndefined kind = iota
+	kindNumber
+	kindArray
+	kindString
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindUndefined:
+		return "undefined"
+	case kindNumber:
+		return "number"
+	case kindArray:
+		return "array"
+	case kindString:
+		return "string"
+	default:
+		return fmt.Sprintf("kind(%d)", int(k))
+	}
+}
+
+type value struct {
+	kind kind
+	num  float64
+	arr  []float64
+	str  string
+}
+
+func number(x float64) value   { return value{kind: kindNumber, num: x} }
+func array(xs []float64) value { return value{kind: kindArray, arr: xs} }
+func boolean(b bool) value {
+	if b {
+		return number(1)
+	}
+	return number(0)
+}
+
+// components returns the value as an array, treating numbers as arrays with
+// one component.
+func (v value) components() []float64 {
+	if v.kind == kindNumber {
+		return []float64{v.num}
+	}
+	return v.arr
+}
+
+func (v value) truthy() bool {
+	switch v.kind {
+	case kindNumber:
+		return v.num != 0 && !math.IsNaN(v.num)
+	case kindArray:
+		return true
+	case kindString:
+		return v.str != ""
+	default:
+		return false
+	}
+}
+
+// propertyValue converts the value of a property to a value.
+func propertyValue(xs []float64) value {
+	if len(xs) == 1 {
+		return number(xs[0])
+	}
+	return array(xs)
+}
+
+type evaluator struct {
+	env  *Env
+	vars []value
+
+	seed     uint64
+	timeless bool
+	// The number of random numbers generated so far.
+	draws uint64
+}
+
+func (e *evaluator) time() float64 {
+	return e.env.Frame / e.env.Framerate
+}
+
+func (e *evaluator) valueAt(frame float64) value {
+	return propertyValue(e.env.Value(frame))
+}
+
+func (e *evaluator) eval(n node) (value, error) {
+	switch n := n.(type) {
+	case numberNode:
+		return number(float64(n)), nil
+	case stringNode:
+		return value{kind: kindString, str: string(n)}, nil
+	case timeNode:
+		return number(e.time()), nil
+	case valueNode:
+		return e.valueAt(e.env.Frame), nil
+	case frameDurationNode:
+		return number(1 / e.env.Framerate), nil
+	case varNode:
+		v := e.vars[n]
+		if v.kind == kindUndefined {
+			return value{}, errors.New("use of undefined variable")
+		}
+		return v, nil
+	case arrayNode:
+		xs := make([]float64, len(n))
+		for i, el := range n {
+			v, err := e.number(el)
+			if err != nil {
+				return value{}, err
+			}
+			xs[i] = v
+		}
+		return array(xs), nil
+	case indexNode:
+		x, err := e.eval(n.x)
+		if err != nil {
+			return value{}, err
+		}
+		index, err := e.number(n.index)
+		if err != nil {
+			return value{}, err
+		}
+		if x.kind != kindArray {
+			return value{}, fmt.Errorf("cannot index %s", x.kind)
+		}
+		i := int(index)
+		if float64(i) != index || i < 0 || i >= len(x.arr) {
+			return value{}, fmt.Errorf("index %v out of range for array of length %d", index, len(x.arr))
+		}
+		return number(x.arr[i]), nil
+	case unaryNode:
+		x, err := e.eval(n.x)
+		if err != nil {
+			return value{}, err
+		}
+		switch n.op {
+		case "!":
+			return boolean(!x.truthy()), nil
+		case "+":
+			return x, checkNumeric("+", x)
+		case "-":
+			return neg(x)
+		}
+	case binaryNode:
+		x, err := e.eval(n.x)
+		if err != nil {
+			return value{}, err
+		}
+		// The logical operators short-circuit and return one of their
+		// operands, like in JavaScript.
+		switch n.op {
+		case "&&":
+			if !x.truthy() {
+				return x, nil
+			}
+			return e.eval(n.y)
+		case "||":
+			if x.truthy() {
+				return x, nil
+			}
+			return e.eval(n.y)
+		}
+		y, err := e.eval(n.y)
+		if err != nil {
+			return value{}, err
+		}
+		return binary(n.op, x, y)
+	case condNode:
+		cond, err := e.eval(n.cond)
+		if err != nil {
+			return value{}, err
+		}
+		if cond.truthy() {
+			return e.eval(n.then)
+		}
+		return e.eval(n.els)
+	case callNode:
+		args := make([]value, len(n.args))
+		for i, arg := range n.args {
+			v, err := e.eval(arg)
+			if err != nil {
+				return value{}, err
+			}
+			args[i] = v
+		}
+		v, err := n.fn.fn(e, args)
+		if err != nil {
+			return value{}, fmt.Errorf("%s: %w", n.name, err)
+		}
+		return v, nil
+	}
+	panic(fmt.Sprintf("internal error: unhandled node %T", n))
+}
+
+func (e *evaluator) number(n node) (float64, error) {
+	v, err := e.eval(n)
+	if err != nil {
+		return 0, err
+	}
+	if v.kind != kindNumber {
+		return 0, fmt.Errorf("got %s, want number", v.kind)
+	}
+	return v.num, nil
+}
+
+func checkNumeric(op string, vs ...value) error {
+	for _, v := range vs {
+		if v.kind != kindNumber && v.kind != kindArray {
+			return fmt.Errorf("invalid operand of %s: %s", op, v.kind)
+		}
+	}
+	return nil
+}
+
+func neg(x value) (value, error) {
+	if err := checkNumeric("-", x); err != nil {
+		return value{}, err
+	}
+	if x.kind == kindNumber {
+		return number(-x.num), nil
+	}
+	out := make([]float64, len(x.arr))
+	for i, v := range x.arr {
+		out[i] = -v
+	}
+	return array(out), nil
+}
+
+// binary applies a binary operator, following the rules of After Effects and
+// lottie-web for arrays.
+func binary(op string, x, y value) (value, error) {
+	if err := checkNumeric(op, x, y); err != nil {
+		return value{}, err
+	}
+	if x.kind == kindNumber && y.kind == kindNumber {
+		a, b := x.num, y.num
+		switch op {
+		case "+":
+			return number(a + b), nil
+		case "-":
+			return number(a - b), nil
+		case "*":
+			return number(a * b), nil
+		case "/":
+			return number(a / b), nil
+		case "%":
+			return number(math.Mod(a, b)), nil
+		case "<":
+			return boolean(a < b), nil
+		case ">":
+			return boolean(a > b), nil
+		case "<=":
+			return boolean(a <= b), nil
+		case ">=":
+			return boolean(a >= b), nil
+		case "==", "===":
+			return boolean(a == b), nil
+		case "!=", "!==":
+			return boolean(a != b), nil
+		}
+		panic(fmt.Sprintf("internal error: unhandled operator %s", op))
+	}
+
+	switch op {
+	case "+", "-":
+		sign := 1.0
+		if op == "-" {
+			sign = -1
+		}
+		switch {
+		case y.kind == kindNumber:
+			// Numbers only affect the first component.
+			out := append([]float64(nil), x.arr...)
+			if len(out) > 0 {
+				out[0] += sign * y.num
+			}
+			return array(out), nil
+		case x.kind == kindNumber:
+			out := make([]float64, len(y.arr))
+			for i, v := range y.arr {
+				out[i] = sign * v
+			}
+			if len(out) > 0 {
+				out[0] += x.num
+			}
+			return array(out), nil
+		default:
+			// Missing components count as zero.
+			out := make([]float64, max(len(x.arr), len(y.arr)))
+			for i := range out {
+				var a, b float64
+				if i < len(x.arr) {
+					a = x.arr[i]
+				}
+				if i < len(y.arr) {
+					b = y.arr[i]
+				}
+				out[i] = a + sign*b
+			}
+			return array(out), nil
+		}
+	case "*":
+		if x.kind == kindNumber {
+			x, y = y, x
+		}
+		if y.kind == kindNumber {
+			return scale(x.arr, y.num), nil
+		}
+	case "/":
+		if y.kind == kindNumber {
+			return scale(x.arr, 1/y.num), nil
+		}
+	case "==", "===", "!=", "!==":
+		eq := x.kind == y.kind && len(x.arr) == len(y.arr)
+		for i := 0; eq && i < len(x.arr); i++ {
+			eq = x.arr[i] == y.arr[i]
+		}
+		return boolean(eq == (op == "==" || op == "===")), nil
+	}
+	return value{}, fmt.Errorf("unsupported operands of %s: %s and %s", op, x.kind, y.kind)
+}
+
+func scale(xs []float64, f float64) value {
+	out := make([]float64, len(xs))
+	for i, v := range xs {
+		out[i] = v * f
+	}
+	return array(out)
+}
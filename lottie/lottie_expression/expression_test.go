@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_expression
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ramp is a scalar property that goes from 0 to 10 between frames 0 and 10.
+func ramp(frame float64) []float64 {
+	return []float64{math.Min(math.Max(frame, 0), 10)}
+}
+
+func eval(t *testing.T, src string, frame float64, prop func(float64) []float64) []float64 {
+	t.Helper()
+	prog, err := Parse(src)
+	if err != nil {
+		t.Fatalf("%s: %s", src, err)
+	}
+	res, err := prog.Evaluate(&Env{
+		Frame:     frame,
+		Framerate: 10,
+		Value:     prop,
+		Keyframes: []float64{0, 10},
+		Seed:      1,
+	})
+	if err != nil {
+		t.Fatalf("%s: %s", src, err)
+	}
+	return res
+}
+
+func TestEvaluate(t *testing.T) {
+	point := func(frame float64) []float64 { return []float64{frame, 2 * frame} }
+	tests := []struct {
+		src   string
+		frame float64
+		prop  func(float64) []float64
+		want  []float64
+	}{
+		{"time * 360", 5, ramp, []float64{180}},
+		{"value + 1", 5, ramp, []float64{6}},
+		{"2 + 3 * 4 - (1 - 2) / 2", 0, ramp, []float64{14.5}},
+		{"10 % 4", 0, ramp, []float64{2}},
+		{"-value", 5, ramp, []float64{-5}},
+		{"var $bm_rt;\n$bm_rt = $bm_sum(value, $bm_mul(time, 10));", 5, ramp, []float64{10}},
+		{"var x = 2; x *= 3; x + 1", 0, ramp, []float64{7}},
+		{"time > 0.5 ? 1 : 0 // comment", 6, ramp, []float64{1}},
+		{"/* comment */ Math.max(1, Math.abs(-3), 2)", 0, ramp, []float64{3}},
+		{"[value[1], value[0]]", 2, point, []float64{4, 2}},
+		{"value + 1", 2, point, []float64{3, 4}},
+		{"value + [1, 1]", 2, point, []float64{3, 5}},
+		{"value * 2", 2, point, []float64{4, 8}},
+		{"valueAtTime(0.5)", 0, ramp, []float64{5}},
+		{"thisComp.frameDuration", 0, ramp, []float64{0.1}},
+		{"timeToFrames()", 3, ramp, []float64{3}},
+		{"clamp(value, 2, 4)", 5, ramp, []float64{4}},
+		{"length([3, 4])", 0, ramp, []float64{5}},
+
+		{"linear(time, 0, 2, 0, 100)", 5, ramp, []float64{25}},
+		{"linear(time, 1, 0, 0, 100)", 5, ramp, []float64{50}},
+		{"linear(time, 0, 1, [0, 0], [10, 20])", 20, ramp, []float64{10, 20}},
+		{"linear(0.25, 0, 100)", 0, ramp, []float64{25}},
+		{"ease(time, 0, 1, 0, 100)", 0, ramp, []float64{0}},
+		{"ease(time, 0, 1, 0, 100)", 10, ramp, []float64{100}},
+
+		{"loopOut()", 5, ramp, []float64{5}},
+		{"loopOut('cycle')", 13, ramp, []float64{3}},
+		{"loopOut('pingpong')", 13, ramp, []float64{7}},
+		{"loopOut('pingpong')", 23, ramp, []float64{3}},
+		{"loopOut('offset')", 13, ramp, []float64{13}},
+		{"loopOut('offset')", 25, ramp, []float64{25}},
+		{"loopOut('continue')", 15, ramp, []float64{15}},
+		{"loopIn('cycle')", -3, ramp, []float64{7}},
+		{"loopIn('pingpong')", -3, ramp, []float64{3}},
+		{"loopIn('offset')", -3, ramp, []float64{-3}},
+		{"loopOutDuration('cycle', 0.5)", 13, ramp, []float64{8}},
+	}
+	for _, tt := range tests {
+		got := eval(t, tt.src, tt.frame, tt.prop)
+		if !slices.EqualFunc(got, tt.want, func(a, b float64) bool { return math.Abs(a-b) < 1e-6 }) {
+			t.Errorf("%s at frame %v: got %v, want %v", tt.src, tt.frame, got, tt.want)
+		}
+	}
+}
+
+func TestWiggle(t *testing.T) {
+	still := func(float64) []float64 { return []float64{50, 50} }
+	var prev []float64
+	moved := false
+	for frame := 0.0; frame < 30; frame++ {
+		got := eval(t, "wiggle(2, 10)", frame, still)
+		again := eval(t, "wiggle(2, 10)", frame, still)
+		if !slices.Equal(got, again) {
+			t.Fatalf("frame %v: wiggle isn't deterministic: %v and %v", frame, got, again)
+		}
+		for _, v := range got {
+			if v < 40 || v > 60 {
+				t.Errorf("frame %v: %v exceeds amplitude", frame, got)
+			}
+		}
+		if prev != nil && !slices.Equal(prev, got) {
+			moved = true
+		}
+		prev = got
+	}
+	if !moved {
+		t.Error("wiggle didn't change the value")
+	}
+	if a, b := eval(t, "wiggle(2, 10)", 3, still), eval(t, "seedRandom(5); wiggle(2, 10)", 3, still); slices.Equal(a, b) {
+		t.Error("seedRandom didn't change the seed")
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"thisComp.layer('Null 1').transform.position", "unsupported function thisComp.layer"},
+		{"effect('Slider Control')('Slider')", "unsupported function effect"},
+		{"if (time > 1) { value } else { 0 }", `unsupported statement "if"`},
+		{"foo + 1", "unsupported identifier foo"},
+		{"Math.sin", "unsupported use of function Math.sin"},
+		{"wiggle(1)", "wrong number of arguments for wiggle"},
+		{"value = 1", "cannot assign to value"},
+		{"", "expression is empty"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: got error %v, want %q", tt.src, err, tt.want)
+		}
+	}
+
+	prog, err := Parse("loopOut('bounce')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = prog.Evaluate(&Env{Frame: 20, Framerate: 10, Value: ramp, Keyframes: []float64{0, 10}})
+	if err == nil || !strings.Contains(err.Error(), `unsupported loop type "bounce"`) {
+		t.Errorf("got error %v, want unsupported loop type", err)
+	}
+}
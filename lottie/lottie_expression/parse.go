@@ -0,0 +1,501 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_expression
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// Punctuators, longest first so that we match greedily.
+var puncts = []string{
+	"===", "!==",
+	"==", "!=", "<=", ">=", "&&", "||", "+=", "-=", "*=", "/=",
+	"(", ")", "[", "]", "{", "}", ",", ";", "=", "+", "-", "*", "/", "%", "<", ">", "?", ":", "!", ".",
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end == -1 {
+				i = len(src)
+			} else {
+				i += end
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 {
+				return nil, fmt.Errorf("offset %d: unterminated comment", i)
+			}
+			i += end + 4
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				i++
+				if i < len(src) && (src[i] == '+' || src[i] == '-') {
+					i++
+				}
+				for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+					i++
+				}
+			}
+			n, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("offset %d: invalid number %q", start, src[start:i])
+			}
+			toks = append(toks, token{kind: tokenNumber, text: src[start:i], num: n, pos: start})
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			var sb strings.Builder
+			for {
+				if i >= len(src) || src[i] == '\n' {
+					return nil, fmt.Errorf("offset %d: unterminated string", start)
+				}
+				if src[i] == c {
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+				}
+				sb.WriteByte(src[i])
+				i++
+			}
+			toks = append(toks, token{kind: tokenString, text: sb.String(), pos: start})
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && (isIdentStart(src[i]) || src[i] >= '0' && src[i] <= '9') {
+				i++
+			}
+			toks = append(toks, token{kind: tokenIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, p := range puncts {
+				if strings.HasPrefix(src[i:], p) {
+					toks = append(toks, token{kind: tokenPunct, text: p, pos: i})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				r, _ := utf8.DecodeRuneInString(src[i:])
+				if unicode.IsLetter(r) {
+					return nil, fmt.Errorf("offset %d: unsupported identifier character %q", i, r)
+				}
+				return nil, fmt.Errorf("offset %d: unexpected character %q", i, r)
+			}
+		}
+	}
+	toks = append(toks, token{kind: tokenEOF, pos: len(src)})
+	return toks, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$'
+}
+
+type node interface{}
+
+type (
+	numberNode float64
+	stringNode string
+	// timeNode is the current time, in seconds.
+	timeNode struct{}
+	// valueNode is the value of the property at the current time.
+	valueNode struct{}
+	// frameDurationNode is the duration of a frame, in seconds.
+	frameDurationNode struct{}
+	// varNode refers to a variable, by its index.
+	varNode   int
+	arrayNode []node
+	indexNode struct{ x, index node }
+	unaryNode struct {
+		op string
+		x  node
+	}
+	binaryNode struct {
+		op   string
+		x, y node
+	}
+	condNode struct{ cond, then, els node }
+	callNode struct {
+		name string
+		fn   *builtin
+		args []node
+	}
+)
+
+// statement assigns to a variable, or evaluates an expression if variable is
+// -1.
+type statement struct {
+	variable int
+	x        node
+}
+
+type parser struct {
+	toks []token
+	pos  int
+	vars map[string]int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isPunct(s string) bool {
+	t := p.peek()
+	return t.kind == tokenPunct && t.text == s
+}
+
+func (p *parser) accept(s string) bool {
+	if p.isPunct(s) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.accept(s) {
+		return p.errorf("expected %q, found %s", s, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("offset %d: %s", p.peek().pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) variable(name string) int {
+	if i, ok := p.vars[name]; ok {
+		return i
+	}
+	i := len(p.vars)
+	p.vars[name] = i
+	return i
+}
+
+func (p *parser) parseProgram() ([]statement, error) {
+	var stmts []statement
+	for p.peek().kind != tokenEOF {
+		if p.accept(";") {
+			continue
+		}
+		s, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, s...)
+	}
+	return stmts, nil
+}
+
+func (p *parser) parseStatement() ([]statement, error) {
+	t := p.peek()
+	if t.kind == tokenIdent {
+		switch t.text {
+		case "var", "let", "const":
+			p.next()
+			var stmts []statement
+			for {
+				name := p.next()
+				if name.kind != tokenIdent {
+					return nil, p.errorf("expected variable name, found %s", name)
+				}
+				if _, ok := p.vars[name.text]; !ok && reserved(name.text) {
+					return nil, fmt.Errorf("offset %d: cannot declare variable %s", name.pos, name.text)
+				}
+				if p.accept("=") {
+					x, err := p.parseExpr()
+					if err != nil {
+						return nil, err
+					}
+					stmts = append(stmts, statement{p.variable(name.text), x})
+				} else {
+					p.variable(name.text)
+				}
+				if !p.accept(",") {
+					break
+				}
+			}
+			return stmts, nil
+		case "if", "else", "for", "while", "do", "function", "return", "switch", "try", "new":
+			return nil, p.errorf("unsupported statement %q", t.text)
+		}
+		if next := p.toks[p.pos+1]; next.kind == tokenPunct {
+			switch next.text {
+			case "=", "+=", "-=", "*=", "/=":
+				if reserved(t.text) {
+					return nil, p.errorf("cannot assign to %s", t.text)
+				}
+				p.next()
+				p.next()
+				x, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				v := p.variable(t.text)
+				if next.text != "=" {
+					x = binaryNode{next.text[:1], varNode(v), x}
+				}
+				return []statement{{v, x}}, nil
+			}
+		}
+	}
+	x, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return []statement{{-1, x}}, nil
+}
+
+func (p *parser) parseExpr() (node, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("?") {
+		return cond, nil
+	}
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	els, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return condNode{cond, then, els}, nil
+}
+
+// Binary operators by increasing precedence.
+var precedences = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "===", "!=="},
+	{"<", ">", "<=", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (node, error) {
+	if level == len(precedences) {
+		return p.parseUnary()
+	}
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenPunct || !slices.Contains(precedences[level], t.text) {
+			return x, nil
+		}
+		p.next()
+		y, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = binaryNode{t.text, x, y}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if t := p.peek(); t.kind == tokenPunct && (t.text == "-" || t.text == "+" || t.text == "!") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{t.text, x}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("[") {
+		index, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		x = indexNode{x, index}
+	}
+	if p.isPunct("(") || p.isPunct(".") {
+		return nil, p.errorf("unsupported use of %s", p.peek())
+	}
+	return x, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		return numberNode(t.num), nil
+	case tokenString:
+		return stringNode(t.text), nil
+	case tokenPunct:
+		switch t.text {
+		case "(":
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		case "[":
+			var elems arrayNode
+			for !p.accept("]") {
+				x, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				elems = append(elems, x)
+				if !p.accept(",") {
+					if err := p.expect("]"); err != nil {
+						return nil, err
+					}
+					break
+				}
+			}
+			return elems, nil
+		}
+	case tokenIdent:
+		return p.parseName(t)
+	}
+	return nil, fmt.Errorf("offset %d: unexpected %s", t.pos, t)
+}
+
+// parseName parses a variable, a constant, or a call of a function, all of
+// which may be qualified, as in Math.sin(x).
+func (p *parser) parseName(t token) (node, error) {
+	name := t.text
+	for p.isPunct(".") {
+		if _, ok := p.vars[name]; ok {
+			break
+		}
+		p.next()
+		sel := p.next()
+		if sel.kind != tokenIdent {
+			return nil, fmt.Errorf("offset %d: expected name after %q, found %s", sel.pos, name+".", sel)
+		}
+		name += "." + sel.text
+	}
+
+	if p.accept("(") {
+		fn, ok := builtins[name]
+		if !ok {
+			return nil, fmt.Errorf("offset %d: unsupported function %s", t.pos, name)
+		}
+		var args []node
+		for !p.accept(")") {
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, x)
+			if !p.accept(",") {
+				if err := p.expect(")"); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+		if len(args) < fn.minArgs || fn.maxArgs >= 0 && len(args) > fn.maxArgs {
+			return nil, fmt.Errorf("offset %d: wrong number of arguments for %s: got %d", t.pos, name, len(args))
+		}
+		return callNode{name, fn, args}, nil
+	}
+
+	if v, ok := p.vars[name]; ok {
+		return varNode(v), nil
+	}
+	switch name {
+	case "time":
+		return timeNode{}, nil
+	case "value":
+		return valueNode{}, nil
+	case "thisComp.frameDuration":
+		return frameDurationNode{}, nil
+	}
+	if c, ok := constants[name]; ok {
+		return numberNode(c), nil
+	}
+	if _, ok := builtins[name]; ok {
+		return nil, fmt.Errorf("offset %d: unsupported use of function %s", t.pos, name)
+	}
+	return nil, fmt.Errorf("offset %d: unsupported identifier %s", t.pos, name)
+}
+
+// reserved reports whether a name refers to something other than a variable.
+func reserved(name string) bool {
+	if name == "time" || name == "value" {
+		return true
+	}
+	_, ok := constants[name]
+	if ok {
+		return true
+	}
+	_, ok = builtins[name]
+	return ok
+}
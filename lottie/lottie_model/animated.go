@@ -47,7 +47,8 @@ func (b Brush) Evaluate(alpha, frame float64) gfx.Paint {
 }
 
 type Star struct {
-	IsPolygon      bool
+	IsPolygon bool
+	// 1 for clockwise, -1 for counter-clockwise
 	Direction      float64
 	Position       animation.KeyframedPoint
 	InnerRadius    animation.Keyframes[float64]
@@ -58,6 +59,57 @@ type Star struct {
 	Points         animation.Keyframes[float64]
 }
 
+// Evaluate appends the star's outline to path. Like lottie-web, it starts at
+// the top and places the handles of rounded corners perpendicular to the
+// radius.
+func (s Star) Evaluate(frame float64, path curve.BezPath) curve.BezPath {
+	pos := s.Position.Evaluate(frame)
+	rotation := toRadians(s.Rotation.Evaluate(frame))
+	outerRadius := s.OuterRadius.Evaluate(frame)
+	outerRoundness := s.OuterRoundness.Evaluate(frame) / 100
+	var innerRadius, innerRoundness float64
+	numPoints := int(math.Floor(s.Points.Evaluate(frame)))
+	numVertices := numPoints
+	if !s.IsPolygon {
+		innerRadius = s.InnerRadius.Evaluate(frame)
+		innerRoundness = s.InnerRoundness.Evaluate(frame) / 100
+		numVertices *= 2
+	}
+	if numVertices <= 0 {
+		return path
+	}
+	dir := s.Direction
+	if dir == 0 {
+		dir = 1
+	}
+
+	angle := 2 * math.Pi / float64(numVertices)
+	// The handle lengths are fractions of the circumference. lottie-web uses
+	// shorter handles for polygons than for stars, and we match its output.
+	handleScale := 2 * math.Pi / float64(numVertices*4)
+	if !s.IsPolygon {
+		handleScale = 2 * math.Pi / float64(numVertices*2)
+	}
+	points := make([]curve.Point, 0, numVertices*3)
+	for i := range numVertices {
+		radius, roundness := outerRadius, outerRoundness
+		if i%2 == 1 && !s.IsPolygon {
+			radius, roundness = innerRadius, innerRoundness
+		}
+		a := -math.Pi/2 + rotation + float64(i)*angle*dir
+		sin, cos := math.Sincos(a)
+		// The handles are perpendicular to the radius.
+		handle := curve.Vec(-sin, cos).Mul(radius * handleScale * roundness * dir)
+		points = append(points,
+			curve.Pt(pos.X+radius*cos, pos.Y+radius*sin),
+			curve.Point(handle.Negate()),
+			curve.Point(handle),
+		)
+	}
+	path, _ = ToPath(points, true, path)
+	return path
+}
+
 type Spline struct {
 	IsClosed bool
 	animation.Keyframes[[]curve.Point]
@@ -146,7 +198,17 @@ func (s Spline) Evaluate(frame float64, path curve.BezPath) (curve.BezPath, bool
 	return ToPath3(from, to, t, s.IsClosed, path)
 }
 
+type RepeaterComposite int
+
+const (
+	// Each copy is drawn above the previous one.
+	RepeaterCompositeAbove RepeaterComposite = iota + 1
+	// Each copy is drawn below the previous one.
+	RepeaterCompositeBelow
+)
+
 type Repeater struct {
+	Composite    RepeaterComposite
 	Copies       animation.Keyframes[float64]
 	Offset       animation.Keyframes[float64]
 	AnchorPoint  animation.KeyframedPoint
@@ -167,6 +229,7 @@ func (r Repeater) Evaluate(frame float64) FixedRepeater {
 	startOpacity := r.StartOpacity.Evaluate(frame)
 	endOpacity := r.EndOpacity.Evaluate(frame)
 	return FixedRepeater{
+		Composite:    r.Composite,
 		Copies:       int(math.Round(copies)),
 		Offset:       offset,
 		AnchorPoint:  anchorPoint,
@@ -177,3 +240,68 @@ func (r Repeater) Evaluate(frame float64) FixedRepeater {
 		EndOpacity:   endOpacity,
 	}
 }
+
+// Dash is the dash pattern of a stroke.
+type Dash struct {
+	// Lengths of dashes and gaps, in alternating order, starting with a dash.
+	Pattern []animation.Keyframes[float64]
+	Offset  animation.Keyframes[float64]
+}
+
+// Evaluate returns stroke with the dash pattern applied. Patterns that
+// cannot be drawn, such as ones whose lengths add up to zero, leave the
+// stroke solid.
+func (d Dash) Evaluate(frame float64, stroke curve.Stroke) curve.Stroke {
+	if len(d.Pattern) == 0 {
+		return stroke
+	}
+	n := len(d.Pattern)
+	if n%2 == 1 {
+		// Like SVG, repeat patterns with an odd number of lengths, so that
+		// dashes and gaps keep alternating.
+		n *= 2
+	}
+	pattern := make([]float64, n)
+	var total float64
+	for i := range pattern {
+		v := max(d.Pattern[i%len(d.Pattern)].Evaluate(frame), 0)
+		pattern[i] = v
+		total += v
+	}
+	if total == 0 || math.IsInf(total, 0) || math.IsNaN(total) {
+		return stroke
+	}
+	offset := math.Mod(d.Offset.Evaluate(frame), total)
+	if offset < 0 {
+		offset += total
+	}
+	return stroke.WithDashes(offset, pattern)
+}
+
+type TrimMode int
+
+const (
+	// Each path is trimmed on its own.
+	TrimModeSimultaneous TrimMode = iota + 1
+	// All paths are trimmed as if they were a single path.
+	TrimModeIndividual
+)
+
+// Trim trims the paths of preceding geometries.
+type Trim struct {
+	Mode TrimMode
+	// Start and End are fractions of the path's length, in [0, 1].
+	Start animation.Keyframes[float64]
+	End   animation.Keyframes[float64]
+	// Offset shifts start and end, in full turns around the path.
+	Offset animation.Keyframes[float64]
+}
+
+func (t Trim) Evaluate(frame float64) FixedTrim {
+	return FixedTrim{
+		Mode:   t.Mode,
+		Start:  t.Start.Evaluate(frame),
+		End:    t.End.Evaluate(frame),
+		Offset: t.Offset.Evaluate(frame),
+	}
+}
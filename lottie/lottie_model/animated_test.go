@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_model
+
+import (
+	"math"
+	"testing"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/animation"
+)
+
+func static(v float64) animation.Keyframes[float64] {
+	return animation.Keyframes[float64]{
+		Frames: []float64{0},
+		Curves: []animation.Curve{animation.CurveIdentity},
+		Values: []float64{v},
+	}
+}
+
+// vertex is a vertex of a Lottie path with its absolute in and out tangents.
+type vertex struct {
+	v, in, out curve.Point
+}
+
+// closedPath converts the vertices of a closed Lottie path to a BezPath.
+func closedPath(vs []vertex) curve.BezPath {
+	path := curve.BezPath{curve.MoveTo(vs[0].v)}
+	for i := range vs {
+		from, to := vs[i], vs[(i+1)%len(vs)]
+		if from.out == from.v && to.in == to.v {
+			path = append(path, curve.LineTo(to.v))
+		} else {
+			path = append(path, curve.CubicTo(from.out, to.in, to.v))
+		}
+	}
+	return append(path, curve.ClosePath())
+}
+
+func TestStarEvaluate(t *testing.T) {
+	// The vertices were computed with lottie-web's convertStarToPath and
+	// convertPolygonToPath.
+	tests := []struct {
+		name string
+		star Star
+		want []vertex
+	}{
+		{
+			"star",
+			Star{
+				Direction:   1,
+				Position:    animation.KeyframedPoint{X: static(100), Y: static(100)},
+				InnerRadius: static(20),
+				OuterRadius: static(50),
+				Points:      static(3),
+			},
+			[]vertex{
+				{curve.Pt(100, 50), curve.Pt(100, 50), curve.Pt(100, 50)},
+				{curve.Pt(117.32050807568876, 90), curve.Pt(117.32050807568876, 90), curve.Pt(117.32050807568876, 90)},
+				{curve.Pt(143.30127018922195, 125), curve.Pt(143.30127018922195, 125), curve.Pt(143.30127018922195, 125)},
+				{curve.Pt(100, 120), curve.Pt(100, 120), curve.Pt(100, 120)},
+				{curve.Pt(56.698729810778076, 125), curve.Pt(56.698729810778076, 125), curve.Pt(56.698729810778076, 125)},
+				{curve.Pt(82.67949192431122, 90), curve.Pt(82.67949192431122, 90), curve.Pt(82.67949192431122, 90)},
+			},
+		},
+		{
+			"rounded star",
+			Star{
+				Direction:      1,
+				Position:       animation.KeyframedPoint{X: static(10), Y: static(20)},
+				InnerRadius:    static(20),
+				InnerRoundness: static(25),
+				OuterRadius:    static(40),
+				OuterRoundness: static(50),
+				Rotation:       static(45),
+				Points:         static(4),
+			},
+			[]vertex{
+				{curve.Pt(38.2842712474619, -8.284271247461898), curve.Pt(32.73066757476394, -13.837874920159855), curve.Pt(43.83787492015986, -2.730667574763941)},
+				{curve.Pt(30, 20), curve.Pt(30, 18.03650459150638), curve.Pt(30, 21.96349540849362)},
+				{curve.Pt(38.2842712474619, 48.2842712474619), curve.Pt(43.83787492015986, 42.73066757476394), curve.Pt(32.73066757476394, 53.83787492015986)},
+				{curve.Pt(10, 40), curve.Pt(11.963495408493623, 40), curve.Pt(8.03650459150638, 40)},
+				{curve.Pt(-18.2842712474619, 48.2842712474619), curve.Pt(-12.730667574763942, 53.83787492015986), curve.Pt(-23.837874920159855, 42.73066757476394)},
+				{curve.Pt(-10, 20), curve.Pt(-10, 21.963495408493625), curve.Pt(-10, 18.036504591506382)},
+				{curve.Pt(-18.28427124746191, -8.284271247461898), curve.Pt(-23.837874920159866, -2.730667574763941), curve.Pt(-12.730667574763952, -13.837874920159855)},
+				{curve.Pt(10, 0), curve.Pt(8.036504591506375, 0), curve.Pt(11.963495408493618, 0)},
+			},
+		},
+		{
+			"counter-clockwise rounded star",
+			Star{
+				Direction:      -1,
+				Position:       animation.KeyframedPoint{X: static(10), Y: static(20)},
+				InnerRadius:    static(20),
+				InnerRoundness: static(25),
+				OuterRadius:    static(40),
+				OuterRoundness: static(50),
+				Rotation:       static(45),
+				Points:         static(4),
+			},
+			[]vertex{
+				{curve.Pt(38.2842712474619, -8.284271247461898), curve.Pt(43.83787492015986, -2.730667574763941), curve.Pt(32.73066757476394, -13.837874920159855)},
+				{curve.Pt(10, 0), curve.Pt(11.963495408493623, 0), curve.Pt(8.03650459150638, 0)},
+				{curve.Pt(-18.2842712474619, -8.284271247461902), curve.Pt(-12.730667574763942, -13.837874920159859), curve.Pt(-23.837874920159855, -2.7306675747639453)},
+				{curve.Pt(-10, 20), curve.Pt(-10, 18.036504591506375), curve.Pt(-10, 21.963495408493618)},
+				{curve.Pt(-18.28427124746191, 48.2842712474619), curve.Pt(-23.837874920159866, 42.73066757476394), curve.Pt(-12.730667574763952, 53.83787492015986)},
+				{curve.Pt(10, 40), curve.Pt(8.036504591506375, 40), curve.Pt(11.963495408493618, 40)},
+				{curve.Pt(38.284271247461895, 48.28427124746191), curve.Pt(32.730667574763935, 53.83787492015986), curve.Pt(43.837874920159855, 42.730667574763956)},
+				{curve.Pt(30, 20), curve.Pt(30, 21.963495408493625), curve.Pt(30, 18.036504591506382)},
+			},
+		},
+		{
+			// Fractional numbers of points are rounded down.
+			"polygon",
+			Star{
+				IsPolygon:   true,
+				Direction:   1,
+				Position:    animation.KeyframedPoint{X: static(0), Y: static(0)},
+				OuterRadius: static(30),
+				Points:      static(5.7),
+			},
+			[]vertex{
+				{curve.Pt(0, -30), curve.Pt(0, -30), curve.Pt(0, -30)},
+				{curve.Pt(28.531695488854606, -9.270509831248422), curve.Pt(28.531695488854606, -9.270509831248422), curve.Pt(28.531695488854606, -9.270509831248422)},
+				{curve.Pt(17.633557568774194, 24.270509831248425), curve.Pt(17.633557568774194, 24.270509831248425), curve.Pt(17.633557568774194, 24.270509831248425)},
+				{curve.Pt(-17.63355756877419, 24.270509831248425), curve.Pt(-17.63355756877419, 24.270509831248425), curve.Pt(-17.63355756877419, 24.270509831248425)},
+				{curve.Pt(-28.53169548885461, -9.270509831248418), curve.Pt(-28.53169548885461, -9.270509831248418), curve.Pt(-28.53169548885461, -9.270509831248418)},
+			},
+		},
+		{
+			"rounded polygon",
+			Star{
+				IsPolygon:      true,
+				Direction:      1,
+				Position:       animation.KeyframedPoint{X: static(50), Y: static(-50)},
+				OuterRadius:    static(30),
+				OuterRoundness: static(100),
+				Rotation:       static(30),
+				Points:         static(3),
+			},
+			[]vertex{
+				{curve.Pt(65, -75.98076211353316), curve.Pt(51.39650476824337, -83.83474374750764), curve.Pt(78.60349523175664, -68.12678047955868)},
+				{curve.Pt(65, -24.019237886466847), curve.Pt(78.60349523175664, -31.873219520441335), curve.Pt(51.39650476824339, -16.16525625249236)},
+				{curve.Pt(20, -50), curve.Pt(20, -34.292036732051024), curve.Pt(20, -65.70796326794895)},
+			},
+		},
+		{
+			"counter-clockwise rounded polygon",
+			Star{
+				IsPolygon:      true,
+				Direction:      -1,
+				Position:       animation.KeyframedPoint{X: static(50), Y: static(-50)},
+				OuterRadius:    static(30),
+				OuterRoundness: static(100),
+				Rotation:       static(30),
+				Points:         static(3),
+			},
+			[]vertex{
+				{curve.Pt(65, -75.98076211353316), curve.Pt(78.60349523175664, -68.12678047955868), curve.Pt(51.39650476824337, -83.83474374750764)},
+				{curve.Pt(20, -50), curve.Pt(20, -65.70796326794897), curve.Pt(20, -34.292036732051045)},
+				{curve.Pt(65, -24.01923788646683), curve.Pt(51.39650476824335, -16.16525625249236), curve.Pt(78.60349523175663, -31.8732195204413)},
+			},
+		},
+	}
+
+	near := func(a, b curve.Point) bool {
+		return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+	}
+	for _, tt := range tests {
+		got := tt.star.Evaluate(0, nil)
+		want := closedPath(tt.want)
+		if len(got) != len(want) {
+			t.Errorf("%s: got %d path elements, want %d", tt.name, len(got), len(want))
+			continue
+		}
+		for i := range got {
+			g, w := got[i], want[i]
+			if g.Kind != w.Kind || !near(g.P0, w.P0) || !near(g.P1, w.P1) || !near(g.P2, w.P2) {
+				t.Errorf("%s: element %d is %v, want %v", tt.name, i, g, w)
+			}
+		}
+	}
+
+	// Stars without points have no outline.
+	empty := Star{Points: static(0.5), OuterRadius: static(10)}
+	if path := empty.Evaluate(0, nil); len(path) != 0 {
+		t.Errorf("star without points has outline %v", path)
+	}
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_model
+
+import (
+	"honnef.co/go/gutter/animation"
+	"honnef.co/go/gutter/lottie/lottie_expression"
+)
+
+// BindExpression makes the components of a property, such as the X and Y
+// coordinates of a position, evaluate an expression instead of their
+// keyframes, whose values the expression can refer to.
+//
+// The keyframes' values use the units of Lottie files, and fn, which may be
+// nil, converts values from those units, such as degrees to radians. Seed
+// seeds the random numbers of functions like wiggle.
+//
+// Components fall back to their keyframes if the expression fails or
+// doesn't produce a value for them.
+func BindExpression(
+	prog *lottie_expression.Program,
+	framerate float64,
+	seed uint64,
+	fn func(float64) float64,
+	components ...*animation.Keyframes[float64],
+) {
+	// Copy the keyframes before we set their expressions. The copies share
+	// the values, so that slots affect them.
+	raw := make([]animation.Keyframes[float64], len(components))
+	for i, c := range components {
+		raw[i] = *c
+	}
+	valueAt := func(frame float64) []float64 {
+		out := make([]float64, len(raw))
+		for i := range raw {
+			out[i] = raw[i].EvaluateKeyframes(frame)
+		}
+		return out
+	}
+	for i, c := range components {
+		c.Expression = func(frame float64) float64 {
+			// OPT(dh): we evaluate the whole expression once per component.
+			res, err := prog.Evaluate(&lottie_expression.Env{
+				Frame:     frame,
+				Framerate: framerate,
+				Value:     valueAt,
+				Keyframes: raw[0].Frames,
+				Seed:      seed,
+			})
+			var v float64
+			if err == nil && i < len(res) {
+				v = res[i]
+			} else {
+				v = raw[i].EvaluateKeyframes(frame)
+			}
+			if fn != nil {
+				v = fn(v)
+			}
+			return v
+		}
+	}
+}
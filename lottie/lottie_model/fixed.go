@@ -12,9 +12,11 @@ import (
 
 	"honnef.co/go/curve"
 	"honnef.co/go/gutter/gfx"
+	"honnef.co/go/stuff/math/mathutil"
 )
 
 type FixedRepeater struct {
+	Composite    RepeaterComposite
 	Copies       int
 	Offset       float64
 	AnchorPoint  curve.Point
@@ -25,6 +27,42 @@ type FixedRepeater struct {
 	EndOpacity   float64
 }
 
+type FixedTrim struct {
+	Mode   TrimMode
+	Start  float64
+	End    float64
+	Offset float64
+}
+
+// Intervals returns the parts of a path that remain after trimming, as
+// fractions of its length. Trimming that wraps around the end of the path
+// produces two intervals, the first of which ends at 1 and the second of
+// which starts at 0.
+func (t *FixedTrim) Intervals() (out [2][2]float64, n int) {
+	start := mathutil.Clamp(t.Start, 0, 1)
+	end := mathutil.Clamp(t.End, 0, 1)
+	if start > end {
+		start, end = end, start
+	}
+	if start == end {
+		return out, 0
+	}
+	offset := t.Offset - math.Floor(t.Offset)
+	start += offset
+	end += offset
+	if start >= 1 {
+		start--
+		end--
+	}
+	if end <= 1 {
+		out[0] = [2]float64{start, end}
+		return out, 1
+	}
+	out[0] = [2]float64{start, 1}
+	out[1] = [2]float64{0, end - 1}
+	return out, 2
+}
+
 func toRadians(deg float64) float64 {
 	return deg * (math.Pi / 180)
 }
@@ -43,6 +81,16 @@ func (r *FixedRepeater) Transform(index int) curve.Affine {
 		Mul(curve.Translate(curve.Vec(-r.AnchorPoint.X, -r.AnchorPoint.Y)))
 }
 
+// Opacity returns the opacity of the copy with the given index, in [0, 1].
+// Opacity is interpolated linearly from the first to the last copy.
+func (r *FixedRepeater) Opacity(index int) float64 {
+	t := 0.0
+	if r.Copies > 1 {
+		t = float64(index) / float64(r.Copies-1)
+	}
+	return mathutil.Lerp(r.StartOpacity, r.EndOpacity, t) / 100
+}
+
 func BrushWithAlpha(brush gfx.Paint, alpha float64) gfx.Paint {
 	if alpha == 1 {
 		return brush
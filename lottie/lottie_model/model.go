@@ -7,6 +7,7 @@ package lottie_model
 
 import (
 	"fmt"
+	"image"
 	"math"
 	"slices"
 	"time"
@@ -35,7 +36,27 @@ type Composition struct {
 	Width      int
 	Height     int
 	Assets     map[string][]Layer
-	Layers     []Layer
+	Images     map[string]Image
+	// Fonts by the names that text documents use.
+	Fonts map[string]Font
+	// Embedded glyphs. If an animation embeds glyphs, text is drawn using
+	// them instead of fonts.
+	Glyphs map[GlyphKey]Glyph
+	Layers []Layer
+	// Markers in the order they appear in the animation.
+	Markers []Marker
+	// Slots by their IDs.
+	Slots map[string]*Slot
+}
+
+// Image is an image asset.
+type Image struct {
+	// The size at which the image gets drawn, which doesn't have to match the
+	// size of the image.
+	Width  float64
+	Height float64
+	// Image is nil if the image couldn't be loaded.
+	Image image.Image
 }
 
 func (c *Composition) Duration() time.Duration {
@@ -49,6 +70,7 @@ const (
 	GeometryKindRect
 	GeometryKindEllipse
 	GeometryKindSpline
+	GeometryKindStar
 )
 
 type Geometry struct {
@@ -57,6 +79,7 @@ type Geometry struct {
 	Rect    animation.KeyframedRoundedRect
 	Ellipse animation.KeyframedEllipse
 	Spline  Spline
+	Star    Star
 }
 
 func (g Geometry) Evaluate(frame float64, path curve.BezPath) curve.BezPath {
@@ -70,6 +93,8 @@ func (g Geometry) Evaluate(frame float64, path curve.BezPath) curve.BezPath {
 	case GeometryKindSpline:
 		path, _ = g.Spline.Evaluate(frame, path)
 		return path
+	case GeometryKindStar:
+		return g.Star.Evaluate(frame, path)
 	default:
 		panic(fmt.Sprintf("internal error: unhandled geometry kind %v", g.Kind))
 	}
@@ -77,9 +102,13 @@ func (g Geometry) Evaluate(frame float64, path curve.BezPath) curve.BezPath {
 
 type Draw struct {
 	Stroke maybe.Option[animation.KeyframedStroke]
-	Brush  Brush
+	// Dash is only used by strokes.
+	Dash  maybe.Option[Dash]
+	Brush Brush
 	// XXX use 0-1, not 0-100
 	Opacity animation.Keyframes[float64]
+	// FillRule is only used by fills.
+	FillRule gfx.FillRule
 }
 
 type ShapeKind int
@@ -89,6 +118,7 @@ const (
 	ShapeKindGeometry
 	ShapeKindDraw
 	ShapeKindRepeater
+	ShapeKindTrim
 )
 
 // OPT(dh): don't use a fat union for this
@@ -99,6 +129,7 @@ type Shape struct {
 	Geometry       Geometry
 	Draw           Draw
 	Repeater       Repeater
+	Trim           Trim
 }
 
 type GroupTransform struct {
@@ -122,17 +153,48 @@ type Layer struct {
 	StartFrame float64
 	Masks      []Mask
 	IsMask     bool
-	// TODO(dh): the two Mask fields should be a single field
-	MaskLayerMode maybe.Option[gfx.BlendMode]
-	MaskLayerID   maybe.Option[int]
-	Content       Content
+	Matte      maybe.Option[Matte]
+	Content    Content
 }
 
+type MaskMode int
+
+const (
+	MaskModeAdd MaskMode = iota
+	MaskModeSubtract
+	MaskModeIntersect
+	MaskModeLighten
+	MaskModeDarken
+	MaskModeDifference
+)
+
 type Mask struct {
-	Mode     gfx.BlendMode
+	Mode     MaskMode
+	Inverted bool
 	Geometry Geometry
 	// XXX use 0-1, not 0-100
 	Opacity animation.Keyframes[float64]
+	// Feather is the width of the mask's soft edge, in the layer's coordinate
+	// space.
+	Feather animation.KeyframedVec2
+	// Expansion grows the mask, or shrinks it if negative.
+	Expansion animation.Keyframes[float64]
+}
+
+type MatteMode int
+
+const (
+	MatteModeAlpha MatteMode = iota
+	MatteModeInvertedAlpha
+	MatteModeLuma
+	MatteModeInvertedLuma
+)
+
+type Matte struct {
+	Mode MatteMode
+	// Layer is the index of the matte layer in the list of layers that
+	// contains the matted layer.
+	Layer int
 }
 
 type ContentKind int
@@ -141,6 +203,8 @@ const (
 	ContentKindNone ContentKind = iota
 	ContentKindInstance
 	ContentKindShapes
+	ContentKindImage
+	ContentKindText
 )
 
 type Content struct {
@@ -150,4 +214,7 @@ type Content struct {
 		TimeRemap maybe.Option[animation.Keyframes[float64]]
 	}
 	Shapes []Shape
+	// Image is the ID of an image asset.
+	Image string
+	Text  Text
 }
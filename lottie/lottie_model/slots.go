@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_model
+
+import (
+	"slices"
+
+	"honnef.co/go/color"
+	"honnef.co/go/stuff/container/maybe"
+)
+
+// Marker is a named range of frames, which designers use to split an
+// animation into segments, such as "intro" and "loop".
+type Marker struct {
+	Name     string
+	Frame    float64
+	Duration float64
+}
+
+// End returns the frame at which the marker ends.
+func (m Marker) End() float64 {
+	return m.Frame + m.Duration
+}
+
+// Marker returns the marker with the given name.
+func (c *Composition) Marker(name string) (Marker, bool) {
+	for _, m := range c.Markers {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Marker{}, false
+}
+
+type SlotKind int
+
+const (
+	SlotKindColor SlotKind = iota + 1
+	SlotKindScalar
+	SlotKindText
+)
+
+// SlotValue is a value for a slot. Use ColorValue, ScalarValue, and TextValue
+// to construct slot values.
+type SlotValue struct {
+	Kind   SlotKind
+	Color  color.Color
+	Scalar float64
+	Text   string
+}
+
+func ColorValue(c color.Color) SlotValue { return SlotValue{Kind: SlotKindColor, Color: c} }
+
+// ScalarValue returns a slot value for a scalar property. Values use the units
+// of Lottie files, such as degrees for rotations and percentages for
+// opacities.
+func ScalarValue(v float64) SlotValue { return SlotValue{Kind: SlotKindScalar, Scalar: v} }
+
+func TextValue(s string) SlotValue { return SlotValue{Kind: SlotKindText, Text: s} }
+
+// Slot is a named property whose value can be overridden at runtime, for
+// example to theme an animation. Any number of properties can share a slot.
+//
+// Setting a slot modifies the composition in place, overriding the values of
+// all of its properties, including animated ones. Slots must not be modified
+// while the composition is being rendered.
+type Slot struct {
+	Kind SlotKind
+
+	// The value the slot has been set to, if any.
+	value   maybe.Option[SlotValue]
+	scalars []slotScalar
+	colors  []slotColor
+	texts   [][]TextDocumentKeyframe
+	// The original texts, in the same order as the documents in texts.
+	defaultTexts []string
+}
+
+type slotScalar struct {
+	values   []float64
+	defaults []float64
+	// fn converts from Lottie's units to the units used by the model.
+	fn func(float64) float64
+}
+
+type slotColor struct {
+	values   [3][]float64
+	defaults [3][]float64
+}
+
+// BindScalar adds the keyframe values of a scalar property to the slot. fn
+// converts values from Lottie's units to the units the property is stored in.
+// It may be nil.
+func (s *Slot) BindScalar(values []float64, fn func(float64) float64) {
+	s.scalars = append(s.scalars, slotScalar{values, slices.Clone(values), fn})
+}
+
+// BindColor adds the keyframe values of a color property, one slice per
+// channel, to the slot.
+func (s *Slot) BindColor(values [3][]float64) {
+	var defaults [3][]float64
+	for i, vs := range values {
+		defaults[i] = slices.Clone(vs)
+	}
+	s.colors = append(s.colors, slotColor{values, defaults})
+}
+
+// BindText adds the documents of a text property to the slot.
+func (s *Slot) BindText(docs []TextDocumentKeyframe) {
+	s.texts = append(s.texts, docs)
+	for _, doc := range docs {
+		s.defaultTexts = append(s.defaultTexts, doc.Document.Text)
+	}
+}
+
+// Set overrides the values of the slot's properties. Values of the wrong kind
+// are ignored.
+func (s *Slot) Set(v SlotValue) {
+	if v.Kind != s.Kind {
+		return
+	}
+	s.value = maybe.Some(v)
+	switch v.Kind {
+	case SlotKindColor:
+		c := v.Color.Convert(ParsedColorSpace)
+		for _, b := range s.colors {
+			for ch, vs := range b.values {
+				for i := range vs {
+					vs[i] = c.Values[ch]
+				}
+			}
+		}
+	case SlotKindScalar:
+		for _, b := range s.scalars {
+			x := v.Scalar
+			if b.fn != nil {
+				x = b.fn(x)
+			}
+			for i := range b.values {
+				b.values[i] = x
+			}
+		}
+	case SlotKindText:
+		for _, docs := range s.texts {
+			for i := range docs {
+				docs[i].Document.Text = v.Text
+			}
+		}
+	}
+}
+
+// Value returns the value the slot has been set to. It returns false if the
+// slot's properties have the values from the animation.
+func (s *Slot) Value() (SlotValue, bool) {
+	return s.value.Get()
+}
+
+// Reset restores the values that the slot's properties had in the
+// animation.
+func (s *Slot) Reset() {
+	s.value.Clear()
+	for _, b := range s.scalars {
+		copy(b.values, b.defaults)
+	}
+	for _, b := range s.colors {
+		for ch := range b.values {
+			copy(b.values[ch], b.defaults[ch])
+		}
+	}
+	n := 0
+	for _, docs := range s.texts {
+		for i := range docs {
+			docs[i].Document.Text = s.defaultTexts[n]
+			n++
+		}
+	}
+}
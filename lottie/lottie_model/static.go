@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_model
+
+import (
+	"honnef.co/go/gutter/animation"
+	"honnef.co/go/stuff/container/maybe"
+)
+
+// The IsStatic methods report whether values don't change over time, which
+// allows renderers to cache them.
+
+func allStatic[T interface{ IsStatic() bool }](vs []T) bool {
+	for _, v := range vs {
+		if !v.IsStatic() {
+			return false
+		}
+	}
+	return true
+}
+
+func optionStatic[T interface{ IsStatic() bool }](o maybe.Option[T]) bool {
+	v, ok := o.Get()
+	return !ok || v.IsStatic()
+}
+
+func colorStatic(c [3]animation.Keyframes[float64]) bool {
+	return c[0].IsStatic() && c[1].IsStatic() && c[2].IsStatic()
+}
+
+func optionColorStatic(o maybe.Option[[3]animation.Keyframes[float64]]) bool {
+	c, ok := o.Get()
+	return !ok || colorStatic(c)
+}
+
+func (b Brush) IsStatic() bool {
+	switch b.Kind {
+	case BrushKindSolid:
+		return colorStatic(b.Solid)
+	case BrushKindGradient:
+		return b.Gradient.IsStatic()
+	default:
+		return true
+	}
+}
+
+func (s Star) IsStatic() bool {
+	return s.Position.IsStatic() &&
+		s.InnerRadius.IsStatic() &&
+		s.InnerRoundness.IsStatic() &&
+		s.OuterRadius.IsStatic() &&
+		s.OuterRoundness.IsStatic() &&
+		s.Rotation.IsStatic() &&
+		s.Points.IsStatic()
+}
+
+func (g Geometry) IsStatic() bool {
+	switch g.Kind {
+	case GeometryKindRect:
+		return g.Rect.IsStatic()
+	case GeometryKindEllipse:
+		return g.Ellipse.IsStatic()
+	case GeometryKindSpline:
+		return g.Spline.IsStatic()
+	case GeometryKindStar:
+		return g.Star.IsStatic()
+	default:
+		return true
+	}
+}
+
+func (d Dash) IsStatic() bool {
+	return allStatic(d.Pattern) && d.Offset.IsStatic()
+}
+
+func (d Draw) IsStatic() bool {
+	return optionStatic(d.Stroke) &&
+		optionStatic(d.Dash) &&
+		d.Brush.IsStatic() &&
+		d.Opacity.IsStatic()
+}
+
+func (r Repeater) IsStatic() bool {
+	return r.Copies.IsStatic() &&
+		r.Offset.IsStatic() &&
+		r.AnchorPoint.IsStatic() &&
+		r.Position.IsStatic() &&
+		r.Rotation.IsStatic() &&
+		r.Scale.IsStatic() &&
+		r.StartOpacity.IsStatic() &&
+		r.EndOpacity.IsStatic()
+}
+
+func (t Trim) IsStatic() bool {
+	return t.Start.IsStatic() && t.End.IsStatic() && t.Offset.IsStatic()
+}
+
+func (t GroupTransform) IsStatic() bool {
+	return t.Transform.IsStatic() && t.Opacity.IsStatic()
+}
+
+func (s Shape) IsStatic() bool {
+	switch s.Kind {
+	case ShapeKindGroup:
+		return optionStatic(s.GroupTransform) && allStatic(s.GroupShapes)
+	case ShapeKindGeometry:
+		return s.Geometry.IsStatic()
+	case ShapeKindDraw:
+		return s.Draw.IsStatic()
+	case ShapeKindRepeater:
+		return s.Repeater.IsStatic()
+	case ShapeKindTrim:
+		return s.Trim.IsStatic()
+	default:
+		return true
+	}
+}
+
+func (m Mask) IsStatic() bool {
+	return m.Geometry.IsStatic() &&
+		m.Opacity.IsStatic() &&
+		m.Feather.IsStatic() &&
+		m.Expansion.IsStatic()
+}
+
+func (r TextRange) IsStatic() bool {
+	st := &r.Style
+	return r.Start.IsStatic() &&
+		r.End.IsStatic() &&
+		r.Offset.IsStatic() &&
+		r.Amount.IsStatic() &&
+		r.MinEase.IsStatic() &&
+		r.MaxEase.IsStatic() &&
+		st.Anchor.IsStatic() &&
+		st.Position.IsStatic() &&
+		optionStatic(st.Scale) &&
+		st.Rotation.IsStatic() &&
+		optionStatic(st.Opacity) &&
+		optionColorStatic(st.FillColor) &&
+		optionColorStatic(st.StrokeColor) &&
+		optionStatic(st.StrokeWidth) &&
+		optionStatic(st.FillOpacity) &&
+		optionStatic(st.StrokeOpacity) &&
+		optionStatic(st.Tracking)
+}
+
+func (t Text) IsStatic() bool {
+	return len(t.Documents) <= 1 && allStatic(t.Ranges)
+}
@@ -0,0 +1,319 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_model
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"honnef.co/go/color"
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/animation"
+	"honnef.co/go/stuff/container/maybe"
+	"honnef.co/go/stuff/math/mathutil"
+)
+
+// Font is a font that text documents refer to by name.
+type Font struct {
+	Family string
+	// The style name, such as "Bold Italic".
+	Style string
+	// The font's ascent, as a fraction of the font size. Zero if unknown.
+	Ascent float64
+}
+
+// GlyphKey identifies an embedded glyph.
+type GlyphKey struct {
+	Character string
+	Family    string
+	Style     string
+}
+
+// Glyph is the outline of a character, for animations that embed their
+// glyphs instead of relying on fonts.
+type Glyph struct {
+	// The glyph's advance, at a font size of 100.
+	Width float64
+	// The glyph's outline, at a font size of 100. Only geometry is used.
+	Shapes []Shape
+}
+
+type Text struct {
+	// The text documents, in order of their frames. Text documents can't be
+	// interpolated, each holds until the next one.
+	Documents []TextDocumentKeyframe
+	Ranges    []TextRange
+}
+
+type TextDocumentKeyframe struct {
+	Frame    float64
+	Document TextDocument
+}
+
+// Document returns the text document to use at the given frame.
+func (t *Text) Document(frame float64) *TextDocument {
+	if len(t.Documents) == 0 {
+		return nil
+	}
+	i, _ := slices.BinarySearchFunc(t.Documents, frame, func(kf TextDocumentKeyframe, frame float64) int {
+		if kf.Frame <= frame {
+			return -1
+		}
+		return 1
+	})
+	return &t.Documents[max(i-1, 0)].Document
+}
+
+type TextJustify int
+
+const (
+	TextJustifyLeft TextJustify = iota
+	TextJustifyRight
+	TextJustifyCenter
+	// Justified text, with the last line aligned to the left, right, or
+	// center, or justified, too.
+	TextJustifyLastLineLeft
+	TextJustifyLastLineRight
+	TextJustifyLastLineCenter
+	TextJustifyLastLineFull
+)
+
+type TextCaps int
+
+const (
+	TextCapsRegular TextCaps = iota
+	TextCapsAllCaps
+	TextCapsSmallCaps
+)
+
+type TextDocument struct {
+	// The name of the font, which is a key in Composition.Fonts.
+	Font string
+	Size float64
+	// Lines are separated by line feeds.
+	Text           string
+	Fill           maybe.Option[color.Color]
+	Stroke         maybe.Option[color.Color]
+	StrokeWidth    float64
+	StrokeOverFill bool
+	// The distance between baselines.
+	LineHeight float64
+	// Box is set for text that wraps inside of a box. Without a box, the
+	// first line's baseline starts at the origin.
+	Box     maybe.Option[curve.Rect]
+	Justify TextJustify
+	Caps    TextCaps
+	// Additional space between characters, in thousandths of an em.
+	Tracking float64
+	// Vertical offset of the baseline, upwards.
+	BaselineShift float64
+}
+
+type TextBasis int
+
+const (
+	TextBasisCharacters TextBasis = iota
+	TextBasisCharactersExcludingSpaces
+	TextBasisWords
+	TextBasisLines
+)
+
+type TextRangeShape int
+
+const (
+	TextRangeShapeSquare TextRangeShape = iota
+	TextRangeShapeRampUp
+	TextRangeShapeRampDown
+	TextRangeShapeTriangle
+	TextRangeShapeRound
+	TextRangeShapeSmooth
+)
+
+// TextRange animates the properties of part of a text. Its selector computes
+// how much each unit of text, such as a character or a word, is affected.
+type TextRange struct {
+	Basis TextBasis
+	Shape TextRangeShape
+	// Whether Start, End and Offset are unit indices instead of
+	// percentages.
+	Indices   bool
+	Randomize bool
+	Start     animation.Keyframes[float64]
+	End       animation.Keyframes[float64]
+	Offset    animation.Keyframes[float64]
+	// The percentage of the style to apply.
+	Amount animation.Keyframes[float64]
+	// Easing of the selector's shape, in percent.
+	MinEase animation.Keyframes[float64]
+	MaxEase animation.Keyframes[float64]
+	Style   TextRangeStyle
+}
+
+// TextRangeStyle holds the properties that a text range applies. Transforms
+// are relative to each character's position.
+type TextRangeStyle struct {
+	Anchor   animation.KeyframedPoint
+	Position animation.KeyframedPoint
+	// In percent.
+	Scale maybe.Option[animation.KeyframedVec2]
+	// In degrees.
+	Rotation animation.Keyframes[float64]
+	// In percent.
+	Opacity       maybe.Option[animation.Keyframes[float64]]
+	FillColor     maybe.Option[[3]animation.Keyframes[float64]]
+	StrokeColor   maybe.Option[[3]animation.Keyframes[float64]]
+	StrokeWidth   maybe.Option[animation.Keyframes[float64]]
+	FillOpacity   maybe.Option[animation.Keyframes[float64]]
+	StrokeOpacity maybe.Option[animation.Keyframes[float64]]
+	// In thousandths of an em.
+	Tracking maybe.Option[animation.Keyframes[float64]]
+}
+
+// FixedTextRange is a text range evaluated at a frame.
+type FixedTextRange struct {
+	Basis TextBasis
+	Shape TextRangeShape
+	// Start and end of the selection, in units.
+	Start, End float64
+	Amount     float64
+	ease       animation.Curve
+	// The order of units, if randomized.
+	order []int
+
+	Anchor        curve.Point
+	Position      curve.Vec2
+	Scale         maybe.Option[curve.Vec2]
+	Rotation      float64
+	Opacity       maybe.Option[float64]
+	FillColor     maybe.Option[color.Color]
+	StrokeColor   maybe.Option[color.Color]
+	StrokeWidth   maybe.Option[float64]
+	FillOpacity   maybe.Option[float64]
+	StrokeOpacity maybe.Option[float64]
+	Tracking      float64
+}
+
+// Evaluate evaluates the range at frame, for a text consisting of numUnits
+// units. The index of the range is used to seed randomization.
+func (r *TextRange) Evaluate(frame float64, numUnits int, index int) FixedTextRange {
+	start := r.Start.Evaluate(frame)
+	end := r.End.Evaluate(frame)
+	offset := r.Offset.Evaluate(frame)
+	if !r.Indices {
+		start = start / 100 * float64(numUnits)
+		end = end / 100 * float64(numUnits)
+		offset = offset / 100 * float64(numUnits)
+	}
+	start, end = start+offset, end+offset
+	if start > end {
+		start, end = end, start
+	}
+
+	// Easing flattens the shape's low and high ends.
+	var ease animation.Curve = animation.CurveIdentity
+	minEase := mathutil.Clamp(r.MinEase.Evaluate(frame)/100, 0, 1)
+	maxEase := mathutil.Clamp(r.MaxEase.Evaluate(frame)/100, 0, 1)
+	if minEase != 0 || maxEase != 0 {
+		// TODO(dh): support negative easing
+		ease = animation.CurveCubicBezier{
+			P1: curve.Pt(minEase, 0),
+			P2: curve.Pt(1-maxEase, 1),
+		}
+	}
+
+	out := FixedTextRange{
+		Basis:    r.Basis,
+		Shape:    r.Shape,
+		Start:    start,
+		End:      end,
+		Amount:   r.Amount.Evaluate(frame) / 100,
+		ease:     ease,
+		Anchor:   r.Style.Anchor.Evaluate(frame),
+		Position: curve.Vec2(r.Style.Position.Evaluate(frame)),
+		Rotation: r.Style.Rotation.Evaluate(frame),
+	}
+	if r.Randomize {
+		// The order has to be stable across frames, so we use a fixed seed.
+		rng := rand.New(rand.NewPCG(uint64(index), uint64(numUnits)))
+		out.order = rng.Perm(numUnits)
+	}
+	if v, ok := r.Style.Scale.Get(); ok {
+		out.Scale = maybe.Some(v.Evaluate(frame))
+	}
+	if v, ok := r.Style.Opacity.Get(); ok {
+		out.Opacity = maybe.Some(v.Evaluate(frame))
+	}
+	if v, ok := r.Style.FillColor.Get(); ok {
+		out.FillColor = maybe.Some(color.Make(ParsedColorSpace, v[0].Evaluate(frame), v[1].Evaluate(frame), v[2].Evaluate(frame), 1))
+	}
+	if v, ok := r.Style.StrokeColor.Get(); ok {
+		out.StrokeColor = maybe.Some(color.Make(ParsedColorSpace, v[0].Evaluate(frame), v[1].Evaluate(frame), v[2].Evaluate(frame), 1))
+	}
+	if v, ok := r.Style.StrokeWidth.Get(); ok {
+		out.StrokeWidth = maybe.Some(v.Evaluate(frame))
+	}
+	if v, ok := r.Style.FillOpacity.Get(); ok {
+		out.FillOpacity = maybe.Some(v.Evaluate(frame))
+	}
+	if v, ok := r.Style.StrokeOpacity.Get(); ok {
+		out.StrokeOpacity = maybe.Some(v.Evaluate(frame))
+	}
+	if v, ok := r.Style.Tracking.Get(); ok {
+		out.Tracking = v.Evaluate(frame)
+	}
+	return out
+}
+
+// Coverage returns how much the unit with the given index is affected by the
+// range, in [0, 1], taking the range's amount into account.
+func (r *FixedTextRange) Coverage(index int) float64 {
+	if r.order != nil && index < len(r.order) {
+		index = r.order[index]
+	}
+	i := float64(index)
+	s, e := r.Start, r.End
+	var t float64
+	switch r.Shape {
+	case TextRangeShapeRampUp, TextRangeShapeRampDown, TextRangeShapeTriangle:
+		if e == s {
+			if r.Shape == TextRangeShapeRampUp && i >= e {
+				t = 1
+			} else if r.Shape == TextRangeShapeRampDown && i < e {
+				t = 1
+			}
+			break
+		}
+		// Sample the ramp at the unit's center.
+		t = mathutil.Clamp((i+0.5-s)/(e-s), 0, 1)
+		switch r.Shape {
+		case TextRangeShapeRampDown:
+			t = 1 - t
+		case TextRangeShapeTriangle:
+			if t < 0.5 {
+				t *= 2
+			} else {
+				t = 2 * (1 - t)
+			}
+		}
+	case TextRangeShapeRound:
+		if e == s {
+			break
+		}
+		half := (e - s) / 2
+		x := mathutil.Clamp(i+0.5-s, 0, e-s) - half
+		t = math.Sqrt(max(1-(x*x)/(half*half), 0))
+	case TextRangeShapeSmooth:
+		if e == s {
+			break
+		}
+		x := mathutil.Clamp(i+0.5-s, 0, e-s)
+		t = (1 + math.Cos(math.Pi+2*math.Pi*x/(e-s))) / 2
+	default:
+		// Units that are partially selected are partially affected.
+		t = mathutil.Clamp(min(i+1, e)-max(i, s), 0, 1)
+	}
+	return r.ease.Transform(t) * r.Amount
+}
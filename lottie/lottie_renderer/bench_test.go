@@ -21,7 +21,10 @@ func BenchmarkRender(b *testing.B) {
 	if err != nil {
 		b.Fatal(err)
 	}
-	ccomp := lottie_converter.ConvertAnimation(comp)
+	ccomp, err := lottie_converter.ConvertAnimation(comp, lottie_converter.Options{})
+	if err != nil {
+		b.Fatal(err)
+	}
 	b.ResetTimer()
 
 	var r Renderer
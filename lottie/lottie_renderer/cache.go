@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"fmt"
+	"unsafe"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/gfx"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+)
+
+// isStatic reports whether a layer renders the same in all frames in which
+// it is visible, in which case we record it once and replay the recording.
+func (r *Renderer) isStatic(layerSet []model.Layer, layer *model.Layer) bool {
+	if s, ok := r.static[layer]; ok {
+		return s
+	}
+	if r.static == nil {
+		r.static = make(map[*model.Layer]bool)
+	}
+	// Mark the layer as not static while we analyze it, in case of cycles
+	// between mattes.
+	r.static[layer] = false
+	s := r.computeStatic(layerSet, layer)
+	r.static[layer] = s
+	return s
+}
+
+func (r *Renderer) computeStatic(layerSet []model.Layer, layer *model.Layer) bool {
+	if !layer.Opacity.IsStatic() || !layer.Transform.IsStatic() {
+		return false
+	}
+	// Like computeTransform, guard against cycles in the parent chain.
+	parentIndex := layer.Parent
+	for range len(layerSet) {
+		index, ok := parentIndex.Get()
+		if !ok || index < 0 || index >= len(layerSet) {
+			break
+		}
+		parent := &layerSet[index]
+		if !parent.Transform.IsStatic() {
+			return false
+		}
+		parentIndex = parent.Parent
+	}
+	for _, mask := range layer.Masks {
+		if !mask.IsStatic() {
+			return false
+		}
+	}
+	if matte, ok := layer.Matte.Get(); ok && matte.Layer >= 0 && matte.Layer < len(layerSet) {
+		// The matte has to be visible whenever the layer is.
+		m := &layerSet[matte.Layer]
+		if m.FirstFrame > layer.FirstFrame || m.LastFrame < layer.LastFrame || !r.isStatic(layerSet, m) {
+			return false
+		}
+	}
+	switch layer.Content.Kind {
+	case model.ContentKindNone, model.ContentKindImage:
+		return true
+	case model.ContentKindShapes:
+		for _, shape := range layer.Content.Shapes {
+			if !shape.IsStatic() {
+				return false
+			}
+		}
+		return true
+	case model.ContentKindText:
+		return layer.Content.Text.IsStatic()
+	case model.ContentKindInstance:
+		// The layers of precompositions get cached individually.
+		return false
+	default:
+		panic(fmt.Sprintf("internal error: unhandled content kind %v", layer.Content.Kind))
+	}
+}
+
+// replay records the commands of a recording, relative to the recorder's
+// current transform. Unlike PlayRecording, this lets the recording take part
+// in the optimizations of its parent recording, and renderers never see the
+// same recording twice.
+func replay(rec gfx.Recorder, recording gfx.Recording) {
+	for _, cmd := range recording {
+		switch cmd := cmd.(type) {
+		case gfx.CommandPushLayer:
+			rec.PushTransform(cmd.Transform)
+			old := rec.SetFillRule(cmd.FillRule)
+			rec.PushLayer(cmd.Layer)
+			rec.SetFillRule(old)
+			rec.PopTransform()
+		case gfx.CommandPopLayer:
+			rec.PopLayer()
+		case gfx.CommandPushClip:
+			rec.PushTransform(cmd.Transform)
+			old := rec.SetFillRule(cmd.FillRule)
+			rec.PushClip(cmd.Clip)
+			rec.SetFillRule(old)
+			rec.PopTransform()
+		case gfx.CommandPopClip:
+			rec.PopClip()
+		case gfx.CommandFill:
+			rec.PushTransform(cmd.Transform)
+			old := rec.SetFillRule(cmd.FillRule)
+			rec.Fill(cmd.Shape, cmd.Paint)
+			rec.SetFillRule(old)
+			rec.PopTransform()
+		case gfx.CommandStroke:
+			rec.PushTransform(cmd.Transform)
+			rec.Stroke(cmd.Shape, cmd.Stroke, cmd.Paint)
+			rec.PopTransform()
+		case gfx.CommandPlayRecording:
+			rec.PushTransform(cmd.Transform)
+			rec.PlayRecording(cmd.Recording)
+			rec.PopTransform()
+		default:
+			panic(fmt.Sprintf("internal error: unexpected command %T", cmd))
+		}
+	}
+}
+
+// frameCache caches the recordings of whole frames. When it is full, it
+// evicts the frames it cached first.
+type frameCache struct {
+	frames map[float64]cachedFrame
+	// Frames in the order they were added.
+	order []float64
+	size  int
+}
+
+type cachedFrame struct {
+	recording gfx.Recording
+	size      int
+}
+
+func (c *frameCache) get(frame float64) (gfx.Recording, bool) {
+	f, ok := c.frames[frame]
+	return f.recording, ok
+}
+
+// add adds a frame, evicting other frames to stay within limit bytes.
+func (c *frameCache) add(frame float64, recording gfx.Recording, limit int) {
+	size := recordingSize(recording)
+	if size > limit {
+		return
+	}
+	for c.size+size > limit && len(c.order) > 0 {
+		c.size -= c.frames[c.order[0]].size
+		delete(c.frames, c.order[0])
+		c.order = c.order[1:]
+	}
+	if c.frames == nil {
+		c.frames = make(map[float64]cachedFrame)
+	}
+	c.frames[frame] = cachedFrame{recording, size}
+	c.order = append(c.order, frame)
+	c.size += size
+}
+
+func (c *frameCache) reset() {
+	*c = frameCache{}
+}
+
+// recordingSize estimates the memory used by a recording, in bytes. It
+// doesn't account for paints, which are small, or images, which belong to
+// the composition.
+func recordingSize(recording gfx.Recording) int {
+	shapeSize := func(s gfx.Shape) int {
+		if p, ok := s.(curve.BezPath); ok {
+			return len(p) * int(unsafe.Sizeof(curve.PathElement{}))
+		}
+		return 0
+	}
+	n := len(recording) * int(unsafe.Sizeof(gfx.Command(nil)))
+	for _, cmd := range recording {
+		switch cmd := cmd.(type) {
+		case gfx.CommandPushLayer:
+			n += int(unsafe.Sizeof(cmd)) + shapeSize(cmd.Layer.Clip)
+		case gfx.CommandPushClip:
+			n += int(unsafe.Sizeof(cmd)) + shapeSize(cmd.Clip)
+		case gfx.CommandFill:
+			n += int(unsafe.Sizeof(cmd)) + shapeSize(cmd.Shape)
+		case gfx.CommandStroke:
+			n += int(unsafe.Sizeof(cmd)) + shapeSize(cmd.Shape) + len(cmd.Stroke.DashPattern)*8
+		case gfx.CommandPlayRecording:
+			n += int(unsafe.Sizeof(cmd)) + recordingSize(cmd.Recording)
+		}
+	}
+	return n
+}
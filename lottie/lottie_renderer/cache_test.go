@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"reflect"
+	"testing"
+
+	"honnef.co/go/color"
+	"honnef.co/go/gutter/lottie/lottie_converter"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+)
+
+// A null layer moving to the right, a static square parented to it, and a
+// static circle that isn't, whose color is a slot.
+const cacheJSON = `{
+	"v": "5.8.1", "fr": 10, "ip": 0, "op": 10, "w": 200, "h": 200,
+	"layers": [
+		{"ty": 3, "ind": 1, "ip": 0, "op": 10, "st": 0, "sr": 1,
+			"ks": {"p": {"a": 1, "k": [
+				{"t": 0, "s": [0, 0], "o": {"x": [0], "y": [0]}, "i": {"x": [1], "y": [1]}},
+				{"t": 10, "s": [100, 0]}
+			]}, "s": {"a": 0, "k": [100, 100]}}},
+		{"ty": 4, "ind": 2, "parent": 1, "ip": 0, "op": 10, "st": 0, "sr": 1,
+			"ks": {},
+			"shapes": [
+				{"ty": "rc", "p": {"a": 0, "k": [10, 10]}, "s": {"a": 0, "k": [10, 10]}, "r": {"a": 0, "k": 0}},
+				{"ty": "fl", "c": {"a": 0, "k": [1, 0, 0]}, "o": {"a": 0, "k": 100}}
+			]},
+		{"ty": 4, "ind": 3, "ip": 0, "op": 10, "st": 0, "sr": 1,
+			"ks": {"p": {"a": 0, "k": [50, 50]}, "s": {"a": 0, "k": [100, 100]}},
+			"shapes": [
+				{"ty": "el", "p": {"a": 0, "k": [0, 0]}, "s": {"a": 0, "k": [20, 20]}},
+				{"ty": "st", "c": {"a": 0, "k": [0, 0, 1], "sid": "stroke"}, "o": {"a": 0, "k": 100}, "w": {"a": 0, "k": 2}}
+			]}
+	]
+}`
+
+func TestStaticLayers(t *testing.T) {
+	comp := convert(t, cacheJSON, lottie_converter.Options{})
+	var r Renderer
+	for _, frame := range []float64{0, 5, 7.5} {
+		got := render(&r, comp, frame)
+		var fresh Renderer
+		if want := render(&fresh, comp, frame); !reflect.DeepEqual(got, want) {
+			t.Errorf("frame %v: cached rendering differs from fresh rendering", frame)
+		}
+	}
+
+	// The square moves with its parent, but the circle is static.
+	for i, want := range []bool{false, false, true} {
+		if got := r.static[&comp.Layers[i]]; got != want {
+			t.Errorf("layer %d: got static = %t, want %t", i, got, want)
+		}
+	}
+	if len(r.recordings) != 1 {
+		t.Errorf("got %d recordings, want 1", len(r.recordings))
+	}
+
+	// Rendering another composition discards the caches.
+	other := convert(t, cacheJSON, lottie_converter.Options{})
+	render(&r, other, 0)
+	if _, ok := r.recordings[&comp.Layers[2]]; ok {
+		t.Error("recordings of old composition weren't discarded")
+	}
+}
+
+func TestFrameCache(t *testing.T) {
+	comp := convert(t, cacheJSON, lottie_converter.Options{})
+	r := Renderer{FrameCacheSize: 1 << 20}
+	got := render(&r, comp, 2.4)
+	// Frames get rounded to whole frames.
+	var fresh Renderer
+	if want := render(&fresh, comp, 2); !reflect.DeepEqual(got, want) {
+		t.Error("cached frame differs from fresh rendering of the whole frame")
+	}
+	render(&r, comp, 1.8)
+	render(&r, comp, 3)
+	if len(r.frames.frames) != 2 {
+		t.Errorf("got %d cached frames, want 2", len(r.frames.frames))
+	}
+
+	// Evict the oldest frames to stay within the limit.
+	size := r.frames.frames[2].size
+	r = Renderer{FrameCacheSize: size*2 + size/2}
+	for _, frame := range []float64{2, 3, 4} {
+		render(&r, comp, frame)
+	}
+	if _, ok := r.frames.get(2); ok || len(r.frames.frames) != 2 {
+		t.Errorf("got frames %v, want 3 and 4", r.frames.order)
+	}
+	if r.frames.size > r.FrameCacheSize {
+		t.Errorf("cache uses %d bytes, more than its limit of %d", r.frames.size, r.FrameCacheSize)
+	}
+}
+
+func TestCachesAndSlots(t *testing.T) {
+	comp := convert(t, cacheJSON, lottie_converter.Options{})
+	r := Renderer{FrameCacheSize: 1 << 20}
+	render(&r, comp, 0)
+
+	// Changing slots discards the caches, no matter how it's done.
+	slot := comp.Slots["stroke"]
+	for _, set := range []func(){
+		func() { slot.Set(model.ColorValue(color.Make(color.SRGB, 0, 1, 0, 1))) },
+		func() { slot.Set(model.ColorValue(color.Make(color.SRGB, 1, 0, 0, 1))) },
+		slot.Reset,
+	} {
+		set()
+		got := render(&r, comp, 0)
+		var fresh Renderer
+		if want := render(&fresh, comp, 0); !reflect.DeepEqual(got, want) {
+			v, _ := slot.Value()
+			t.Errorf("rendering with slot value %v used stale caches", v)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"honnef.co/go/gutter/fontdb"
+	"honnef.co/go/gutter/gfx"
+	"honnef.co/go/gutter/lottie/lottie_converter"
+	"honnef.co/go/gutter/text"
+)
+
+// Text that doesn't embed its glyphs and uses the Go font.
+const fontTextJSON = `{
+	"v": "5.8.1", "fr": 10, "ip": 0, "op": 10, "w": 100, "h": 100,
+	"fonts": {"list": [{"fName": "Go-Regular", "fFamily": "Go", "fStyle": "Regular", "ascent": 75}]},
+	"layers": [{
+		"ty": 5, "ind": 1, "ip": 0, "op": 10, "st": 0, "sr": 1,
+		"ks": {},
+		"t": {
+			"d": {"k": [{"t": 0, "s": {"f": "Go-Regular", "s": 20, "t": "Hi", "fc": [1, 0, 0], "j": 0, "tr": 0, "lh": 24}}]},
+			"a": [],
+			"m": {"g": 1, "a": {"a": 0, "k": [0, 0]}},
+			"p": {}
+		}
+	}]
+}`
+
+func TestFontText(t *testing.T) {
+	fonts := fontdb.NewEmpty()
+	if err := fonts.AddData("Go-Regular.ttf", goregular.TTF); err != nil {
+		t.Fatal(err)
+	}
+	r := Renderer{Fonts: fonts}
+	fills := commands[gfx.CommandFill](render(&r, convert(t, fontTextJSON, lottie_converter.Options{}), 0))
+	if len(fills) != 2 {
+		t.Fatalf("got %d fills, want 2", len(fills))
+	}
+	// The Go font has 2048 units per em, and H advances by 1479 units.
+	wantX := []float64{0, 1479 * 20 / 2048.0}
+	for i, fill := range fills {
+		tr := fill.Transform
+		if math.Abs(tr.N4-wantX[i]) > 1e-6 || tr.N5 != 0 {
+			t.Errorf("glyph %d is at (%g, %g), want (%g, 0)", i, tr.N4, tr.N5, wantX[i])
+		}
+	}
+	// The H is 1480 units tall.
+	var top float64
+	for _, p := range pathPoints(fills[0].Shape) {
+		top = min(top, p.Y)
+	}
+	if want := -1480 * 20 / 2048.0; math.Abs(top-want) > 0.01 {
+		t.Errorf("H reaches up to %g, want %g", top, want)
+	}
+}
+
+func TestParseFontStyle(t *testing.T) {
+	tests := []struct {
+		name   string
+		weight float64
+		style  text.FontStyle
+	}{
+		{"Regular", 400, text.FontStyleNormal},
+		{"Italic", 400, text.FontStyleItalic},
+		{"Bold", 700, text.FontStyleNormal},
+		{"Semibold Italic", 600, text.FontStyleItalic},
+		{"Extra-Light", 200, text.FontStyleNormal},
+		{"UltraBold Oblique", 800, text.FontStyleItalic},
+		{"Black", 900, text.FontStyleNormal},
+	}
+	for _, tt := range tests {
+		weight, style := parseFontStyle(tt.name)
+		if weight != tt.weight || style != tt.style {
+			t.Errorf("%q: got weight %g and style %v, want %g and %v", tt.name, weight, style, tt.weight, tt.style)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"testing"
+	"testing/fstest"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/gfx"
+	"honnef.co/go/gutter/lottie/lottie_converter"
+)
+
+const imageJSONTemplate = `{
+	"v": "5.8.1", "fr": 10, "ip": 0, "op": 10, "w": 100, "h": 100,
+	"assets": [
+		{"id": "embedded", "w": 40, "h": 20, "u": "", "p": %q, "e": 1},
+		{"id": "external", "w": 4, "h": 2, "u": "/images/", "p": "img_0.png", "e": 0}
+	],
+	"layers": [
+		{"ty": 2, "ind": 1, "ip": 0, "op": 10, "st": 0, "sr": 1, "refId": "embedded",
+			"ks": {}},
+		{"ty": 2, "ind": 2, "ip": 0, "op": 10, "st": 0, "sr": 1, "refId": "external",
+			"ks": {}}
+	]
+}`
+
+func TestImageLayer(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	dataURL := "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	fsys := fstest.MapFS{"images/img_0.png": {Data: buf.Bytes()}}
+
+	data := fmt.Sprintf(imageJSONTemplate, dataURL)
+	ccomp := convert(t, data, lottie_converter.Options{
+		ResolveImage: lottie_converter.FSImageResolver(fsys),
+	})
+	var r Renderer
+	var fills []gfx.CommandFill
+	for _, cmd := range commands[gfx.CommandFill](render(&r, ccomp, 0)) {
+		if _, ok := cmd.Paint.(*gfx.Image); ok {
+			fills = append(fills, cmd)
+		}
+	}
+	if len(fills) != 2 {
+		t.Fatalf("got %d image fills, want 2", len(fills))
+	}
+	// Layers render in reverse order. The embedded image gets scaled to the
+	// asset's size.
+	for i, want := range []curve.Affine{curve.Identity, curve.Scale(10, 10)} {
+		if got := fills[i].Transform; got != want {
+			t.Errorf("image %d: got transform %v, want %v", i, got, want)
+		}
+	}
+
+	// Without a resolver, external images don't render.
+	ccomp = convert(t, data, lottie_converter.Options{})
+	if ccomp.Images["external"].Image != nil {
+		t.Error("external image got loaded without a resolver")
+	}
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"honnef.co/go/color"
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/gfx"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+	"honnef.co/go/stuff/math/mathutil"
+)
+
+// The number of bands we use to approximate a feathered edge.
+const featherSteps = 8
+
+// pushMasks pushes layers that apply masks to everything drawn into them and
+// returns the number of layers it pushed.
+func (r *Renderer) pushMasks(
+	rec gfx.Recorder,
+	anim *model.Composition,
+	masks []model.Mask,
+	trans curve.Affine,
+	frame float64,
+) int {
+	if masksAreClips(masks, frame) {
+		// The common case of a single mask, or of masks that intersect each
+		// other, can be implemented with nested clips, which is a lot cheaper
+		// than blending masks.
+		rec.PushTransform(trans)
+		for i := range masks {
+			mask := &masks[i]
+			rec.PushLayer(gfx.Layer{
+				Opacity: float32(maskOpacity(mask, frame)),
+				// Recorders hold on to shapes, so every mask needs its own
+				// path.
+				Clip: mask.Geometry.Evaluate(frame, nil),
+			})
+		}
+		rec.PopTransform()
+		return len(masks)
+	}
+
+	// We combine all masks in a single luminance mask, where each pixel's
+	// brightness is the mask's value at that pixel. The mix functions then
+	// compute the mask modes: screening computes a union, multiplying an
+	// intersection, and so on.
+	//
+	// The layers are in the composition's coordinate space, which covers all
+	// of the visible content.
+	bounds := curve.Rect{X1: float64(anim.Width), Y1: float64(anim.Height)}
+	rec.PushLayer(gfx.Layer{Opacity: 1})
+	rec.PushLayer(gfx.Layer{Opacity: 1, LuminanceToAlpha: true})
+
+	// Masks that take away from the mask start out with everything visible.
+	var initial float64
+	switch masks[0].Mode {
+	case model.MaskModeSubtract, model.MaskModeIntersect, model.MaskModeDarken:
+		initial = 1
+	}
+	rec.Fill(bounds, gray(initial))
+
+	for i := range masks {
+		mask := &masks[i]
+		// The mask's values inside and outside of its shape.
+		in, out := maskOpacity(mask, frame), 0.0
+		if mask.Inverted {
+			in, out = out, in
+		}
+		var mix gfx.Mix
+		switch mask.Mode {
+		case model.MaskModeAdd:
+			mix = gfx.MixScreen
+		case model.MaskModeSubtract:
+			// Multiplying by the inverse removes the mask.
+			in, out = 1-in, 1-out
+			mix = gfx.MixMultiply
+		case model.MaskModeIntersect:
+			mix = gfx.MixMultiply
+		case model.MaskModeLighten:
+			mix = gfx.MixLighten
+		case model.MaskModeDarken:
+			mix = gfx.MixDarken
+		case model.MaskModeDifference:
+			mix = gfx.MixDifference
+		}
+
+		rec.PushLayer(gfx.Layer{BlendMode: gfx.BlendMode{Mix: mix}, Opacity: 1})
+		rec.Fill(bounds, gray(out))
+		rec.PushTransform(trans)
+		drawMask(rec, mask, frame, in, out)
+		rec.PopTransform()
+		rec.PopLayer()
+	}
+
+	rec.PopLayer()
+	rec.PushLayer(gfx.Layer{BlendMode: gfx.BlendMode{Compose: gfx.ComposeSrcIn}, Opacity: 1})
+	return 2
+}
+
+// masksAreClips reports whether masks can be implemented as nested clips at
+// the given frame.
+func masksAreClips(masks []model.Mask, frame float64) bool {
+	for i := range masks {
+		mask := &masks[i]
+		switch mask.Mode {
+		case model.MaskModeAdd:
+			if i != 0 {
+				return false
+			}
+		case model.MaskModeIntersect:
+		default:
+			return false
+		}
+		if mask.Inverted || mask.Expansion.Evaluate(frame) != 0 {
+			return false
+		}
+		if f := mask.Feather.Evaluate(frame); f.X != 0 || f.Y != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func maskOpacity(mask *model.Mask, frame float64) float64 {
+	return mathutil.Clamp(mask.Opacity.Evaluate(frame)/100.0, 0, 1)
+}
+
+// drawMask fills the mask's shape, taking expansion and feathering into
+// account. Inside of the shape, it draws the value in, fading to out across
+// the feathered edge.
+func drawMask(rec gfx.Recorder, mask *model.Mask, frame float64, in, out float64) {
+	path := mask.Geometry.Evaluate(frame, nil)
+	expansion := mask.Expansion.Evaluate(frame)
+	// TODO(dh): support different amounts of horizontal and vertical
+	// feathering.
+	f := mask.Feather.Evaluate(frame)
+	feather := max((f.X+f.Y)/2, 0)
+	if feather == 0 {
+		fillExpanded(rec, path, expansion, gray(in))
+		return
+	}
+
+	// Lacking blurs, we approximate the blurred edge with bands of increasing
+	// value, from the outside in, centered on the edge. The innermost band
+	// has the full value.
+	for i := range featherSteps {
+		t := (float64(i) + 0.5) / featherSteps
+		v := float64(i+1) / featherSteps
+		fillExpanded(rec, path, expansion+feather/2-feather*t, gray(out+(in-out)*v))
+	}
+}
+
+// fillExpanded fills path after growing it by offset, or shrinking it if
+// offset is negative.
+func fillExpanded(rec gfx.Recorder, path curve.BezPath, offset float64, paint gfx.Paint) {
+	switch {
+	case offset > 0:
+		rec.Fill(path, paint)
+		rec.Stroke(path, curve.DefaultStroke.WithWidth(2*offset), paint)
+	case offset < 0:
+		rec.PushLayer(gfx.Layer{Opacity: 1})
+		rec.Fill(path, paint)
+		rec.PushLayer(gfx.Layer{BlendMode: gfx.BlendMode{Compose: gfx.ComposeDestOut}, Opacity: 1})
+		rec.Stroke(path, curve.DefaultStroke.WithWidth(-2*offset), gray(1))
+		rec.PopLayer()
+		rec.PopLayer()
+	default:
+		rec.Fill(path, paint)
+	}
+}
+
+// gray returns an opaque gray whose luminance is v.
+func gray(v float64) gfx.Paint {
+	return gfx.Solid(color.Make(color.LinearSRGB, v, v, v, 1))
+}
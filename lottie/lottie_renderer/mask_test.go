@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"honnef.co/go/gutter/gfx"
+)
+
+const maskJSONTemplate = `{
+	"v": "5.8.1", "fr": 10, "ip": 0, "op": 10, "w": 100, "h": 100,
+	"layers": [
+		{"ty": 4, "ind": 1, "ip": 0, "op": 10, "st": 0, "sr": 1, "td": 1,
+			"ks": {}, "shapes": [%[1]s]},
+		{"ty": 4, "ind": 2, "ip": 0, "op": 10, "st": 0, "sr": 1, "tt": %[2]d,
+			"ks": {}, "shapes": [%[1]s], "masksProperties": [%[3]s]}
+	]
+}`
+
+const maskRect = `{"ty": "gr", "it": [
+	{"ty": "rc", "p": {"a": 0, "k": [50, 50]}, "s": {"a": 0, "k": [50, 50]}, "r": {"a": 0, "k": 0}},
+	{"ty": "fl", "c": {"a": 0, "k": [1, 1, 1]}, "o": {"a": 0, "k": 100}}
+]}`
+
+func mask(mode string, extra string) string {
+	return fmt.Sprintf(`{"mode": %q, "o": {"a": 0, "k": 50}, "pt": {"a": 0, "k": {"c": true, "v": [[0, 0], [10, 0], [10, 10]], "i": [[0, 0], [0, 0], [0, 0]], "o": [[0, 0], [0, 0], [0, 0]]}}%s}`, mode, extra)
+}
+
+// A white layer covering the whole composition, with masks.
+const maskedJSONTemplate = `{
+	"v": "5.8.1", "fr": 10, "ip": 0, "op": 10, "w": 100, "h": 10,
+	"layers": [
+		{"ty": 4, "ind": 1, "ip": 0, "op": 10, "st": 0, "sr": 1,
+			"ks": {}, "masksProperties": [%s], "shapes": [
+				{"ty": "rc", "p": {"a": 0, "k": [50, 5]}, "s": {"a": 0, "k": [100, 10]}, "r": {"a": 0, "k": 0}},
+				{"ty": "fl", "c": {"a": 0, "k": [1, 1, 1]}, "o": {"a": 0, "k": 100}}
+			]}
+	]
+}`
+
+// rectMask returns a mask that covers the columns [x0, x1) with the given
+// opacity.
+func rectMask(mode string, x0, x1, opacity float64, extra string) string {
+	return fmt.Sprintf(`{"mode": %q, "o": {"a": 0, "k": %g}, "pt": {"a": 0, "k": {"c": true, "v": [[%[3]g, -50], [%[4]g, -50], [%[4]g, 50], [%[3]g, 50]], "i": [[0, 0], [0, 0], [0, 0], [0, 0]], "o": [[0, 0], [0, 0], [0, 0], [0, 0]]}}%[5]s}`,
+		mode, opacity, x0, x1, extra)
+}
+
+func renderLayers(t *testing.T, matte int, masks string) []gfx.Layer {
+	t.Helper()
+	rec := renderJSON(t, fmt.Sprintf(maskJSONTemplate, maskRect, matte, masks), 0)
+	var out []gfx.Layer
+	for _, cmd := range commands[gfx.CommandPushLayer](rec) {
+		out = append(out, cmd.Layer)
+	}
+	// Skip the composition's layer.
+	return out[1:]
+}
+
+func TestMatte(t *testing.T) {
+	tests := []struct {
+		mode      int
+		luminance bool
+		compose   gfx.Compose
+	}{
+		{1, false, gfx.ComposeSrcIn},
+		{2, false, gfx.ComposeSrcOut},
+		{3, true, gfx.ComposeSrcIn},
+		{4, true, gfx.ComposeSrcOut},
+	}
+	for _, tt := range tests {
+		// The matted layer, the matte group, the matte, the matte layer, and
+		// the layer that gets matted.
+		layers := renderLayers(t, tt.mode, "")
+		if len(layers) != 5 {
+			t.Fatalf("mode %d: got %d layers, want 5", tt.mode, len(layers))
+		}
+		if got := layers[2].LuminanceToAlpha; got != tt.luminance {
+			t.Errorf("mode %d: got luminance %t, want %t", tt.mode, got, tt.luminance)
+		}
+		if got := layers[4].BlendMode.Compose; got != tt.compose {
+			t.Errorf("mode %d: got compose %s, want %s", tt.mode, got, tt.compose)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	// A single mask is a clip.
+	layers := renderLayers(t, 0, mask("a", ""))
+	if len(layers) != 2 || layers[1].Clip == nil || layers[1].Opacity != 0.5 {
+		t.Errorf("single mask didn't produce a clip layer with opacity 0.5: %v", layers)
+	}
+
+	// Other combinations of masks are composited.
+	layers = renderLayers(t, 0, mask("a", "")+","+mask("s", `, "inv": true`)+","+mask("f", `, "x": {"a": 0, "k": 2}`))
+	var mixes []gfx.Mix
+	var luminance bool
+	for _, l := range layers {
+		luminance = luminance || l.LuminanceToAlpha
+		if l.BlendMode.Mix != gfx.MixNormal {
+			mixes = append(mixes, l.BlendMode.Mix)
+		}
+	}
+	if !luminance {
+		t.Error("masks weren't combined in a luminance mask")
+	}
+	want := []gfx.Mix{gfx.MixScreen, gfx.MixMultiply, gfx.MixDifference}
+	if fmt.Sprint(mixes) != fmt.Sprint(want) {
+		t.Errorf("got mixes %v, want %v", mixes, want)
+	}
+	if last := layers[len(layers)-1]; last.BlendMode.Compose != gfx.ComposeSrcIn {
+		t.Errorf("layer content is composited with %s, want SrcIn", last.BlendMode.Compose)
+	}
+}
+
+func TestMaskCoverage(t *testing.T) {
+	a := func(extra string) string { return rectMask("a", 0, 50, 100, extra) }
+	tests := []struct {
+		name  string
+		masks []string
+		// The alpha at x = 10, 40, 60, and 90.
+		want [4]float64
+	}{
+		{"add", []string{a("")}, [4]float64{1, 1, 0, 0}},
+		{"add twice", []string{a(""), rectMask("a", 25, 75, 100, "")}, [4]float64{1, 1, 1, 0}},
+		{"add transparent", []string{a(""), rectMask("a", 25, 75, 50, "")}, [4]float64{1, 1, 0.5, 0}},
+		{"subtract", []string{a(""), rectMask("s", 25, 75, 100, "")}, [4]float64{1, 0, 0, 0}},
+		{"intersect", []string{a(""), rectMask("i", 25, 75, 100, "")}, [4]float64{0, 1, 0, 0}},
+		{"intersect transparent", []string{a(""), rectMask("i", 25, 75, 50, "")}, [4]float64{0, 0.5, 0, 0}},
+		{"inverted", []string{rectMask("a", 0, 50, 50, `, "inv": true`)}, [4]float64{0, 0, 0.5, 0.5}},
+		{"subtract inverted", []string{a(""), rectMask("s", 25, 75, 50, `, "inv": true`)}, [4]float64{0.5, 1, 0, 0}},
+		{"intersect inverted", []string{a(""), rectMask("i", 25, 75, 100, `, "inv": true`)}, [4]float64{1, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := renderJSON(t, fmt.Sprintf(maskedJSONTemplate, strings.Join(tt.masks, ",")), 0)
+			pixels := rasterize(rec, 100, 10)
+			for i, x := range []int{10, 40, 60, 90} {
+				if got := pixels[5*100+x][3]; math.Abs(float64(got)-tt.want[i]) > 0.01 {
+					t.Errorf("alpha at x = %d is %g, want %g", x, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMaskFeather(t *testing.T) {
+	rec := renderJSON(t, fmt.Sprintf(maskedJSONTemplate, rectMask("a", 0, 50, 100, `, "f": {"a": 0, "k": [20, 20]}`)), 0)
+	pixels := rasterize(rec, 100, 10)
+	alpha := func(x int) float64 { return float64(pixels[5*100+x][3]) }
+	if got := alpha(30); got < 0.99 {
+		t.Errorf("alpha inside of the feathered edge is %g, want 1", got)
+	}
+	if got := alpha(70); got > 0.01 {
+		t.Errorf("alpha outside of the feathered edge is %g, want 0", got)
+	}
+	// The edge fades out, centered on the mask's edge.
+	for x := 41; x < 60; x++ {
+		if alpha(x) > alpha(x-1) {
+			t.Errorf("alpha increases from %g to %g at x = %d", alpha(x-1), alpha(x), x)
+		}
+	}
+	if got := alpha(50); math.Abs(got-0.5) > 0.1 {
+		t.Errorf("alpha at the edge is %g, want about 0.5", got)
+	}
+}
@@ -7,17 +7,69 @@ package lottie_renderer
 
 import (
 	"fmt"
+	"math"
+	"slices"
 
 	"honnef.co/go/curve"
 	"honnef.co/go/gutter/animation"
+	"honnef.co/go/gutter/fontdb"
 	"honnef.co/go/gutter/gfx"
 	model "honnef.co/go/gutter/lottie/lottie_model"
+	"honnef.co/go/gutter/text"
 	"honnef.co/go/stuff/container/maybe"
 )
 
+// Renderer renders compositions. It caches the rendering of layers that
+// don't change over time, as well as other data derived from the
+// composition. The caches are discarded when rendering a different
+// composition or when the values of the composition's slots change, and have
+// to be discarded with [Renderer.Reset] after modifying a composition in
+// other ways.
 type Renderer struct {
-	batch        batch
-	maskElements curve.BezPath
+	// Fonts is the font database used for text layers that don't embed their
+	// glyphs. If nil, paint.DefaultFonts is used.
+	Fonts *fontdb.Faces
+	// FrameCacheSize is the number of bytes to use for caching whole frames,
+	// which benefits short, looping animations. If it is zero, frames aren't
+	// cached. When caching frames, the renderer rounds frame numbers to whole
+	// frames, rendering the animation at its native frame rate.
+	FrameCacheSize int
+
+	batch       batch
+	fontLoader  text.FontLoader
+	textLayouts map[*model.TextDocument]*textLayout
+
+	// The composition that the caches belong to, and the values that its
+	// slots had been set to.
+	anim  *model.Composition
+	slots map[*model.Slot]model.SlotValue
+	// Whether layers are static, and the recordings of static layers.
+	static     map[*model.Layer]bool
+	recordings map[*model.Layer]gfx.Recording
+	frames     frameCache
+}
+
+// Reset discards all cached data.
+func (r *Renderer) Reset() {
+	r.anim = nil
+	r.slots = nil
+	r.textLayouts = nil
+	r.static = nil
+	r.recordings = nil
+	r.frames.reset()
+}
+
+// slotsChanged reports whether the values of the composition's slots differ
+// from the ones the caches were computed with.
+func (r *Renderer) slotsChanged(anim *model.Composition) bool {
+	for _, slot := range anim.Slots {
+		v, ok := slot.Value()
+		old, oldOk := r.slots[slot]
+		if ok != oldOk || v != old {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *Renderer) Render(
@@ -26,6 +78,18 @@ func (r *Renderer) Render(
 	alpha float64,
 	rec gfx.Recorder,
 ) {
+	if anim != r.anim || r.slotsChanged(anim) {
+		r.Reset()
+		r.anim = anim
+		for _, slot := range anim.Slots {
+			if v, ok := slot.Value(); ok {
+				if r.slots == nil {
+					r.slots = make(map[*model.Slot]model.SlotValue)
+				}
+				r.slots[slot] = v
+			}
+		}
+	}
 	rec = rec.Checkpoint()
 	r.batch.reset(r)
 
@@ -40,8 +104,24 @@ func (r *Renderer) Render(
 	})
 	defer rec.PopLayer()
 
+	if r.FrameCacheSize <= 0 {
+		r.renderLayers(anim, frame, rec)
+		return
+	}
+	frame = math.Round(frame)
+	recording, ok := r.frames.get(frame)
+	if !ok {
+		sub := gfx.NewRecorder()
+		r.renderLayers(anim, frame, sub)
+		recording = sub.Finish()
+		r.frames.add(frame, recording, r.FrameCacheSize)
+	}
+	replay(rec, recording)
+}
+
+func (r *Renderer) renderLayers(anim *model.Composition, frame float64, rec gfx.Recorder) {
 	for i := len(anim.Layers) - 1; i >= 0; i-- {
-		layer := anim.Layers[i]
+		layer := &anim.Layers[i]
 		if layer.IsMask {
 			continue
 		}
@@ -59,7 +139,7 @@ func (r *Renderer) Render(
 func (r *Renderer) renderLayer(
 	anim *model.Composition,
 	layerSet []model.Layer,
-	layer model.Layer,
+	layer *model.Layer,
 	trans curve.Affine,
 	frame float64,
 	rec gfx.Recorder,
@@ -68,6 +148,33 @@ func (r *Renderer) renderLayer(
 		return
 	}
 
+	if !r.isStatic(layerSet, layer) {
+		r.renderLayerContents(anim, layerSet, layer, trans, frame, rec)
+		return
+	}
+	recording, ok := r.recordings[layer]
+	if !ok {
+		sub := gfx.NewRecorder()
+		r.renderLayerContents(anim, layerSet, layer, curve.Identity, frame, sub)
+		recording = sub.Finish()
+		if r.recordings == nil {
+			r.recordings = make(map[*model.Layer]gfx.Recording)
+		}
+		r.recordings[layer] = recording
+	}
+	rec.PushTransform(trans)
+	replay(rec, recording)
+	rec.PopTransform()
+}
+
+func (r *Renderer) renderLayerContents(
+	anim *model.Composition,
+	layerSet []model.Layer,
+	layer *model.Layer,
+	trans curve.Affine,
+	frame float64,
+	rec gfx.Recorder,
+) {
 	alpha := layer.Opacity.Evaluate(frame) / 100.0
 	if alpha == 0 {
 		return
@@ -77,44 +184,41 @@ func (r *Renderer) renderLayer(
 	defer rec.PopLayer()
 
 	parentTransform := trans
-	trans = r.computeTransform(layerSet, layer, parentTransform, frame)
-	if maskIndex, ok := layer.MaskLayerID.Get(); ok {
-		if mode, ok := layer.MaskLayerMode.Get(); ok {
-			// OPT(dh): Can this layer be pushed into the branch that follows?
-			rec.PushLayer(gfx.Layer{
-				Opacity: 1,
-			})
-			defer rec.PopLayer()
-
-			if maskIndex >= 0 && maskIndex < len(layerSet) {
-				r.renderLayer(
-					anim,
-					layerSet,
-					layerSet[maskIndex],
-					parentTransform,
-					frame,
-					rec,
-				)
-			}
+	trans = r.computeTransform(layerSet, *layer, parentTransform, frame)
+	if matte, ok := layer.Matte.Get(); ok {
+		// OPT(dh): Can this layer be pushed into the branch that follows?
+		rec.PushLayer(gfx.Layer{
+			Opacity: 1,
+		})
+		defer rec.PopLayer()
 
-			rec.PushLayer(gfx.Layer{BlendMode: mode, Opacity: 1})
-			defer rec.PopLayer()
+		if matte.Layer >= 0 && matte.Layer < len(layerSet) {
+			luma := matte.Mode == model.MatteModeLuma || matte.Mode == model.MatteModeInvertedLuma
+			rec.PushLayer(gfx.Layer{Opacity: 1, LuminanceToAlpha: luma})
+			r.renderLayer(
+				anim,
+				layerSet,
+				&layerSet[matte.Layer],
+				parentTransform,
+				frame,
+				rec,
+			)
+			rec.PopLayer()
 		}
+
+		mode := gfx.BlendMode{Compose: gfx.ComposeSrcIn}
+		if matte.Mode == model.MatteModeInvertedAlpha || matte.Mode == model.MatteModeInvertedLuma {
+			mode.Compose = gfx.ComposeSrcOut
+		}
+		rec.PushLayer(gfx.Layer{BlendMode: mode, Opacity: 1})
+		defer rec.PopLayer()
 	}
-	for _, mask := range layer.Masks {
-		alpha := mask.Opacity.Evaluate(frame) / 100.0
-		r.maskElements = mask.Geometry.Evaluate(frame, r.maskElements)
+	var maskLayers int
+	if len(layer.Masks) > 0 {
 		// TODO(dh): surely we can push the transformation once and then keep it
 		// on the stack? and then avoid having to manually apply it in all the
 		// places where we do drawing?
-		rec.PushTransform(trans)
-		rec.PushLayer(gfx.Layer{
-			BlendMode: mask.Mode,
-			Opacity:   float32(alpha),
-			Clip:      r.maskElements,
-		})
-		rec.PopTransform()
-		r.maskElements = r.maskElements[:0]
+		maskLayers = r.pushMasks(rec, anim, layer.Masks, trans, frame)
 	}
 	switch layer.Content.Kind {
 	case model.ContentKindNone:
@@ -140,7 +244,7 @@ func (r *Renderer) renderLayer(
 			rec.PopTransform()
 
 			for i := len(assetLayers) - 1; i >= 0; i-- {
-				assetLayer := assetLayers[i]
+				assetLayer := &assetLayers[i]
 				if assetLayer.IsMask {
 					continue
 				}
@@ -158,13 +262,35 @@ func (r *Renderer) renderLayer(
 		r.renderShapes(rec, layer.Content.Shapes, trans, frame)
 		r.batch.render(rec, &r.batch.draws[0])
 		r.batch.reset(r)
+	case model.ContentKindImage:
+		if img, ok := anim.Images[layer.Content.Image]; ok && img.Image != nil {
+			renderImage(rec, img, trans)
+		}
+	case model.ContentKindText:
+		r.renderText(rec, anim, &layer.Content.Text, trans, frame)
 	}
 
-	for range len(layer.Masks) {
+	for range maskLayers {
 		rec.PopLayer()
 	}
 }
 
+// renderImage draws an image asset at the origin, scaled to the asset's size.
+func renderImage(rec gfx.Recorder, img model.Image, trans curve.Affine) {
+	size := img.Image.Bounds().Size()
+	if size.X == 0 || size.Y == 0 {
+		return
+	}
+	w, h := float64(size.X), float64(size.Y)
+	scale := curve.Identity
+	if img.Width > 0 && img.Height > 0 {
+		scale = curve.Scale(img.Width/w, img.Height/h)
+	}
+	rec.PushTransform(trans.Mul(scale))
+	rec.Fill(curve.Rect{X1: w, Y1: h}, &gfx.Image{Image: img.Image})
+	rec.PopTransform()
+}
+
 func (r *Renderer) renderShapes(
 	rec gfx.Recorder,
 	shapes []model.Shape,
@@ -190,14 +316,15 @@ func (r *Renderer) renderShapes(
 			r.renderShapes(rec, shape.GroupShapes, trans.Mul(groupTransform), frame)
 			r.batch.popGroup()
 		case model.ShapeKindGeometry:
-			r.batch.pushGeometry(&shape.Geometry, trans, frame)
+			r.batch.pushGeometry(&shape.Geometry, trans, frame, geometryStart)
 		case model.ShapeKindDraw:
 			r.batch.pushDraw(shape.Draw, geometryStart, frame)
+		case model.ShapeKindTrim:
+			trim := shape.Trim.Evaluate(frame)
+			r.batch.trim(&trim, geometryStart)
 		case model.ShapeKindRepeater:
-			_ = drawStart
-			panic("TODO")
-			// repeater := shape.Repeater.Evaluate(frame)
-			// r.batch.repeat(&repeater, geometryStart, drawStart)
+			repeater := shape.Repeater.Evaluate(frame)
+			r.batch.repeat(&repeater, trans, geometryStart, drawStart)
 		}
 	}
 }
@@ -249,6 +376,7 @@ type drawData struct {
 	stroke   maybe.Option[curve.Stroke]
 	brush    gfx.Paint
 	alpha    float64
+	fillRule gfx.FillRule
 	geometry [2]int
 
 	parent     int
@@ -261,10 +389,17 @@ func newDrawData(draw model.Draw, geometry [2]int, frame float64) drawData {
 		kind: drawDataDraw,
 		stroke: maybe.Map(
 			draw.Stroke,
-			func(stroke animation.KeyframedStroke) curve.Stroke { return stroke.Evaluate(frame) },
+			func(stroke animation.KeyframedStroke) curve.Stroke {
+				s := stroke.Evaluate(frame)
+				if dash, ok := draw.Dash.Get(); ok {
+					s = dash.Evaluate(frame, s)
+				}
+				return s
+			},
 		),
 		brush:    draw.Brush.Evaluate(1, frame),
 		alpha:    draw.Opacity.Evaluate(frame) / 100.0,
+		fillRule: draw.FillRule,
 		geometry: geometry,
 	}
 }
@@ -272,19 +407,24 @@ func newDrawData(draw model.Draw, geometry [2]int, frame float64) drawData {
 type geometryData struct {
 	elements  [2]int
 	transform curve.Affine
+	// If non-zero, the geometry has been replaced by a trimmed copy and
+	// draws with an index of at least supersededAt don't use it.
+	supersededAt int
 }
 
 type batch struct {
 	elements   curve.BezPath
 	geometries []geometryData
-	// repeatGeometries []GeometryData
-	// repeatDraws      []DrawData
 	// Length of geometries at time of most recent draw. This is used to prevent
 	// merging into already used geometries.
 	drawnGeometry int
 	root          drawData
 	draws         []drawData
 	curGroup      int
+
+	// Scratch space for trimming paths.
+	segments []curve.PathSegment
+	lengths  []float64
 }
 
 func (b *batch) pushGroup(opacity float64) {
@@ -302,12 +442,14 @@ func (b *batch) popGroup() {
 	b.curGroup = b.draws[b.curGroup].parent
 }
 
-func (b *batch) pushGeometry(geometry *model.Geometry, transform curve.Affine, frame float64) {
-	// Merge with the previous geometry if possible. There are two
+func (b *batch) pushGeometry(geometry *model.Geometry, transform curve.Affine, frame float64, geometryStart int) {
+	// Merge with the previous geometry if possible. There are three
 	// conditions:
 	// 1. The previous geometry has not yet been referenced by a draw
-	// 2. The geometries have the same transform
-	if b.drawnGeometry < len(b.geometries) && b.geometries[len(b.geometries)-1].transform == transform {
+	// 2. The previous geometry wasn't pushed before the current group
+	//    started, as the group's draws only use geometries pushed since
+	// 3. The geometries have the same transform
+	if max(b.drawnGeometry, geometryStart) < len(b.geometries) && b.geometries[len(b.geometries)-1].transform == transform {
 		b.elements = geometry.Evaluate(frame, b.elements)
 		b.geometries[len(b.geometries)-1].elements[1] = len(b.elements)
 	} else {
@@ -327,8 +469,74 @@ func (b *batch) pushDraw(draw model.Draw, geometryStart int, frame float64) {
 	b.drawnGeometry = len(b.geometries)
 }
 
-func (b *batch) repeat(repeater *model.Repeater, geometryStart int, drawStart int) {
-	panic("TODO")
+// repeat replaces the geometries and draws that have been pushed to the
+// current group since geometryStart and drawStart with the repeater's copies
+// of them. trans is the group's transform.
+func (b *batch) repeat(repeater *model.FixedRepeater, trans curve.Affine, geometryStart int, drawStart int) {
+	// Everything pushed since drawStart belongs to the current group and is
+	// stored contiguously at the end of b.draws. This includes the contents
+	// of nested groups, which we copy along with their draws.
+	topLevel := slices.Clone(b.draws[b.curGroup].children[drawStart:])
+	b.draws[b.curGroup].children = b.draws[b.curGroup].children[:drawStart]
+	firstDraw := len(b.draws)
+	if len(topLevel) > 0 {
+		firstDraw = int(topLevel[0])
+	}
+	lastDraw := len(b.draws)
+	geometryEnd := len(b.geometries)
+
+	// The repeater's transform applies in the group's coordinate space, but
+	// geometries store their full transforms.
+	inv := trans.Invert()
+	copies := max(repeater.Copies, 0)
+	for n := range copies {
+		// Draws earlier in a group's list of children are drawn on top.
+		i := n
+		if repeater.Composite != model.RepeaterCompositeBelow {
+			i = copies - 1 - n
+		}
+		alpha := repeater.Opacity(i)
+		aff := trans.Mul(repeater.Transform(i)).Mul(inv)
+
+		geometryBase := len(b.geometries)
+		drawBase := len(b.draws)
+		remapDraw := func(idx int) int { return idx - firstDraw + drawBase }
+		for _, g := range b.geometries[geometryStart:geometryEnd] {
+			g.transform = aff.Mul(g.transform)
+			if g.supersededAt != 0 {
+				g.supersededAt = remapDraw(max(g.supersededAt, firstDraw))
+			}
+			b.geometries = append(b.geometries, g)
+		}
+		for _, d := range b.draws[firstDraw:lastDraw] {
+			switch d.kind {
+			case drawDataDraw:
+				d.geometry[0] += geometryBase - geometryStart
+				d.geometry[1] += geometryBase - geometryStart
+				d.alpha *= alpha
+			case drawDataGroup:
+				if d.parent != b.curGroup {
+					d.parent = remapDraw(d.parent)
+				}
+				d.children = slices.Clone(d.children)
+				for j, child := range d.children {
+					d.children[j] = int32(remapDraw(int(child)))
+				}
+			}
+			b.draws = append(b.draws, d)
+		}
+		for _, child := range topLevel {
+			b.draws[b.curGroup].children = append(b.draws[b.curGroup].children, int32(remapDraw(int(child))))
+		}
+	}
+
+	// Draws that follow the repeater use the copies, not the originals.
+	for i := geometryStart; i < geometryEnd; i++ {
+		if b.geometries[i].supersededAt == 0 {
+			b.geometries[i].supersededAt = len(b.draws)
+		}
+	}
+	b.drawnGeometry = len(b.geometries)
 }
 
 func (b *batch) render(rec gfx.Recorder, group *drawData) {
@@ -343,11 +551,15 @@ func (b *batch) render(rec gfx.Recorder, group *drawData) {
 
 	// Process all draws in reverse
 	for i := len(group.children) - 1; i >= 0; i-- {
-		draw := &b.draws[group.children[i]]
+		drawIdx := int(group.children[i])
+		draw := &b.draws[drawIdx]
 		switch draw.kind {
 		case drawDataDraw:
 			brush := model.BrushWithAlpha(draw.brush, draw.alpha)
 			for _, geometry := range b.geometries[draw.geometry[0]:draw.geometry[1]] {
+				if geometry.supersededAt != 0 && drawIdx >= geometry.supersededAt {
+					continue
+				}
 				path := b.elements[geometry.elements[0]:geometry.elements[1]]
 				transform := geometry.transform
 				if stroke, ok := draw.stroke.Get(); ok {
@@ -360,7 +572,9 @@ func (b *batch) render(rec gfx.Recorder, group *drawData) {
 					}
 				} else {
 					rec.PushTransform(transform)
+					oldRule := rec.SetFillRule(draw.fillRule)
 					rec.Fill(path, brush)
+					rec.SetFillRule(oldRule)
 					rec.PopTransform()
 				}
 			}
@@ -385,6 +599,5 @@ func (b *batch) reset(r *Renderer) {
 		groupAlpha: 1,
 	}
 	b.curGroup = 0
-	// b.repeatGeometries = b.repeatGeometries[:0]
 	b.drawnGeometry = 0
 }
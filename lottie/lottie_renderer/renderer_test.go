@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/gfx"
+	"honnef.co/go/gutter/internal/sparse"
+	"honnef.co/go/gutter/lottie/lottie_converter"
+	"honnef.co/go/gutter/lottie/lottie_encoding"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+)
+
+func convert(t *testing.T, data string, opts lottie_converter.Options) *model.Composition {
+	t.Helper()
+	comp, err := lottie_encoding.Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ccomp, err := lottie_converter.ConvertAnimation(comp, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ccomp
+}
+
+func render(r *Renderer, comp *model.Composition, frame float64) gfx.Recording {
+	rec := gfx.NewRecorder()
+	r.Render(comp, frame, 1, rec)
+	return rec.Finish()
+}
+
+// renderJSON renders a frame of an animation with a new renderer.
+func renderJSON(t *testing.T, data string, frame float64) gfx.Recording {
+	t.Helper()
+	var r Renderer
+	return render(&r, convert(t, data, lottie_converter.Options{}), frame)
+}
+
+// commands returns the recording's commands of type T.
+func commands[T gfx.Command](rec gfx.Recording) []T {
+	var out []T
+	for _, cmd := range rec {
+		if cmd, ok := cmd.(T); ok {
+			out = append(out, cmd)
+		}
+	}
+	return out
+}
+
+// rasterize renders a recording to pixels with premultiplied alpha.
+func rasterize(rec gfx.Recording, width, height int) []gfx.PlainColor {
+	r := sparse.NewRenderer(uint16(width), uint16(height))
+	sparse.PlayRecording(rec, r, curve.Identity)
+	out := make([]gfx.PlainColor, width*height)
+	r.Render(&sparse.PackerFloat32{Out: out, Width: width, Height: height})
+	return out
+}
+
+func pathPoints(shape gfx.Shape) []curve.Point {
+	var out []curve.Point
+	for el := range shape.PathElements(0.1) {
+		switch el.Kind {
+		case curve.MoveToKind, curve.LineToKind:
+			out = append(out, el.P0)
+		case curve.QuadToKind:
+			out = append(out, el.P1)
+		case curve.CubicToKind:
+			out = append(out, el.P2)
+		}
+	}
+	return out
+}
+
+func equalPoints(a, b []curve.Point) bool {
+	return slices.EqualFunc(a, b, func(p, q curve.Point) bool {
+		return math.Abs(p.X-q.X) < 1e-6 && math.Abs(p.Y-q.Y) < 1e-6
+	})
+}
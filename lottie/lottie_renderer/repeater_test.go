@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"math"
+	"testing"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/gfx"
+)
+
+// A stroked line repeated three times, fading out, followed by a filled
+// square drawn as a polygon, which the repeater doesn't affect.
+const repeaterJSON = `{
+	"v": "5.8.1", "fr": 10, "ip": 0, "op": 10, "w": 200, "h": 200,
+	"layers": [{
+		"ty": 4, "ind": 1, "ip": 0, "op": 10, "st": 0, "sr": 1,
+		"ks": {},
+		"shapes": [
+			{"ty": "gr", "it": [
+				{"ty": "sh", "ks": {"a": 0, "k": {"c": false, "v": [[0, 0], [0, 100]], "i": [[0, 0], [0, 0]], "o": [[0, 0], [0, 0]]}}},
+				{"ty": "st", "c": {"a": 0, "k": [1, 0, 0]}, "o": {"a": 0, "k": 100}, "w": {"a": 0, "k": 2}},
+				{"ty": "rp", "c": {"a": 0, "k": 3}, "o": {"a": 0, "k": 0}, "m": 1,
+					"tr": {
+						"a": {"a": 0, "k": [0, 0]},
+						"p": {"a": 0, "k": [10, 0]},
+						"s": {"a": 0, "k": [100, 100]},
+						"r": {"a": 0, "k": 0},
+						"so": {"a": 0, "k": 100},
+						"eo": {"a": 0, "k": 50}
+					}}
+			]},
+			{"ty": "gr", "it": [
+				{"ty": "sr", "sy": 2, "d": 1,
+					"p": {"a": 0, "k": [50, 50]},
+					"or": {"a": 0, "k": 10},
+					"os": {"a": 0, "k": 0},
+					"r": {"a": 0, "k": 0},
+					"pt": {"a": 0, "k": 4}},
+				{"ty": "fl", "c": {"a": 0, "k": [0, 0, 1]}, "o": {"a": 0, "k": 100}}
+			]}
+		]
+	}]
+}`
+
+func TestRepeater(t *testing.T) {
+	rec := renderJSON(t, repeaterJSON, 0)
+	strokes := commands[gfx.CommandStroke](rec)
+	fills := commands[gfx.CommandFill](rec)
+
+	// Later copies are composited above earlier ones, so they are drawn
+	// later.
+	if len(strokes) != 3 {
+		t.Fatalf("got %d strokes, want 3", len(strokes))
+	}
+	for i, s := range strokes {
+		want := curve.Translate(curve.Vec(10*float64(i), 0))
+		if s.Transform != want {
+			t.Errorf("copy %d has transform %v, want %v", i, s.Transform, want)
+		}
+		alpha := s.Paint.(gfx.Solid).Values[3]
+		if want := 1 - 0.25*float64(i); math.Abs(alpha-want) > 1e-9 {
+			t.Errorf("copy %d has alpha %g, want %g", i, alpha, want)
+		}
+	}
+
+	if len(fills) != 1 {
+		t.Fatalf("got %d fills, want 1", len(fills))
+	}
+	got := pathPoints(fills[0].Shape)
+	want := []curve.Point{curve.Pt(50, 40), curve.Pt(60, 50), curve.Pt(50, 60), curve.Pt(40, 50), curve.Pt(50, 40)}
+	if !equalPoints(got, want) {
+		t.Errorf("polygon has points %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,509 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"honnef.co/go/color"
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/fontdb"
+	"honnef.co/go/gutter/gfx"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+	"honnef.co/go/gutter/paint"
+	"honnef.co/go/gutter/text"
+	"honnef.co/go/stuff/container/maybe"
+)
+
+// smallCapsScale is the size of synthesized small capitals, relative to the
+// font size. It's only used for embedded glyphs, fonts use their own small
+// capitals.
+const smallCapsScale = 0.7
+
+// textLayout is a text document that has been laid out, before text ranges
+// get applied.
+type textLayout struct {
+	// The text that was laid out. Slots can change the text of documents.
+	text   string
+	glyphs []textGlyph
+	// The number of units of text, indexed by model.TextBasis.
+	units [4]int
+}
+
+type textGlyph struct {
+	// The glyph's outline, relative to its origin.
+	outline curve.BezPath
+	// The glyph's origin on the baseline, in the layer's coordinate space.
+	origin  curve.Point
+	advance float64
+	space   bool
+	// The index of the glyph's first character.
+	char int
+	// The unit that the glyph belongs to, for each text basis. Spaces don't
+	// belong to a unit when spaces are excluded and use -1.
+	units [4]int
+}
+
+type textLine struct {
+	glyphs []textGlyph
+	// Whether the line ends a paragraph, instead of having been wrapped.
+	last bool
+}
+
+// width returns the width of the line, ignoring trailing spaces.
+func (l *textLine) width() float64 {
+	for i := len(l.glyphs) - 1; i >= 0; i-- {
+		if g := &l.glyphs[i]; !g.space {
+			return g.origin.X + g.advance - l.glyphs[0].origin.X
+		}
+	}
+	return 0
+}
+
+func (r *Renderer) fonts() *fontdb.Faces {
+	if r.Fonts != nil {
+		return r.Fonts
+	}
+	return paint.DefaultFonts()
+}
+
+func (r *Renderer) renderText(
+	rec gfx.Recorder,
+	anim *model.Composition,
+	txt *model.Text,
+	trans curve.Affine,
+	frame float64,
+) {
+	doc := txt.Document(frame)
+	if doc == nil {
+		return
+	}
+	layout := r.layoutText(anim, doc)
+
+	ranges := make([]model.FixedTextRange, len(txt.Ranges))
+	for i := range txt.Ranges {
+		rng := &txt.Ranges[i]
+		ranges[i] = rng.Evaluate(frame, layout.units[rng.Basis], i)
+	}
+
+	// Tracking applied by ranges moves all following glyphs on the same
+	// line.
+	//
+	// TODO(dh): After Effects applies tracking before aligning lines, which
+	// we don't do.
+	var shift float64
+	line := -1
+	for i := range layout.glyphs {
+		g := &layout.glyphs[i]
+		if g.units[model.TextBasisLines] != line {
+			line = g.units[model.TextBasisLines]
+			shift = 0
+		}
+
+		fill, stroke := doc.Fill, doc.Stroke
+		strokeWidth := doc.StrokeWidth
+		opacity, fillOpacity, strokeOpacity := 1.0, 1.0, 1.0
+		scale := curve.Vec(1, 1)
+		var position, anchor curve.Vec2
+		var rotation, tracking float64
+		for j := range ranges {
+			rng := &ranges[j]
+			unit := g.units[rng.Basis]
+			if unit < 0 {
+				continue
+			}
+			c := rng.Coverage(unit)
+			if c == 0 {
+				continue
+			}
+			position = position.Add(rng.Position.Mul(c))
+			anchor = anchor.Add(curve.Vec2(rng.Anchor).Mul(c))
+			rotation += rng.Rotation * c
+			tracking += rng.Tracking * c
+			if s, ok := rng.Scale.Get(); ok {
+				scale.X *= 1 + (s.X/100-1)*c
+				scale.Y *= 1 + (s.Y/100-1)*c
+			}
+			if o, ok := rng.Opacity.Get(); ok {
+				opacity *= 1 + (o/100-1)*c
+			}
+			if o, ok := rng.FillOpacity.Get(); ok {
+				fillOpacity *= 1 + (o/100-1)*c
+			}
+			if o, ok := rng.StrokeOpacity.Get(); ok {
+				strokeOpacity *= 1 + (o/100-1)*c
+			}
+			if w, ok := rng.StrokeWidth.Get(); ok {
+				strokeWidth += (w - strokeWidth) * c
+			}
+			if to, ok := rng.FillColor.Get(); ok {
+				if from, ok := fill.Get(); ok {
+					fill = maybe.Some(lerpColor(from, to, c))
+				}
+			}
+			if to, ok := rng.StrokeColor.Get(); ok {
+				if from, ok := stroke.Get(); ok {
+					stroke = maybe.Some(lerpColor(from, to, c))
+				}
+			}
+		}
+
+		origin := g.origin.Translate(curve.Vec(shift, 0))
+		shift += tracking / 1000 * doc.Size
+		if len(g.outline) == 0 || opacity <= 0 {
+			continue
+		}
+
+		// Glyphs are transformed around the center of their baseline, like
+		// layers are transformed around their anchor point.
+		center := curve.Vec(g.advance/2, 0)
+		glyphTrans := curve.Translate(curve.Vec2(origin).Add(center).Add(position)).
+			Mul(curve.Rotate(rotation * math.Pi / 180)).
+			Mul(curve.Scale(scale.X, scale.Y)).
+			Mul(curve.Translate(center.Add(anchor).Negate()))
+
+		drawFill := func() {
+			if c, ok := fill.Get(); ok {
+				rec.Fill(g.outline, model.BrushWithAlpha(gfx.Solid(c), opacity*fillOpacity))
+			}
+		}
+		drawStroke := func() {
+			if c, ok := stroke.Get(); ok && strokeWidth > 0 {
+				rec.Stroke(
+					g.outline,
+					curve.DefaultStroke.WithWidth(strokeWidth),
+					model.BrushWithAlpha(gfx.Solid(c), opacity*strokeOpacity),
+				)
+			}
+		}
+		rec.PushTransform(trans.Mul(glyphTrans))
+		if doc.StrokeOverFill {
+			drawFill()
+			drawStroke()
+		} else {
+			drawStroke()
+			drawFill()
+		}
+		rec.PopTransform()
+	}
+}
+
+func lerpColor(a, b color.Color, t float64) color.Color {
+	b = b.Convert(a.Space)
+	for i := range a.Values {
+		a.Values[i] += (b.Values[i] - a.Values[i]) * t
+	}
+	return a
+}
+
+// layoutText lays out a text document. Layouts are cached, as they don't
+// depend on the frame.
+func (r *Renderer) layoutText(anim *model.Composition, doc *model.TextDocument) *textLayout {
+	if l, ok := r.textLayouts[doc]; ok && l.text == doc.Text {
+		return l
+	}
+
+	font, ok := anim.Fonts[doc.Font]
+	if !ok {
+		font = model.Font{Family: doc.Font}
+	}
+	s := doc.Text
+	if doc.Caps == model.TextCapsAllCaps {
+		s = strings.ToUpper(s)
+	}
+	box, hasBox := doc.Box.Get()
+	width := math.Inf(1)
+	if hasBox {
+		width = box.Width()
+	}
+
+	var lines []textLine
+	var numChars int
+	for para := range strings.SplitSeq(s, "\n") {
+		runes := []rune(para)
+		var paraLines []textLine
+		// Animations that embed glyphs don't expect fonts to be available.
+		if len(anim.Glyphs) > 0 {
+			paraLines = layoutEmbeddedGlyphs(anim, font, doc, runes, width)
+		} else {
+			paraLines = r.layoutFontGlyphs(font, doc, runes, width)
+		}
+		for _, l := range paraLines {
+			for i := range l.glyphs {
+				l.glyphs[i].char += numChars
+			}
+		}
+		numChars += len(runes)
+		lines = append(lines, paraLines...)
+	}
+
+	var y float64
+	if hasBox {
+		ascent := font.Ascent * doc.Size
+		if ascent == 0 {
+			ascent = 0.75 * doc.Size
+		}
+		y = box.Y0 + ascent
+	}
+	y -= doc.BaselineShift
+
+	out := &textLayout{text: doc.Text}
+	var numNonSpaces, numWords int
+	for lineIdx, line := range lines {
+		if len(line.glyphs) == 0 {
+			y += doc.LineHeight
+			continue
+		}
+		w := line.width()
+		x, justify := alignLine(doc.Justify, line.last || !hasBox, doc.Box, w)
+		x -= line.glyphs[0].origin.X
+
+		var spacing float64
+		if justify {
+			// Spread the remaining space across the spaces that aren't
+			// trailing.
+			end := len(line.glyphs)
+			for end > 0 && line.glyphs[end-1].space {
+				end--
+			}
+			var n int
+			for _, g := range line.glyphs[:end] {
+				if g.space {
+					n++
+				}
+			}
+			if n > 0 {
+				spacing = max(box.Width()-w, 0) / float64(n)
+			}
+		}
+
+		inWord := false
+		var extra float64
+		for _, g := range line.glyphs {
+			g.origin = curve.Pt(g.origin.X+x+extra, y)
+			g.units[model.TextBasisCharacters] = g.char
+			g.units[model.TextBasisLines] = lineIdx
+			if g.space {
+				g.units[model.TextBasisCharactersExcludingSpaces] = -1
+				extra += spacing
+				inWord = false
+			} else {
+				g.units[model.TextBasisCharactersExcludingSpaces] = numNonSpaces
+				numNonSpaces++
+				if !inWord {
+					numWords++
+					inWord = true
+				}
+			}
+			// Spaces belong to the preceding word.
+			g.units[model.TextBasisWords] = max(numWords-1, 0)
+			out.glyphs = append(out.glyphs, g)
+		}
+		y += doc.LineHeight
+	}
+	out.units = [4]int{
+		model.TextBasisCharacters:                numChars,
+		model.TextBasisCharactersExcludingSpaces: numNonSpaces,
+		model.TextBasisWords:                     numWords,
+		model.TextBasisLines:                     len(lines),
+	}
+
+	if r.textLayouts == nil {
+		r.textLayouts = make(map[*model.TextDocument]*textLayout)
+	}
+	r.textLayouts[doc] = out
+	return out
+}
+
+// alignLine returns the horizontal position of a line of the given width, and
+// whether the line should be justified. Text without a box is aligned
+// relative to the origin.
+func alignLine(justify model.TextJustify, last bool, box maybe.Option[curve.Rect], width float64) (float64, bool) {
+	var left, center, right float64
+	if b, ok := box.Get(); ok {
+		left, center, right = b.X0, (b.X0+b.X1)/2, b.X1
+	}
+	switch justify {
+	case model.TextJustifyRight:
+		return right - width, false
+	case model.TextJustifyCenter:
+		return center - width/2, false
+	case model.TextJustifyLastLineLeft, model.TextJustifyLastLineRight,
+		model.TextJustifyLastLineCenter, model.TextJustifyLastLineFull:
+		if !last {
+			return left, true
+		}
+		switch justify {
+		case model.TextJustifyLastLineRight:
+			return right - width, false
+		case model.TextJustifyLastLineCenter:
+			return center - width/2, false
+		case model.TextJustifyLastLineFull:
+			_, hasBox := box.Get()
+			return left, hasBox
+		}
+	}
+	return left, false
+}
+
+// layoutEmbeddedGlyphs lays out a paragraph using the glyphs embedded in the
+// animation, wrapping lines at spaces to fit into width.
+func layoutEmbeddedGlyphs(
+	anim *model.Composition,
+	font model.Font,
+	doc *model.TextDocument,
+	runes []rune,
+	width float64,
+) []textLine {
+	tracking := doc.Tracking / 1000 * doc.Size
+	glyphs := make([]textGlyph, 0, len(runes))
+	var x float64
+	for i, ch := range runes {
+		size := doc.Size
+		if doc.Caps == model.TextCapsSmallCaps && unicode.IsLower(ch) {
+			ch = unicode.ToUpper(ch)
+			size *= smallCapsScale
+		}
+		glyph := anim.Glyphs[model.GlyphKey{
+			Character: string(ch),
+			Family:    font.Family,
+			Style:     font.Style,
+		}]
+		scale := size / 100
+		outline := appendGlyphShapes(nil, glyph.Shapes, curve.Scale(scale, scale))
+		advance := glyph.Width*scale + tracking
+		glyphs = append(glyphs, textGlyph{
+			outline: outline,
+			origin:  curve.Pt(x, 0),
+			advance: advance,
+			space:   unicode.IsSpace(ch),
+			char:    i,
+		})
+		x += advance
+	}
+
+	var lines []textLine
+	start, lastSpace := 0, -1
+	for i := range glyphs {
+		g := &glyphs[i]
+		if g.space {
+			lastSpace = i
+			continue
+		}
+		if lastSpace > start && g.origin.X+g.advance-glyphs[start].origin.X > width {
+			lines = append(lines, textLine{glyphs: glyphs[start:lastSpace]})
+			start = lastSpace + 1
+		}
+	}
+	return append(lines, textLine{glyphs: glyphs[start:], last: true})
+}
+
+// appendGlyphShapes appends the geometry of an embedded glyph's shapes to
+// path.
+func appendGlyphShapes(path curve.BezPath, shapes []model.Shape, trans curve.Affine) curve.BezPath {
+	for _, shape := range shapes {
+		switch shape.Kind {
+		case model.ShapeKindGroup:
+			groupTrans := trans
+			if t, ok := shape.GroupTransform.Get(); ok {
+				groupTrans = trans.Mul(t.Transform.Evaluate(0))
+			}
+			path = appendGlyphShapes(path, shape.GroupShapes, groupTrans)
+		case model.ShapeKindGeometry:
+			start := len(path)
+			path = shape.Geometry.Evaluate(0, path)
+			added := path[start:]
+			added.ApplyTransform(trans)
+		}
+	}
+	return path
+}
+
+// layoutFontGlyphs lays out a paragraph using the font database.
+func (r *Renderer) layoutFontGlyphs(
+	font model.Font,
+	doc *model.TextDocument,
+	runes []rune,
+	width float64,
+) []textLine {
+	if len(runes) == 0 {
+		return []textLine{{last: true}}
+	}
+
+	weight, fontStyle := parseFontStyle(font.Style)
+	style := text.Style{
+		FontFamilies: maybe.Some([]string{font.Family}),
+		// Lottie font sizes are the size of the em in pixels.
+		FontSize:      maybe.Some(text.FontSizeForEm(doc.Size)),
+		FontWeight:    maybe.Some(weight),
+		FontStyle:     maybe.Some(fontStyle),
+		LetterSpacing: maybe.Some(doc.Tracking / 1000 * doc.Size),
+	}
+	if doc.Caps == model.TextCapsSmallCaps {
+		style.FontFeatures = maybe.Some([]text.FontFeature{{Feature: "smcp", Value: 1}})
+	}
+	pb := text.NewParagraphBuilder(&text.ParagraphStyle{})
+	pb.PushStyle(&style)
+	pb.AddString(string(runes))
+	pb.PopStyle()
+	p := pb.Build(r.fonts(), &r.fontLoader)
+	p.Layout(width)
+
+	lines := make([]textLine, p.NumLines())
+	for g := range p.Glyphs() {
+		// Make the outline relative to the glyph's origin.
+		trans := curve.Translate(curve.Vec2(g.Origin).Negate()).Mul(g.Transform)
+		char := min(max(g.Cluster, 0), len(runes)-1)
+		lines[g.Line].glyphs = append(lines[g.Line].glyphs, textGlyph{
+			outline: g.Font.GlyphOutline(g.ID).Transform(trans),
+			origin:  curve.Pt(g.Origin.X, 0),
+			advance: g.Advance,
+			space:   unicode.IsSpace(runes[char]),
+			char:    char,
+		})
+	}
+	if len(lines) > 0 {
+		lines[len(lines)-1].last = true
+	}
+	return lines
+}
+
+// parseFontStyle maps a font's style name, such as "Semibold Italic", to a
+// weight and style.
+func parseFontStyle(name string) (float64, text.FontStyle) {
+	name = strings.ToLower(name)
+	name = strings.NewReplacer(" ", "", "-", "", "_", "").Replace(name)
+
+	fontStyle := text.FontStyleNormal
+	if strings.Contains(name, "italic") || strings.Contains(name, "oblique") {
+		fontStyle = text.FontStyleItalic
+	}
+	// Prefixed weights have to be checked before the weights they prefix.
+	weights := []struct {
+		name   string
+		weight float64
+	}{
+		{"extralight", 200},
+		{"ultralight", 200},
+		{"semibold", 600},
+		{"demibold", 600},
+		{"extrabold", 800},
+		{"ultrabold", 800},
+		{"thin", 100},
+		{"light", 300},
+		{"medium", 500},
+		{"bold", 700},
+		{"black", 900},
+		{"heavy", 900},
+	}
+	for _, w := range weights {
+		if strings.Contains(name, w.name) {
+			return w.weight, fontStyle
+		}
+	}
+	return 400, fontStyle
+}
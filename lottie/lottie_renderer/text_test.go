@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"fmt"
+	"testing"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/gfx"
+)
+
+// Text with embedded glyphs for "A" and space, with a range that moves the
+// first half of the characters up.
+const textJSON = `{
+	"v": "5.8.1", "fr": 10, "ip": 0, "op": 10, "w": 100, "h": 100,
+	"fonts": {"list": [{"fName": "Test-Regular", "fFamily": "Test", "fStyle": "Regular", "ascent": 75}]},
+	"chars": [
+		{"ch": "A", "fFamily": "Test", "style": "Regular", "size": 100, "w": 50, "data": {"shapes": [
+			{"ty": "gr", "it": [
+				{"ty": "sh", "ks": {"a": 0, "k": {"c": true, "v": [[0, 0], [50, 0], [50, -50], [0, -50]], "i": [[0, 0], [0, 0], [0, 0], [0, 0]], "o": [[0, 0], [0, 0], [0, 0], [0, 0]]}}}
+			]}
+		]}},
+		{"ch": " ", "fFamily": "Test", "style": "Regular", "size": 100, "w": 25, "data": {}}
+	],
+	"layers": [{
+		"ty": 5, "ind": 1, "ip": 0, "op": 10, "st": 0, "sr": 1,
+		"ks": {},
+		"t": {
+			"d": {"k": [{"t": 0, "s": {"f": "Test-Regular", "s": 20, "t": "AA A", "fc": [1, 0, 0], "j": %d, "tr": 0, "lh": 24}}]},
+			"a": [{
+				"s": {"t": 0, "r": 1, "b": 1, "sh": 1, "s": {"a": 0, "k": 0}, "e": {"a": 0, "k": 50}, "o": {"a": 0, "k": 0}},
+				"a": {"p": {"a": 0, "k": [0, -10]}}
+			}],
+			"m": {"g": 1, "a": {"a": 0, "k": [0, 0]}},
+			"p": {}
+		}
+	}]
+}`
+
+func TestTextLayer(t *testing.T) {
+	tests := []struct {
+		name    string
+		justify int
+		want    []curve.Vec2
+	}{
+		{"left", 0, []curve.Vec2{{X: 0, Y: -10}, {X: 10, Y: -10}, {X: 25, Y: 0}}},
+		// The line is 35 units wide.
+		{"center", 2, []curve.Vec2{{X: -17.5, Y: -10}, {X: -7.5, Y: -10}, {X: 7.5, Y: 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fills := commands[gfx.CommandFill](renderJSON(t, fmt.Sprintf(textJSON, tt.justify), 0))
+			// The space doesn't have an outline.
+			if len(fills) != len(tt.want) {
+				t.Fatalf("got %d fills, want %d", len(fills), len(tt.want))
+			}
+			for i, fill := range fills {
+				if want := curve.Translate(tt.want[i]); fill.Transform != want {
+					t.Errorf("glyph %d has transform %v, want %v", i, fill.Transform, want)
+				}
+			}
+			got := pathPoints(fills[0].Shape)
+			want := []curve.Point{curve.Pt(0, 0), curve.Pt(10, 0), curve.Pt(10, -10), curve.Pt(0, -10), curve.Pt(0, 0)}
+			if !equalPoints(got, want) {
+				t.Errorf("glyph has points %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"iter"
+	"slices"
+
+	"honnef.co/go/curve"
+	model "honnef.co/go/gutter/lottie/lottie_model"
+)
+
+// The accuracy with which we measure and cut paths, in the same units as the
+// paths.
+const arclenAccuracy = 1e-3
+
+// trim replaces the geometries pushed since geometryStart with trimmed
+// copies. Draws that have already been pushed keep using the original
+// geometries.
+func (b *batch) trim(trim *model.FixedTrim, geometryStart int) {
+	intervals, n := trim.Intervals()
+	if n == 1 && intervals[0] == [2]float64{0, 1} {
+		return
+	}
+
+	end := len(b.geometries)
+	supersededAt := len(b.draws)
+
+	// In individual mode, all paths are trimmed as if they were one long path.
+	var total float64
+	if trim.Mode == model.TrimModeIndividual {
+		for _, g := range b.geometries[geometryStart:end] {
+			if g.supersededAt != 0 {
+				continue
+			}
+			for sub := range subpaths(b.elements[g.elements[0]:g.elements[1]]) {
+				total += b.measure(sub)
+			}
+		}
+	}
+
+	// The length of the paths preceding the current one, in individual mode.
+	var offset float64
+	for i := geometryStart; i < end; i++ {
+		g := b.geometries[i]
+		if g.supersededAt != 0 {
+			continue
+		}
+		// Appending to b.elements doesn't modify the data we're iterating
+		// over, even if it reallocates.
+		start := len(b.elements)
+		for sub := range subpaths(b.elements[g.elements[0]:g.elements[1]]) {
+			// OPT(dh): in individual mode, we measure every path twice.
+			length := b.measure(sub)
+			switch trim.Mode {
+			case model.TrimModeIndividual:
+				for _, iv := range intervals[:n] {
+					b.appendTrimmed(sub, iv[0]*total-offset, iv[1]*total-offset, true)
+				}
+				offset += length
+			default:
+				closed := sub[len(sub)-1].Kind == curve.ClosePathKind
+				for j, iv := range intervals[:n] {
+					// When trimming wraps around the end of a closed path, the
+					// second interval continues where the first one ended.
+					b.appendTrimmed(sub, iv[0]*length, iv[1]*length, j == 0 || !closed)
+				}
+			}
+		}
+		b.geometries[i].supersededAt = supersededAt
+		b.geometries = append(b.geometries, geometryData{
+			elements:  [2]int{start, len(b.elements)},
+			transform: g.transform,
+		})
+	}
+}
+
+// measure splits path into segments and computes their cumulative lengths.
+// It returns the path's total length.
+func (b *batch) measure(path curve.BezPath) float64 {
+	b.segments = slices.AppendSeq(b.segments[:0], path.Segments())
+	b.lengths = b.lengths[:0]
+	var total float64
+	for _, seg := range b.segments {
+		total += seg.Arclen(arclenAccuracy)
+		b.lengths = append(b.lengths, total)
+	}
+	return total
+}
+
+// appendTrimmed appends the part of path between the lengths from and to.
+// The path must have been measured by the most recent call to measure. If
+// moveTo is false, the part continues the previously appended path.
+func (b *batch) appendTrimmed(path curve.BezPath, from, to float64, moveTo bool) {
+	if len(b.lengths) == 0 {
+		return
+	}
+	total := b.lengths[len(b.lengths)-1]
+	from = max(from, 0)
+	to = min(to, total)
+	if from >= to {
+		return
+	}
+	if from == 0 && to == total && moveTo {
+		// Keep the path intact, including how it's closed.
+		b.elements = append(b.elements, path...)
+		return
+	}
+
+	var l0 float64
+	for i, seg := range b.segments {
+		l1 := b.lengths[i]
+		lo, hi := max(from, l0), min(to, l1)
+		if lo < hi {
+			t0, t1 := 0.0, 1.0
+			if lo > l0 {
+				t0 = seg.SolveForArclen(lo-l0, arclenAccuracy)
+			}
+			if hi < l1 {
+				t1 = seg.SolveForArclen(hi-l0, arclenAccuracy)
+			}
+			sub := seg.Subsegment(t0, t1)
+			if moveTo {
+				b.elements.MoveTo(sub.Start())
+				moveTo = false
+			}
+			b.elements.Push(sub.PathElement())
+		}
+		if l1 >= to {
+			break
+		}
+		l0 = l1
+	}
+}
+
+// subpaths splits path at each MoveTo.
+func subpaths(path curve.BezPath) iter.Seq[curve.BezPath] {
+	return func(yield func(curve.BezPath) bool) {
+		start := 0
+		for i := 1; i < len(path); i++ {
+			if path[i].Kind == curve.MoveToKind {
+				if !yield(path[start:i]) {
+					return
+				}
+				start = i
+			}
+		}
+		if start < len(path) {
+			yield(path[start:])
+		}
+	}
+}
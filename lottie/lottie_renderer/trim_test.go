@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package lottie_renderer
+
+import (
+	"slices"
+	"testing"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/gfx"
+)
+
+// A line that gets drawn on over ten frames with a dashed stroke, and a
+// square whose trimmed part wraps around the square's starting point.
+const trimJSON = `{
+	"v": "5.8.1", "fr": 10, "ip": 0, "op": 11, "w": 200, "h": 200,
+	"layers": [{
+		"ty": 4, "ind": 1, "ip": 0, "op": 11, "st": 0, "sr": 1,
+		"ks": {},
+		"shapes": [
+			{"ty": "gr", "it": [
+				{"ty": "sh", "ks": {"a": 0, "k": {"c": false, "v": [[0, 0], [100, 0]], "i": [[0, 0], [0, 0]], "o": [[0, 0], [0, 0]]}}},
+				{"ty": "tm",
+					"s": {"a": 0, "k": 0},
+					"e": {"a": 1, "k": [
+						{"t": 0, "s": [0], "o": {"x": [0], "y": [0]}, "i": {"x": [1], "y": [1]}},
+						{"t": 10, "s": [100]}
+					]},
+					"o": {"a": 0, "k": 0},
+					"m": 1},
+				{"ty": "st", "c": {"a": 0, "k": [1, 0, 0]}, "o": {"a": 0, "k": 100}, "w": {"a": 0, "k": 2},
+					"d": [
+						{"n": "d", "v": {"a": 0, "k": 10}},
+						{"n": "g", "v": {"a": 0, "k": 5}},
+						{"n": "o", "v": {"a": 0, "k": -3}}
+					]}
+			]},
+			{"ty": "gr", "it": [
+				{"ty": "sh", "ks": {"a": 0, "k": {"c": true, "v": [[0, 0], [100, 0], [100, 100], [0, 100]], "i": [[0, 0], [0, 0], [0, 0], [0, 0]], "o": [[0, 0], [0, 0], [0, 0], [0, 0]]}}},
+				{"ty": "tm", "s": {"a": 0, "k": 0}, "e": {"a": 0, "k": 25}, "o": {"a": 0, "k": 315}, "m": 1},
+				{"ty": "st", "c": {"a": 0, "k": [0, 0, 1]}, "o": {"a": 0, "k": 100}, "w": {"a": 0, "k": 2}}
+			]}
+		]
+	}]
+}`
+
+func renderStrokes(t *testing.T, frame float64) []gfx.CommandStroke {
+	t.Helper()
+	return commands[gfx.CommandStroke](renderJSON(t, trimJSON, frame))
+}
+
+func TestTrim(t *testing.T) {
+	tests := []struct {
+		frame  float64
+		line   []curve.Point
+		square []curve.Point
+	}{
+		// At frame 0, the line is trimmed away entirely.
+		{0, nil, []curve.Point{curve.Pt(0, 50), curve.Pt(0, 0), curve.Pt(50, 0)}},
+		{5, []curve.Point{curve.Pt(0, 0), curve.Pt(50, 0)}, []curve.Point{curve.Pt(0, 50), curve.Pt(0, 0), curve.Pt(50, 0)}},
+		{10, []curve.Point{curve.Pt(0, 0), curve.Pt(100, 0)}, []curve.Point{curve.Pt(0, 50), curve.Pt(0, 0), curve.Pt(50, 0)}},
+	}
+	for _, tt := range tests {
+		strokes := renderStrokes(t, tt.frame)
+		// Draws are rendered in reverse, so the square comes first.
+		if len(strokes) != 2 {
+			t.Fatalf("frame %g: got %d strokes, want 2", tt.frame, len(strokes))
+		}
+		if got := pathPoints(strokes[0].Shape); !equalPoints(got, tt.square) {
+			t.Errorf("frame %g: square is %v, want %v", tt.frame, got, tt.square)
+		}
+		if got := pathPoints(strokes[1].Shape); !equalPoints(got, tt.line) {
+			t.Errorf("frame %g: line is %v, want %v", tt.frame, got, tt.line)
+		}
+	}
+}
+
+func TestDash(t *testing.T) {
+	strokes := renderStrokes(t, 5)
+	line := strokes[1].Stroke
+	if !slices.Equal(line.DashPattern, []float64{10, 5}) || line.DashOffset != 12 {
+		t.Errorf("got dashes %v with offset %g, want [10 5] with offset 12", line.DashPattern, line.DashOffset)
+	}
+	if square := strokes[0].Stroke; len(square.DashPattern) != 0 {
+		t.Errorf("undashed stroke has dashes %v", square.DashPattern)
+	}
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import "iter"
+
+// SegmentMaps returns the segment maps, one per axis.
+func (tbl *AvarTable) SegmentMaps() iter.Seq[SegmentMaps] {
+	return func(yield func(SegmentMaps) bool) {
+		b := tbl.segmentMaps
+		for range tbl.AxisCount {
+			if len(b) < 2 {
+				return
+			}
+			var seg SegmentMaps
+			dyn := parseSegmentMaps(b, &seg)
+			if !yield(seg) {
+				return
+			}
+			b = b[dyn+2:]
+		}
+	}
+}
+
+// Map applies the axis mappings to normalized variation coordinates, in
+// place.
+func (tbl *AvarTable) Map(coords []float64) {
+	i := 0
+	for seg := range tbl.SegmentMaps() {
+		if i >= len(coords) {
+			break
+		}
+		coords[i] = seg.Map(coords[i])
+		i++
+	}
+}
+
+// Map maps a normalized coordinate using the piecewise linear function
+// described by the axis value maps.
+func (tbl *SegmentMaps) Map(v float64) float64 {
+	n := tbl.NumAxisValueMaps()
+	if n == 0 {
+		return v
+	}
+	prev := tbl.AxisValueMap(0)
+	if v <= prev.FromCoordinate.Float() {
+		return prev.ToCoordinate.Float()
+	}
+	for i := 1; i < n; i++ {
+		cur := tbl.AxisValueMap(i)
+		from0, from1 := prev.FromCoordinate.Float(), cur.FromCoordinate.Float()
+		if v <= from1 {
+			to0, to1 := prev.ToCoordinate.Float(), cur.ToCoordinate.Float()
+			if from1 == from0 {
+				return to1
+			}
+			return to0 + (to1-to0)*(v-from0)/(from1-from0)
+		}
+		prev = cur
+	}
+	return prev.ToCoordinate.Float()
+}
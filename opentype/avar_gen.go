@@ -0,0 +1,113 @@
+// Code generated by honnef.co/go/gutter/internal/cmd/gen_parser. DO NOT EDIT.
+
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import "iter"
+
+type AvarTable struct {
+	MajorVersion uint16
+	MinorVersion uint16
+
+	AxisCount uint16
+
+	// One variable-size SegmentMaps per axis.
+	segmentMaps []byte
+}
+
+type SegmentMaps struct {
+	axisValueMaps Slice[AxisValueMap]
+}
+
+type AxisValueMap struct {
+	FromCoordinate Int2_14
+	ToCoordinate   Int2_14
+}
+func ParseAvarTable(buf []byte, out *AvarTable) int {
+	*out = AvarTable{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 8 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.MajorVersion)
+	/* XXX return error */
+	if out.MajorVersion > 1 {
+		return dynSize
+	}
+	parseUint16(buf[2:4], &out.MinorVersion)
+	parseUint16(buf[6:8], &out.AxisCount)
+	{
+		out.segmentMaps = buf[8:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseAxisValueMap(buf []byte, out *AxisValueMap) int {
+	*out = AxisValueMap{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 4 {
+		return dynSize
+	}
+	parseInt2_14(buf[0:2], &out.FromCoordinate)
+	parseInt2_14(buf[2:4], &out.ToCoordinate)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseSegmentMaps(buf []byte, out *SegmentMaps) int {
+	*out = SegmentMaps{}
+	origBuf := buf
+	var dynSize int
+	var positionMapCount uint16
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &positionMapCount)
+	{
+		n := int(positionMapCount)
+		/* FIXME: check that buf is long enough */
+		out.axisValueMaps = buf[2 : 2+n*4]
+		dynSize += n * 4
+		buf = buf[2+n*4:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func (tbl *SegmentMaps) AxisValueMap(i int) AxisValueMap {
+	var out AxisValueMap
+	parseAxisValueMap(tbl.axisValueMaps[i*4:(i+1)*4:len(tbl.axisValueMaps)], &out)
+	return out
+}
+
+func (tbl *SegmentMaps) AxisValueMaps() iter.Seq2[int, AxisValueMap] {
+	return func(yield func(int, AxisValueMap) bool) {
+		for i := range tbl.NumAxisValueMaps() {
+			if !yield(i, tbl.AxisValueMap(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *SegmentMaps) NumAxisValueMaps() int {
+	return len(tbl.axisValueMaps) / 4
+}
+
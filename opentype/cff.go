@@ -0,0 +1,965 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"honnef.co/go/curve"
+)
+
+// The CFF and CFF2 tables embed a format of their own, which consists of
+// variable-length structures that the parser generator can't describe. This
+// file implements it by hand.
+//
+// See the Compact Font Format Specification (Adobe Technical Note #5176), the
+// Type 2 Charstring Format (Adobe Technical Note #5177) and the OpenType
+// specification of the CFF2 table.
+
+var errCFFTruncated = errors.New("CFF data is truncated")
+
+// cffIndex is an INDEX, an array of variable-sized objects.
+type cffIndex struct {
+	count   int
+	offSize int
+	offsets []byte
+	// The objects' data. Offsets are relative to the byte preceding data.
+	data []byte
+}
+
+// parseCFFIndex parses an INDEX and returns its size in bytes. CFF2 uses
+// 32-bit counts.
+func parseCFFIndex(buf []byte, cff2 bool) (cffIndex, int, error) {
+	var idx cffIndex
+	hdr := 2
+	if cff2 {
+		hdr = 4
+	}
+	if len(buf) < hdr {
+		return idx, 0, errCFFTruncated
+	}
+	if cff2 {
+		idx.count = int(binary.BigEndian.Uint32(buf))
+	} else {
+		idx.count = int(binary.BigEndian.Uint16(buf))
+	}
+	if idx.count == 0 {
+		// Empty INDEXes consist of just the count.
+		return idx, hdr, nil
+	}
+	if len(buf) < hdr+1 {
+		return idx, 0, errCFFTruncated
+	}
+	idx.offSize = int(buf[hdr])
+	if idx.offSize < 1 || idx.offSize > 4 {
+		return idx, 0, fmt.Errorf("invalid CFF INDEX offset size %d", idx.offSize)
+	}
+	start := hdr + 1
+	n := (idx.count + 1) * idx.offSize
+	if len(buf)-start < n {
+		return idx, 0, errCFFTruncated
+	}
+	idx.offsets = buf[start : start+n]
+	dataStart := start + n
+	last := idx.offset(idx.count)
+	if last < 1 || len(buf)-dataStart < last-1 {
+		return idx, 0, errCFFTruncated
+	}
+	idx.data = buf[dataStart : dataStart+last-1]
+	return idx, dataStart + last - 1, nil
+}
+
+func (idx *cffIndex) offset(i int) int {
+	var v int
+	for _, b := range idx.offsets[i*idx.offSize : (i+1)*idx.offSize] {
+		v = v<<8 | int(b)
+	}
+	return v
+}
+
+// get returns the i-th object.
+func (idx *cffIndex) get(i int) ([]byte, error) {
+	if i < 0 || i >= idx.count {
+		return nil, fmt.Errorf("CFF INDEX entry %d out of range", i)
+	}
+	start, end := idx.offset(i)-1, idx.offset(i+1)-1
+	if start < 0 || start > end || end > len(idx.data) {
+		return nil, fmt.Errorf("invalid offsets for CFF INDEX entry %d", i)
+	}
+	return idx.data[start:end], nil
+}
+
+// DICT operators. Two-byte operators are represented as 1200 plus their
+// second byte.
+const (
+	cffDictCharStrings    = 17
+	cffDictPrivate        = 18
+	cffDictSubrs          = 19
+	cffDictVSIndex        = 22
+	cffDictBlend          = 23
+	cffDictVStore         = 24
+	cffDictCharstringType = 1206
+	cffDictROS            = 1230
+	cffDictFDArray        = 1236
+	cffDictFDSelect       = 1237
+)
+
+// cffDict maps DICT operators to their operands.
+type cffDict map[int][]float64
+
+func parseCFFDict(buf []byte) (cffDict, error) {
+	out := cffDict{}
+	var operands []float64
+	for len(buf) > 0 {
+		b0 := buf[0]
+		switch {
+		case b0 <= 27:
+			op := int(b0)
+			buf = buf[1:]
+			if b0 == 12 {
+				if len(buf) < 1 {
+					return nil, errCFFTruncated
+				}
+				op = 1200 + int(buf[0])
+				buf = buf[1:]
+			}
+			if op == cffDictBlend {
+				// Blended values in DICTs are only used for hinting, which we
+				// don't do. Drop them instead of evaluating them.
+				operands = operands[:0]
+				continue
+			}
+			out[op] = operands
+			operands = nil
+		case b0 == 28:
+			if len(buf) < 3 {
+				return nil, errCFFTruncated
+			}
+			operands = append(operands, float64(int16(binary.BigEndian.Uint16(buf[1:]))))
+			buf = buf[3:]
+		case b0 == 29:
+			if len(buf) < 5 {
+				return nil, errCFFTruncated
+			}
+			operands = append(operands, float64(int32(binary.BigEndian.Uint32(buf[1:]))))
+			buf = buf[5:]
+		case b0 == 30:
+			v, n, err := parseCFFReal(buf[1:])
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, v)
+			buf = buf[1+n:]
+		case b0 >= 32 && b0 <= 254:
+			v, n, ok := parseCFFSmallInt(buf)
+			if !ok {
+				return nil, errCFFTruncated
+			}
+			operands = append(operands, v)
+			buf = buf[n:]
+		default:
+			return nil, fmt.Errorf("invalid CFF DICT byte %d", b0)
+		}
+	}
+	return out, nil
+}
+
+// parseCFFSmallInt parses the one and two byte integer encodings shared by
+// DICTs and charstrings.
+func parseCFFSmallInt(buf []byte) (float64, int, bool) {
+	b0 := int(buf[0])
+	switch {
+	case b0 >= 32 && b0 <= 246:
+		return float64(b0 - 139), 1, true
+	case b0 >= 247 && b0 <= 250:
+		if len(buf) < 2 {
+			return 0, 0, false
+		}
+		return float64((b0-247)*256 + int(buf[1]) + 108), 2, true
+	case b0 >= 251 && b0 <= 254:
+		if len(buf) < 2 {
+			return 0, 0, false
+		}
+		return float64(-(b0-251)*256 - int(buf[1]) - 108), 2, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// parseCFFReal parses a real number operand, which is encoded as a sequence of
+// nibbles.
+func parseCFFReal(buf []byte) (float64, int, error) {
+	var sb strings.Builder
+	for i, b := range buf {
+		for _, nib := range [2]byte{b >> 4, b & 0xF} {
+			switch {
+			case nib <= 9:
+				sb.WriteByte('0' + nib)
+			case nib == 0xA:
+				sb.WriteByte('.')
+			case nib == 0xB:
+				sb.WriteByte('E')
+			case nib == 0xC:
+				sb.WriteString("E-")
+			case nib == 0xE:
+				sb.WriteByte('-')
+			case nib == 0xF:
+				v, err := strconv.ParseFloat(sb.String(), 64)
+				if err != nil {
+					return 0, 0, fmt.Errorf("invalid CFF real number: %w", err)
+				}
+				return v, i + 1, nil
+			default:
+				return 0, 0, fmt.Errorf("invalid CFF real number nibble %#x", nib)
+			}
+		}
+	}
+	return 0, 0, errCFFTruncated
+}
+
+// CFF is a parsed CFF or CFF2 table, which contains glyph outlines in the form
+// of Type 2 charstrings.
+type CFF struct {
+	cff2        bool
+	charStrings cffIndex
+	globalSubrs cffIndex
+	// The private DICTs of all font DICTs. Fonts that aren't CID-keyed have a
+	// single private DICT.
+	privates []cffPrivate
+	// The raw FDSelect data, which maps glyphs to font DICTs, or nil if there
+	// is only one font DICT.
+	fdSelect []byte
+
+	hasVarStore bool
+	varStore    ItemVariationStoreTable
+	regions     VariationRegionList
+}
+
+type cffPrivate struct {
+	subrs   cffIndex
+	vsindex int
+}
+
+// ParseCFF parses a CFF or CFF2 table. The version is determined from the
+// table's header.
+func ParseCFF(data []byte) (*CFF, error) {
+	if len(data) < 4 {
+		return nil, errCFFTruncated
+	}
+	switch data[0] {
+	case 1:
+		return parseCFF1(data)
+	case 2:
+		return parseCFF2(data)
+	default:
+		return nil, fmt.Errorf("unsupported CFF major version %d", data[0])
+	}
+}
+
+func parseCFF1(data []byte) (*CFF, error) {
+	c := &CFF{}
+	buf := data[min(int(data[2]), len(data)):]
+
+	// Name INDEX
+	_, n, err := parseCFFIndex(buf, false)
+	if err != nil {
+		return nil, err
+	}
+	buf = buf[n:]
+	topDicts, n, err := parseCFFIndex(buf, false)
+	if err != nil {
+		return nil, err
+	}
+	buf = buf[n:]
+	// String INDEX
+	_, n, err = parseCFFIndex(buf, false)
+	if err != nil {
+		return nil, err
+	}
+	buf = buf[n:]
+	c.globalSubrs, _, err = parseCFFIndex(buf, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// CFF tables in OpenType fonts contain exactly one font.
+	topData, err := topDicts.get(0)
+	if err != nil {
+		return nil, err
+	}
+	top, err := parseCFFDict(topData)
+	if err != nil {
+		return nil, err
+	}
+	if typ, ok := top[cffDictCharstringType]; ok && (len(typ) != 1 || typ[0] != 2) {
+		return nil, errors.New("unsupported charstring type")
+	}
+	if err := c.parseCommon(data, top); err != nil {
+		return nil, err
+	}
+
+	if _, ok := top[cffDictROS]; ok {
+		// CID-keyed fonts have per-glyph font DICTs.
+		if err := c.parseFDArray(data, top); err != nil {
+			return nil, err
+		}
+	} else {
+		p, err := c.parsePrivate(data, top)
+		if err != nil {
+			return nil, err
+		}
+		c.privates = []cffPrivate{p}
+	}
+	return c, nil
+}
+
+func parseCFF2(data []byte) (*CFF, error) {
+	c := &CFF{cff2: true}
+	if len(data) < 5 {
+		return nil, errCFFTruncated
+	}
+	hdrSize := int(data[2])
+	topLen := int(binary.BigEndian.Uint16(data[3:]))
+	if len(data)-hdrSize < topLen {
+		return nil, errCFFTruncated
+	}
+	top, err := parseCFFDict(data[hdrSize : hdrSize+topLen])
+	if err != nil {
+		return nil, err
+	}
+	c.globalSubrs, _, err = parseCFFIndex(data[hdrSize+topLen:], true)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.parseCommon(data, top); err != nil {
+		return nil, err
+	}
+
+	if ops := top[cffDictVStore]; len(ops) > 0 {
+		// The item variation store is preceded by its length.
+		off := int(ops[0]) + 2
+		if off < 2 || off >= len(data) {
+			return nil, errors.New("invalid CFF2 variation store offset")
+		}
+		ParseItemVariationStoreTable(data[off:], &c.varStore)
+		c.hasVarStore = c.varStore.VariationRegionList(&c.regions)
+	}
+
+	if err := c.parseFDArray(data, top); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseCommon parses the parts of the top DICT that CFF and CFF2 have in
+// common.
+func (c *CFF) parseCommon(data []byte, top cffDict) error {
+	buf, err := cffOffset(data, top, cffDictCharStrings)
+	if err != nil {
+		return err
+	}
+	if buf == nil {
+		return errors.New("CFF font has no charstrings")
+	}
+	c.charStrings, _, err = parseCFFIndex(buf, c.cff2)
+	return err
+}
+
+func (c *CFF) parseFDArray(data []byte, top cffDict) error {
+	buf, err := cffOffset(data, top, cffDictFDArray)
+	if err != nil {
+		return err
+	}
+	if buf == nil {
+		return errors.New("CFF font has no FDArray")
+	}
+	fdArray, _, err := parseCFFIndex(buf, c.cff2)
+	if err != nil {
+		return err
+	}
+	c.privates = make([]cffPrivate, fdArray.count)
+	for i := range fdArray.count {
+		fdData, err := fdArray.get(i)
+		if err != nil {
+			return err
+		}
+		fd, err := parseCFFDict(fdData)
+		if err != nil {
+			return err
+		}
+		c.privates[i], err = c.parsePrivate(data, fd)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.fdSelect, err = cffOffset(data, top, cffDictFDSelect)
+	if err != nil {
+		return err
+	}
+	if c.fdSelect == nil && fdArray.count > 1 {
+		return errors.New("CFF font has multiple font DICTs but no FDSelect")
+	}
+	if c.fdSelect != nil && len(c.fdSelect) < 1 {
+		return errCFFTruncated
+	}
+	return nil
+}
+
+func (c *CFF) parsePrivate(data []byte, dict cffDict) (cffPrivate, error) {
+	var p cffPrivate
+	ops := dict[cffDictPrivate]
+	if len(ops) < 2 {
+		// The private DICT is required, but we don't need anything from it
+		// that doesn't have a default.
+		return p, nil
+	}
+	size, off := int(ops[0]), int(ops[1])
+	if size < 0 || off < 0 || off > len(data) || len(data)-off < size {
+		return p, errors.New("invalid CFF private DICT location")
+	}
+	priv, err := parseCFFDict(data[off : off+size])
+	if err != nil {
+		return p, err
+	}
+	if v := priv[cffDictVSIndex]; len(v) > 0 {
+		p.vsindex = int(v[0])
+	}
+	if v := priv[cffDictSubrs]; len(v) > 0 {
+		// The offset to the local subroutines is relative to the private
+		// DICT.
+		sub := off + int(v[0])
+		if sub < 0 || sub >= len(data) {
+			return p, errors.New("invalid CFF local subroutines offset")
+		}
+		p.subrs, _, err = parseCFFIndex(data[sub:], c.cff2)
+		if err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+// cffOffset returns the data at the offset stored for the operator, or nil if
+// the DICT doesn't contain the operator.
+func cffOffset(data []byte, dict cffDict, op int) ([]byte, error) {
+	ops, ok := dict[op]
+	if !ok {
+		return nil, nil
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("missing operand for CFF DICT operator %d", op)
+	}
+	off := int(ops[len(ops)-1])
+	if off <= 0 || off >= len(data) {
+		return nil, fmt.Errorf("invalid offset for CFF DICT operator %d", op)
+	}
+	return data[off:], nil
+}
+
+// NumGlyphs returns the number of glyphs in the font.
+func (c *CFF) NumGlyphs() int {
+	return c.charStrings.count
+}
+
+// fdIndex returns the index of the font DICT used by a glyph.
+func (c *CFF) fdIndex(glyph GlyphID) (int, error) {
+	b := c.fdSelect
+	if b == nil {
+		return 0, nil
+	}
+	switch b[0] {
+	case 0:
+		if int(glyph)+1 >= len(b) {
+			return 0, errCFFTruncated
+		}
+		return int(b[1+int(glyph)]), nil
+	case 3:
+		if len(b) < 3 {
+			return 0, errCFFTruncated
+		}
+		n := int(binary.BigEndian.Uint16(b[1:]))
+		ranges := b[3:]
+		if len(ranges) < n*3+2 {
+			return 0, errCFFTruncated
+		}
+		// Find the first range that starts after the glyph. The sentinel
+		// marks the end of the last range.
+		i := sort.Search(n+1, func(i int) bool {
+			return GlyphID(binary.BigEndian.Uint16(ranges[i*3:])) > glyph
+		})
+		if i == 0 || i > n {
+			return 0, fmt.Errorf("glyph %d not covered by FDSelect", glyph)
+		}
+		return int(ranges[(i-1)*3+2]), nil
+	case 4:
+		if len(b) < 5 {
+			return 0, errCFFTruncated
+		}
+		n := int(binary.BigEndian.Uint32(b[1:]))
+		ranges := b[5:]
+		if n < 0 || len(ranges) < n*6+4 {
+			return 0, errCFFTruncated
+		}
+		i := sort.Search(n+1, func(i int) bool {
+			return GlyphID(binary.BigEndian.Uint32(ranges[i*6:])) > glyph
+		})
+		if i == 0 || i > n {
+			return 0, fmt.Errorf("glyph %d not covered by FDSelect", glyph)
+		}
+		return int(binary.BigEndian.Uint16(ranges[(i-1)*6+4:])), nil
+	default:
+		return 0, fmt.Errorf("unsupported FDSelect format %d", b[0])
+	}
+}
+
+// Outline appends the outline of a glyph to path and returns the result.
+// Coordinates are in font units, with the y axis pointing up. Coords are the
+// normalized variation coordinates of the instance, one per axis in the
+// font's fvar table. They're only used by CFF2, and missing coordinates are
+// treated as zero, which selects the default instance.
+//
+// Hints are ignored.
+func (c *CFF) Outline(glyph GlyphID, coords []float64, path curve.BezPath) (curve.BezPath, error) {
+	cs, err := c.charStrings.get(int(glyph))
+	if err != nil {
+		return path, err
+	}
+	fd, err := c.fdIndex(glyph)
+	if err != nil {
+		return path, err
+	}
+	if fd >= len(c.privates) {
+		return path, fmt.Errorf("invalid font DICT index %d", fd)
+	}
+	maxStack := 48
+	if c.cff2 {
+		maxStack = 513
+	}
+	in := charstringInterp{
+		cff:     c,
+		private: &c.privates[fd],
+		coords:  coords,
+		path:    path,
+		stack:   make([]float64, 0, maxStack),
+		vsindex: c.privates[fd].vsindex,
+	}
+	if err := in.run(cs, 0); err != nil && err != errEndChar {
+		return path, err
+	}
+	in.closePath()
+	return in.path, nil
+}
+
+var errEndChar = errors.New("endchar")
+
+// The maximum nesting depth of subroutine calls, as defined by the Type 2
+// charstring format.
+const maxSubrDepth = 10
+
+// Charstring operators. Two-byte operators are represented as 1200 plus their
+// second byte.
+const (
+	csHstem      = 1
+	csVstem      = 3
+	csVmoveto    = 4
+	csRlineto    = 5
+	csHlineto    = 6
+	csVlineto    = 7
+	csRrcurveto  = 8
+	csCallsubr   = 10
+	csReturn     = 11
+	csEndchar    = 14
+	csVSIndex    = 15
+	csBlend      = 16
+	csHstemhm    = 18
+	csHintmask   = 19
+	csCntrmask   = 20
+	csRmoveto    = 21
+	csHmoveto    = 22
+	csVstemhm    = 23
+	csRcurveline = 24
+	csRlinecurve = 25
+	csVvcurveto  = 26
+	csHhcurveto  = 27
+	csCallgsubr  = 29
+	csVhcurveto  = 30
+	csHvcurveto  = 31
+	csHflex      = 1234
+	csFlex       = 1235
+	csHflex1     = 1236
+	csFlex1      = 1237
+)
+
+type charstringInterp struct {
+	cff     *CFF
+	private *cffPrivate
+	coords  []float64
+	path    curve.BezPath
+
+	stack  []float64
+	x, y   float64
+	nStems int
+	// Whether we've seen the first stack-clearing operator, which in CFF
+	// may be preceded by the glyph's advance width.
+	seenWidth bool
+	// Whether there is a contour that hasn't been closed yet.
+	open bool
+
+	vsindex int
+	// The scalars of the regions used by vsindex, computed on demand.
+	scalars []float64
+}
+
+func subrBias(n int) int {
+	switch {
+	case n < 1240:
+		return 107
+	case n < 33900:
+		return 1131
+	default:
+		return 32768
+	}
+}
+
+func (in *charstringInterp) push(v float64) error {
+	if len(in.stack) == cap(in.stack) {
+		return errors.New("charstring stack overflow")
+	}
+	in.stack = append(in.stack, v)
+	return nil
+}
+
+func (in *charstringInterp) run(cs []byte, depth int) error {
+	if depth > maxSubrDepth {
+		return errors.New("charstring subroutines nested too deeply")
+	}
+	for len(cs) > 0 {
+		b0 := cs[0]
+		switch {
+		case b0 == 28:
+			if len(cs) < 3 {
+				return errCFFTruncated
+			}
+			if err := in.push(float64(int16(binary.BigEndian.Uint16(cs[1:])))); err != nil {
+				return err
+			}
+			cs = cs[3:]
+			continue
+		case b0 >= 32 && b0 <= 254:
+			v, n, ok := parseCFFSmallInt(cs)
+			if !ok {
+				return errCFFTruncated
+			}
+			if err := in.push(v); err != nil {
+				return err
+			}
+			cs = cs[n:]
+			continue
+		case b0 == 255:
+			// 16.16 fixed-point number
+			if len(cs) < 5 {
+				return errCFFTruncated
+			}
+			if err := in.push(float64(int32(binary.BigEndian.Uint32(cs[1:]))) / (1 << 16)); err != nil {
+				return err
+			}
+			cs = cs[5:]
+			continue
+		}
+
+		op := int(b0)
+		cs = cs[1:]
+		if op == 12 {
+			if len(cs) < 1 {
+				return errCFFTruncated
+			}
+			op = 1200 + int(cs[0])
+			cs = cs[1:]
+		}
+
+		s := in.stack
+		switch op {
+		case csCallsubr, csCallgsubr:
+			if len(s) < 1 {
+				return errors.New("charstring stack underflow")
+			}
+			subrs := &in.private.subrs
+			if op == csCallgsubr {
+				subrs = &in.cff.globalSubrs
+			}
+			idx := int(s[len(s)-1]) + subrBias(subrs.count)
+			in.stack = s[:len(s)-1]
+			sub, err := subrs.get(idx)
+			if err != nil {
+				return err
+			}
+			if err := in.run(sub, depth+1); err != nil {
+				return err
+			}
+			// Subroutines leave their results on the stack.
+			continue
+		case csReturn:
+			return nil
+		case csEndchar:
+			in.skipWidth(len(s) == 1 || len(s) == 5)
+			// XXX support the deprecated seac form of endchar, which composes
+			// accented characters from glyphs in the standard encoding.
+			return errEndChar
+		case csHstem, csVstem, csHstemhm, csVstemhm:
+			in.skipWidth(len(s)%2 == 1)
+			in.nStems += len(in.stack) / 2
+		case csHintmask, csCntrmask:
+			// Any operands are implicit vstem hints.
+			in.skipWidth(len(s)%2 == 1)
+			in.nStems += len(in.stack) / 2
+			n := (in.nStems + 7) / 8
+			if len(cs) < n {
+				return errCFFTruncated
+			}
+			cs = cs[n:]
+		case csRmoveto:
+			in.skipWidth(len(s) > 2)
+			if s = in.stack; len(s) < 2 {
+				return errors.New("charstring stack underflow")
+			}
+			in.moveTo(s[0], s[1])
+		case csHmoveto:
+			in.skipWidth(len(s) > 1)
+			if s = in.stack; len(s) < 1 {
+				return errors.New("charstring stack underflow")
+			}
+			in.moveTo(s[0], 0)
+		case csVmoveto:
+			in.skipWidth(len(s) > 1)
+			if s = in.stack; len(s) < 1 {
+				return errors.New("charstring stack underflow")
+			}
+			in.moveTo(0, s[0])
+		case csRlineto:
+			for ; len(s) >= 2; s = s[2:] {
+				in.lineTo(s[0], s[1])
+			}
+		case csHlineto, csVlineto:
+			horizontal := op == csHlineto
+			for ; len(s) >= 1; s = s[1:] {
+				if horizontal {
+					in.lineTo(s[0], 0)
+				} else {
+					in.lineTo(0, s[0])
+				}
+				horizontal = !horizontal
+			}
+		case csRrcurveto:
+			for ; len(s) >= 6; s = s[6:] {
+				in.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			}
+		case csRcurveline:
+			for ; len(s) >= 8; s = s[6:] {
+				in.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			}
+			if len(s) >= 2 {
+				in.lineTo(s[0], s[1])
+			}
+		case csRlinecurve:
+			for ; len(s) >= 8; s = s[2:] {
+				in.lineTo(s[0], s[1])
+			}
+			if len(s) >= 6 {
+				in.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			}
+		case csVvcurveto:
+			var dx1 float64
+			if len(s)%2 == 1 {
+				dx1, s = s[0], s[1:]
+			}
+			for ; len(s) >= 4; s = s[4:] {
+				in.curveTo(dx1, s[0], s[1], s[2], 0, s[3])
+				dx1 = 0
+			}
+		case csHhcurveto:
+			var dy1 float64
+			if len(s)%2 == 1 {
+				dy1, s = s[0], s[1:]
+			}
+			for ; len(s) >= 4; s = s[4:] {
+				in.curveTo(s[0], dy1, s[1], s[2], s[3], 0)
+				dy1 = 0
+			}
+		case csHvcurveto, csVhcurveto:
+			horizontal := op == csHvcurveto
+			for len(s) >= 4 {
+				// The last curve may have an extra argument.
+				var extra float64
+				if len(s) == 5 {
+					extra = s[4]
+				}
+				if horizontal {
+					in.curveTo(s[0], 0, s[1], s[2], extra, s[3])
+				} else {
+					in.curveTo(0, s[0], s[1], s[2], s[3], extra)
+				}
+				s = s[4:]
+				horizontal = !horizontal
+			}
+		case csHflex:
+			if len(s) < 7 {
+				return errors.New("charstring stack underflow")
+			}
+			in.curveTo(s[0], 0, s[1], s[2], s[3], 0)
+			in.curveTo(s[4], 0, s[5], -s[2], s[6], 0)
+		case csFlex:
+			if len(s) < 13 {
+				return errors.New("charstring stack underflow")
+			}
+			in.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			in.curveTo(s[6], s[7], s[8], s[9], s[10], s[11])
+		case csHflex1:
+			if len(s) < 9 {
+				return errors.New("charstring stack underflow")
+			}
+			in.curveTo(s[0], s[1], s[2], s[3], s[4], 0)
+			in.curveTo(s[5], 0, s[6], s[7], s[8], -(s[1] + s[3] + s[7]))
+		case csFlex1:
+			if len(s) < 11 {
+				return errors.New("charstring stack underflow")
+			}
+			var dx, dy float64
+			for i := 0; i < 10; i += 2 {
+				dx += s[i]
+				dy += s[i+1]
+			}
+			in.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			if math.Abs(dx) > math.Abs(dy) {
+				in.curveTo(s[6], s[7], s[8], s[9], s[10], -dy)
+			} else {
+				in.curveTo(s[6], s[7], s[8], s[9], -dx, s[10])
+			}
+		case csVSIndex:
+			if !in.cff.cff2 {
+				return errors.New("vsindex operator in CFF charstring")
+			}
+			if len(s) < 1 {
+				return errors.New("charstring stack underflow")
+			}
+			in.vsindex = int(s[len(s)-1])
+			in.scalars = nil
+		case csBlend:
+			if !in.cff.cff2 {
+				return errors.New("blend operator in CFF charstring")
+			}
+			if err := in.blend(); err != nil {
+				return err
+			}
+			// Blend leaves its results on the stack.
+			continue
+		default:
+			return fmt.Errorf("unsupported charstring operator %d", op)
+		}
+		in.stack = in.stack[:0]
+	}
+	return nil
+}
+
+// skipWidth drops the glyph's advance width, which CFF charstrings pass as an
+// additional operand to the first stack-clearing operator.
+func (in *charstringInterp) skipWidth(hasWidth bool) {
+	if !in.cff.cff2 && !in.seenWidth && hasWidth && len(in.stack) > 0 {
+		copy(in.stack, in.stack[1:])
+		in.stack = in.stack[:len(in.stack)-1]
+	}
+	in.seenWidth = true
+}
+
+// blend replaces default values and their deltas on the stack with the
+// values for the current instance.
+func (in *charstringInterp) blend() error {
+	s := in.stack
+	if len(s) < 1 {
+		return errors.New("charstring stack underflow")
+	}
+	n := int(s[len(s)-1])
+	s = s[:len(s)-1]
+	scalars := in.regionScalars()
+	k := len(scalars)
+	if n < 0 || len(s) < n*(k+1) {
+		return errors.New("charstring stack underflow")
+	}
+	base := len(s) - n*(k+1)
+	values := s[base : base+n]
+	deltas := s[base+n:]
+	for i := range values {
+		for j, scalar := range scalars {
+			values[i] += deltas[i*k+j] * scalar
+		}
+	}
+	in.stack = s[:base+n]
+	return nil
+}
+
+// regionScalars returns the scalars of the regions referenced by the current
+// item variation data.
+func (in *charstringInterp) regionScalars() []float64 {
+	if in.scalars != nil || !in.cff.hasVarStore {
+		return in.scalars
+	}
+	var data ItemVariationDataSubtable
+	if !in.cff.varStore.ItemVariationData(in.vsindex, &data) {
+		return nil
+	}
+	in.scalars = make([]float64, data.NumRegionIndexes())
+	for i, region := range data.RegionIndexes() {
+		in.scalars[i] = in.cff.regions.RegionScalar(int(region), in.coords)
+	}
+	return in.scalars
+}
+
+func (in *charstringInterp) moveTo(dx, dy float64) {
+	in.closePath()
+	in.x += dx
+	in.y += dy
+	in.path.MoveTo(curve.Pt(in.x, in.y))
+	in.open = true
+}
+
+func (in *charstringInterp) lineTo(dx, dy float64) {
+	in.startContour()
+	in.x += dx
+	in.y += dy
+	in.path.LineTo(curve.Pt(in.x, in.y))
+}
+
+func (in *charstringInterp) curveTo(dxa, dya, dxb, dyb, dxc, dyc float64) {
+	in.startContour()
+	p1 := curve.Pt(in.x+dxa, in.y+dya)
+	p2 := curve.Pt(p1.X+dxb, p1.Y+dyb)
+	p3 := curve.Pt(p2.X+dxc, p2.Y+dyc)
+	in.path.CubicTo(p1, p2, p3)
+	in.x, in.y = p3.X, p3.Y
+}
+
+// startContour starts a contour at the current point if the charstring draws
+// without moving first, which malformed charstrings may do.
+func (in *charstringInterp) startContour() {
+	if !in.open {
+		in.path.MoveTo(curve.Pt(in.x, in.y))
+		in.open = true
+	}
+}
+
+// closePath closes the current contour. Contours in charstrings are closed
+// implicitly.
+func (in *charstringInterp) closePath() {
+	if in.open {
+		in.path.ClosePath()
+		in.open = false
+	}
+}
@@ -6,6 +6,7 @@ package opentype
 
 import (
 	"cmp"
+	"iter"
 	"sort"
 )
 
@@ -65,6 +66,21 @@ func (cmap *CmapSubtable) Lookup(r rune) GlyphID {
 	}
 }
 
+// Mappings returns the subtable's mappings from runes to glyphs, in ascending
+// order of runes. Runes that map to the missing glyph are skipped.
+func (cmap *CmapSubtable) Mappings() iter.Seq2[rune, GlyphID] {
+	switch cmap.Format {
+	case 4:
+		return cmap.Format4.Mappings()
+	case 12:
+		return cmap.Format12.Mappings()
+	case 13:
+		return cmap.Format13.Mappings()
+	default:
+		panic("not implemented")
+	}
+}
+
 func (tbl *CmapSubtableFormat4) Lookup(r rune) GlyphID {
 	n := tbl.NumEndCodes()
 	i := sort.Search(n, func(i int) bool {
@@ -104,6 +120,22 @@ func (tbl *CmapSubtableFormat4) Lookup(r rune) GlyphID {
 	}
 }
 
+func (tbl *CmapSubtableFormat4) Mappings() iter.Seq2[rune, GlyphID] {
+	return func(yield func(rune, GlyphID) bool) {
+		for i := range tbl.NumEndCodes() {
+			// OPT(dh): decode the segment directly instead of searching for it
+			// for every rune.
+			for r := rune(tbl.StartCode(i)); r <= rune(tbl.EndCode(i)) && r < 0xFFFF; r++ {
+				if g := tbl.Lookup(r); g != 0 {
+					if !yield(r, g) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
 func (tbl *CmapSubtableFormat12) Lookup(r rune) GlyphID {
 	n := tbl.NumGroups()
 	i := sort.Search(n, func(i int) bool {
@@ -121,6 +153,19 @@ func (tbl *CmapSubtableFormat12) Lookup(r rune) GlyphID {
 	return GlyphID(r - rune(group.StartCharCode) + rune(group.StartGlyphID))
 }
 
+func (tbl *CmapSubtableFormat12) Mappings() iter.Seq2[rune, GlyphID] {
+	return func(yield func(rune, GlyphID) bool) {
+		for _, group := range tbl.Groups() {
+			for r := rune(group.StartCharCode); r <= rune(group.EndCharCode); r++ {
+				g := GlyphID(r - rune(group.StartCharCode) + rune(group.StartGlyphID))
+				if g != 0 && !yield(r, g) {
+					return
+				}
+			}
+		}
+	}
+}
+
 func (tbl *CmapSubtableFormat13) Lookup(r rune) GlyphID {
 	n := tbl.NumGroups()
 	i := sort.Search(n, func(i int) bool {
@@ -138,6 +183,21 @@ func (tbl *CmapSubtableFormat13) Lookup(r rune) GlyphID {
 	return GlyphID(group.GlyphID)
 }
 
+func (tbl *CmapSubtableFormat13) Mappings() iter.Seq2[rune, GlyphID] {
+	return func(yield func(rune, GlyphID) bool) {
+		for _, group := range tbl.Groups() {
+			if group.GlyphID == 0 {
+				continue
+			}
+			for r := rune(group.StartCharCode); r <= rune(group.EndCharCode); r++ {
+				if !yield(r, GlyphID(group.GlyphID)) {
+					return
+				}
+			}
+		}
+	}
+}
+
 // Table preference (preferring 32-bit over 16 over 8, sorted by how likely we
 // are to find it in the font):
 //
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// IsCollection reports whether data starts with the header of a font
+// collection.
+func IsCollection(data []byte) bool {
+	return len(data) >= 4 && string(data[:4]) == "ttcf"
+}
+
+// ParseCollection parses the header of a font collection.
+func ParseCollection(data []byte, out *CollectionHeader) error {
+	if !IsCollection(data) {
+		return errors.New("not a font collection")
+	}
+	if len(data) < 12 {
+		return errors.New("truncated collection header")
+	}
+	n := int(binary.BigEndian.Uint32(data[8:12]))
+	if len(data) < 12+n*4 {
+		return errors.New("truncated collection header")
+	}
+	parseCollectionHeader(data, out)
+	if out.MajorVersion > 2 {
+		return fmt.Errorf("unsupported collection version %d", out.MajorVersion)
+	}
+	return nil
+}
+
+// NumFonts returns the number of fonts in the collection.
+func (hdr *CollectionHeader) NumFonts() int {
+	return hdr.NumTableDirectoryOffsets()
+}
+
+// TableDirectory parses the table directory of the i-th font in the
+// collection.
+func (hdr *CollectionHeader) TableDirectory(i int, out *TableDirectory) error {
+	off := int(binary.BigEndian.Uint32(hdr.tableDirectoryOffsets[i*4:]))
+	if err := parseTableDirectory(hdr.data, off, out); err != nil {
+		return fmt.Errorf("font %d: %w", i, err)
+	}
+	return nil
+}
+
+// parseTableDirectory parses the table directory at offset off in a font
+// file and checks that the table records and the tables they point to lie
+// within the file.
+func parseTableDirectory(data []byte, off int, out *TableDirectory) error {
+	if off < 0 || off+12 > len(data) {
+		return errors.New("truncated table directory")
+	}
+	n := int(binary.BigEndian.Uint16(data[off+4:]))
+	if off+12+n*16 > len(data) {
+		return errors.New("truncated table directory")
+	}
+	ParseTableDirectory(data[off:], out)
+	// Table offsets are relative to the start of the file, not the start of
+	// the table directory.
+	out.data = data
+	for _, rec := range out.TableRecords() {
+		if int64(rec.Offset)+int64(rec.Length) > int64(len(data)) {
+			return fmt.Errorf("%s table is out of bounds", rec.Tag)
+		}
+	}
+	return nil
+}
+
+// ParseFont parses the table directory of a font. Data may either contain a
+// single font or a font collection, in which case index selects the font.
+// Index must be 0 for single fonts.
+func ParseFont(data []byte, index int, out *TableDirectory) error {
+	if !IsCollection(data) {
+		if index != 0 {
+			return fmt.Errorf("font index %d out of range", index)
+		}
+		return parseTableDirectory(data, 0, out)
+	}
+	var hdr CollectionHeader
+	if err := ParseCollection(data, &hdr); err != nil {
+		return err
+	}
+	if index < 0 || index >= hdr.NumFonts() {
+		return fmt.Errorf("font index %d out of range", index)
+	}
+	return hdr.TableDirectory(index, out)
+}
+
+// NumFonts returns the number of fonts in data, which may either contain a
+// single font or a font collection.
+func NumFonts(data []byte) int {
+	if !IsCollection(data) {
+		return 1
+	}
+	var hdr CollectionHeader
+	if err := ParseCollection(data, &hdr); err != nil {
+		return 0
+	}
+	return hdr.NumFonts()
+}
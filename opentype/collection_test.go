@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// collection builds a font collection from fonts.
+func collection(fonts ...[]byte) []byte {
+	be := binary.BigEndian
+	var out []byte
+	out = append(out, "ttcf"...)
+	out = be.AppendUint32(out, 0x00010000)
+	out = be.AppendUint32(out, uint32(len(fonts)))
+	off := 12 + 4*len(fonts)
+	for _, font := range fonts {
+		out = be.AppendUint32(out, uint32(off))
+		off += len(font)
+	}
+	for _, font := range fonts {
+		// Table offsets are relative to the start of the collection, so we
+		// have to move the font's tables.
+		off := uint32(len(out))
+		out = append(out, font...)
+		face := out[off:]
+		numTables := int(be.Uint16(face[4:]))
+		for i := range numTables {
+			rec := face[12+16*i:]
+			be.PutUint32(rec[8:], be.Uint32(rec[8:])+off)
+		}
+	}
+	return out
+}
+
+// tableData returns the data of a table in a font.
+func tableData(t *testing.T, data []byte, index int, tag Tag) []byte {
+	t.Helper()
+	var dir TableDirectory
+	if err := ParseFont(data, index, &dir); err != nil {
+		t.Fatalf("font %d: %s", index, err)
+	}
+	rec, ok := dir.FindTable(tag)
+	if !ok {
+		t.Fatalf("font %d has no %s table", index, tag)
+	}
+	return rec.Data()
+}
+
+func TestParseCollection(t *testing.T) {
+	fonts := [][]byte{goregular.TTF, gomono.TTF}
+	data := collection(fonts...)
+
+	var hdr CollectionHeader
+	if err := ParseCollection(data, &hdr); err != nil {
+		t.Fatal(err)
+	}
+	if n := hdr.NumFonts(); n != 2 {
+		t.Errorf("header has %d fonts, want 2", n)
+	}
+	if n := NumFonts(data); n != 2 {
+		t.Errorf("NumFonts returned %d, want 2", n)
+	}
+	for i, font := range fonts {
+		for _, tag := range []Tag{"head", "glyf"} {
+			if !bytes.Equal(tableData(t, data, i, tag), tableData(t, font, 0, tag)) {
+				t.Errorf("font %d has the wrong %s table", i, tag)
+			}
+		}
+	}
+
+	var dir TableDirectory
+	for _, index := range []int{-1, 2} {
+		if err := ParseFont(data, index, &dir); err == nil {
+			t.Errorf("font index %d didn't cause an error", index)
+		}
+	}
+	if err := ParseCollection(goregular.TTF, &hdr); err == nil {
+		t.Error("parsing a single font as a collection didn't cause an error")
+	}
+}
+
+func TestParseFont(t *testing.T) {
+	data := goregular.TTF
+	if n := NumFonts(data); n != 1 {
+		t.Errorf("NumFonts returned %d, want 1", n)
+	}
+	var dir TableDirectory
+	if err := ParseFont(data, 0, &dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dir.FindTable("cmap"); !ok {
+		t.Error("font has no cmap table")
+	}
+	if err := ParseFont(data, 1, &dir); err == nil {
+		t.Error("font index 1 of a single font didn't cause an error")
+	}
+}
+
+func TestParseTruncated(t *testing.T) {
+	coll := collection(goregular.TTF, gomono.TTF)
+	// A collection whose second table directory lies beyond the end of the
+	// file.
+	badOffset := bytes.Clone(coll)
+	binary.BigEndian.PutUint32(badOffset[16:], uint32(len(coll)))
+
+	tests := []struct {
+		name string
+		data []byte
+		// The number of fonts that NumFonts reports, and the index of the
+		// font to parse.
+		numFonts, index int
+	}{
+		{"empty", nil, 1, 0},
+		{"short table directory", goregular.TTF[:8], 1, 0},
+		{"missing table records", []byte("\x00\x01\x00\x00\xff\xffjunkjunkjunkjunk"), 1, 0},
+		{"tables out of bounds", goregular.TTF[:len(goregular.TTF)/2], 1, 0},
+		{"short collection header", coll[:10], 0, 0},
+		{"missing table directory offsets", coll[:16], 0, 0},
+		{"table directory out of bounds", badOffset, 2, 1},
+		{"collection tables out of bounds", coll[:len(coll)-len(gomono.TTF)/2], 2, 1},
+	}
+	for _, tt := range tests {
+		if n := NumFonts(tt.data); n != tt.numFonts {
+			t.Errorf("%s: NumFonts returned %d, want %d", tt.name, n, tt.numFonts)
+		}
+		var dir TableDirectory
+		if err := ParseFont(tt.data, tt.index, &dir); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
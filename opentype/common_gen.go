@@ -287,6 +287,658 @@ type VariationIndexTable struct {
 	DeltaSetInnerIndex uint16
 	DeltaFormat        uint16
 }
+
+type ChainedSequenceContextTable struct {
+	data []byte
+
+	Format  uint16
+	Format1 ChainedSequenceContextTableFormat1
+	Format2 ChainedSequenceContextTableFormat2
+	Format3 ChainedSequenceContextTableFormat3
+}
+
+type ChainedSequenceContextTableFormat1 struct {
+	parentData []byte
+
+	coverageOffset Offset16[CoverageTable]
+
+	chainedSeqRuleSetOffsets Slice[Offset16[ChainedSequenceRuleSetTable]]
+}
+
+type ChainedSequenceRuleSetTable struct {
+	data []byte
+
+	chainedSeqRuleOffsets Slice[Offset16[ChainedSequenceRuleTable]]
+}
+
+type ChainedSequenceRuleTable struct {
+	backtrackSequence Slice[uint16]
+
+	inputSequence Slice[uint16]
+
+	lookaheadSequence Slice[uint16]
+
+	seqLookupRecords Slice[SequenceLookupRecord]
+}
+
+type ChainedSequenceContextTableFormat2 struct {
+	parentData []byte
+
+	coverageOffset          Offset16[CoverageTable]
+	backtrackClassDefOffset Offset16[ClassDefTable]
+	inputClassDefOffset     Offset16[ClassDefTable]
+	lookaheadClassDefOffset Offset16[ClassDefTable]
+
+	chainedClassSeqRuleSetOffsets Slice[Offset16[ChainedClassSequenceRuleSetTable]]
+}
+
+type ChainedClassSequenceRuleSetTable struct {
+	data []byte
+
+	chainedClassSeqRuleOffsets Slice[Offset16[ChainedClassSequenceRuleTable]]
+}
+
+type ChainedClassSequenceRuleTable struct {
+	backtrackSequence Slice[uint16]
+
+	inputSequence Slice[uint16]
+
+	lookaheadSequence Slice[uint16]
+
+	seqLookupRecords Slice[SequenceLookupRecord]
+}
+
+type ChainedSequenceContextTableFormat3 struct {
+	parentData []byte
+
+	backtrackCoverageOffsets Slice[Offset16[CoverageTable]]
+
+	inputCoverageOffsets Slice[Offset16[CoverageTable]]
+
+	lookaheadCoverageOffsets Slice[Offset16[CoverageTable]]
+
+	seqLookupRecords Slice[SequenceLookupRecord]
+}
+func ParseChainedClassSequenceRuleSetTable(buf []byte, out *ChainedClassSequenceRuleSetTable) int {
+	*out = ChainedClassSequenceRuleSetTable{}
+	origBuf := buf
+	var dynSize int
+	var chainedClassSeqRuleCount uint16
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &chainedClassSeqRuleCount)
+	{
+		n := int(chainedClassSeqRuleCount)
+		/* FIXME: check that buf is long enough */
+		out.chainedClassSeqRuleOffsets = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func (tbl *ChainedClassSequenceRuleSetTable) NumChainedClassSeqRuleOffsets() int {
+	return len(tbl.chainedClassSeqRuleOffsets) / 2
+}
+
+func ParseChainedClassSequenceRuleTable(buf []byte, out *ChainedClassSequenceRuleTable) int {
+	*out = ChainedClassSequenceRuleTable{}
+	origBuf := buf
+	var dynSize int
+	var backtrackGlyphCount uint16
+	var inputGlyphCount uint16
+	var lookaheadGlyphCount uint16
+	var seqLookupCount uint16
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &backtrackGlyphCount)
+	{
+		n := int(backtrackGlyphCount)
+		/* FIXME: check that buf is long enough */
+		out.backtrackSequence = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &inputGlyphCount)
+	{
+		n := int(inputGlyphCount - 1)
+		/* FIXME: check that buf is long enough */
+		out.inputSequence = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &lookaheadGlyphCount)
+	{
+		n := int(lookaheadGlyphCount)
+		/* FIXME: check that buf is long enough */
+		out.lookaheadSequence = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &seqLookupCount)
+	{
+		n := int(seqLookupCount)
+		/* FIXME: check that buf is long enough */
+		out.seqLookupRecords = buf[2 : 2+n*4]
+		dynSize += n * 4
+		buf = buf[2+n*4:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func (tbl *ChainedClassSequenceRuleTable) GetBacktrackSequence(i int) uint16 {
+	var out uint16
+	parseUint16(tbl.backtrackSequence[i*2:(i+1)*2:len(tbl.backtrackSequence)], &out)
+	return out
+}
+
+func (tbl *ChainedClassSequenceRuleTable) BacktrackSequence() iter.Seq2[int, uint16] {
+	return func(yield func(int, uint16) bool) {
+		for i := range tbl.NumBacktrackSequence() {
+			if !yield(i, tbl.GetBacktrackSequence(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *ChainedClassSequenceRuleTable) NumBacktrackSequence() int {
+	return len(tbl.backtrackSequence) / 2
+}
+
+func (tbl *ChainedClassSequenceRuleTable) GetInputSequence(i int) uint16 {
+	var out uint16
+	parseUint16(tbl.inputSequence[i*2:(i+1)*2:len(tbl.inputSequence)], &out)
+	return out
+}
+
+func (tbl *ChainedClassSequenceRuleTable) InputSequence() iter.Seq2[int, uint16] {
+	return func(yield func(int, uint16) bool) {
+		for i := range tbl.NumInputSequence() {
+			if !yield(i, tbl.GetInputSequence(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *ChainedClassSequenceRuleTable) NumInputSequence() int {
+	return len(tbl.inputSequence) / 2
+}
+
+func (tbl *ChainedClassSequenceRuleTable) GetLookaheadSequence(i int) uint16 {
+	var out uint16
+	parseUint16(tbl.lookaheadSequence[i*2:(i+1)*2:len(tbl.lookaheadSequence)], &out)
+	return out
+}
+
+func (tbl *ChainedClassSequenceRuleTable) LookaheadSequence() iter.Seq2[int, uint16] {
+	return func(yield func(int, uint16) bool) {
+		for i := range tbl.NumLookaheadSequence() {
+			if !yield(i, tbl.GetLookaheadSequence(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *ChainedClassSequenceRuleTable) NumLookaheadSequence() int {
+	return len(tbl.lookaheadSequence) / 2
+}
+
+func (tbl *ChainedClassSequenceRuleTable) SeqLookupRecord(i int) SequenceLookupRecord {
+	var out SequenceLookupRecord
+	parseSequenceLookupRecord(tbl.seqLookupRecords[i*4:(i+1)*4:len(tbl.seqLookupRecords)], &out)
+	return out
+}
+
+func (tbl *ChainedClassSequenceRuleTable) SeqLookupRecords() iter.Seq2[int, SequenceLookupRecord] {
+	return func(yield func(int, SequenceLookupRecord) bool) {
+		for i := range tbl.NumSeqLookupRecords() {
+			if !yield(i, tbl.SeqLookupRecord(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *ChainedClassSequenceRuleTable) NumSeqLookupRecords() int {
+	return len(tbl.seqLookupRecords) / 4
+}
+
+func ParseChainedSequenceContextTable(buf []byte, out *ChainedSequenceContextTable) int {
+	*out = ChainedSequenceContextTable{}
+	origBuf := buf
+	var dynSize int
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.Format)
+
+	switch out.Format {
+	case 1:
+		dynSize += parseChainedSequenceContextTableFormat1(buf[2:], buf, &out.Format1)
+	case 2:
+		dynSize += parseChainedSequenceContextTableFormat2(buf[2:], buf, &out.Format2)
+	case 3:
+		dynSize += parseChainedSequenceContextTableFormat3(buf[2:], buf, &out.Format3)
+	}
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseChainedSequenceContextTableFormat1(buf, parentBuf []byte, out *ChainedSequenceContextTableFormat1) int {
+	*out = ChainedSequenceContextTableFormat1{}
+	origBuf := buf
+	var dynSize int
+	var chainedSeqRuleSetCount uint16
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 4 {
+		return dynSize
+	}
+	parseOffset16(buf[0:2], &out.coverageOffset)
+	parseUint16(buf[2:4], &chainedSeqRuleSetCount)
+	{
+		n := int(chainedSeqRuleSetCount)
+		/* FIXME: check that buf is long enough */
+		out.chainedSeqRuleSetOffsets = buf[4 : 4+n*2]
+		dynSize += n * 2
+		buf = buf[4+n*2:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *ChainedSequenceContextTableFormat1) Coverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.coverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.coverageOffset:], into)
+	return true
+}
+
+func (tbl *ChainedSequenceContextTableFormat1) NumChainedSeqRuleSetOffsets() int {
+	return len(tbl.chainedSeqRuleSetOffsets) / 2
+}
+
+func parseChainedSequenceContextTableFormat2(buf, parentBuf []byte, out *ChainedSequenceContextTableFormat2) int {
+	*out = ChainedSequenceContextTableFormat2{}
+	origBuf := buf
+	var dynSize int
+	var chainedClassSeqRuleSetCount uint16
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 10 {
+		return dynSize
+	}
+	parseOffset16(buf[0:2], &out.coverageOffset)
+	parseOffset16(buf[2:4], &out.backtrackClassDefOffset)
+	parseOffset16(buf[4:6], &out.inputClassDefOffset)
+	parseOffset16(buf[6:8], &out.lookaheadClassDefOffset)
+	parseUint16(buf[8:10], &chainedClassSeqRuleSetCount)
+	{
+		n := int(chainedClassSeqRuleSetCount)
+		/* FIXME: check that buf is long enough */
+		out.chainedClassSeqRuleSetOffsets = buf[10 : 10+n*2]
+		dynSize += n * 2
+		buf = buf[10+n*2:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *ChainedSequenceContextTableFormat2) Coverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.coverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.coverageOffset:], into)
+	return true
+}
+
+func (tbl *ChainedSequenceContextTableFormat2) BacktrackClassDef(into *ClassDefTable) bool {
+	// FIXME: check bounds
+	if tbl.backtrackClassDefOffset == 0 {
+		return false
+	}
+	ParseClassDefTable(tbl.parentData[tbl.backtrackClassDefOffset:], into)
+	return true
+}
+
+func (tbl *ChainedSequenceContextTableFormat2) InputClassDef(into *ClassDefTable) bool {
+	// FIXME: check bounds
+	if tbl.inputClassDefOffset == 0 {
+		return false
+	}
+	ParseClassDefTable(tbl.parentData[tbl.inputClassDefOffset:], into)
+	return true
+}
+
+func (tbl *ChainedSequenceContextTableFormat2) LookaheadClassDef(into *ClassDefTable) bool {
+	// FIXME: check bounds
+	if tbl.lookaheadClassDefOffset == 0 {
+		return false
+	}
+	ParseClassDefTable(tbl.parentData[tbl.lookaheadClassDefOffset:], into)
+	return true
+}
+
+func (tbl *ChainedSequenceContextTableFormat2) NumChainedClassSeqRuleSetOffsets() int {
+	return len(tbl.chainedClassSeqRuleSetOffsets) / 2
+}
+
+func parseChainedSequenceContextTableFormat3(buf, parentBuf []byte, out *ChainedSequenceContextTableFormat3) int {
+	*out = ChainedSequenceContextTableFormat3{}
+	origBuf := buf
+	var dynSize int
+	var backtrackGlyphCount uint16
+	var inputGlyphCount uint16
+	var lookaheadGlyphCount uint16
+	var seqLookupCount uint16
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &backtrackGlyphCount)
+	{
+		n := int(backtrackGlyphCount)
+		/* FIXME: check that buf is long enough */
+		out.backtrackCoverageOffsets = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &inputGlyphCount)
+	{
+		n := int(inputGlyphCount)
+		/* FIXME: check that buf is long enough */
+		out.inputCoverageOffsets = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &lookaheadGlyphCount)
+	{
+		n := int(lookaheadGlyphCount)
+		/* FIXME: check that buf is long enough */
+		out.lookaheadCoverageOffsets = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &seqLookupCount)
+	{
+		n := int(seqLookupCount)
+		/* FIXME: check that buf is long enough */
+		out.seqLookupRecords = buf[2 : 2+n*4]
+		dynSize += n * 4
+		buf = buf[2+n*4:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func (tbl *ChainedSequenceContextTableFormat3) NumBacktrackCoverageOffsets() int {
+	return len(tbl.backtrackCoverageOffsets) / 2
+}
+
+func (tbl *ChainedSequenceContextTableFormat3) NumInputCoverageOffsets() int {
+	return len(tbl.inputCoverageOffsets) / 2
+}
+
+func (tbl *ChainedSequenceContextTableFormat3) NumLookaheadCoverageOffsets() int {
+	return len(tbl.lookaheadCoverageOffsets) / 2
+}
+
+func (tbl *ChainedSequenceContextTableFormat3) SeqLookupRecord(i int) SequenceLookupRecord {
+	var out SequenceLookupRecord
+	parseSequenceLookupRecord(tbl.seqLookupRecords[i*4:(i+1)*4:len(tbl.seqLookupRecords)], &out)
+	return out
+}
+
+func (tbl *ChainedSequenceContextTableFormat3) SeqLookupRecords() iter.Seq2[int, SequenceLookupRecord] {
+	return func(yield func(int, SequenceLookupRecord) bool) {
+		for i := range tbl.NumSeqLookupRecords() {
+			if !yield(i, tbl.SeqLookupRecord(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *ChainedSequenceContextTableFormat3) NumSeqLookupRecords() int {
+	return len(tbl.seqLookupRecords) / 4
+}
+
+func ParseChainedSequenceRuleSetTable(buf []byte, out *ChainedSequenceRuleSetTable) int {
+	*out = ChainedSequenceRuleSetTable{}
+	origBuf := buf
+	var dynSize int
+	var chainedSeqRuleCount uint16
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &chainedSeqRuleCount)
+	{
+		n := int(chainedSeqRuleCount)
+		/* FIXME: check that buf is long enough */
+		out.chainedSeqRuleOffsets = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func (tbl *ChainedSequenceRuleSetTable) NumChainedSeqRuleOffsets() int {
+	return len(tbl.chainedSeqRuleOffsets) / 2
+}
+
+func ParseChainedSequenceRuleTable(buf []byte, out *ChainedSequenceRuleTable) int {
+	*out = ChainedSequenceRuleTable{}
+	origBuf := buf
+	var dynSize int
+	var backtrackGlyphCount uint16
+	var inputGlyphCount uint16
+	var lookaheadGlyphCount uint16
+	var seqLookupCount uint16
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &backtrackGlyphCount)
+	{
+		n := int(backtrackGlyphCount)
+		/* FIXME: check that buf is long enough */
+		out.backtrackSequence = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &inputGlyphCount)
+	{
+		n := int(inputGlyphCount - 1)
+		/* FIXME: check that buf is long enough */
+		out.inputSequence = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &lookaheadGlyphCount)
+	{
+		n := int(lookaheadGlyphCount)
+		/* FIXME: check that buf is long enough */
+		out.lookaheadSequence = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &seqLookupCount)
+	{
+		n := int(seqLookupCount)
+		/* FIXME: check that buf is long enough */
+		out.seqLookupRecords = buf[2 : 2+n*4]
+		dynSize += n * 4
+		buf = buf[2+n*4:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func (tbl *ChainedSequenceRuleTable) GetBacktrackSequence(i int) uint16 {
+	var out uint16
+	parseUint16(tbl.backtrackSequence[i*2:(i+1)*2:len(tbl.backtrackSequence)], &out)
+	return out
+}
+
+func (tbl *ChainedSequenceRuleTable) BacktrackSequence() iter.Seq2[int, uint16] {
+	return func(yield func(int, uint16) bool) {
+		for i := range tbl.NumBacktrackSequence() {
+			if !yield(i, tbl.GetBacktrackSequence(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *ChainedSequenceRuleTable) NumBacktrackSequence() int {
+	return len(tbl.backtrackSequence) / 2
+}
+
+func (tbl *ChainedSequenceRuleTable) GetInputSequence(i int) uint16 {
+	var out uint16
+	parseUint16(tbl.inputSequence[i*2:(i+1)*2:len(tbl.inputSequence)], &out)
+	return out
+}
+
+func (tbl *ChainedSequenceRuleTable) InputSequence() iter.Seq2[int, uint16] {
+	return func(yield func(int, uint16) bool) {
+		for i := range tbl.NumInputSequence() {
+			if !yield(i, tbl.GetInputSequence(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *ChainedSequenceRuleTable) NumInputSequence() int {
+	return len(tbl.inputSequence) / 2
+}
+
+func (tbl *ChainedSequenceRuleTable) GetLookaheadSequence(i int) uint16 {
+	var out uint16
+	parseUint16(tbl.lookaheadSequence[i*2:(i+1)*2:len(tbl.lookaheadSequence)], &out)
+	return out
+}
+
+func (tbl *ChainedSequenceRuleTable) LookaheadSequence() iter.Seq2[int, uint16] {
+	return func(yield func(int, uint16) bool) {
+		for i := range tbl.NumLookaheadSequence() {
+			if !yield(i, tbl.GetLookaheadSequence(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *ChainedSequenceRuleTable) NumLookaheadSequence() int {
+	return len(tbl.lookaheadSequence) / 2
+}
+
+func (tbl *ChainedSequenceRuleTable) SeqLookupRecord(i int) SequenceLookupRecord {
+	var out SequenceLookupRecord
+	parseSequenceLookupRecord(tbl.seqLookupRecords[i*4:(i+1)*4:len(tbl.seqLookupRecords)], &out)
+	return out
+}
+
+func (tbl *ChainedSequenceRuleTable) SeqLookupRecords() iter.Seq2[int, SequenceLookupRecord] {
+	return func(yield func(int, SequenceLookupRecord) bool) {
+		for i := range tbl.NumSeqLookupRecords() {
+			if !yield(i, tbl.SeqLookupRecord(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *ChainedSequenceRuleTable) NumSeqLookupRecords() int {
+	return len(tbl.seqLookupRecords) / 4
+}
+
 func ParseClassDefTable(buf []byte, out *ClassDefTable) int {
 	*out = ClassDefTable{}
 	origBuf := buf
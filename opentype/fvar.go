@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import (
+	"encoding/binary"
+	"iter"
+)
+
+// Normalize maps a user-space axis value to a normalized coordinate in the
+// range [-1, 1], with the axis's default value mapping to 0. It doesn't apply
+// avar mappings.
+func (rec *VariationAxisRecord) Normalize(v float64) float64 {
+	lo, def, hi := rec.MinValue.Float(), rec.DefaultValue.Float(), rec.MaxValue.Float()
+	v = max(lo, min(hi, v))
+	switch {
+	case v < def && def > lo:
+		return -(def - v) / (def - lo)
+	case v > def && hi > def:
+		return (v - def) / (hi - def)
+	default:
+		return 0
+	}
+}
+
+// InstanceRecord is a named instance of a variable font.
+type InstanceRecord struct {
+	SubfamilyNameID NameID
+	Flags           uint16
+	// Coordinates holds the instance's user-space value for each axis.
+	Coordinates []float64
+	// PostScriptNameID is the name ID of the instance's PostScript name, or
+	// 0xFFFF if the instance doesn't have one.
+	PostScriptNameID NameID
+}
+
+// NumInstances returns the number of named instances.
+func (tbl *FvarTable) NumInstances() int {
+	return int(tbl.instanceCount)
+}
+
+// Instance returns the i-th named instance.
+func (tbl *FvarTable) Instance(i int) (InstanceRecord, bool) {
+	if i < 0 || i >= tbl.NumInstances() {
+		return InstanceRecord{}, false
+	}
+	axes := tbl.NumAxes()
+	size := int(tbl.instanceSize)
+	off := int(tbl.axesArrayOffset) + len(tbl.axes) + i*size
+	if size < 4+axes*4 || off+size > len(tbl.data) {
+		return InstanceRecord{}, false
+	}
+	b := tbl.data[off : off+size]
+
+	out := InstanceRecord{
+		SubfamilyNameID:  NameID(binary.BigEndian.Uint16(b)),
+		Flags:            binary.BigEndian.Uint16(b[2:]),
+		Coordinates:      make([]float64, axes),
+		PostScriptNameID: 0xFFFF,
+	}
+	for j := range out.Coordinates {
+		out.Coordinates[j] = Int16_16(binary.BigEndian.Uint32(b[4+j*4:])).Float()
+	}
+	// The PostScript name ID is optional, which is indicated by the size
+	// of the records.
+	if size >= 6+axes*4 {
+		out.PostScriptNameID = NameID(binary.BigEndian.Uint16(b[4+axes*4:]))
+	}
+	return out, true
+}
+
+// Instances returns all named instances.
+func (tbl *FvarTable) Instances() iter.Seq2[int, InstanceRecord] {
+	return func(yield func(int, InstanceRecord) bool) {
+		for i := range tbl.NumInstances() {
+			inst, ok := tbl.Instance(i)
+			if !ok || !yield(i, inst) {
+				return
+			}
+		}
+	}
+}
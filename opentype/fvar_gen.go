@@ -9,17 +9,21 @@ package opentype
 import "iter"
 
 type FvarTable struct {
+	data []byte
+
 	MajorVersion uint16
 	MinorVersion uint16
 
-	axisSize uint16
+	axesArrayOffset uint16
 
-	instanceSize uint16
+	axisSize      uint16
+	instanceCount uint16
+	instanceSize  uint16
 
 	axes Slice[VariationAxisRecord]
 
-	// XXX parse instances
-	// instances Slice[InstanceRecord]      `gen:"slice(offset=int(axesArrayOffset)+len(out.axes), count=instanceCount, size=instanceSize)"`
+	// The instance records follow the axes. Their size depends on the number
+	// of axes, which the parser generator can't express.
 }
 
 type VariationAxisRecord struct {
@@ -30,27 +34,12 @@ type VariationAxisRecord struct {
 	Flags        uint16
 	NameID       NameID
 }
-
-// type InstanceRecord struct {
-// 	Subfamily   NameID
-// 	Flags       uint16
-// 	Coordinates UserTuple
-
-// 	_          sizeDelimiter
-// 	PostScript NameID
-// }
-
-// type UserTuple struct {
-// 	// XXX axisCount from FvarTable
-// 	coordinates Slice[Int16_16] `gen:"slice(count=axisCount)"`
-// }
 func ParseFvarTable(buf []byte, out *FvarTable) int {
 	*out = FvarTable{}
 	origBuf := buf
 	var dynSize int
-	var axesArrayOffset uint16
 	var axisCount uint16
-	var instanceCount uint16
+	out.data = buf
 
 	/* FIXME return error */
 	if len(buf) < 16 {
@@ -62,15 +51,15 @@ func ParseFvarTable(buf []byte, out *FvarTable) int {
 		return dynSize
 	}
 	parseUint16(buf[2:4], &out.MinorVersion)
-	parseUint16(buf[4:6], &axesArrayOffset)
+	parseUint16(buf[4:6], &out.axesArrayOffset)
 	parseUint16(buf[8:10], &axisCount)
 	parseUint16(buf[10:12], &out.axisSize)
-	parseUint16(buf[12:14], &instanceCount)
+	parseUint16(buf[12:14], &out.instanceCount)
 	parseUint16(buf[14:16], &out.instanceSize)
 	{
 		sz := int(out.axisSize)
 		n := int(axisCount)
-		start := int(axesArrayOffset)
+		start := int(out.axesArrayOffset)
 		/* FIXME: check that buf is long enough */
 		out.axes = origBuf[start : start+n*sz]
 	}
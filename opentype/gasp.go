@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+// Range gasp behavior flags.
+const (
+	GaspGridfit            = 0x0001
+	GaspDoGray             = 0x0002
+	GaspSymmetricGridfit   = 0x0004
+	GaspSymmetricSmoothing = 0x0008
+)
+
+// Behavior returns the recommended rasterization behavior for a size in
+// pixels per em. It returns false if no range covers the size.
+func (tbl *GaspTable) Behavior(ppem uint16) (uint16, bool) {
+	for _, r := range tbl.GaspRanges() {
+		if ppem <= r.RangeMaxPPEM {
+			return r.RangeGaspBehavior, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,81 @@
+// Code generated by honnef.co/go/gutter/internal/cmd/gen_parser. DO NOT EDIT.
+
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import "iter"
+
+type GaspTable struct {
+	Version uint16
+
+	gaspRanges Slice[GaspRange]
+}
+
+type GaspRange struct {
+	RangeMaxPPEM      uint16
+	RangeGaspBehavior uint16
+}
+func parseGaspRange(buf []byte, out *GaspRange) int {
+	*out = GaspRange{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 4 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.RangeMaxPPEM)
+	parseUint16(buf[2:4], &out.RangeGaspBehavior)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func ParseGaspTable(buf []byte, out *GaspTable) int {
+	*out = GaspTable{}
+	origBuf := buf
+	var dynSize int
+	var numRanges uint16
+
+	/* FIXME return error */
+	if len(buf) < 4 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.Version)
+	parseUint16(buf[2:4], &numRanges)
+	{
+		n := int(numRanges)
+		/* FIXME: check that buf is long enough */
+		out.gaspRanges = buf[4 : 4+n*4]
+		dynSize += n * 4
+		buf = buf[4+n*4:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func (tbl *GaspTable) GaspRange(i int) GaspRange {
+	var out GaspRange
+	parseGaspRange(tbl.gaspRanges[i*4:(i+1)*4:len(tbl.gaspRanges)], &out)
+	return out
+}
+
+func (tbl *GaspTable) GaspRanges() iter.Seq2[int, GaspRange] {
+	return func(yield func(int, GaspRange) bool) {
+		for i := range tbl.NumGaspRanges() {
+			if !yield(i, tbl.GaspRange(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *GaspTable) NumGaspRanges() int {
+	return len(tbl.gaspRanges) / 4
+}
+
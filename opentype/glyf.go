@@ -4,6 +4,14 @@
 
 package opentype
 
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"honnef.co/go/curve"
+)
+
 type GlyphTableKind int
 
 const (
@@ -25,7 +33,7 @@ func (tbl *GlyfTable) Glyph(offset uint32) GlyphTable {
 		return out
 	} else {
 		out := GlyphTable{
-			Kind: SimpleGlyphTableKind,
+			Kind: CompositeGlyphTableKind,
 		}
 		ParseCompositeGlyphTable(data, &out.Composite)
 		return out
@@ -38,3 +46,326 @@ type GlyphTable struct {
 	Simple    SimpleGlyphTable
 	Composite CompositeGlyphTable
 }
+
+var errMalformedGlyph = errors.New("malformed glyph")
+
+// Flags of simple glyph points.
+const (
+	onCurvePoint                  = 0x01
+	xShortVector                  = 0x02
+	yShortVector                  = 0x04
+	repeatFlag                    = 0x08
+	xIsSameOrPositiveXShortVector = 0x10
+	yIsSameOrPositiveYShortVector = 0x20
+)
+
+// GlyphPoint is a point of a TrueType outline, in font units. Coordinates are
+// floating point so that they can hold the results of applying variations.
+type GlyphPoint struct {
+	X, Y    float64
+	OnCurve bool
+}
+
+// NumPoints returns the number of points in the glyph's contours.
+func (tbl *SimpleGlyphTable) NumPoints() int {
+	if tbl.NumberOfContours <= 0 {
+		return 0
+	}
+	return int(tbl.EndPtsOfContour(int(tbl.NumberOfContours)-1)) + 1
+}
+
+// Points decodes the glyph's points and appends them to out.
+func (tbl *SimpleGlyphTable) Points(out []GlyphPoint) ([]GlyphPoint, error) {
+	n := tbl.NumPoints()
+	base := len(out)
+	b := tbl.Data
+
+	// The flags, which may be run-length encoded.
+	for len(out)-base < n {
+		if len(b) == 0 {
+			return out, errMalformedGlyph
+		}
+		flag := b[0]
+		b = b[1:]
+		count := 1
+		if flag&repeatFlag != 0 {
+			if len(b) == 0 {
+				return out, errMalformedGlyph
+			}
+			count += int(b[0])
+			b = b[1:]
+		}
+		for range min(count, n-(len(out)-base)) {
+			// Stash the flag in X until the coordinates have been decoded.
+			out = append(out, GlyphPoint{X: float64(flag), OnCurve: flag&onCurvePoint != 0})
+		}
+	}
+	pts := out[base:]
+	flags := make([]byte, n)
+	for i := range pts {
+		flags[i] = byte(pts[i].X)
+	}
+
+	coord := func(flag, short, same byte) (int, bool) {
+		switch {
+		case flag&short != 0:
+			if len(b) < 1 {
+				return 0, false
+			}
+			d := int(b[0])
+			b = b[1:]
+			if flag&same == 0 {
+				d = -d
+			}
+			return d, true
+		case flag&same != 0:
+			return 0, true
+		default:
+			if len(b) < 2 {
+				return 0, false
+			}
+			d := int(int16(uint16(b[0])<<8 | uint16(b[1])))
+			b = b[2:]
+			return d, true
+		}
+	}
+	var x int
+	for i, flag := range flags {
+		d, ok := coord(flag, xShortVector, xIsSameOrPositiveXShortVector)
+		if !ok {
+			return out, errMalformedGlyph
+		}
+		x += d
+		pts[i].X = float64(x)
+	}
+	var y int
+	for i, flag := range flags {
+		d, ok := coord(flag, yShortVector, yIsSameOrPositiveYShortVector)
+		if !ok {
+			return out, errMalformedGlyph
+		}
+		y += d
+		pts[i].Y = float64(y)
+	}
+	return out, nil
+}
+
+// Flags of composite glyph components.
+const (
+	ComponentArg1And2AreWords        = 0x0001
+	ComponentArgsAreXYValues         = 0x0002
+	ComponentRoundXYToGrid           = 0x0004
+	ComponentWeHaveAScale            = 0x0008
+	ComponentMoreComponents          = 0x0020
+	ComponentWeHaveAnXAndYScale      = 0x0040
+	ComponentWeHaveATwoByTwo         = 0x0080
+	ComponentWeHaveInstructions      = 0x0100
+	ComponentUseMyMetrics            = 0x0200
+	ComponentOverlapCompound         = 0x0400
+	ComponentScaledComponentOffset   = 0x0800
+	ComponentUnscaledComponentOffset = 0x1000
+)
+
+// GlyphComponent is a component of a composite glyph.
+type GlyphComponent struct {
+	Flags      uint16
+	GlyphIndex GlyphID
+	// Arg1 and Arg2 are the component's offset if Flags has
+	// ComponentArgsAreXYValues set. Otherwise, they are the indices of a point
+	// in the composite glyph and a point in the component that get aligned.
+	Arg1, Arg2 int
+	// The component's transformation, mapping (x, y) to (XX*x + YX*y, XY*x +
+	// YY*y).
+	XX, XY, YX, YY float64
+}
+
+// Components decodes the glyph's components and appends them to out.
+func (tbl *CompositeGlyphTable) Components(out []GlyphComponent) ([]GlyphComponent, error) {
+	flags, glyph := tbl.Flags, tbl.GlyphIndex
+	b := tbl.Data
+	f2dot14 := func() float64 {
+		v := Int2_14(uint16(b[0])<<8 | uint16(b[1]))
+		b = b[2:]
+		return v.Float()
+	}
+	for {
+		c := GlyphComponent{
+			Flags:      flags,
+			GlyphIndex: GlyphID(glyph),
+			XX:         1,
+			YY:         1,
+		}
+
+		argSize := 2
+		if flags&ComponentArg1And2AreWords != 0 {
+			argSize = 4
+		}
+		scaleSize := 0
+		switch {
+		case flags&ComponentWeHaveAScale != 0:
+			scaleSize = 2
+		case flags&ComponentWeHaveAnXAndYScale != 0:
+			scaleSize = 4
+		case flags&ComponentWeHaveATwoByTwo != 0:
+			scaleSize = 8
+		}
+		if len(b) < argSize+scaleSize {
+			return out, errMalformedGlyph
+		}
+
+		signed := flags&ComponentArgsAreXYValues != 0
+		if argSize == 4 {
+			a1, a2 := uint16(b[0])<<8|uint16(b[1]), uint16(b[2])<<8|uint16(b[3])
+			if signed {
+				c.Arg1, c.Arg2 = int(int16(a1)), int(int16(a2))
+			} else {
+				c.Arg1, c.Arg2 = int(a1), int(a2)
+			}
+		} else {
+			if signed {
+				c.Arg1, c.Arg2 = int(int8(b[0])), int(int8(b[1]))
+			} else {
+				c.Arg1, c.Arg2 = int(b[0]), int(b[1])
+			}
+		}
+		b = b[argSize:]
+
+		switch scaleSize {
+		case 2:
+			c.XX = f2dot14()
+			c.YY = c.XX
+		case 4:
+			c.XX = f2dot14()
+			c.YY = f2dot14()
+		case 8:
+			c.XX = f2dot14()
+			c.XY = f2dot14()
+			c.YX = f2dot14()
+			c.YY = f2dot14()
+		}
+		out = append(out, c)
+
+		if flags&ComponentMoreComponents == 0 {
+			return out, nil
+		}
+		if len(b) < 4 {
+			return out, errMalformedGlyph
+		}
+		flags = uint16(b[0])<<8 | uint16(b[1])
+		glyph = uint16(b[2])<<8 | uint16(b[3])
+		b = b[4:]
+	}
+}
+
+// RemapComponents rewrites the glyph indices of a composite glyph's
+// components in place. glyph is the glyph's data, starting with its header.
+// Simple glyphs are left unchanged.
+func RemapComponents(glyph []byte, remap func(GlyphID) GlyphID) error {
+	if len(glyph) < 10 {
+		return errMalformedGlyph
+	}
+	if int16(binary.BigEndian.Uint16(glyph)) >= 0 {
+		return nil
+	}
+	b := glyph[10:]
+	for {
+		if len(b) < 4 {
+			return errMalformedGlyph
+		}
+		flags := binary.BigEndian.Uint16(b)
+		g := remap(GlyphID(binary.BigEndian.Uint16(b[2:])))
+		if g < 0 || g > 0xFFFF {
+			return fmt.Errorf("invalid glyph %d", g)
+		}
+		binary.BigEndian.PutUint16(b[2:], uint16(g))
+
+		n := 4 + 2
+		if flags&ComponentArg1And2AreWords != 0 {
+			n = 4 + 4
+		}
+		switch {
+		case flags&ComponentWeHaveAScale != 0:
+			n += 2
+		case flags&ComponentWeHaveAnXAndYScale != 0:
+			n += 4
+		case flags&ComponentWeHaveATwoByTwo != 0:
+			n += 8
+		}
+		if len(b) < n {
+			return errMalformedGlyph
+		}
+		b = b[n:]
+		if flags&ComponentMoreComponents == 0 {
+			return nil
+		}
+	}
+}
+
+// AppendOutline converts TrueType contours to quadratic Bézier curves and
+// appends them to path. endPts holds the index of the last point of each
+// contour. The outline is in font units, with the y axis pointing up.
+func AppendOutline(path curve.BezPath, points []GlyphPoint, endPts []int) curve.BezPath {
+	pt := func(p GlyphPoint) curve.Point { return curve.Pt(p.X, p.Y) }
+	mid := func(p, q GlyphPoint) curve.Point { return curve.Pt((p.X+q.X)/2, (p.Y+q.Y)/2) }
+
+	start := 0
+	for _, end := range endPts {
+		if end < start || end >= len(points) {
+			break
+		}
+		contour := points[start : end+1]
+		start = end + 1
+
+		// Start at an on-curve point, or at the implied on-curve point
+		// between the first two points if there are none.
+		first := -1
+		for i, p := range contour {
+			if p.OnCurve {
+				first = i
+				break
+			}
+		}
+		n := len(contour)
+		// The point to start at and the number of points that follow it,
+		// wrapping around to the start.
+		var startPt curve.Point
+		var rest int
+		if first == -1 {
+			if n == 1 {
+				path.MoveTo(pt(contour[0]))
+				path.ClosePath()
+				continue
+			}
+			startPt = mid(contour[0], contour[1])
+			first, rest = 1, n
+		} else {
+			startPt = pt(contour[first])
+			first, rest = first+1, n-1
+		}
+		path.MoveTo(startPt)
+
+		var ctrl GlyphPoint
+		haveCtrl := false
+		for i := range rest {
+			p := contour[(first+i)%n]
+			if p.OnCurve {
+				if haveCtrl {
+					path.QuadTo(pt(ctrl), pt(p))
+					haveCtrl = false
+				} else {
+					path.LineTo(pt(p))
+				}
+				continue
+			}
+			if haveCtrl {
+				path.QuadTo(pt(ctrl), mid(ctrl, p))
+			}
+			ctrl, haveCtrl = p, true
+		}
+		if haveCtrl {
+			path.QuadTo(pt(ctrl), startPt)
+		}
+		path.ClosePath()
+	}
+	return path
+}
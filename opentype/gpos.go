@@ -0,0 +1,245 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import (
+	"math/bits"
+	"sort"
+)
+
+func parseValueFormat(buf []byte, out *ValueFormat) int {
+	var v uint16
+	parseUint16(buf, &v)
+	*out = ValueFormat(v)
+	return 0
+}
+
+// Size returns the size in bytes of value records using this format.
+func (f ValueFormat) Size() int {
+	return 2 * bits.OnesCount16(uint16(f&0xFF))
+}
+
+// ValueRecord is a decoded value record. Fields that aren't present in the
+// record's value format are zero.
+type ValueRecord struct {
+	parentData []byte
+
+	XPlacement int16
+	YPlacement int16
+	XAdvance   int16
+	YAdvance   int16
+
+	xPlaDeviceOffset Offset16[DeviceOrVariationIndexTable]
+	yPlaDeviceOffset Offset16[DeviceOrVariationIndexTable]
+	xAdvDeviceOffset Offset16[DeviceOrVariationIndexTable]
+	yAdvDeviceOffset Offset16[DeviceOrVariationIndexTable]
+}
+
+// parseValueRecord parses a value record of the given format. Offsets to
+// device tables are relative to parentBuf.
+func parseValueRecord(buf, parentBuf []byte, format ValueFormat, out *ValueRecord) int {
+	*out = ValueRecord{parentData: parentBuf}
+	if len(buf) < format.Size() {
+		// XXX return error
+		return 0
+	}
+	next := func() uint16 {
+		var v uint16
+		parseUint16(buf, &v)
+		buf = buf[2:]
+		return v
+	}
+	if format&ValueFormatXPlacement != 0 {
+		out.XPlacement = int16(next())
+	}
+	if format&ValueFormatYPlacement != 0 {
+		out.YPlacement = int16(next())
+	}
+	if format&ValueFormatXAdvance != 0 {
+		out.XAdvance = int16(next())
+	}
+	if format&ValueFormatYAdvance != 0 {
+		out.YAdvance = int16(next())
+	}
+	if format&ValueFormatXPlaDevice != 0 {
+		out.xPlaDeviceOffset = Offset16[DeviceOrVariationIndexTable](next())
+	}
+	if format&ValueFormatYPlaDevice != 0 {
+		out.yPlaDeviceOffset = Offset16[DeviceOrVariationIndexTable](next())
+	}
+	if format&ValueFormatXAdvDevice != 0 {
+		out.xAdvDeviceOffset = Offset16[DeviceOrVariationIndexTable](next())
+	}
+	if format&ValueFormatYAdvDevice != 0 {
+		out.yAdvDeviceOffset = Offset16[DeviceOrVariationIndexTable](next())
+	}
+	return 0
+}
+
+func (rec *ValueRecord) device(off Offset16[DeviceOrVariationIndexTable], into *DeviceOrVariationIndexTable) bool {
+	if off == 0 {
+		return false
+	}
+	// FIXME: check bounds
+	ParseDeviceOrVariationIndexTable(rec.parentData[off:], into)
+	return true
+}
+
+func (rec *ValueRecord) XPlaDevice(into *DeviceOrVariationIndexTable) bool {
+	return rec.device(rec.xPlaDeviceOffset, into)
+}
+
+func (rec *ValueRecord) YPlaDevice(into *DeviceOrVariationIndexTable) bool {
+	return rec.device(rec.yPlaDeviceOffset, into)
+}
+
+func (rec *ValueRecord) XAdvDevice(into *DeviceOrVariationIndexTable) bool {
+	return rec.device(rec.xAdvDeviceOffset, into)
+}
+
+func (rec *ValueRecord) YAdvDevice(into *DeviceOrVariationIndexTable) bool {
+	return rec.device(rec.yAdvDeviceOffset, into)
+}
+
+// ValueRecord returns the value record that applies to all covered glyphs.
+func (tbl *SinglePosTableFormat1) ValueRecord() ValueRecord {
+	var out ValueRecord
+	parseValueRecord(tbl.valueRecord, tbl.parentData, tbl.ValueFormat, &out)
+	return out
+}
+
+// ValueRecord returns the value record of the glyph with coverage index i.
+func (tbl *SinglePosTableFormat2) ValueRecord(i int) ValueRecord {
+	var out ValueRecord
+	sz := tbl.ValueFormat.Size()
+	// FIXME: check bounds
+	parseValueRecord(tbl.valueRecords[i*sz:], tbl.parentData, tbl.ValueFormat, &out)
+	return out
+}
+
+// PairSet returns the pair set of the first glyph with coverage index i.
+func (tbl *PairPosTableFormat1) PairSet(i int, into *PairSetTable) bool {
+	if i < 0 || i >= tbl.NumPairSetOffsets() {
+		return false
+	}
+	var off Offset16[PairSetTable]
+	parseOffset16(tbl.pairSetOffsets[i*2:], &off)
+	// FIXME: check bounds
+	ParsePairSetTable(tbl.parentData[off:], into)
+	return true
+}
+
+// Find returns the value records for the pair whose second glyph is second.
+// The value formats are those of the PairPosTableFormat1 containing the pair
+// set.
+func (tbl *PairSetTable) Find(second GlyphID, format1, format2 ValueFormat) (v1, v2 ValueRecord, ok bool) {
+	sz1, sz2 := format1.Size(), format2.Size()
+	recSize := 2 + sz1 + sz2
+	n := min(int(tbl.PairValueCount), len(tbl.pairValueRecords)/recSize)
+	i, ok := sort.Find(n, func(i int) int {
+		var g uint16
+		parseUint16(tbl.pairValueRecords[i*recSize:], &g)
+		return int(second) - int(g)
+	})
+	if !ok {
+		return ValueRecord{}, ValueRecord{}, false
+	}
+	rec := tbl.pairValueRecords[i*recSize+2:]
+	parseValueRecord(rec, tbl.data, format1, &v1)
+	parseValueRecord(rec[sz1:], tbl.data, format2, &v2)
+	return v1, v2, true
+}
+
+// ValueRecords returns the value records for a pair of glyph classes.
+func (tbl *PairPosTableFormat2) ValueRecords(class1, class2 uint16) (v1, v2 ValueRecord, ok bool) {
+	if class1 >= tbl.Class1Count || class2 >= tbl.Class2Count {
+		return ValueRecord{}, ValueRecord{}, false
+	}
+	sz1, sz2 := tbl.ValueFormat1.Size(), tbl.ValueFormat2.Size()
+	off := (int(class1)*int(tbl.Class2Count) + int(class2)) * (sz1 + sz2)
+	if off+sz1+sz2 > len(tbl.class1Records) {
+		return ValueRecord{}, ValueRecord{}, false
+	}
+	rec := tbl.class1Records[off:]
+	parseValueRecord(rec, tbl.parentData, tbl.ValueFormat1, &v1)
+	parseValueRecord(rec[sz1:], tbl.parentData, tbl.ValueFormat2, &v2)
+	return v1, v2, true
+}
+
+// Coordinates returns the anchor's coordinates in font units, ignoring
+// contour points and device tables.
+func (tbl *AnchorTable) Coordinates() (x, y int16) {
+	switch tbl.AnchorFormat {
+	case 1:
+		return tbl.Format1.XCoordinate, tbl.Format1.YCoordinate
+	case 2:
+		return tbl.Format2.XCoordinate, tbl.Format2.YCoordinate
+	case 3:
+		return tbl.Format3.XCoordinate, tbl.Format3.YCoordinate
+	default:
+		return 0, 0
+	}
+}
+
+// anchorMatrix looks up an anchor in a matrix of anchor offsets with one row
+// per glyph and one column per mark class, as used by base arrays, ligature
+// attach tables and mark2 arrays. Offsets are relative to data.
+func anchorMatrix(data, records []byte, rows, row, class, classCount int, into *AnchorTable) bool {
+	if row < 0 || row >= rows || class < 0 || class >= classCount {
+		return false
+	}
+	idx := (row*classCount + class) * 2
+	if idx+2 > len(records) {
+		return false
+	}
+	var off Offset16[AnchorTable]
+	parseOffset16(records[idx:], &off)
+	if off == 0 {
+		return false
+	}
+	// FIXME: check bounds
+	ParseAnchorTable(data[off:], into)
+	return true
+}
+
+// BaseAnchor returns the anchor of the base glyph with coverage index base for
+// marks of the given class. classCount is the MarkClassCount of the
+// MarkBasePosTableFormat1 containing the base array.
+func (tbl *BaseArrayTable) BaseAnchor(base int, class, classCount uint16, into *AnchorTable) bool {
+	return anchorMatrix(tbl.data, tbl.baseRecords, int(tbl.BaseCount), base, int(class), int(classCount), into)
+}
+
+// LigatureAttach returns the attachment table of the ligature with coverage
+// index i.
+func (tbl *LigatureArrayTable) LigatureAttach(i int, into *LigatureAttachTable) bool {
+	if i < 0 || i >= tbl.NumLigatureAttachOffsets() {
+		return false
+	}
+	var off Offset16[LigatureAttachTable]
+	parseOffset16(tbl.ligatureAttachOffsets[i*2:], &off)
+	// FIXME: check bounds
+	ParseLigatureAttachTable(tbl.data[off:], into)
+	return true
+}
+
+// ComponentAnchor returns the anchor of a ligature component for marks of the
+// given class. classCount is the MarkClassCount of the MarkLigPosTableFormat1
+// containing the ligature.
+func (tbl *LigatureAttachTable) ComponentAnchor(component int, class, classCount uint16, into *AnchorTable) bool {
+	return anchorMatrix(tbl.data, tbl.componentRecords, int(tbl.ComponentCount), component, int(class), int(classCount), into)
+}
+
+// Mark2Anchor returns the anchor of the mark glyph with coverage index mark2
+// for attaching marks of the given class. classCount is the MarkClassCount of
+// the MarkMarkPosTableFormat1 containing the array.
+func (tbl *Mark2ArrayTable) Mark2Anchor(mark2 int, class, classCount uint16, into *AnchorTable) bool {
+	return anchorMatrix(tbl.data, tbl.mark2Records, int(tbl.Mark2Count), mark2, int(class), int(classCount), into)
+}
+
+// Extension returns the data of the subtable that the extension points to.
+func (tbl *ExtensionPosTableFormat1) Extension() []byte {
+	// FIXME: check bounds
+	return tbl.parentData[tbl.ExtensionOffset:]
+}
@@ -0,0 +1,1165 @@
+// Code generated by honnef.co/go/gutter/internal/cmd/gen_parser. DO NOT EDIT.
+
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import "iter"
+
+const (
+	GPOSLookupTypeSingle          = 1
+	GPOSLookupTypePair            = 2
+	GPOSLookupTypeCursive         = 3
+	GPOSLookupTypeMarkToBase      = 4
+	GPOSLookupTypeMarkToLigature  = 5
+	GPOSLookupTypeMarkToMark      = 6
+	GPOSLookupTypeContext         = 7
+	GPOSLookupTypeChainingContext = 8
+	GPOSLookupTypeExtension       = 9
+)
+
+// ValueFormat describes which fields are present in a value record.
+type ValueFormat uint16
+
+const (
+	ValueFormatXPlacement ValueFormat = 0x0001
+	ValueFormatYPlacement ValueFormat = 0x0002
+	ValueFormatXAdvance   ValueFormat = 0x0004
+	ValueFormatYAdvance   ValueFormat = 0x0008
+	ValueFormatXPlaDevice ValueFormat = 0x0010
+	ValueFormatYPlaDevice ValueFormat = 0x0020
+	ValueFormatXAdvDevice ValueFormat = 0x0040
+	ValueFormatYAdvDevice ValueFormat = 0x0080
+)
+
+type GPOSTable struct {
+	data []byte
+
+	MajorVersion      uint16
+	MinorVersion      uint16
+	scriptListOffset  Offset16[ScriptListTable]
+	featureListOffset Offset16[FeatureListTable]
+	lookupListOffset  Offset16[LookupListTable]
+
+	featureVariationsOffset Offset32[FeatureVariationsTable]
+}
+
+// Value records have sizes that depend on their value formats, which the
+// generator can't express. They're stored as raw bytes and decoded by
+// hand-written accessors.
+
+type SinglePosTable struct {
+	PosFormat uint16
+	Format1   SinglePosTableFormat1
+	Format2   SinglePosTableFormat2
+}
+
+type SinglePosTableFormat1 struct {
+	parentData []byte
+
+	coverageOffset Offset16[CoverageTable]
+	ValueFormat    ValueFormat
+	valueRecord    []byte
+}
+
+type SinglePosTableFormat2 struct {
+	parentData []byte
+
+	coverageOffset Offset16[CoverageTable]
+	ValueFormat    ValueFormat
+	ValueCount     uint16
+	valueRecords   []byte
+}
+
+type PairPosTable struct {
+	PosFormat uint16
+	Format1   PairPosTableFormat1
+	Format2   PairPosTableFormat2
+}
+
+type PairPosTableFormat1 struct {
+	parentData []byte
+
+	coverageOffset Offset16[CoverageTable]
+	ValueFormat1   ValueFormat
+	ValueFormat2   ValueFormat
+
+	pairSetOffsets Slice[Offset16[PairSetTable]]
+}
+
+type PairSetTable struct {
+	data []byte
+
+	PairValueCount   uint16
+	pairValueRecords []byte
+}
+
+type PairPosTableFormat2 struct {
+	parentData []byte
+
+	coverageOffset  Offset16[CoverageTable]
+	ValueFormat1    ValueFormat
+	ValueFormat2    ValueFormat
+	classDef1Offset Offset16[ClassDefTable]
+	classDef2Offset Offset16[ClassDefTable]
+	Class1Count     uint16
+	Class2Count     uint16
+	class1Records   []byte
+}
+
+type CursivePosTable struct {
+	PosFormat uint16
+	Format1   CursivePosTableFormat1
+}
+
+type CursivePosTableFormat1 struct {
+	parentData []byte
+
+	coverageOffset Offset16[CoverageTable]
+
+	entryExitRecords Slice[EntryExitRecord]
+}
+
+type EntryExitRecord struct {
+	parentData []byte
+
+	entryAnchorOffset Offset16[AnchorTable]
+	exitAnchorOffset  Offset16[AnchorTable]
+}
+
+type AnchorTable struct {
+	AnchorFormat uint16
+	Format1      AnchorTableFormat1
+	Format2      AnchorTableFormat2
+	Format3      AnchorTableFormat3
+}
+
+type AnchorTableFormat1 struct {
+	XCoordinate int16
+	YCoordinate int16
+}
+
+type AnchorTableFormat2 struct {
+	XCoordinate int16
+	YCoordinate int16
+	AnchorPoint uint16
+}
+
+type AnchorTableFormat3 struct {
+	parentData []byte
+
+	XCoordinate   int16
+	YCoordinate   int16
+	xDeviceOffset Offset16[DeviceOrVariationIndexTable]
+	yDeviceOffset Offset16[DeviceOrVariationIndexTable]
+}
+
+type MarkArrayTable struct {
+	data []byte
+
+	markRecords Slice[MarkRecord]
+}
+
+type MarkRecord struct {
+	parentData []byte
+
+	MarkClass        uint16
+	markAnchorOffset Offset16[AnchorTable]
+}
+
+type MarkBasePosTable struct {
+	PosFormat uint16
+	Format1   MarkBasePosTableFormat1
+}
+
+type MarkBasePosTableFormat1 struct {
+	parentData []byte
+
+	markCoverageOffset Offset16[CoverageTable]
+	baseCoverageOffset Offset16[CoverageTable]
+	MarkClassCount     uint16
+	markArrayOffset    Offset16[MarkArrayTable]
+	baseArrayOffset    Offset16[BaseArrayTable]
+}
+
+// The base records contain one anchor offset per mark class, which the
+// generator can't express.
+type BaseArrayTable struct {
+	data []byte
+
+	BaseCount   uint16
+	baseRecords []byte
+}
+
+type MarkLigPosTable struct {
+	PosFormat uint16
+	Format1   MarkLigPosTableFormat1
+}
+
+type MarkLigPosTableFormat1 struct {
+	parentData []byte
+
+	markCoverageOffset     Offset16[CoverageTable]
+	ligatureCoverageOffset Offset16[CoverageTable]
+	MarkClassCount         uint16
+	markArrayOffset        Offset16[MarkArrayTable]
+	ligatureArrayOffset    Offset16[LigatureArrayTable]
+}
+
+type LigatureArrayTable struct {
+	data []byte
+
+	ligatureAttachOffsets Slice[Offset16[LigatureAttachTable]]
+}
+
+type LigatureAttachTable struct {
+	data []byte
+
+	ComponentCount   uint16
+	componentRecords []byte
+}
+
+type MarkMarkPosTable struct {
+	PosFormat uint16
+	Format1   MarkMarkPosTableFormat1
+}
+
+type MarkMarkPosTableFormat1 struct {
+	parentData []byte
+
+	mark1CoverageOffset Offset16[CoverageTable]
+	mark2CoverageOffset Offset16[CoverageTable]
+	MarkClassCount      uint16
+	mark1ArrayOffset    Offset16[MarkArrayTable]
+	mark2ArrayOffset    Offset16[Mark2ArrayTable]
+}
+
+type Mark2ArrayTable struct {
+	data []byte
+
+	Mark2Count   uint16
+	mark2Records []byte
+}
+
+type ExtensionPosTable struct {
+	PosFormat uint16
+	Format1   ExtensionPosTableFormat1
+}
+
+type ExtensionPosTableFormat1 struct {
+	parentData []byte
+
+	ExtensionLookupType uint16
+	ExtensionOffset     Offset32[any]
+}
+func ParseAnchorTable(buf []byte, out *AnchorTable) int {
+	*out = AnchorTable{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.AnchorFormat)
+
+	switch out.AnchorFormat {
+	case 1:
+		parseAnchorTableFormat1(buf[2:6], &out.Format1)
+	case 2:
+		parseAnchorTableFormat2(buf[2:8], &out.Format2)
+	case 3:
+		parseAnchorTableFormat3(buf[2:10], buf, &out.Format3)
+	}
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseAnchorTableFormat1(buf []byte, out *AnchorTableFormat1) int {
+	*out = AnchorTableFormat1{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 4 {
+		return dynSize
+	}
+	parseInt16(buf[0:2], &out.XCoordinate)
+	parseInt16(buf[2:4], &out.YCoordinate)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseAnchorTableFormat2(buf []byte, out *AnchorTableFormat2) int {
+	*out = AnchorTableFormat2{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 6 {
+		return dynSize
+	}
+	parseInt16(buf[0:2], &out.XCoordinate)
+	parseInt16(buf[2:4], &out.YCoordinate)
+	parseUint16(buf[4:6], &out.AnchorPoint)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseAnchorTableFormat3(buf, parentBuf []byte, out *AnchorTableFormat3) int {
+	*out = AnchorTableFormat3{}
+	origBuf := buf
+	var dynSize int
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 8 {
+		return dynSize
+	}
+	parseInt16(buf[0:2], &out.XCoordinate)
+	parseInt16(buf[2:4], &out.YCoordinate)
+	parseOffset16(buf[4:6], &out.xDeviceOffset)
+	parseOffset16(buf[6:8], &out.yDeviceOffset)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *AnchorTableFormat3) XDevice(into *DeviceOrVariationIndexTable) bool {
+	// FIXME: check bounds
+	if tbl.xDeviceOffset == 0 {
+		return false
+	}
+	ParseDeviceOrVariationIndexTable(tbl.parentData[tbl.xDeviceOffset:], into)
+	return true
+}
+
+func (tbl *AnchorTableFormat3) YDevice(into *DeviceOrVariationIndexTable) bool {
+	// FIXME: check bounds
+	if tbl.yDeviceOffset == 0 {
+		return false
+	}
+	ParseDeviceOrVariationIndexTable(tbl.parentData[tbl.yDeviceOffset:], into)
+	return true
+}
+
+func ParseBaseArrayTable(buf []byte, out *BaseArrayTable) int {
+	*out = BaseArrayTable{}
+	origBuf := buf
+	var dynSize int
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.BaseCount)
+	{
+		out.baseRecords = buf[2:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func ParseCursivePosTable(buf []byte, out *CursivePosTable) int {
+	*out = CursivePosTable{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.PosFormat)
+
+	switch out.PosFormat {
+	case 1:
+		dynSize += parseCursivePosTableFormat1(buf[2:], buf, &out.Format1)
+	}
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseCursivePosTableFormat1(buf, parentBuf []byte, out *CursivePosTableFormat1) int {
+	*out = CursivePosTableFormat1{}
+	origBuf := buf
+	var dynSize int
+	var entryExitCount uint16
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 4 {
+		return dynSize
+	}
+	parseOffset16(buf[0:2], &out.coverageOffset)
+	parseUint16(buf[2:4], &entryExitCount)
+	{
+		n := int(entryExitCount)
+		/* FIXME: check that buf is long enough */
+		out.entryExitRecords = buf[4 : 4+n*4]
+		dynSize += n * 4
+		buf = buf[4+n*4:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *CursivePosTableFormat1) Coverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.coverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.coverageOffset:], into)
+	return true
+}
+
+func (tbl *CursivePosTableFormat1) EntryExitRecord(i int) EntryExitRecord {
+	var out EntryExitRecord
+	parseEntryExitRecord(tbl.entryExitRecords[i*4:(i+1)*4:len(tbl.entryExitRecords)], tbl.parentData, &out)
+	return out
+}
+
+func (tbl *CursivePosTableFormat1) EntryExitRecords() iter.Seq2[int, EntryExitRecord] {
+	return func(yield func(int, EntryExitRecord) bool) {
+		for i := range tbl.NumEntryExitRecords() {
+			if !yield(i, tbl.EntryExitRecord(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *CursivePosTableFormat1) NumEntryExitRecords() int {
+	return len(tbl.entryExitRecords) / 4
+}
+
+func parseEntryExitRecord(buf, parentBuf []byte, out *EntryExitRecord) int {
+	*out = EntryExitRecord{}
+	origBuf := buf
+	var dynSize int
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 4 {
+		return dynSize
+	}
+	parseOffset16(buf[0:2], &out.entryAnchorOffset)
+	parseOffset16(buf[2:4], &out.exitAnchorOffset)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *EntryExitRecord) EntryAnchor(into *AnchorTable) bool {
+	// FIXME: check bounds
+	if tbl.entryAnchorOffset == 0 {
+		return false
+	}
+	ParseAnchorTable(tbl.parentData[tbl.entryAnchorOffset:], into)
+	return true
+}
+
+func (tbl *EntryExitRecord) ExitAnchor(into *AnchorTable) bool {
+	// FIXME: check bounds
+	if tbl.exitAnchorOffset == 0 {
+		return false
+	}
+	ParseAnchorTable(tbl.parentData[tbl.exitAnchorOffset:], into)
+	return true
+}
+
+func ParseExtensionPosTable(buf []byte, out *ExtensionPosTable) int {
+	*out = ExtensionPosTable{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.PosFormat)
+
+	switch out.PosFormat {
+	case 1:
+		parseExtensionPosTableFormat1(buf[2:8], buf, &out.Format1)
+	}
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseExtensionPosTableFormat1(buf, parentBuf []byte, out *ExtensionPosTableFormat1) int {
+	*out = ExtensionPosTableFormat1{}
+	origBuf := buf
+	var dynSize int
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 6 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.ExtensionLookupType)
+	parseOffset32(buf[2:6], &out.ExtensionOffset)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func ParseGPOSTable(buf []byte, out *GPOSTable) int {
+	*out = GPOSTable{}
+	origBuf := buf
+	var dynSize int
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 10 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.MajorVersion)
+	/* XXX return error */
+	if out.MajorVersion > 1 {
+		return dynSize
+	}
+	parseUint16(buf[2:4], &out.MinorVersion)
+	parseOffset16(buf[4:6], &out.scriptListOffset)
+	parseOffset16(buf[6:8], &out.featureListOffset)
+	parseOffset16(buf[8:10], &out.lookupListOffset)
+
+	if out.MajorVersion < 1 {
+		return dynSize
+	}
+	if out.MajorVersion == 1 && out.MinorVersion < 1 {
+		return dynSize
+	}
+	/* FIXME return error */
+	if len(buf) < 14 {
+		return dynSize
+	}
+	parseOffset32(buf[10:14], &out.featureVariationsOffset)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *GPOSTable) ScriptList(into *ScriptListTable) bool {
+	// FIXME: check bounds
+	if tbl.scriptListOffset == 0 {
+		return false
+	}
+	ParseScriptListTable(tbl.data[tbl.scriptListOffset:], into)
+	return true
+}
+
+func (tbl *GPOSTable) FeatureList(into *FeatureListTable) bool {
+	// FIXME: check bounds
+	if tbl.featureListOffset == 0 {
+		return false
+	}
+	ParseFeatureListTable(tbl.data[tbl.featureListOffset:], into)
+	return true
+}
+
+func (tbl *GPOSTable) LookupList(into *LookupListTable) bool {
+	// FIXME: check bounds
+	if tbl.lookupListOffset == 0 {
+		return false
+	}
+	ParseLookupListTable(tbl.data[tbl.lookupListOffset:], into)
+	return true
+}
+
+func (tbl *GPOSTable) FeatureVariations(into *FeatureVariationsTable) bool {
+	// FIXME: check bounds
+	if tbl.featureVariationsOffset == 0 {
+		return false
+	}
+	ParseFeatureVariationsTable(tbl.data[tbl.featureVariationsOffset:], into)
+	return true
+}
+
+func ParseLigatureArrayTable(buf []byte, out *LigatureArrayTable) int {
+	*out = LigatureArrayTable{}
+	origBuf := buf
+	var dynSize int
+	var ligatureCount uint16
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &ligatureCount)
+	{
+		n := int(ligatureCount)
+		/* FIXME: check that buf is long enough */
+		out.ligatureAttachOffsets = buf[2 : 2+n*2]
+		dynSize += n * 2
+		buf = buf[2+n*2:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func (tbl *LigatureArrayTable) NumLigatureAttachOffsets() int {
+	return len(tbl.ligatureAttachOffsets) / 2
+}
+
+func ParseLigatureAttachTable(buf []byte, out *LigatureAttachTable) int {
+	*out = LigatureAttachTable{}
+	origBuf := buf
+	var dynSize int
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.ComponentCount)
+	{
+		out.componentRecords = buf[2:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func ParseMark2ArrayTable(buf []byte, out *Mark2ArrayTable) int {
+	*out = Mark2ArrayTable{}
+	origBuf := buf
+	var dynSize int
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.Mark2Count)
+	{
+		out.mark2Records = buf[2:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func ParseMarkArrayTable(buf []byte, out *MarkArrayTable) int {
+	*out = MarkArrayTable{}
+	origBuf := buf
+	var dynSize int
+	var markCount uint16
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &markCount)
+	{
+		n := int(markCount)
+		/* FIXME: check that buf is long enough */
+		out.markRecords = buf[2 : 2+n*4]
+		dynSize += n * 4
+		buf = buf[2+n*4:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func (tbl *MarkArrayTable) MarkRecord(i int) MarkRecord {
+	var out MarkRecord
+	parseMarkRecord(tbl.markRecords[i*4:(i+1)*4:len(tbl.markRecords)], tbl.data, &out)
+	return out
+}
+
+func (tbl *MarkArrayTable) MarkRecords() iter.Seq2[int, MarkRecord] {
+	return func(yield func(int, MarkRecord) bool) {
+		for i := range tbl.NumMarkRecords() {
+			if !yield(i, tbl.MarkRecord(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *MarkArrayTable) NumMarkRecords() int {
+	return len(tbl.markRecords) / 4
+}
+
+func ParseMarkBasePosTable(buf []byte, out *MarkBasePosTable) int {
+	*out = MarkBasePosTable{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.PosFormat)
+
+	switch out.PosFormat {
+	case 1:
+		parseMarkBasePosTableFormat1(buf[2:12], buf, &out.Format1)
+	}
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseMarkBasePosTableFormat1(buf, parentBuf []byte, out *MarkBasePosTableFormat1) int {
+	*out = MarkBasePosTableFormat1{}
+	origBuf := buf
+	var dynSize int
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 10 {
+		return dynSize
+	}
+	parseOffset16(buf[0:2], &out.markCoverageOffset)
+	parseOffset16(buf[2:4], &out.baseCoverageOffset)
+	parseUint16(buf[4:6], &out.MarkClassCount)
+	parseOffset16(buf[6:8], &out.markArrayOffset)
+	parseOffset16(buf[8:10], &out.baseArrayOffset)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *MarkBasePosTableFormat1) MarkCoverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.markCoverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.markCoverageOffset:], into)
+	return true
+}
+
+func (tbl *MarkBasePosTableFormat1) BaseCoverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.baseCoverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.baseCoverageOffset:], into)
+	return true
+}
+
+func (tbl *MarkBasePosTableFormat1) MarkArray(into *MarkArrayTable) bool {
+	// FIXME: check bounds
+	if tbl.markArrayOffset == 0 {
+		return false
+	}
+	ParseMarkArrayTable(tbl.parentData[tbl.markArrayOffset:], into)
+	return true
+}
+
+func (tbl *MarkBasePosTableFormat1) BaseArray(into *BaseArrayTable) bool {
+	// FIXME: check bounds
+	if tbl.baseArrayOffset == 0 {
+		return false
+	}
+	ParseBaseArrayTable(tbl.parentData[tbl.baseArrayOffset:], into)
+	return true
+}
+
+func ParseMarkLigPosTable(buf []byte, out *MarkLigPosTable) int {
+	*out = MarkLigPosTable{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.PosFormat)
+
+	switch out.PosFormat {
+	case 1:
+		parseMarkLigPosTableFormat1(buf[2:12], buf, &out.Format1)
+	}
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseMarkLigPosTableFormat1(buf, parentBuf []byte, out *MarkLigPosTableFormat1) int {
+	*out = MarkLigPosTableFormat1{}
+	origBuf := buf
+	var dynSize int
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 10 {
+		return dynSize
+	}
+	parseOffset16(buf[0:2], &out.markCoverageOffset)
+	parseOffset16(buf[2:4], &out.ligatureCoverageOffset)
+	parseUint16(buf[4:6], &out.MarkClassCount)
+	parseOffset16(buf[6:8], &out.markArrayOffset)
+	parseOffset16(buf[8:10], &out.ligatureArrayOffset)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *MarkLigPosTableFormat1) MarkCoverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.markCoverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.markCoverageOffset:], into)
+	return true
+}
+
+func (tbl *MarkLigPosTableFormat1) LigatureCoverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.ligatureCoverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.ligatureCoverageOffset:], into)
+	return true
+}
+
+func (tbl *MarkLigPosTableFormat1) MarkArray(into *MarkArrayTable) bool {
+	// FIXME: check bounds
+	if tbl.markArrayOffset == 0 {
+		return false
+	}
+	ParseMarkArrayTable(tbl.parentData[tbl.markArrayOffset:], into)
+	return true
+}
+
+func (tbl *MarkLigPosTableFormat1) LigatureArray(into *LigatureArrayTable) bool {
+	// FIXME: check bounds
+	if tbl.ligatureArrayOffset == 0 {
+		return false
+	}
+	ParseLigatureArrayTable(tbl.parentData[tbl.ligatureArrayOffset:], into)
+	return true
+}
+
+func ParseMarkMarkPosTable(buf []byte, out *MarkMarkPosTable) int {
+	*out = MarkMarkPosTable{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.PosFormat)
+
+	switch out.PosFormat {
+	case 1:
+		parseMarkMarkPosTableFormat1(buf[2:12], buf, &out.Format1)
+	}
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseMarkMarkPosTableFormat1(buf, parentBuf []byte, out *MarkMarkPosTableFormat1) int {
+	*out = MarkMarkPosTableFormat1{}
+	origBuf := buf
+	var dynSize int
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 10 {
+		return dynSize
+	}
+	parseOffset16(buf[0:2], &out.mark1CoverageOffset)
+	parseOffset16(buf[2:4], &out.mark2CoverageOffset)
+	parseUint16(buf[4:6], &out.MarkClassCount)
+	parseOffset16(buf[6:8], &out.mark1ArrayOffset)
+	parseOffset16(buf[8:10], &out.mark2ArrayOffset)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *MarkMarkPosTableFormat1) Mark1Coverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.mark1CoverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.mark1CoverageOffset:], into)
+	return true
+}
+
+func (tbl *MarkMarkPosTableFormat1) Mark2Coverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.mark2CoverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.mark2CoverageOffset:], into)
+	return true
+}
+
+func (tbl *MarkMarkPosTableFormat1) Mark1Array(into *MarkArrayTable) bool {
+	// FIXME: check bounds
+	if tbl.mark1ArrayOffset == 0 {
+		return false
+	}
+	ParseMarkArrayTable(tbl.parentData[tbl.mark1ArrayOffset:], into)
+	return true
+}
+
+func (tbl *MarkMarkPosTableFormat1) Mark2Array(into *Mark2ArrayTable) bool {
+	// FIXME: check bounds
+	if tbl.mark2ArrayOffset == 0 {
+		return false
+	}
+	ParseMark2ArrayTable(tbl.parentData[tbl.mark2ArrayOffset:], into)
+	return true
+}
+
+func parseMarkRecord(buf, parentBuf []byte, out *MarkRecord) int {
+	*out = MarkRecord{}
+	origBuf := buf
+	var dynSize int
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 4 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.MarkClass)
+	parseOffset16(buf[2:4], &out.markAnchorOffset)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *MarkRecord) MarkAnchor(into *AnchorTable) bool {
+	// FIXME: check bounds
+	if tbl.markAnchorOffset == 0 {
+		return false
+	}
+	ParseAnchorTable(tbl.parentData[tbl.markAnchorOffset:], into)
+	return true
+}
+
+func ParsePairPosTable(buf []byte, out *PairPosTable) int {
+	*out = PairPosTable{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.PosFormat)
+
+	switch out.PosFormat {
+	case 1:
+		dynSize += parsePairPosTableFormat1(buf[2:], buf, &out.Format1)
+	case 2:
+		dynSize += parsePairPosTableFormat2(buf[2:], buf, &out.Format2)
+	}
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parsePairPosTableFormat1(buf, parentBuf []byte, out *PairPosTableFormat1) int {
+	*out = PairPosTableFormat1{}
+	origBuf := buf
+	var dynSize int
+	var pairSetCount uint16
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 8 {
+		return dynSize
+	}
+	parseOffset16(buf[0:2], &out.coverageOffset)
+	parseValueFormat(buf[2:4], &out.ValueFormat1)
+	parseValueFormat(buf[4:6], &out.ValueFormat2)
+	parseUint16(buf[6:8], &pairSetCount)
+	{
+		n := int(pairSetCount)
+		/* FIXME: check that buf is long enough */
+		out.pairSetOffsets = buf[8 : 8+n*2]
+		dynSize += n * 2
+		buf = buf[8+n*2:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *PairPosTableFormat1) Coverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.coverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.coverageOffset:], into)
+	return true
+}
+
+func (tbl *PairPosTableFormat1) NumPairSetOffsets() int {
+	return len(tbl.pairSetOffsets) / 2
+}
+
+func parsePairPosTableFormat2(buf, parentBuf []byte, out *PairPosTableFormat2) int {
+	*out = PairPosTableFormat2{}
+	origBuf := buf
+	var dynSize int
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 14 {
+		return dynSize
+	}
+	parseOffset16(buf[0:2], &out.coverageOffset)
+	parseValueFormat(buf[2:4], &out.ValueFormat1)
+	parseValueFormat(buf[4:6], &out.ValueFormat2)
+	parseOffset16(buf[6:8], &out.classDef1Offset)
+	parseOffset16(buf[8:10], &out.classDef2Offset)
+	parseUint16(buf[10:12], &out.Class1Count)
+	parseUint16(buf[12:14], &out.Class2Count)
+	{
+		out.class1Records = buf[14:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *PairPosTableFormat2) Coverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.coverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.coverageOffset:], into)
+	return true
+}
+
+func (tbl *PairPosTableFormat2) ClassDef1(into *ClassDefTable) bool {
+	// FIXME: check bounds
+	if tbl.classDef1Offset == 0 {
+		return false
+	}
+	ParseClassDefTable(tbl.parentData[tbl.classDef1Offset:], into)
+	return true
+}
+
+func (tbl *PairPosTableFormat2) ClassDef2(into *ClassDefTable) bool {
+	// FIXME: check bounds
+	if tbl.classDef2Offset == 0 {
+		return false
+	}
+	ParseClassDefTable(tbl.parentData[tbl.classDef2Offset:], into)
+	return true
+}
+
+func ParsePairSetTable(buf []byte, out *PairSetTable) int {
+	*out = PairSetTable{}
+	origBuf := buf
+	var dynSize int
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.PairValueCount)
+	{
+		out.pairValueRecords = buf[2:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func ParseSinglePosTable(buf []byte, out *SinglePosTable) int {
+	*out = SinglePosTable{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.PosFormat)
+
+	switch out.PosFormat {
+	case 1:
+		dynSize += parseSinglePosTableFormat1(buf[2:], buf, &out.Format1)
+	case 2:
+		dynSize += parseSinglePosTableFormat2(buf[2:], buf, &out.Format2)
+	}
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseSinglePosTableFormat1(buf, parentBuf []byte, out *SinglePosTableFormat1) int {
+	*out = SinglePosTableFormat1{}
+	origBuf := buf
+	var dynSize int
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 4 {
+		return dynSize
+	}
+	parseOffset16(buf[0:2], &out.coverageOffset)
+	parseValueFormat(buf[2:4], &out.ValueFormat)
+	{
+		out.valueRecord = buf[4:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *SinglePosTableFormat1) Coverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.coverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.coverageOffset:], into)
+	return true
+}
+
+func parseSinglePosTableFormat2(buf, parentBuf []byte, out *SinglePosTableFormat2) int {
+	*out = SinglePosTableFormat2{}
+	origBuf := buf
+	var dynSize int
+	out.parentData = parentBuf
+
+	/* FIXME return error */
+	if len(buf) < 6 {
+		return dynSize
+	}
+	parseOffset16(buf[0:2], &out.coverageOffset)
+	parseValueFormat(buf[2:4], &out.ValueFormat)
+	parseUint16(buf[4:6], &out.ValueCount)
+	{
+		out.valueRecords = buf[6:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *SinglePosTableFormat2) Coverage(into *CoverageTable) bool {
+	// FIXME: check bounds
+	if tbl.coverageOffset == 0 {
+		return false
+	}
+	ParseCoverageTable(tbl.parentData[tbl.coverageOffset:], into)
+	return true
+}
+
+
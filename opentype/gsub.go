@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+// Sequence returns the substitution sequence for the glyph with coverage
+// index i.
+func (tbl *MultipleSubstTableFormat1) Sequence(i int, into *SequenceTable) bool {
+	if i < 0 || i >= tbl.NumSequenceOffsets() {
+		return false
+	}
+	var off Offset16[SequenceTable]
+	parseOffset16(tbl.sequenceOffsets[i*2:], &off)
+	// FIXME: check bounds
+	ParseSequenceTable(tbl.parentData[off:], into)
+	return true
+}
+
+// AlternateSet returns the alternates of the glyph with coverage index i.
+func (tbl *AlternateSubstTableFormat1) AlternateSet(i int, into *AlternateSetTable) bool {
+	if i < 0 || i >= tbl.NumAlternateSetOffsets() {
+		return false
+	}
+	var off Offset16[AlternateSetTable]
+	parseOffset16(tbl.alternateSetOffsets[i*2:], &off)
+	// FIXME: check bounds
+	ParseAlternateSetTable(tbl.parentData[off:], into)
+	return true
+}
+
+// LigatureSet returns the ligatures starting with the glyph with coverage
+// index i.
+func (tbl *LigatureSubstTableFormat1) LigatureSet(i int, into *LigatureSetTable) bool {
+	if i < 0 || i >= tbl.NumLigatureSetOffsets() {
+		return false
+	}
+	var off Offset16[LigatureSetTable]
+	parseOffset16(tbl.ligatureSetOffsets[i*2:], &off)
+	// FIXME: check bounds
+	ParseLigatureSetTable(tbl.parentData[off:], into)
+	return true
+}
+
+// Ligature returns the i-th ligature of the set.
+func (tbl *LigatureSetTable) Ligature(i int, into *LigatureTable) bool {
+	if i < 0 || i >= tbl.NumLigatureOffsets() {
+		return false
+	}
+	var off Offset16[LigatureTable]
+	parseOffset16(tbl.ligatureOffsets[i*2:], &off)
+	// FIXME: check bounds
+	ParseLigatureTable(tbl.data[off:], into)
+	return true
+}
+
+// Extension returns the data of the subtable that the extension points to.
+func (tbl *ExtensionSubstTableFormat1) Extension() []byte {
+	// FIXME: check bounds
+	return tbl.parentData[tbl.ExtensionOffset:]
+}
@@ -100,6 +100,8 @@ type LigatureSubstTableFormat1 struct {
 }
 
 type LigatureSetTable struct {
+	data []byte
+
 	ligatureOffsets Slice[Offset16[LigatureTable]]
 }
 
@@ -352,6 +354,7 @@ func ParseLigatureSetTable(buf []byte, out *LigatureSetTable) int {
 	origBuf := buf
 	var dynSize int
 	var ligatureCount uint16
+	out.data = buf
 
 	/* FIXME return error */
 	if len(buf) < 2 {
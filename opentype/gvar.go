@@ -0,0 +1,434 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errMalformedVariations = errors.New("malformed glyph variation data")
+
+const (
+	gvarLongOffsets = 0x0001
+
+	// Flags of the tuple variation count and tuple indices.
+	sharedPointNumbers = 0x8000
+	tupleCountMask     = 0x0FFF
+	tupleEmbeddedPeak  = 0x8000
+	tupleIntermediate  = 0x4000
+	tuplePrivatePoints = 0x2000
+	tupleIndexMask     = 0x0FFF
+
+	// Control bytes of packed point numbers and packed deltas.
+	pointsAreWords    = 0x80
+	pointRunCountMask = 0x7F
+	deltasAreZero     = 0x80
+	deltasAreWords    = 0x40
+	// Both bits set denotes 32-bit deltas.
+	deltasAreLongs    = deltasAreZero | deltasAreWords
+	deltaRunCountMask = 0x3F
+)
+
+// GlyphVariationData returns the serialized variation data of a glyph, or nil
+// if the glyph has none.
+func (tbl *GvarTable) GlyphVariationData(glyph GlyphID) []byte {
+	i := int(glyph)
+	if i < 0 || i >= int(tbl.GlyphCount) {
+		return nil
+	}
+	var start, end int
+	if tbl.Flags&gvarLongOffsets != 0 {
+		if len(tbl.glyphVariationDataOffsets) < (i+2)*4 {
+			return nil
+		}
+		start = int(binary.BigEndian.Uint32(tbl.glyphVariationDataOffsets[i*4:]))
+		end = int(binary.BigEndian.Uint32(tbl.glyphVariationDataOffsets[i*4+4:]))
+	} else {
+		if len(tbl.glyphVariationDataOffsets) < (i+2)*2 {
+			return nil
+		}
+		start = int(binary.BigEndian.Uint16(tbl.glyphVariationDataOffsets[i*2:])) * 2
+		end = int(binary.BigEndian.Uint16(tbl.glyphVariationDataOffsets[i*2+2:])) * 2
+	}
+	base := int(tbl.glyphVariationDataArrayOffset)
+	if start >= end || base+end > len(tbl.data) {
+		return nil
+	}
+	return tbl.data[base+start : base+end]
+}
+
+// SharedTuples returns the serialized array of shared tuple records, which
+// the glyphs' variation data refer to by index.
+func (tbl *GvarTable) SharedTuples() []byte {
+	size := int(tbl.SharedTupleCount) * int(tbl.AxisCount) * 2
+	off := int(tbl.sharedTuplesOffset)
+	if off+size > len(tbl.data) {
+		return nil
+	}
+	return tbl.data[off : off+size]
+}
+
+func (tbl *GvarTable) sharedTuple(i int) []byte {
+	if i >= int(tbl.SharedTupleCount) {
+		return nil
+	}
+	size := int(tbl.AxisCount) * 2
+	off := int(tbl.sharedTuplesOffset) + i*size
+	if off+size > len(tbl.data) {
+		return nil
+	}
+	return tbl.data[off : off+size]
+}
+
+// ApplyDeltas adds the deltas of a glyph's variations for an instance, given
+// by its normalized variation coordinates, to the glyph's points.
+//
+// For simple glyphs, points holds the glyph's points followed by its four
+// phantom points, and endPts holds the index of the last point of each
+// contour. Deltas of points that the variation data doesn't reference are
+// inferred from the neighbouring points in the same contour.
+//
+// For composite glyphs, points holds one point per component, storing the
+// component's offset, followed by the four phantom points, and endPts is nil.
+func (tbl *GvarTable) ApplyDeltas(glyph GlyphID, coords []float64, points []GlyphPoint, endPts []int) error {
+	data := tbl.GlyphVariationData(glyph)
+	if len(data) < 4 {
+		return nil
+	}
+	count := binary.BigEndian.Uint16(data)
+	dataOffset := int(binary.BigEndian.Uint16(data[2:]))
+	if dataOffset > len(data) {
+		return errMalformedVariations
+	}
+	headers := data[4:]
+	serialized := data[dataOffset:]
+
+	var shared []uint16
+	sharedAll := true
+	if count&sharedPointNumbers != 0 {
+		var n int
+		var ok bool
+		shared, sharedAll, n, ok = unpackPoints(serialized)
+		if !ok {
+			return errMalformedVariations
+		}
+		serialized = serialized[n:]
+	}
+
+	axes := int(tbl.AxisCount)
+	dx := make([]float64, len(points))
+	dy := make([]float64, len(points))
+	var (
+		tx, ty  []float64
+		touched []bool
+	)
+	for range count & tupleCountMask {
+		if len(headers) < 4 {
+			return errMalformedVariations
+		}
+		size := int(binary.BigEndian.Uint16(headers))
+		index := binary.BigEndian.Uint16(headers[2:])
+		headers = headers[4:]
+
+		var peak, start, end []byte
+		if index&tupleEmbeddedPeak != 0 {
+			if len(headers) < axes*2 {
+				return errMalformedVariations
+			}
+			peak, headers = headers[:axes*2], headers[axes*2:]
+		} else {
+			peak = tbl.sharedTuple(int(index & tupleIndexMask))
+			if peak == nil {
+				return errMalformedVariations
+			}
+		}
+		if index&tupleIntermediate != 0 {
+			if len(headers) < axes*4 {
+				return errMalformedVariations
+			}
+			start, end, headers = headers[:axes*2], headers[axes*2:axes*4], headers[axes*4:]
+		}
+		if len(serialized) < size {
+			return errMalformedVariations
+		}
+		tuple := serialized[:size]
+		serialized = serialized[size:]
+
+		scalar := tupleScalar(coords, peak, start, end)
+		if scalar == 0 {
+			continue
+		}
+
+		pts, all := shared, sharedAll
+		if index&tuplePrivatePoints != 0 {
+			var n int
+			var ok bool
+			pts, all, n, ok = unpackPoints(tuple)
+			if !ok {
+				return errMalformedVariations
+			}
+			tuple = tuple[n:]
+		}
+		n := len(pts)
+		if all {
+			n = len(points)
+		}
+		xs, rest, ok := unpackDeltas(tuple, n)
+		if !ok {
+			return errMalformedVariations
+		}
+		ys, _, ok := unpackDeltas(rest, n)
+		if !ok {
+			return errMalformedVariations
+		}
+
+		if all {
+			for i := range points {
+				dx[i] += scalar * xs[i]
+				dy[i] += scalar * ys[i]
+			}
+			continue
+		}
+
+		if tx == nil {
+			tx = make([]float64, len(points))
+			ty = make([]float64, len(points))
+			touched = make([]bool, len(points))
+		} else {
+			clear(tx)
+			clear(ty)
+			clear(touched)
+		}
+		for i, p := range pts {
+			if int(p) >= len(points) {
+				continue
+			}
+			tx[p] += xs[i]
+			ty[p] += ys[i]
+			touched[p] = true
+		}
+		if endPts != nil {
+			inferDeltas(points, tx, ty, touched, endPts)
+		}
+		for i := range points {
+			dx[i] += scalar * tx[i]
+			dy[i] += scalar * ty[i]
+		}
+	}
+
+	for i := range points {
+		points[i].X += dx[i]
+		points[i].Y += dy[i]
+	}
+	return nil
+}
+
+// tupleScalar computes the scalar of a tuple variation. peak, start and end
+// are arrays of F2DOT14; start and end are nil for tuples without an
+// intermediate region.
+func tupleScalar(coords []float64, peak, start, end []byte) float64 {
+	f2dot14 := func(b []byte, i int) float64 {
+		return Int2_14(binary.BigEndian.Uint16(b[i*2:])).Float()
+	}
+	scalar := 1.0
+	for axis := range len(peak) / 2 {
+		p := f2dot14(peak, axis)
+		if p == 0 {
+			continue
+		}
+		var v float64
+		if axis < len(coords) {
+			v = coords[axis]
+		}
+		if v == p {
+			continue
+		}
+		if start != nil {
+			s, e := f2dot14(start, axis), f2dot14(end, axis)
+			switch {
+			case v <= s || v >= e:
+				return 0
+			case v < p:
+				scalar *= (v - s) / (p - s)
+			default:
+				scalar *= (e - v) / (e - p)
+			}
+		} else {
+			if v == 0 || v < min(0, p) || v > max(0, p) {
+				return 0
+			}
+			scalar *= v / p
+		}
+	}
+	return scalar
+}
+
+// unpackPoints decodes packed point numbers. It returns all == true if the
+// data refers to all points of the glyph, as well as the number of bytes
+// consumed.
+func unpackPoints(b []byte) (pts []uint16, all bool, n int, ok bool) {
+	if len(b) < 1 {
+		return nil, false, 0, false
+	}
+	count := int(b[0])
+	n = 1
+	if count&0x80 != 0 {
+		if len(b) < 2 {
+			return nil, false, 0, false
+		}
+		count = (count&0x7F)<<8 | int(b[1])
+		n = 2
+	}
+	if count == 0 {
+		return nil, true, n, true
+	}
+	pts = make([]uint16, 0, count)
+	var p uint16
+	for len(pts) < count {
+		if n >= len(b) {
+			return nil, false, 0, false
+		}
+		ctl := b[n]
+		n++
+		run := int(ctl&pointRunCountMask) + 1
+		size := 1
+		if ctl&pointsAreWords != 0 {
+			size = 2
+		}
+		if n+run*size > len(b) {
+			return nil, false, 0, false
+		}
+		for range min(run, count-len(pts)) {
+			if size == 2 {
+				p += binary.BigEndian.Uint16(b[n:])
+			} else {
+				p += uint16(b[n])
+			}
+			n += size
+			pts = append(pts, p)
+		}
+	}
+	return pts, false, n, true
+}
+
+// unpackDeltas decodes count packed deltas and returns the remaining data.
+func unpackDeltas(b []byte, count int) ([]float64, []byte, bool) {
+	out := make([]float64, 0, count)
+	for len(out) < count {
+		if len(b) < 1 {
+			return nil, nil, false
+		}
+		ctl := b[0]
+		b = b[1:]
+		run := min(int(ctl&deltaRunCountMask)+1, count-len(out))
+		var size int
+		switch ctl & deltasAreLongs {
+		case deltasAreZero:
+			size = 0
+		case deltasAreWords:
+			size = 2
+		case deltasAreLongs:
+			size = 4
+		default:
+			size = 1
+		}
+		if len(b) < run*size {
+			return nil, nil, false
+		}
+		for range run {
+			var d int32
+			switch size {
+			case 1:
+				d = int32(int8(b[0]))
+			case 2:
+				d = int32(int16(binary.BigEndian.Uint16(b)))
+			case 4:
+				d = int32(binary.BigEndian.Uint32(b))
+			}
+			b = b[size:]
+			out = append(out, float64(d))
+		}
+	}
+	return out, b, true
+}
+
+// inferDeltas infers the deltas of the points that aren't touched by a tuple
+// variation ("interpolate untouched points"), contour by contour. Phantom
+// points, which don't belong to any contour, aren't affected.
+func inferDeltas(points []GlyphPoint, dx, dy []float64, touched []bool, endPts []int) {
+	start := 0
+	for _, end := range endPts {
+		if end < start || end >= len(points) {
+			return
+		}
+		inferContour(points, dx, dy, touched, start, end)
+		start = end + 1
+	}
+}
+
+func inferContour(points []GlyphPoint, dx, dy []float64, touched []bool, start, end int) {
+	first := -1
+	for i := start; i <= end; i++ {
+		if touched[i] {
+			first = i
+			break
+		}
+	}
+	if first == -1 {
+		return
+	}
+	n := end - start + 1
+	next := func(i int) int {
+		if i == end {
+			return start
+		}
+		return i + 1
+	}
+
+	// Walk the contour starting at the first touched point, interpolating
+	// the untouched points between each pair of touched points. With a
+	// single touched point, both references are the same point and all
+	// untouched points get its delta.
+	prev := first
+	i := next(first)
+	for range n {
+		if !touched[i] {
+			i = next(i)
+			continue
+		}
+		for j := next(prev); j != i; j = next(j) {
+			dx[j] = inferDelta(points[j].X, points[prev].X, points[i].X, dx[prev], dx[i])
+			dy[j] = inferDelta(points[j].Y, points[prev].Y, points[i].Y, dy[prev], dy[i])
+		}
+		if i == first {
+			break
+		}
+		prev = i
+		i = next(i)
+	}
+}
+
+// inferDelta infers the delta of a coordinate v from the coordinates and
+// deltas of two reference points.
+func inferDelta(v, a, b, da, db float64) float64 {
+	if a > b {
+		a, b = b, a
+		da, db = db, da
+	}
+	switch {
+	case a == b:
+		if da == db {
+			return da
+		}
+		return 0
+	case v <= a:
+		return da
+	case v >= b:
+		return db
+	default:
+		return da + (v-a)*(db-da)/(b-a)
+	}
+}
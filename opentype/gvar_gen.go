@@ -0,0 +1,54 @@
+// Code generated by honnef.co/go/gutter/internal/cmd/gen_parser. DO NOT EDIT.
+
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+type GvarTable struct {
+	data []byte
+
+	MajorVersion                  uint16
+	MinorVersion                  uint16
+	AxisCount                     uint16
+	SharedTupleCount              uint16
+	sharedTuplesOffset            uint32
+	GlyphCount                    uint16
+	Flags                         uint16
+	glyphVariationDataArrayOffset uint32
+
+	// Either GlyphCount+1 Offset16 or Offset32, depending on Flags.
+	glyphVariationDataOffsets []byte
+}
+func ParseGvarTable(buf []byte, out *GvarTable) int {
+	*out = GvarTable{}
+	origBuf := buf
+	var dynSize int
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 20 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.MajorVersion)
+	/* XXX return error */
+	if out.MajorVersion > 1 {
+		return dynSize
+	}
+	parseUint16(buf[2:4], &out.MinorVersion)
+	parseUint16(buf[4:6], &out.AxisCount)
+	parseUint16(buf[6:8], &out.SharedTupleCount)
+	parseUint32(buf[8:12], &out.sharedTuplesOffset)
+	parseUint16(buf[12:14], &out.GlyphCount)
+	parseUint16(buf[14:16], &out.Flags)
+	parseUint32(buf[16:20], &out.glyphVariationDataArrayOffset)
+	{
+		out.glyphVariationDataOffsets = buf[20:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+// Width returns the device advance width of a glyph at a size in pixels per
+// em. It returns false if the table has no record for the size.
+func (tbl *HdmxTable) Width(ppem uint8, glyph GlyphID) (uint8, bool) {
+	if tbl.sizeDeviceRecord == 0 {
+		return 0, false
+	}
+	for _, rec := range tbl.Records() {
+		if rec.PixelSize == ppem {
+			if glyph < 0 || int(glyph) >= len(rec.Widths) {
+				return 0, false
+			}
+			return rec.Widths[glyph], true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,92 @@
+// Code generated by honnef.co/go/gutter/internal/cmd/gen_parser. DO NOT EDIT.
+
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import "iter"
+
+type HdmxTable struct {
+	Version uint16
+
+	sizeDeviceRecord uint32
+
+	records Slice[HdmxDeviceRecord]
+}
+
+type HdmxDeviceRecord struct {
+	PixelSize uint8
+	MaxWidth  uint8
+	// One width per glyph, followed by padding.
+	Widths []byte
+}
+func parseHdmxDeviceRecord(buf []byte, out *HdmxDeviceRecord) int {
+	*out = HdmxDeviceRecord{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 2 {
+		return dynSize
+	}
+	out.PixelSize = buf[0:1][0]
+	out.MaxWidth = buf[1:2][0]
+	{
+		out.Widths = buf[2:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func ParseHdmxTable(buf []byte, out *HdmxTable) int {
+	*out = HdmxTable{}
+	origBuf := buf
+	var dynSize int
+	var numRecords uint16
+
+	/* FIXME return error */
+	if len(buf) < 8 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.Version)
+	parseUint16(buf[2:4], &numRecords)
+	parseUint32(buf[4:8], &out.sizeDeviceRecord)
+	{
+		sz := int(out.sizeDeviceRecord)
+		n := int(numRecords)
+		/* FIXME: check that buf is long enough */
+		out.records = buf[8 : 8+n*sz]
+		dynSize += n * sz
+		buf = buf[8+n*sz:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func (tbl *HdmxTable) Record(i int) HdmxDeviceRecord {
+	sz := int(tbl.sizeDeviceRecord)
+	var out HdmxDeviceRecord
+	parseHdmxDeviceRecord(tbl.records[i*sz:(i+1)*sz:len(tbl.records)], &out)
+	return out
+}
+
+func (tbl *HdmxTable) Records() iter.Seq2[int, HdmxDeviceRecord] {
+	return func(yield func(int, HdmxDeviceRecord) bool) {
+		for i := range tbl.NumRecords() {
+			if !yield(i, tbl.Record(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *HdmxTable) NumRecords() int {
+	return len(tbl.records) / int(tbl.sizeDeviceRecord)
+}
+
@@ -0,0 +1,58 @@
+// Code generated by honnef.co/go/gutter/internal/cmd/gen_parser. DO NOT EDIT.
+
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+type HheaTable struct {
+	MajorVersion uint16
+	MinorVersion uint16
+
+	Ascender            int16
+	Descender           int16
+	LineGap             int16
+	AdvanceWidthMax     uint16
+	MinLeftSideBearing  int16
+	MinRightSideBearing int16
+	XMaxExtent          int16
+	CaretSlopeRise      int16
+	CaretSlopeRun       int16
+	CaretOffset         int16
+
+	MetricDataFormat int16
+	NumberOfHMetrics uint16
+}
+func ParseHheaTable(buf []byte, out *HheaTable) int {
+	*out = HheaTable{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 36 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.MajorVersion)
+	/* XXX return error */
+	if out.MajorVersion > 1 {
+		return dynSize
+	}
+	parseUint16(buf[2:4], &out.MinorVersion)
+	parseInt16(buf[4:6], &out.Ascender)
+	parseInt16(buf[6:8], &out.Descender)
+	parseInt16(buf[8:10], &out.LineGap)
+	parseUint16(buf[10:12], &out.AdvanceWidthMax)
+	parseInt16(buf[12:14], &out.MinLeftSideBearing)
+	parseInt16(buf[14:16], &out.MinRightSideBearing)
+	parseInt16(buf[16:18], &out.XMaxExtent)
+	parseInt16(buf[18:20], &out.CaretSlopeRise)
+	parseInt16(buf[20:22], &out.CaretSlopeRun)
+	parseInt16(buf[22:24], &out.CaretOffset)
+	parseInt16(buf[32:34], &out.MetricDataFormat)
+	parseUint16(buf[34:36], &out.NumberOfHMetrics)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
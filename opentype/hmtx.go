@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+// sideMetric decodes the advance and side bearing of a glyph from hmtx or
+// vmtx data that starts with numLong long metrics. Glyphs past the long
+// metrics use the advance of the last long metric.
+func sideMetric(data []byte, glyph GlyphID, numLong uint16) (advance uint16, bearing int16) {
+	n := int(numLong)
+	if n == 0 || glyph < 0 || len(data) < n*4 {
+		return 0, 0
+	}
+	if int(glyph) < n {
+		parseUint16(data[glyph*4:], &advance)
+		parseInt16(data[glyph*4+2:], &bearing)
+		return advance, bearing
+	}
+	parseUint16(data[(n-1)*4:], &advance)
+	off := n*4 + (int(glyph)-n)*2
+	if off+2 <= len(data) {
+		parseInt16(data[off:], &bearing)
+	}
+	return advance, bearing
+}
+
+// Metric returns the advance width and left side bearing of a glyph.
+// numberOfHMetrics is the value of HheaTable.NumberOfHMetrics.
+func (tbl *HmtxTable) Metric(glyph GlyphID, numberOfHMetrics uint16) LongHorMetric {
+	adv, lsb := sideMetric(tbl.metrics, glyph, numberOfHMetrics)
+	return LongHorMetric{AdvanceWidth: adv, Lsb: lsb}
+}
+
+// Metric returns the advance height and top side bearing of a glyph.
+// numOfLongVerMetrics is the value of VheaTable.NumOfLongVerMetrics.
+func (tbl *VmtxTable) Metric(glyph GlyphID, numOfLongVerMetrics uint16) LongVerMetric {
+	adv, tsb := sideMetric(tbl.metrics, glyph, numOfLongVerMetrics)
+	return LongVerMetric{AdvanceHeight: adv, TopSideBearing: tsb}
+}
@@ -0,0 +1,92 @@
+// Code generated by honnef.co/go/gutter/internal/cmd/gen_parser. DO NOT EDIT.
+
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+// The number of long metrics is stored in the hhea and vhea tables, so the
+// metrics are stored raw and decoded by hand-written accessors.
+
+type HmtxTable struct {
+	// HheaTable.NumberOfHMetrics LongHorMetric records, followed by the left
+	// side bearings of the remaining glyphs.
+	metrics []byte
+}
+
+type LongHorMetric struct {
+	AdvanceWidth uint16
+	Lsb          int16
+}
+
+type VmtxTable struct {
+	// VheaTable.NumOfLongVerMetrics LongVerMetric records, followed by the
+	// top side bearings of the remaining glyphs.
+	metrics []byte
+}
+
+type LongVerMetric struct {
+	AdvanceHeight  uint16
+	TopSideBearing int16
+}
+func ParseHmtxTable(buf []byte, out *HmtxTable) int {
+	*out = HmtxTable{}
+	origBuf := buf
+	var dynSize int
+
+	{
+		out.metrics = buf[0:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseLongHorMetric(buf []byte, out *LongHorMetric) int {
+	*out = LongHorMetric{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 4 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.AdvanceWidth)
+	parseInt16(buf[2:4], &out.Lsb)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func parseLongVerMetric(buf []byte, out *LongVerMetric) int {
+	*out = LongVerMetric{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 4 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.AdvanceHeight)
+	parseInt16(buf[2:4], &out.TopSideBearing)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
+func ParseVmtxTable(buf []byte, out *VmtxTable) int {
+	*out = VmtxTable{}
+	origBuf := buf
+	var dynSize int
+
+	{
+		out.metrics = buf[0:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
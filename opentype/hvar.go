@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import "encoding/binary"
+
+// AdvanceWidthDelta returns the delta of the glyph's advance width for an
+// instance, given by its normalized variation coordinates.
+func (tbl *HVARTable) AdvanceWidthDelta(glyph GlyphID, coords []float64) float64 {
+	var store ItemVariationStoreTable
+	if !tbl.ItemVariationStore(&store) {
+		return 0
+	}
+	// Without a mapping, glyph IDs are used as inner indices into the first
+	// item variation data subtable.
+	outer, inner := uint16(0), uint16(glyph)
+	var m DeltaSetIndexMapTable
+	if tbl.AdvanceWidthMapping(&m) {
+		outer, inner = m.Map(uint32(glyph))
+	}
+	return store.Delta(outer, inner, coords)
+}
+
+// LsbDelta returns the delta of the glyph's left side bearing for an
+// instance. It returns false if the table has no left side bearing mapping,
+// in which case the delta has to be derived from the glyph's phantom points.
+func (tbl *HVARTable) LsbDelta(glyph GlyphID, coords []float64) (float64, bool) {
+	var m DeltaSetIndexMapTable
+	if !tbl.LsbMapping(&m) {
+		return 0, false
+	}
+	return tbl.mappedDelta(&m, glyph, coords), true
+}
+
+// RsbDelta returns the delta of the glyph's right side bearing for an
+// instance. It returns false if the table has no right side bearing mapping.
+func (tbl *HVARTable) RsbDelta(glyph GlyphID, coords []float64) (float64, bool) {
+	var m DeltaSetIndexMapTable
+	if !tbl.RsbMapping(&m) {
+		return 0, false
+	}
+	return tbl.mappedDelta(&m, glyph, coords), true
+}
+
+func (tbl *HVARTable) mappedDelta(m *DeltaSetIndexMapTable, glyph GlyphID, coords []float64) float64 {
+	var store ItemVariationStoreTable
+	if !tbl.ItemVariationStore(&store) {
+		return 0
+	}
+	outer, inner := m.Map(uint32(glyph))
+	return store.Delta(outer, inner, coords)
+}
+
+// Subset returns the data of an HVAR table for a font consisting of glyphs,
+// given by their IDs in this font. The item variation store is copied and the
+// glyphs' delta-set indices are stored in new mappings. It returns nil if the
+// table has no item variation store.
+func (tbl *HVARTable) Subset(glyphs []GlyphID) []byte {
+	if tbl.itemVariationStoreOffset == 0 || int(tbl.itemVariationStoreOffset) > len(tbl.data) {
+		return nil
+	}
+	// OPT(dh): the store extends to the end of the table, which may include
+	// the original mappings.
+	store := tbl.data[tbl.itemVariationStoreOffset:]
+
+	const headerSize = 20
+	out := make([]byte, headerSize, headerSize+len(store))
+	binary.BigEndian.PutUint16(out[0:], 1)
+	binary.BigEndian.PutUint16(out[2:], 0)
+	binary.BigEndian.PutUint32(out[4:], headerSize)
+	out = append(out, store...)
+
+	entries := make([]uint32, len(glyphs))
+	mapping := func(off int, m *DeltaSetIndexMapTable, ok bool) {
+		for i, glyph := range glyphs {
+			// Without a mapping, glyph IDs are used as inner indices into the
+			// first item variation data subtable.
+			outer, inner := uint16(0), uint16(glyph)
+			if ok {
+				outer, inner = m.Map(uint32(glyph))
+			}
+			entries[i] = uint32(outer)<<16 | uint32(inner)
+		}
+		binary.BigEndian.PutUint32(out[off:], uint32(len(out)))
+		out = appendDeltaSetIndexMap(out, entries)
+	}
+	var m DeltaSetIndexMapTable
+	mapping(8, &m, tbl.AdvanceWidthMapping(&m))
+	if tbl.LsbMapping(&m) {
+		mapping(12, &m, true)
+	}
+	if tbl.RsbMapping(&m) {
+		mapping(16, &m, true)
+	}
+	return out
+}
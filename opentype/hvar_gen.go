@@ -0,0 +1,79 @@
+// Code generated by honnef.co/go/gutter/internal/cmd/gen_parser. DO NOT EDIT.
+
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+type HVARTable struct {
+	data []byte
+
+	MajorVersion              uint16
+	MinorVersion              uint16
+	itemVariationStoreOffset  Offset32[ItemVariationStoreTable]
+	advanceWidthMappingOffset Offset32[DeltaSetIndexMapTable]
+	lsbMappingOffset          Offset32[DeltaSetIndexMapTable]
+	rsbMappingOffset          Offset32[DeltaSetIndexMapTable]
+}
+func ParseHVARTable(buf []byte, out *HVARTable) int {
+	*out = HVARTable{}
+	origBuf := buf
+	var dynSize int
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 20 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.MajorVersion)
+	/* XXX return error */
+	if out.MajorVersion > 1 {
+		return dynSize
+	}
+	parseUint16(buf[2:4], &out.MinorVersion)
+	parseOffset32(buf[4:8], &out.itemVariationStoreOffset)
+	parseOffset32(buf[8:12], &out.advanceWidthMappingOffset)
+	parseOffset32(buf[12:16], &out.lsbMappingOffset)
+	parseOffset32(buf[16:20], &out.rsbMappingOffset)
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *HVARTable) ItemVariationStore(into *ItemVariationStoreTable) bool {
+	// FIXME: check bounds
+	if tbl.itemVariationStoreOffset == 0 {
+		return false
+	}
+	ParseItemVariationStoreTable(tbl.data[tbl.itemVariationStoreOffset:], into)
+	return true
+}
+
+func (tbl *HVARTable) AdvanceWidthMapping(into *DeltaSetIndexMapTable) bool {
+	// FIXME: check bounds
+	if tbl.advanceWidthMappingOffset == 0 {
+		return false
+	}
+	ParseDeltaSetIndexMapTable(tbl.data[tbl.advanceWidthMappingOffset:], into)
+	return true
+}
+
+func (tbl *HVARTable) LsbMapping(into *DeltaSetIndexMapTable) bool {
+	// FIXME: check bounds
+	if tbl.lsbMappingOffset == 0 {
+		return false
+	}
+	ParseDeltaSetIndexMapTable(tbl.data[tbl.lsbMappingOffset:], into)
+	return true
+}
+
+func (tbl *HVARTable) RsbMapping(into *DeltaSetIndexMapTable) bool {
+	// FIXME: check bounds
+	if tbl.rsbMappingOffset == 0 {
+		return false
+	}
+	ParseDeltaSetIndexMapTable(tbl.data[tbl.rsbMappingOffset:], into)
+	return true
+}
+
+
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package tables
+
+import "honnef.co/go/gutter/opentype"
+
+type AvarTable struct {
+	MajorVersion uint16 `gen:"1"`
+	MinorVersion uint16
+	_reserved    uint16
+	AxisCount    uint16
+
+	// One variable-size SegmentMaps per axis.
+	segmentMaps []byte `gen:"slice(count=-1)"`
+}
+
+type SegmentMaps struct {
+	positionMapCount uint16                       `gen:"omit()"`
+	axisValueMaps    opentype.Slice[AxisValueMap] `gen:"slice(count=positionMapCount)"`
+}
+
+type AxisValueMap struct {
+	FromCoordinate opentype.Int2_14
+	ToCoordinate   opentype.Int2_14
+}
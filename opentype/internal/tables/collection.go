@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package tables
+
+import "honnef.co/go/gutter/opentype"
+
+// The header of a font collection file (.ttc or .otc).
+type CollectionHeader struct {
+	data []byte
+
+	TTCTag       opentype.Tag
+	MajorVersion uint16 `gen:"2"`
+	MinorVersion uint16
+	numFonts     uint32 `gen:"omit()"`
+	// Offsets to the fonts' table directories, from the start of the file.
+	tableDirectoryOffsets opentype.Slice[opentype.Offset32[TableDirectory]] `gen:"slice(count=numFonts)"`
+	// The DSIG fields that follow in version 2 are deprecated and ignored.
+}
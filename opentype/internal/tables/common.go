@@ -301,3 +301,80 @@ type VariationIndexTable struct {
 	DeltaSetInnerIndex uint16
 	DeltaFormat        uint16
 }
+
+type ChainedSequenceContextTable struct {
+	data []byte
+
+	Format  uint16
+	Format1 ChainedSequenceContextTableFormat1 `gen:"union(Format,1)"`
+	Format2 ChainedSequenceContextTableFormat2 `gen:"union(Format,2)"`
+	Format3 ChainedSequenceContextTableFormat3 `gen:"union(Format,3)"`
+}
+
+type ChainedSequenceContextTableFormat1 struct {
+	parentData []byte
+
+	coverageOffset           opentype.Offset16[CoverageTable]
+	chainedSeqRuleSetCount   uint16 `gen:"omit()"`
+	chainedSeqRuleSetOffsets opentype.Slice[opentype.Offset16[ChainedSequenceRuleSetTable]]
+}
+
+type ChainedSequenceRuleSetTable struct {
+	data []byte
+
+	chainedSeqRuleCount   uint16 `gen:"omit()"`
+	chainedSeqRuleOffsets opentype.Slice[opentype.Offset16[ChainedSequenceRuleTable]]
+}
+
+type ChainedSequenceRuleTable struct {
+	backtrackGlyphCount uint16 `gen:"omit()"`
+	backtrackSequence   opentype.Slice[uint16]
+	inputGlyphCount     uint16                               `gen:"omit()"`
+	inputSequence       opentype.Slice[uint16]               `gen:"slice(count=inputGlyphCount-1)"`
+	lookaheadGlyphCount uint16                               `gen:"omit()"`
+	lookaheadSequence   opentype.Slice[uint16]               `gen:"slice(count=lookaheadGlyphCount)"`
+	seqLookupCount      uint16                               `gen:"omit()"`
+	seqLookupRecords    opentype.Slice[SequenceLookupRecord] `gen:"slice(count=seqLookupCount)"`
+}
+
+type ChainedSequenceContextTableFormat2 struct {
+	parentData []byte
+
+	coverageOffset                opentype.Offset16[CoverageTable]
+	backtrackClassDefOffset       opentype.Offset16[ClassDefTable]
+	inputClassDefOffset           opentype.Offset16[ClassDefTable]
+	lookaheadClassDefOffset       opentype.Offset16[ClassDefTable]
+	chainedClassSeqRuleSetCount   uint16 `gen:"omit()"`
+	chainedClassSeqRuleSetOffsets opentype.Slice[opentype.Offset16[ChainedClassSequenceRuleSetTable]]
+}
+
+type ChainedClassSequenceRuleSetTable struct {
+	data []byte
+
+	chainedClassSeqRuleCount   uint16 `gen:"omit()"`
+	chainedClassSeqRuleOffsets opentype.Slice[opentype.Offset16[ChainedClassSequenceRuleTable]]
+}
+
+type ChainedClassSequenceRuleTable struct {
+	backtrackGlyphCount uint16 `gen:"omit()"`
+	backtrackSequence   opentype.Slice[uint16]
+	inputGlyphCount     uint16                               `gen:"omit()"`
+	inputSequence       opentype.Slice[uint16]               `gen:"slice(count=inputGlyphCount-1)"`
+	lookaheadGlyphCount uint16                               `gen:"omit()"`
+	lookaheadSequence   opentype.Slice[uint16]               `gen:"slice(count=lookaheadGlyphCount)"`
+	seqLookupCount      uint16                               `gen:"omit()"`
+	seqLookupRecords    opentype.Slice[SequenceLookupRecord] `gen:"slice(count=seqLookupCount)"`
+}
+
+type ChainedSequenceContextTableFormat3 struct {
+	parentData []byte
+
+	backtrackGlyphCount      uint16 `gen:"omit()"`
+	backtrackCoverageOffsets opentype.Slice[opentype.Offset16[CoverageTable]]
+	inputGlyphCount          uint16                                           `gen:"omit()"`
+	inputCoverageOffsets     opentype.Slice[opentype.Offset16[CoverageTable]] `gen:"slice(count=inputGlyphCount)"`
+	lookaheadGlyphCount      uint16                                           `gen:"omit()"`
+	lookaheadCoverageOffsets opentype.Slice[opentype.Offset16[CoverageTable]] `gen:"slice(count=lookaheadGlyphCount)"`
+	seqLookupCount           uint16                                           `gen:"omit()"`
+	seqLookupRecords         opentype.Slice[SequenceLookupRecord]             `gen:"slice(count=seqLookupCount)"`
+}
@@ -7,20 +7,22 @@ package tables
 import "honnef.co/go/gutter/opentype"
 
 type FvarTable struct {
+	data []byte
+
 	MajorVersion uint16 `gen:"1"`
 	MinorVersion uint16
 
-	axesArrayOffset uint16 `gen:"omit()"`
+	axesArrayOffset uint16
 	_reserved       uint16
 	axisCount       uint16 `gen:"omit()"`
 	axisSize        uint16
-	instanceCount   uint16 `gen:"omit()"`
+	instanceCount   uint16
 	instanceSize    uint16
 
 	axes opentype.Slice[VariationAxisRecord] `gen:"slice(offset=axesArrayOffset, count=axisCount, size=axisSize, singular=Axis)"`
 
-	// XXX parse instances
-	// instances opentype.Slice[InstanceRecord]      `gen:"slice(offset=int(axesArrayOffset)+len(out.axes), count=instanceCount, size=instanceSize)"`
+	// The instance records follow the axes. Their size depends on the number
+	// of axes, which the parser generator can't express.
 }
 
 type VariationAxisRecord struct {
@@ -31,17 +33,3 @@ type VariationAxisRecord struct {
 	Flags        uint16
 	NameID       opentype.NameID
 }
-
-// type InstanceRecord struct {
-// 	Subfamily   opentype.NameID
-// 	Flags       uint16
-// 	Coordinates UserTuple
-
-// 	_          sizeDelimiter
-// 	PostScript opentype.NameID
-// }
-
-// type UserTuple struct {
-// 	// XXX axisCount from FvarTable
-// 	coordinates opentype.Slice[opentype.Int16_16] `gen:"slice(count=axisCount)"`
-// }
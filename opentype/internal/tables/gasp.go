@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package tables
+
+import "honnef.co/go/gutter/opentype"
+
+type GaspTable struct {
+	Version    uint16
+	numRanges  uint16                    `gen:"omit()"`
+	gaspRanges opentype.Slice[GaspRange] `gen:"slice(count=numRanges)"`
+}
+
+type GaspRange struct {
+	RangeMaxPPEM      uint16
+	RangeGaspBehavior uint16
+}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package tables
+
+import "honnef.co/go/gutter/opentype"
+
+const (
+	GPOSLookupTypeSingle          = 1
+	GPOSLookupTypePair            = 2
+	GPOSLookupTypeCursive         = 3
+	GPOSLookupTypeMarkToBase      = 4
+	GPOSLookupTypeMarkToLigature  = 5
+	GPOSLookupTypeMarkToMark      = 6
+	GPOSLookupTypeContext         = 7
+	GPOSLookupTypeChainingContext = 8
+	GPOSLookupTypeExtension       = 9
+)
+
+// ValueFormat describes which fields are present in a value record.
+type ValueFormat uint16
+
+const (
+	ValueFormatXPlacement ValueFormat = 0x0001
+	ValueFormatYPlacement ValueFormat = 0x0002
+	ValueFormatXAdvance   ValueFormat = 0x0004
+	ValueFormatYAdvance   ValueFormat = 0x0008
+	ValueFormatXPlaDevice ValueFormat = 0x0010
+	ValueFormatYPlaDevice ValueFormat = 0x0020
+	ValueFormatXAdvDevice ValueFormat = 0x0040
+	ValueFormatYAdvDevice ValueFormat = 0x0080
+)
+
+type GPOSTable struct {
+	data []byte
+
+	MajorVersion      uint16 `gen:"1"`
+	MinorVersion      uint16
+	scriptListOffset  opentype.Offset16[ScriptListTable]
+	featureListOffset opentype.Offset16[FeatureListTable]
+	lookupListOffset  opentype.Offset16[LookupListTable]
+
+	_                       versionDelimiter `gen:"1.1"`
+	featureVariationsOffset opentype.Offset32[FeatureVariationsTable]
+}
+
+// Value records have sizes that depend on their value formats, which the
+// generator can't express. They're stored as raw bytes and decoded by
+// hand-written accessors.
+
+type SinglePosTable struct {
+	PosFormat uint16
+	Format1   SinglePosTableFormat1 `gen:"union(PosFormat,1)"`
+	Format2   SinglePosTableFormat2 `gen:"union(PosFormat,2)"`
+}
+
+type SinglePosTableFormat1 struct {
+	parentData []byte
+
+	coverageOffset opentype.Offset16[CoverageTable]
+	ValueFormat    ValueFormat
+	valueRecord    []byte `gen:"slice(count=-1)"`
+}
+
+type SinglePosTableFormat2 struct {
+	parentData []byte
+
+	coverageOffset opentype.Offset16[CoverageTable]
+	ValueFormat    ValueFormat
+	ValueCount     uint16
+	valueRecords   []byte `gen:"slice(count=-1)"`
+}
+
+type PairPosTable struct {
+	PosFormat uint16
+	Format1   PairPosTableFormat1 `gen:"union(PosFormat,1)"`
+	Format2   PairPosTableFormat2 `gen:"union(PosFormat,2)"`
+}
+
+type PairPosTableFormat1 struct {
+	parentData []byte
+
+	coverageOffset opentype.Offset16[CoverageTable]
+	ValueFormat1   ValueFormat
+	ValueFormat2   ValueFormat
+	pairSetCount   uint16 `gen:"omit()"`
+	pairSetOffsets opentype.Slice[opentype.Offset16[PairSetTable]]
+}
+
+type PairSetTable struct {
+	data []byte
+
+	PairValueCount   uint16
+	pairValueRecords []byte `gen:"slice(count=-1)"`
+}
+
+type PairPosTableFormat2 struct {
+	parentData []byte
+
+	coverageOffset  opentype.Offset16[CoverageTable]
+	ValueFormat1    ValueFormat
+	ValueFormat2    ValueFormat
+	classDef1Offset opentype.Offset16[ClassDefTable]
+	classDef2Offset opentype.Offset16[ClassDefTable]
+	Class1Count     uint16
+	Class2Count     uint16
+	class1Records   []byte `gen:"slice(count=-1)"`
+}
+
+type CursivePosTable struct {
+	PosFormat uint16
+	Format1   CursivePosTableFormat1 `gen:"union(PosFormat,1)"`
+}
+
+type CursivePosTableFormat1 struct {
+	parentData []byte
+
+	coverageOffset   opentype.Offset16[CoverageTable]
+	entryExitCount   uint16 `gen:"omit()"`
+	entryExitRecords opentype.Slice[EntryExitRecord]
+}
+
+type EntryExitRecord struct {
+	parentData []byte
+
+	entryAnchorOffset opentype.Offset16[AnchorTable]
+	exitAnchorOffset  opentype.Offset16[AnchorTable]
+}
+
+type AnchorTable struct {
+	AnchorFormat uint16
+	Format1      AnchorTableFormat1 `gen:"union(AnchorFormat,1)"`
+	Format2      AnchorTableFormat2 `gen:"union(AnchorFormat,2)"`
+	Format3      AnchorTableFormat3 `gen:"union(AnchorFormat,3)"`
+}
+
+type AnchorTableFormat1 struct {
+	XCoordinate int16
+	YCoordinate int16
+}
+
+type AnchorTableFormat2 struct {
+	XCoordinate int16
+	YCoordinate int16
+	AnchorPoint uint16
+}
+
+type AnchorTableFormat3 struct {
+	parentData []byte
+
+	XCoordinate   int16
+	YCoordinate   int16
+	xDeviceOffset opentype.Offset16[DeviceOrVariationIndexTable]
+	yDeviceOffset opentype.Offset16[DeviceOrVariationIndexTable]
+}
+
+type MarkArrayTable struct {
+	data []byte
+
+	markCount   uint16 `gen:"omit()"`
+	markRecords opentype.Slice[MarkRecord]
+}
+
+type MarkRecord struct {
+	parentData []byte
+
+	MarkClass        uint16
+	markAnchorOffset opentype.Offset16[AnchorTable]
+}
+
+type MarkBasePosTable struct {
+	PosFormat uint16
+	Format1   MarkBasePosTableFormat1 `gen:"union(PosFormat,1)"`
+}
+
+type MarkBasePosTableFormat1 struct {
+	parentData []byte
+
+	markCoverageOffset opentype.Offset16[CoverageTable]
+	baseCoverageOffset opentype.Offset16[CoverageTable]
+	MarkClassCount     uint16
+	markArrayOffset    opentype.Offset16[MarkArrayTable]
+	baseArrayOffset    opentype.Offset16[BaseArrayTable]
+}
+
+// The base records contain one anchor offset per mark class, which the
+// generator can't express.
+type BaseArrayTable struct {
+	data []byte
+
+	BaseCount   uint16
+	baseRecords []byte `gen:"slice(count=-1)"`
+}
+
+type MarkLigPosTable struct {
+	PosFormat uint16
+	Format1   MarkLigPosTableFormat1 `gen:"union(PosFormat,1)"`
+}
+
+type MarkLigPosTableFormat1 struct {
+	parentData []byte
+
+	markCoverageOffset     opentype.Offset16[CoverageTable]
+	ligatureCoverageOffset opentype.Offset16[CoverageTable]
+	MarkClassCount         uint16
+	markArrayOffset        opentype.Offset16[MarkArrayTable]
+	ligatureArrayOffset    opentype.Offset16[LigatureArrayTable]
+}
+
+type LigatureArrayTable struct {
+	data []byte
+
+	ligatureCount         uint16 `gen:"omit()"`
+	ligatureAttachOffsets opentype.Slice[opentype.Offset16[LigatureAttachTable]]
+}
+
+type LigatureAttachTable struct {
+	data []byte
+
+	ComponentCount   uint16
+	componentRecords []byte `gen:"slice(count=-1)"`
+}
+
+type MarkMarkPosTable struct {
+	PosFormat uint16
+	Format1   MarkMarkPosTableFormat1 `gen:"union(PosFormat,1)"`
+}
+
+type MarkMarkPosTableFormat1 struct {
+	parentData []byte
+
+	mark1CoverageOffset opentype.Offset16[CoverageTable]
+	mark2CoverageOffset opentype.Offset16[CoverageTable]
+	MarkClassCount      uint16
+	mark1ArrayOffset    opentype.Offset16[MarkArrayTable]
+	mark2ArrayOffset    opentype.Offset16[Mark2ArrayTable]
+}
+
+type Mark2ArrayTable struct {
+	data []byte
+
+	Mark2Count   uint16
+	mark2Records []byte `gen:"slice(count=-1)"`
+}
+
+type ExtensionPosTable struct {
+	PosFormat uint16
+	Format1   ExtensionPosTableFormat1 `gen:"union(PosFormat,1)"`
+}
+
+type ExtensionPosTableFormat1 struct {
+	parentData []byte
+
+	ExtensionLookupType uint16
+	ExtensionOffset     opentype.Offset32[any]
+}
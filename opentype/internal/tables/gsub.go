@@ -101,6 +101,8 @@ type LigatureSubstTableFormat1 struct {
 }
 
 type LigatureSetTable struct {
+	data []byte
+
 	ligatureCount   uint16 `gen:"omit()"`
 	ligatureOffsets opentype.Slice[opentype.Offset16[LigatureTable]]
 }
@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package tables
+
+type GvarTable struct {
+	data []byte
+
+	MajorVersion                  uint16 `gen:"1"`
+	MinorVersion                  uint16
+	AxisCount                     uint16
+	SharedTupleCount              uint16
+	sharedTuplesOffset            uint32
+	GlyphCount                    uint16
+	Flags                         uint16
+	glyphVariationDataArrayOffset uint32
+
+	// Either GlyphCount+1 Offset16 or Offset32, depending on Flags.
+	glyphVariationDataOffsets []byte `gen:"slice(count=-1)"`
+}
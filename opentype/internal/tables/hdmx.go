@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package tables
+
+import "honnef.co/go/gutter/opentype"
+
+type HdmxTable struct {
+	Version          uint16
+	numRecords       uint16 `gen:"omit()"`
+	sizeDeviceRecord uint32
+
+	records opentype.Slice[HdmxDeviceRecord] `gen:"slice(count=numRecords, size=sizeDeviceRecord)"`
+}
+
+type HdmxDeviceRecord struct {
+	PixelSize uint8
+	MaxWidth  uint8
+	// One width per glyph, followed by padding.
+	Widths []byte `gen:"slice(count=-1)"`
+}
@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package tables
+
+type HheaTable struct {
+	MajorVersion uint16 `gen:"1"`
+	MinorVersion uint16
+
+	Ascender            int16
+	Descender           int16
+	LineGap             int16
+	AdvanceWidthMax     uint16
+	MinLeftSideBearing  int16
+	MinRightSideBearing int16
+	XMaxExtent          int16
+	CaretSlopeRise      int16
+	CaretSlopeRun       int16
+	CaretOffset         int16
+	_reserved           [4]int16
+	MetricDataFormat    int16
+	NumberOfHMetrics    uint16
+}
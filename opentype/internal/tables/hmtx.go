@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package tables
+
+// The number of long metrics is stored in the hhea and vhea tables, so the
+// metrics are stored raw and decoded by hand-written accessors.
+
+type HmtxTable struct {
+	// HheaTable.NumberOfHMetrics LongHorMetric records, followed by the left
+	// side bearings of the remaining glyphs.
+	metrics []byte `gen:"slice(count=-1)"`
+}
+
+type LongHorMetric struct {
+	AdvanceWidth uint16
+	Lsb          int16
+}
+
+type VmtxTable struct {
+	// VheaTable.NumOfLongVerMetrics LongVerMetric records, followed by the
+	// top side bearings of the remaining glyphs.
+	metrics []byte `gen:"slice(count=-1)"`
+}
+
+type LongVerMetric struct {
+	AdvanceHeight  uint16
+	TopSideBearing int16
+}
@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package tables
+
+import "honnef.co/go/gutter/opentype"
+
+type HVARTable struct {
+	data []byte
+
+	MajorVersion              uint16 `gen:"1"`
+	MinorVersion              uint16
+	itemVariationStoreOffset  opentype.Offset32[ItemVariationStoreTable]
+	advanceWidthMappingOffset opentype.Offset32[DeltaSetIndexMapTable]
+	lsbMappingOffset          opentype.Offset32[DeltaSetIndexMapTable]
+	rsbMappingOffset          opentype.Offset32[DeltaSetIndexMapTable]
+}
@@ -4,5 +4,23 @@
 
 package tables
 
+import "honnef.co/go/gutter/opentype"
+
 type MVARTable struct {
+	data []byte
+
+	MajorVersion             uint16 `gen:"1"`
+	MinorVersion             uint16
+	_reserved                uint16
+	valueRecordSize          uint16
+	valueRecordCount         uint16 `gen:"omit()"`
+	itemVariationStoreOffset opentype.Offset16[ItemVariationStoreTable]
+
+	valueRecords opentype.Slice[MetricsValueRecord] `gen:"slice(count=valueRecordCount, size=valueRecordSize)"`
+}
+
+type MetricsValueRecord struct {
+	ValueTag           opentype.Tag
+	DeltaSetOuterIndex uint16
+	DeltaSetInnerIndex uint16
 }
@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package tables
+
+import "honnef.co/go/gutter/opentype"
+
+type PostTable struct {
+	MajorVersion uint16 `gen:"3"`
+	MinorVersion uint16 `gen:"version16dot16"`
+
+	ItalicAngle        opentype.Int16_16
+	UnderlinePosition  int16
+	UnderlineThickness int16
+	IsFixedPitch       uint32
+	MinMemType42       uint32
+	MaxMemType42       uint32
+	MinMemType1        uint32
+	MaxMemType1        uint32
+
+	// The glyph names of versions 2.0 and 2.5, whose layouts differ in ways
+	// that version delimiters can't express.
+	names []byte `gen:"slice(count=-1)"`
+}
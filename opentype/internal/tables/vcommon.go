@@ -4,7 +4,11 @@
 
 package tables
 
-import "honnef.co/go/gutter/opentype"
+import (
+	"encoding/binary"
+
+	"honnef.co/go/gutter/opentype"
+)
 
 type ItemVariationStoreTable struct {
 	data []byte
@@ -34,3 +38,40 @@ type RegionAxisCoordinates struct {
 	PeakCoord  opentype.Int2_14
 	EndCoord   opentype.Int2_14
 }
+
+// The delta-set index map's entry size and map count size depend on its format
+// and entry format, which the parser generator can't express.
+
+type DeltaSetIndexMapTable struct {
+	Format      uint8
+	EntryFormat uint8
+	MapCount    uint32
+	mapData     []byte
+}
+
+func ParseDeltaSetIndexMapTable(data []byte, out *DeltaSetIndexMapTable) int {
+	*out = DeltaSetIndexMapTable{}
+	if len(data) < 2 {
+		// XXX return error
+		return 0
+	}
+	out.Format = data[0]
+	out.EntryFormat = data[1]
+	switch out.Format {
+	case 0:
+		if len(data) < 4 {
+			return 0
+		}
+		out.MapCount = uint32(binary.BigEndian.Uint16(data[2:4]))
+		out.mapData = data[4:]
+	case 1:
+		if len(data) < 6 {
+			return 0
+		}
+		out.MapCount = binary.BigEndian.Uint32(data[2:6])
+		out.mapData = data[6:]
+	default:
+		// XXX return error
+	}
+	return 0
+}
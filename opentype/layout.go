@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import (
+	"cmp"
+	"iter"
+	"sort"
+)
+
+// This file contains accessors for the common table formats used by GSUB and
+// GPOS that the parser generator can't produce.
+
+// Index returns the coverage index of glyph, or false if the glyph isn't
+// covered.
+func (tbl *CoverageTable) Index(glyph GlyphID) (int, bool) {
+	switch tbl.CoverageFormat {
+	case 1:
+		f := &tbl.Format1
+		return sort.Find(f.NumGlyphArray(), func(i int) int {
+			return cmp.Compare(glyph, GlyphID(f.GetGlyphArray(i)))
+		})
+	case 2:
+		f := &tbl.Format2
+		n := f.NumRangeRecords()
+		i := sort.Search(n, func(i int) bool {
+			return GlyphID(f.RangeRecord(i).EndGlyphID) >= glyph
+		})
+		if i == n {
+			return 0, false
+		}
+		rec := f.RangeRecord(i)
+		if glyph < GlyphID(rec.StartGlyphID) {
+			return 0, false
+		}
+		return int(rec.StartCoverageIndex) + int(glyph) - int(rec.StartGlyphID), true
+	default:
+		return 0, false
+	}
+}
+
+// Glyphs returns the covered glyphs, in ascending order, together with their
+// coverage indices.
+func (tbl *CoverageTable) Glyphs() iter.Seq2[int, GlyphID] {
+	return func(yield func(int, GlyphID) bool) {
+		switch tbl.CoverageFormat {
+		case 1:
+			for i, g := range tbl.Format1.GlyphArray() {
+				if !yield(i, GlyphID(g)) {
+					return
+				}
+			}
+		case 2:
+			for _, rec := range tbl.Format2.RangeRecords() {
+				for g := int(rec.StartGlyphID); g <= int(rec.EndGlyphID); g++ {
+					if !yield(int(rec.StartCoverageIndex)+g-int(rec.StartGlyphID), GlyphID(g)) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// Class returns the class of glyph. Glyphs not assigned a class explicitly
+// are in class 0.
+func (tbl *ClassDefTable) Class(glyph GlyphID) uint16 {
+	switch tbl.ClassFormat {
+	case 1:
+		f := &tbl.Format1
+		i := int(glyph) - int(f.StartGlyphID)
+		if i < 0 || i >= f.NumClassValueArray() {
+			return 0
+		}
+		return f.GetClassValueArray(i)
+	case 2:
+		f := &tbl.Format2
+		n := f.NumClassRangeRecords()
+		i := sort.Search(n, func(i int) bool {
+			return GlyphID(f.ClassRangeRecord(i).EndGlyphID) >= glyph
+		})
+		if i == n {
+			return 0
+		}
+		rec := f.ClassRangeRecord(i)
+		if glyph < GlyphID(rec.StartGlyphID) {
+			return 0
+		}
+		return rec.Class
+	default:
+		return 0
+	}
+}
+
+// Script looks up the script with the given tag.
+func (tbl *ScriptListTable) Script(tag Tag, into *ScriptTable) bool {
+	for _, rec := range tbl.ScriptRecords() {
+		if rec.ScriptTag == tag {
+			return rec.Script(into)
+		}
+	}
+	return false
+}
+
+// LangSys looks up the language system with the given tag, falling back to
+// the default language system if there is none.
+func (tbl *ScriptTable) LangSys(tag Tag, into *LangSysTable) bool {
+	for _, rec := range tbl.LangSysRecords() {
+		if rec.LangSysTag == tag {
+			return rec.LangSys(into)
+		}
+	}
+	return tbl.DefaultLangSys(into)
+}
+
+// Lookup returns the i-th lookup.
+func (tbl *LookupListTable) Lookup(i int, into *LookupTable) bool {
+	if i < 0 || i >= tbl.NumLookupOffsets() {
+		return false
+	}
+	var off Offset16[LookupTable]
+	parseOffset16(tbl.lookupOffsets[i*2:], &off)
+	// FIXME: check bounds
+	ParseLookupTable(tbl.data[off:], into)
+	return true
+}
+
+// Subtable returns the data of the i-th subtable. How to parse it depends on
+// the table containing the lookup and on the lookup's type.
+func (tbl *LookupTable) Subtable(i int) []byte {
+	var off Offset16[any]
+	parseOffset16(tbl.subtableOffsets[i*2:], &off)
+	// FIXME: check bounds
+	return tbl.data[off:]
+}
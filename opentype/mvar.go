@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import (
+	"cmp"
+	"sort"
+)
+
+// Delta returns the delta of the metric identified by tag, such as "hasc" or
+// "xhgt", for an instance, given by its normalized variation coordinates. It
+// returns false if the metric doesn't vary.
+func (tbl *MVARTable) Delta(tag Tag, coords []float64) (float64, bool) {
+	if tbl.valueRecordSize < 8 {
+		return 0, false
+	}
+	i, ok := sort.Find(tbl.NumValueRecords(), func(i int) int {
+		return cmp.Compare(tag, tbl.ValueRecord(i).ValueTag)
+	})
+	if !ok {
+		return 0, false
+	}
+	var store ItemVariationStoreTable
+	if !tbl.ItemVariationStore(&store) {
+		return 0, false
+	}
+	rec := tbl.ValueRecord(i)
+	return store.Delta(rec.DeltaSetOuterIndex, rec.DeltaSetInnerIndex, coords), true
+}
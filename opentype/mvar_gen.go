@@ -6,13 +6,101 @@
 
 package opentype
 
+import "iter"
+
 type MVARTable struct {
+	data []byte
+
+	MajorVersion uint16
+	MinorVersion uint16
+
+	valueRecordSize uint16
+
+	itemVariationStoreOffset Offset16[ItemVariationStoreTable]
+
+	valueRecords Slice[MetricsValueRecord]
+}
+
+type MetricsValueRecord struct {
+	ValueTag           Tag
+	DeltaSetOuterIndex uint16
+	DeltaSetInnerIndex uint16
 }
 func ParseMVARTable(buf []byte, out *MVARTable) int {
 	*out = MVARTable{}
 	origBuf := buf
 	var dynSize int
+	var valueRecordCount uint16
+	out.data = buf
+
+	/* FIXME return error */
+	if len(buf) < 12 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.MajorVersion)
+	/* XXX return error */
+	if out.MajorVersion > 1 {
+		return dynSize
+	}
+	parseUint16(buf[2:4], &out.MinorVersion)
+	parseUint16(buf[6:8], &out.valueRecordSize)
+	parseUint16(buf[8:10], &valueRecordCount)
+	parseOffset16(buf[10:12], &out.itemVariationStoreOffset)
+	{
+		sz := int(out.valueRecordSize)
+		n := int(valueRecordCount)
+		/* FIXME: check that buf is long enough */
+		out.valueRecords = buf[12 : 12+n*sz]
+		dynSize += n * sz
+		buf = buf[12+n*sz:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+func (tbl *MVARTable) ItemVariationStore(into *ItemVariationStoreTable) bool {
+	// FIXME: check bounds
+	if tbl.itemVariationStoreOffset == 0 {
+		return false
+	}
+	ParseItemVariationStoreTable(tbl.data[tbl.itemVariationStoreOffset:], into)
+	return true
+}
+
+func (tbl *MVARTable) ValueRecord(i int) MetricsValueRecord {
+	sz := int(tbl.valueRecordSize)
+	var out MetricsValueRecord
+	parseMetricsValueRecord(tbl.valueRecords[i*sz:(i+1)*sz:len(tbl.valueRecords)], &out)
+	return out
+}
+
+func (tbl *MVARTable) ValueRecords() iter.Seq2[int, MetricsValueRecord] {
+	return func(yield func(int, MetricsValueRecord) bool) {
+		for i := range tbl.NumValueRecords() {
+			if !yield(i, tbl.ValueRecord(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (tbl *MVARTable) NumValueRecords() int {
+	return len(tbl.valueRecords) / int(tbl.valueRecordSize)
+}
+
+func parseMetricsValueRecord(buf []byte, out *MetricsValueRecord) int {
+	*out = MetricsValueRecord{}
+	origBuf := buf
+	var dynSize int
 
+	/* FIXME return error */
+	if len(buf) < 8 {
+		return dynSize
+	}
+	parseTag(buf[0:4], &out.ValueTag)
+	parseUint16(buf[4:6], &out.DeltaSetOuterIndex)
+	parseUint16(buf[6:8], &out.DeltaSetInnerIndex)
 	_ = buf
 	_ = origBuf
 	return dynSize
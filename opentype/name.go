@@ -46,9 +46,9 @@ const (
 )
 
 var names = [...]string{
-	NameCopyright:                      "copyright(0)",
-	NameFontFamilyName:                 "font family name(1)",
-	NameFontSubfamilyName:              "font subfamily name(2)",
+	NameCopyright:                      "copyright (0)",
+	NameFontFamilyName:                 "font family name (1)",
+	NameFontSubfamilyName:              "font subfamily name (2)",
 	NameFontIdentifier:                 "font identifier (3)",
 	NameFullFontName:                   "full font name (4)",
 	NameVersion:                        "version (5)",
@@ -61,7 +61,7 @@ var names = [...]string{
 	NameDesignerURL:                    "designer URL (12)",
 	NameLicenseDescription:             "license description (13)",
 	NameLicenseURL:                     "license URL (14)",
-	NameTypographicFamilyName:          "typogrpahic family name (16)",
+	NameTypographicFamilyName:          "typographic family name (16)",
 	NameTypographicSubfamilyName:       "typographic subfamily name (17)",
 	NameCompatibleFullName:             "compatible full name (18)",
 	NameSampleText:                     "sample text (19)",
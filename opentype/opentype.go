@@ -120,7 +120,7 @@ func (t Tag) Uint32() uint32 {
 	return binary.BigEndian.Uint32(out[:])
 }
 
-// XXX implement table checksumming
+// XXX verify table checksums when parsing
 
 // XXX required tables: cmap, head, hhea, hmtx, maxp, name, OS/2, post
 
@@ -26,10 +26,18 @@ func (f *File) Raw() *opentype.TableDirectory {
 }
 
 func NewFile(data []byte) (*File, error) {
+	return NewFileIndex(data, 0)
+}
+
+// NewFileIndex is like NewFile but supports font collections, using index to
+// select the font.
+func NewFileIndex(data []byte, index int) (*File, error) {
 	f := File{
 		data: data,
 	}
-	opentype.ParseTableDirectory(f.data, &f.directory)
+	if err := opentype.ParseFont(f.data, index, &f.directory); err != nil {
+		return nil, err
+	}
 
 	if rec, ok := f.directory.FindTable("cmap"); ok {
 		var cmap opentype.CmapTable
@@ -47,6 +55,20 @@ func NewFile(data []byte) (*File, error) {
 	return &f, nil
 }
 
+// CFF returns the font's CFF2 or CFF table, which contain the outlines of
+// fonts that don't use TrueType outlines. It returns an error if the font has
+// neither table.
+func (f *File) CFF() (*opentype.CFF, error) {
+	rec, ok := f.directory.FindTable("CFF2")
+	if !ok {
+		rec, ok = f.directory.FindTable("CFF ")
+	}
+	if !ok {
+		return nil, errors.New("font has no CFF or CFF2 table")
+	}
+	return opentype.ParseCFF(rec.Data())
+}
+
 type FamilyKind int
 
 const (
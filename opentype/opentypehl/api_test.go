@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentypehl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// openFont parses a font from the testdata directory.
+func openFont(t *testing.T, name string) *File {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
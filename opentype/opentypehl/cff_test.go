@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentypehl
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/opentype"
+)
+
+func outline(t *testing.T, cff *opentype.CFF, glyph opentype.GlyphID, coords []float64) curve.BezPath {
+	t.Helper()
+	path, err := cff.Outline(glyph, coords, nil)
+	if err != nil {
+		t.Fatalf("glyph %d: %s", glyph, err)
+	}
+	return path
+}
+
+func TestCFFOutlines(t *testing.T) {
+	cff, err := openFont(t, "CFFTest.otf").CFF()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := cff.NumGlyphs(); n != 5 {
+		t.Fatalf("got %d glyphs, want 5", n)
+	}
+
+	// The outlines as drawn in the font's source file.
+	var notdef, zero, one, q, han curve.BezPath
+	notdef.MoveTo(curve.Pt(50, 0))
+	notdef.LineTo(curve.Pt(450, 0))
+	notdef.LineTo(curve.Pt(450, 533))
+	notdef.LineTo(curve.Pt(50, 533))
+	notdef.ClosePath()
+	notdef.MoveTo(curve.Pt(100, 50))
+	notdef.LineTo(curve.Pt(100, 483))
+	notdef.LineTo(curve.Pt(400, 483))
+	notdef.LineTo(curve.Pt(400, 50))
+	notdef.ClosePath()
+
+	zero.MoveTo(curve.Pt(300, 700))
+	zero.CubicTo(curve.Pt(380, 700), curve.Pt(420, 580), curve.Pt(420, 500))
+	zero.CubicTo(curve.Pt(420, 350), curve.Pt(390, 100), curve.Pt(300, 100))
+	zero.CubicTo(curve.Pt(220, 100), curve.Pt(180, 220), curve.Pt(180, 300))
+	zero.CubicTo(curve.Pt(180, 450), curve.Pt(210, 700), curve.Pt(300, 700))
+	zero.ClosePath()
+	zero.MoveTo(curve.Pt(300, 800))
+	zero.CubicTo(curve.Pt(200, 800), curve.Pt(100, 580), curve.Pt(100, 400))
+	zero.CubicTo(curve.Pt(100, 220), curve.Pt(200, 0), curve.Pt(300, 0))
+	zero.CubicTo(curve.Pt(400, 0), curve.Pt(500, 220), curve.Pt(500, 400))
+	zero.CubicTo(curve.Pt(500, 580), curve.Pt(400, 800), curve.Pt(300, 800))
+	zero.ClosePath()
+
+	one.MoveTo(curve.Pt(100, 0))
+	one.LineTo(curve.Pt(300, 0))
+	one.LineTo(curve.Pt(300, 800))
+	one.LineTo(curve.Pt(100, 800))
+	one.ClosePath()
+
+	q.MoveTo(curve.Pt(657, 237))
+	q.LineTo(curve.Pt(289, 387))
+	q.LineTo(curve.Pt(519, 615))
+	q.ClosePath()
+	q.MoveTo(curve.Pt(792, 169))
+	q.CubicTo(curve.Pt(867, 263), curve.Pt(926, 502), curve.Pt(791, 665))
+	q.CubicTo(curve.Pt(645, 840), curve.Pt(380, 831), curve.Pt(228, 673))
+	q.CubicTo(curve.Pt(71, 509), curve.Pt(110, 231), curve.Pt(242, 93))
+	q.CubicTo(curve.Pt(369, -39), curve.Pt(641, 18), curve.Pt(722, 93))
+	q.LineTo(curve.Pt(802, 3))
+	q.LineTo(curve.Pt(864, 83))
+	q.ClosePath()
+
+	han.MoveTo(curve.Pt(141, 520))
+	for _, p := range [][2]float64{
+		{137, 356}, {245, 400}, {331, 26}, {355, 414}, {463, 434},
+		{453, 620}, {341, 592}, {331, 758}, {243, 752}, {235, 562},
+	} {
+		han.LineTo(curve.Pt(p[0], p[1]))
+	}
+	han.ClosePath()
+
+	for i, want := range []curve.BezPath{notdef, zero, one, q, han} {
+		if got := outline(t, cff, opentype.GlyphID(i), nil); !slices.Equal(got, want) {
+			t.Errorf("glyph %d: got %v, want %v", i, got, want)
+		}
+	}
+
+	if _, err := cff.Outline(5, nil, nil); err == nil {
+		t.Error("no error for a glyph that doesn't exist")
+	}
+}
+
+func TestCFF2Outlines(t *testing.T) {
+	cff, err := openFont(t, "CFF2-VF.otf").CFF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The outer contour of the .notdef glyph is a rectangle whose width
+	// depends on the weight.
+	tests := []struct {
+		coord  float64
+		x0, x1 float64
+	}{
+		{-1, 84, 516},
+		{0, 62, 538},
+		{1, 24, 576},
+	}
+	for _, tt := range tests {
+		box := outline(t, cff, 0, []float64{tt.coord})[:5].ControlBox()
+		if want := curve.NewRectFromPoints(curve.Pt(tt.x0, 0), curve.Pt(tt.x1, 660)); box != want {
+			t.Errorf("coordinate %g: got %v, want %v", tt.coord, box, want)
+		}
+	}
+	if box := outline(t, cff, 0, nil)[:5].ControlBox(); box.X0 != 62 || box.X1 != 538 {
+		t.Errorf("missing coordinates didn't select the default instance, got %v", box)
+	}
+
+	// Between the default instance and the masters, points are interpolated
+	// linearly.
+	for glyph := range opentype.GlyphID(cff.NumGlyphs()) {
+		def := outline(t, cff, glyph, []float64{0})
+		for _, master := range []float64{-1, 1} {
+			end := outline(t, cff, glyph, []float64{master})
+			mid := outline(t, cff, glyph, []float64{master / 2})
+			if len(mid) != len(def) || len(end) != len(def) {
+				t.Fatalf("glyph %d: instances have different numbers of elements", glyph)
+			}
+			for i := range mid {
+				for j, p := range [...]curve.Point{mid[i].P0, mid[i].P1, mid[i].P2} {
+					a := [...]curve.Point{def[i].P0, def[i].P1, def[i].P2}[j]
+					b := [...]curve.Point{end[i].P0, end[i].P1, end[i].P2}[j]
+					want := curve.Pt((a.X+b.X)/2, (a.Y+b.Y)/2)
+					if math.Abs(p.X-want.X) > 0.01 || math.Abs(p.Y-want.Y) > 0.01 {
+						t.Errorf("glyph %d at %g: element %d is at %v, want %v", glyph, master/2, i, p, want)
+					}
+				}
+			}
+		}
+	}
+}
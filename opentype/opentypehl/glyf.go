@@ -0,0 +1,266 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentypehl
+
+import (
+	"errors"
+	"fmt"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/opentype"
+)
+
+// maxComponentDepth limits the nesting of composite glyphs.
+const maxComponentDepth = 16
+
+// Glyf provides access to a font's TrueType outlines.
+type Glyf struct {
+	glyf    opentype.GlyfTable
+	short   opentype.ShortLocaTable
+	long    opentype.LongLocaTable
+	isLong  bool
+	gvar    opentype.GvarTable
+	hasGvar bool
+}
+
+// Glyf returns the font's TrueType outlines. It returns false if the font
+// doesn't have glyf and loca tables.
+func (f *File) Glyf() (*Glyf, bool) {
+	glyfRec, ok := f.directory.FindTable("glyf")
+	if !ok {
+		return nil, false
+	}
+	locaRec, ok := f.directory.FindTable("loca")
+	if !ok {
+		return nil, false
+	}
+	headRec, ok := f.directory.FindTable("head")
+	if !ok {
+		return nil, false
+	}
+	var head opentype.HeadTable
+	opentype.ParseHeadTable(headRec.Data(), &head)
+
+	var g Glyf
+	opentype.ParseGlyfTable(glyfRec.Data(), &g.glyf)
+	if head.IndexToLocFormat == 1 {
+		g.isLong = true
+		opentype.ParseLongLocaTable(locaRec.Data(), &g.long)
+	} else {
+		opentype.ParseShortLocaTable(locaRec.Data(), &g.short)
+	}
+	if rec, ok := f.directory.FindTable("gvar"); ok {
+		opentype.ParseGvarTable(rec.Data(), &g.gvar)
+		g.hasGvar = true
+	}
+	return &g, true
+}
+
+// glyph returns the glyph's data. It returns false for glyphs without an
+// outline.
+func (g *Glyf) glyph(glyph opentype.GlyphID) (opentype.GlyphTable, bool, error) {
+	start, end, err := g.bounds(glyph)
+	if err != nil || start == end {
+		return opentype.GlyphTable{}, false, err
+	}
+	return g.glyf.Glyph(start), true, nil
+}
+
+// data returns the glyph's raw data, which is empty for glyphs without an
+// outline.
+func (g *Glyf) data(glyph opentype.GlyphID) ([]byte, error) {
+	start, end, err := g.bounds(glyph)
+	if err != nil {
+		return nil, err
+	}
+	return g.glyf.Data[start:end], nil
+}
+
+// bounds returns the range of the glyf table that stores the glyph.
+func (g *Glyf) bounds(glyph opentype.GlyphID) (start, end uint32, err error) {
+	if g.isLong {
+		if int(glyph) < 0 || int(glyph)+1 >= g.long.NumOffsets() {
+			return 0, 0, fmt.Errorf("invalid glyph %d", glyph)
+		}
+		start, end = g.long.Offset(int(glyph)), g.long.Offset(int(glyph)+1)
+	} else {
+		if int(glyph) < 0 || int(glyph)+1 >= g.short.NumOffsets() {
+			return 0, 0, fmt.Errorf("invalid glyph %d", glyph)
+		}
+		start, end = uint32(g.short.Offset(int(glyph)))*2, uint32(g.short.Offset(int(glyph)+1))*2
+	}
+	if start >= end {
+		return 0, 0, nil
+	}
+	if int(end) > len(g.glyf.Data) {
+		return 0, 0, fmt.Errorf("glyph %d is out of bounds", glyph)
+	}
+	return start, end, nil
+}
+
+// Outline appends the outline of a glyph to path and returns the result.
+// Coordinates are in font units, with the y axis pointing up. Coords are the
+// normalized variation coordinates of the instance, as returned by
+// [File.NormalizeCoordinates]. Missing coordinates are treated as zero, which
+// selects the default instance.
+//
+// Hints are ignored.
+func (g *Glyf) Outline(glyph opentype.GlyphID, coords []float64, path curve.BezPath) (curve.BezPath, error) {
+	pts, endPts, err := g.points(glyph, coords, nil, nil, 0)
+	if err != nil {
+		return path, err
+	}
+	if g.hasGvar && len(coords) > 0 && len(pts) > 0 {
+		// The glyph's origin is at the left phantom point, which may have
+		// moved.
+		//
+		// OPT(dh): this decodes the glyph's variation data a second time.
+		phantoms, err := g.phantomDeltas(glyph, coords)
+		if err != nil {
+			return path, err
+		}
+		if dx := phantoms[0].X; dx != 0 {
+			for i := range pts {
+				pts[i].X -= dx
+			}
+		}
+	}
+	return opentype.AppendOutline(path, pts, endPts), nil
+}
+
+// points appends the points and contour end points of a glyph, with
+// variations applied, to pts and endPts. Composite glyphs are flattened.
+func (g *Glyf) points(glyph opentype.GlyphID, coords []float64, pts []opentype.GlyphPoint, endPts []int, depth int) ([]opentype.GlyphPoint, []int, error) {
+	if depth > maxComponentDepth {
+		return pts, endPts, errors.New("composite glyphs are nested too deeply")
+	}
+	gl, ok, err := g.glyph(glyph)
+	if err != nil || !ok {
+		return pts, endPts, err
+	}
+	vary := g.hasGvar && len(coords) > 0
+
+	switch gl.Kind {
+	case opentype.SimpleGlyphTableKind:
+		base := len(pts)
+		pts, err = gl.Simple.Points(pts)
+		if err != nil {
+			return pts, endPts, err
+		}
+		firstEnd := len(endPts)
+		for _, end := range gl.Simple.EndPtsOfContours() {
+			endPts = append(endPts, base+int(end))
+		}
+		if vary {
+			local := make([]int, len(endPts)-firstEnd)
+			for i, end := range endPts[firstEnd:] {
+				local[i] = end - base
+			}
+			pts = append(pts, make([]opentype.GlyphPoint, 4)...)
+			err = g.gvar.ApplyDeltas(glyph, coords, pts[base:], local)
+			pts = pts[:len(pts)-4]
+		}
+		return pts, endPts, err
+
+	case opentype.CompositeGlyphTableKind:
+		comps, err := gl.Composite.Components(nil)
+		if err != nil {
+			return pts, endPts, err
+		}
+		// The component offsets are subject to variation, too.
+		offsets := make([]opentype.GlyphPoint, len(comps)+4)
+		for i, c := range comps {
+			if c.Flags&opentype.ComponentArgsAreXYValues != 0 {
+				offsets[i] = opentype.GlyphPoint{X: float64(c.Arg1), Y: float64(c.Arg2)}
+			}
+		}
+		if vary {
+			if err := g.gvar.ApplyDeltas(glyph, coords, offsets, nil); err != nil {
+				return pts, endPts, err
+			}
+		}
+
+		for i, c := range comps {
+			base := len(pts)
+			pts, endPts, err = g.points(c.GlyphIndex, coords, pts, endPts, depth+1)
+			if err != nil {
+				return pts, endPts, err
+			}
+			child := pts[base:]
+			for j, p := range child {
+				child[j].X = c.XX*p.X + c.YX*p.Y
+				child[j].Y = c.XY*p.X + c.YY*p.Y
+			}
+
+			var dx, dy float64
+			if c.Flags&opentype.ComponentArgsAreXYValues != 0 {
+				dx, dy = offsets[i].X, offsets[i].Y
+				if c.Flags&opentype.ComponentScaledComponentOffset != 0 {
+					dx, dy = c.XX*dx+c.YX*dy, c.XY*dx+c.YY*dy
+				}
+			} else {
+				// Align a point of the composite glyph so far with a point
+				// of the component.
+				if c.Arg1 >= base || c.Arg2 >= len(child) {
+					return pts, endPts, fmt.Errorf("invalid anchor points in glyph %d", glyph)
+				}
+				dx = pts[c.Arg1].X - child[c.Arg2].X
+				dy = pts[c.Arg1].Y - child[c.Arg2].Y
+			}
+			for j := range child {
+				child[j].X += dx
+				child[j].Y += dy
+			}
+		}
+		return pts, endPts, nil
+
+	default:
+		panic(fmt.Sprintf("unhandled glyph kind %d", gl.Kind))
+	}
+}
+
+// advanceWidthDelta returns the delta of a glyph's advance width, as
+// described by the horizontal phantom points.
+func (g *Glyf) advanceWidthDelta(glyph opentype.GlyphID, coords []float64) (float64, error) {
+	phantoms, err := g.phantomDeltas(glyph, coords)
+	return phantoms[1].X - phantoms[0].X, err
+}
+
+// phantomDeltas returns the deltas of the glyph's four phantom points.
+//
+// XXX the deltas of composite glyphs with USE_MY_METRICS should be those of
+// the component.
+func (g *Glyf) phantomDeltas(glyph opentype.GlyphID, coords []float64) ([4]opentype.GlyphPoint, error) {
+	var out [4]opentype.GlyphPoint
+	if !g.hasGvar {
+		return out, nil
+	}
+	gl, ok, err := g.glyph(glyph)
+	if err != nil {
+		return out, err
+	}
+	var n int
+	if ok {
+		switch gl.Kind {
+		case opentype.SimpleGlyphTableKind:
+			n = gl.Simple.NumPoints()
+		case opentype.CompositeGlyphTableKind:
+			comps, err := gl.Composite.Components(nil)
+			if err != nil {
+				return out, err
+			}
+			n = len(comps)
+		}
+	}
+	// Only the deltas matter, so the points can be zero. Without contour
+	// end points, no deltas get inferred, which doesn't affect phantom
+	// points.
+	pts := make([]opentype.GlyphPoint, n+4)
+	if err := g.gvar.ApplyDeltas(glyph, coords, pts, nil); err != nil {
+		return out, err
+	}
+	copy(out[:], pts[n:])
+	return out, nil
+}
@@ -0,0 +1,365 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentypehl
+
+import (
+	"iter"
+	"slices"
+
+	"honnef.co/go/gutter/opentype"
+)
+
+// GPOS provides access to a font's glyph positioning data.
+//
+// GPOS doesn't implement shaping. It answers questions about individual
+// lookups, such as the kerning of a pair of glyphs or the anchors used to
+// attach a mark, leaving the application of lookups to glyph runs, including
+// the handling of lookup flags and contextual lookups, to the caller.
+type GPOS struct {
+	raw      opentype.GPOSTable
+	scripts  opentype.ScriptListTable
+	features opentype.FeatureListTable
+	lookups  opentype.LookupListTable
+}
+
+// GPOS returns the font's glyph positioning data. It returns false if the font
+// doesn't have a GPOS table.
+func (f *File) GPOS() (*GPOS, bool) {
+	rec, ok := f.directory.FindTable("GPOS")
+	if !ok {
+		return nil, false
+	}
+	var g GPOS
+	opentype.ParseGPOSTable(rec.Data(), &g.raw)
+	g.raw.ScriptList(&g.scripts)
+	g.raw.FeatureList(&g.features)
+	g.raw.LookupList(&g.lookups)
+	return &g, true
+}
+
+func (g *GPOS) Raw() *opentype.GPOSTable {
+	return &g.raw
+}
+
+// Lookups returns the indices of the lookups that implement the features for
+// a script and language system, in the order in which they have to be
+// applied. The language system's required feature, if any, is always
+// included. If the font doesn't support the script, the default script is
+// used, and if it doesn't support the language system, the script's default
+// language system is used.
+func (g *GPOS) Lookups(script, langSys opentype.Tag, features ...opentype.Tag) []int {
+	var sc opentype.ScriptTable
+	if !g.scripts.Script(script, &sc) && !g.scripts.Script("DFLT", &sc) {
+		return nil
+	}
+	var ls opentype.LangSysTable
+	if !sc.LangSys(langSys, &ls) {
+		return nil
+	}
+
+	var out []int
+	add := func(idx uint16, required bool) {
+		if int(idx) >= g.features.NumFeatureRecords() {
+			return
+		}
+		rec := g.features.FeatureRecord(int(idx))
+		if !required && !slices.Contains(features, rec.FeatureTag) {
+			return
+		}
+		var feat opentype.FeatureTable
+		if !rec.Feature(&feat) {
+			return
+		}
+		for _, lookup := range feat.LookupListIndices() {
+			out = append(out, int(lookup))
+		}
+	}
+	if ls.RequiredFeatureIndex != 0xFFFF {
+		add(ls.RequiredFeatureIndex, true)
+	}
+	for _, idx := range ls.FeatureIndices() {
+		add(idx, false)
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// subtables returns the subtables of a lookup, together with their lookup
+// types, resolving extension subtables.
+func (g *GPOS) subtables(lookup int) iter.Seq2[uint16, []byte] {
+	return func(yield func(uint16, []byte) bool) {
+		var tbl opentype.LookupTable
+		if !g.lookups.Lookup(lookup, &tbl) {
+			return
+		}
+		for i := range tbl.NumSubtableOffsets() {
+			typ, data := tbl.LookupType, tbl.Subtable(i)
+			if typ == opentype.GPOSLookupTypeExtension {
+				var ext opentype.ExtensionPosTable
+				opentype.ParseExtensionPosTable(data, &ext)
+				if ext.PosFormat != 1 {
+					continue
+				}
+				typ, data = ext.Format1.ExtensionLookupType, ext.Format1.Extension()
+			}
+			if !yield(typ, data) {
+				return
+			}
+		}
+	}
+}
+
+// Adjustment is an adjustment of a glyph's position and advance, in font
+// units.
+type Adjustment struct {
+	XPlacement int
+	YPlacement int
+	XAdvance   int
+	YAdvance   int
+}
+
+func (adj *Adjustment) add(rec *opentype.ValueRecord) {
+	// XXX apply device and variation index tables
+	adj.XPlacement += int(rec.XPlacement)
+	adj.YPlacement += int(rec.YPlacement)
+	adj.XAdvance += int(rec.XAdvance)
+	adj.YAdvance += int(rec.YAdvance)
+}
+
+// PairAdjustment returns the adjustments of the glyphs first and second when
+// they're adjacent, as specified by the pair positioning lookups among
+// lookups. Lookups of other types are ignored. It returns false if none of
+// the lookups apply to the pair.
+func (g *GPOS) PairAdjustment(lookups []int, first, second opentype.GlyphID) (adj1, adj2 Adjustment, ok bool) {
+	for _, lookup := range lookups {
+		for typ, data := range g.subtables(lookup) {
+			if typ != opentype.GPOSLookupTypePair {
+				continue
+			}
+			v1, v2, found := pairAdjustment(data, first, second)
+			if found {
+				adj1.add(&v1)
+				adj2.add(&v2)
+				ok = true
+				// Only the first matching subtable of a lookup applies.
+				break
+			}
+		}
+	}
+	return adj1, adj2, ok
+}
+
+func pairAdjustment(data []byte, first, second opentype.GlyphID) (v1, v2 opentype.ValueRecord, ok bool) {
+	var tbl opentype.PairPosTable
+	opentype.ParsePairPosTable(data, &tbl)
+	switch tbl.PosFormat {
+	case 1:
+		f := &tbl.Format1
+		var cov opentype.CoverageTable
+		if !f.Coverage(&cov) {
+			return v1, v2, false
+		}
+		idx, ok := cov.Index(first)
+		if !ok {
+			return v1, v2, false
+		}
+		var set opentype.PairSetTable
+		if !f.PairSet(idx, &set) {
+			return v1, v2, false
+		}
+		return set.Find(second, f.ValueFormat1, f.ValueFormat2)
+	case 2:
+		f := &tbl.Format2
+		var cov opentype.CoverageTable
+		if !f.Coverage(&cov) {
+			return v1, v2, false
+		}
+		if _, ok := cov.Index(first); !ok {
+			return v1, v2, false
+		}
+		var cd1, cd2 opentype.ClassDefTable
+		var class1, class2 uint16
+		if f.ClassDef1(&cd1) {
+			class1 = cd1.Class(first)
+		}
+		if f.ClassDef2(&cd2) {
+			class2 = cd2.Class(second)
+		}
+		return f.ValueRecords(class1, class2)
+	default:
+		return v1, v2, false
+	}
+}
+
+// Anchor is an attachment point, in font units.
+type Anchor struct {
+	X int
+	Y int
+}
+
+func anchor(tbl *opentype.AnchorTable) Anchor {
+	// XXX support contour points and device and variation index tables
+	x, y := tbl.Coordinates()
+	return Anchor{X: int(x), Y: int(y)}
+}
+
+// markRecord returns the class and anchor of a mark.
+func markRecord(arr *opentype.MarkArrayTable, idx int) (uint16, Anchor, bool) {
+	if idx >= arr.NumMarkRecords() {
+		return 0, Anchor{}, false
+	}
+	rec := arr.MarkRecord(idx)
+	var tbl opentype.AnchorTable
+	if !rec.MarkAnchor(&tbl) {
+		return 0, Anchor{}, false
+	}
+	return rec.MarkClass, anchor(&tbl), true
+}
+
+// MarkAnchors returns the anchors that attach mark to base, as specified by
+// the first mark-to-base or mark-to-mark positioning lookup among lookups that
+// applies to the pair of glyphs. To attach the mark, it has to be positioned
+// such that markAnchor coincides with baseAnchor.
+func (g *GPOS) MarkAnchors(lookups []int, base, mark opentype.GlyphID) (baseAnchor, markAnchor Anchor, ok bool) {
+	for _, lookup := range lookups {
+		for typ, data := range g.subtables(lookup) {
+			switch typ {
+			case opentype.GPOSLookupTypeMarkToBase:
+				baseAnchor, markAnchor, ok = markToBase(data, base, mark)
+			case opentype.GPOSLookupTypeMarkToMark:
+				baseAnchor, markAnchor, ok = markToMark(data, base, mark)
+			}
+			if ok {
+				return baseAnchor, markAnchor, true
+			}
+		}
+	}
+	return Anchor{}, Anchor{}, false
+}
+
+func markToBase(data []byte, base, mark opentype.GlyphID) (baseAnchor, markAnchor Anchor, ok bool) {
+	var tbl opentype.MarkBasePosTable
+	opentype.ParseMarkBasePosTable(data, &tbl)
+	if tbl.PosFormat != 1 {
+		return Anchor{}, Anchor{}, false
+	}
+	f := &tbl.Format1
+
+	var markCov, baseCov opentype.CoverageTable
+	if !f.MarkCoverage(&markCov) || !f.BaseCoverage(&baseCov) {
+		return Anchor{}, Anchor{}, false
+	}
+	markIdx, ok1 := markCov.Index(mark)
+	baseIdx, ok2 := baseCov.Index(base)
+	if !ok1 || !ok2 {
+		return Anchor{}, Anchor{}, false
+	}
+
+	var marks opentype.MarkArrayTable
+	var bases opentype.BaseArrayTable
+	if !f.MarkArray(&marks) || !f.BaseArray(&bases) {
+		return Anchor{}, Anchor{}, false
+	}
+	class, markAnchor, ok := markRecord(&marks, markIdx)
+	if !ok {
+		return Anchor{}, Anchor{}, false
+	}
+	var tblAnchor opentype.AnchorTable
+	if !bases.BaseAnchor(baseIdx, class, f.MarkClassCount, &tblAnchor) {
+		return Anchor{}, Anchor{}, false
+	}
+	return anchor(&tblAnchor), markAnchor, true
+}
+
+func markToMark(data []byte, base, mark opentype.GlyphID) (baseAnchor, markAnchor Anchor, ok bool) {
+	var tbl opentype.MarkMarkPosTable
+	opentype.ParseMarkMarkPosTable(data, &tbl)
+	if tbl.PosFormat != 1 {
+		return Anchor{}, Anchor{}, false
+	}
+	f := &tbl.Format1
+
+	// Mark1 is the attaching mark, mark2 the mark being attached to.
+	var mark1Cov, mark2Cov opentype.CoverageTable
+	if !f.Mark1Coverage(&mark1Cov) || !f.Mark2Coverage(&mark2Cov) {
+		return Anchor{}, Anchor{}, false
+	}
+	mark1Idx, ok1 := mark1Cov.Index(mark)
+	mark2Idx, ok2 := mark2Cov.Index(base)
+	if !ok1 || !ok2 {
+		return Anchor{}, Anchor{}, false
+	}
+
+	var mark1s opentype.MarkArrayTable
+	var mark2s opentype.Mark2ArrayTable
+	if !f.Mark1Array(&mark1s) || !f.Mark2Array(&mark2s) {
+		return Anchor{}, Anchor{}, false
+	}
+	class, markAnchor, ok := markRecord(&mark1s, mark1Idx)
+	if !ok {
+		return Anchor{}, Anchor{}, false
+	}
+	var tblAnchor opentype.AnchorTable
+	if !mark2s.Mark2Anchor(mark2Idx, class, f.MarkClassCount, &tblAnchor) {
+		return Anchor{}, Anchor{}, false
+	}
+	return anchor(&tblAnchor), markAnchor, true
+}
+
+// LigatureMarkAnchors is like MarkAnchors but attaches mark to a component of
+// a ligature, as specified by mark-to-ligature positioning lookups. Components
+// are numbered from zero, in logical order.
+func (g *GPOS) LigatureMarkAnchors(lookups []int, ligature opentype.GlyphID, component int, mark opentype.GlyphID) (ligatureAnchor, markAnchor Anchor, ok bool) {
+	for _, lookup := range lookups {
+		for typ, data := range g.subtables(lookup) {
+			if typ != opentype.GPOSLookupTypeMarkToLigature {
+				continue
+			}
+			ligatureAnchor, markAnchor, ok = markToLigature(data, ligature, component, mark)
+			if ok {
+				return ligatureAnchor, markAnchor, true
+			}
+		}
+	}
+	return Anchor{}, Anchor{}, false
+}
+
+func markToLigature(data []byte, ligature opentype.GlyphID, component int, mark opentype.GlyphID) (ligatureAnchor, markAnchor Anchor, ok bool) {
+	var tbl opentype.MarkLigPosTable
+	opentype.ParseMarkLigPosTable(data, &tbl)
+	if tbl.PosFormat != 1 {
+		return Anchor{}, Anchor{}, false
+	}
+	f := &tbl.Format1
+
+	var markCov, ligCov opentype.CoverageTable
+	if !f.MarkCoverage(&markCov) || !f.LigatureCoverage(&ligCov) {
+		return Anchor{}, Anchor{}, false
+	}
+	markIdx, ok1 := markCov.Index(mark)
+	ligIdx, ok2 := ligCov.Index(ligature)
+	if !ok1 || !ok2 {
+		return Anchor{}, Anchor{}, false
+	}
+
+	var marks opentype.MarkArrayTable
+	var ligs opentype.LigatureArrayTable
+	if !f.MarkArray(&marks) || !f.LigatureArray(&ligs) {
+		return Anchor{}, Anchor{}, false
+	}
+	class, markAnchor, ok := markRecord(&marks, markIdx)
+	if !ok {
+		return Anchor{}, Anchor{}, false
+	}
+	var attach opentype.LigatureAttachTable
+	if !ligs.LigatureAttach(ligIdx, &attach) {
+		return Anchor{}, Anchor{}, false
+	}
+	var tblAnchor opentype.AnchorTable
+	if !attach.ComponentAnchor(component, class, f.MarkClassCount, &tblAnchor) {
+		return Anchor{}, Anchor{}, false
+	}
+	return anchor(&tblAnchor), markAnchor, true
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentypehl
+
+import (
+	"testing"
+
+	"honnef.co/go/gutter/opentype"
+)
+
+// The fonts are from the Adobe OpenType Test Suite. The expected values are
+// derived from the positions in the suite's test cases, which apply the
+// fonts' "test" feature. Their glyphs have advances of 1500 units.
+
+func gposLookups(t *testing.T, name string) (*GPOS, []int) {
+	t.Helper()
+	g, ok := openFont(t, name).GPOS()
+	if !ok {
+		t.Fatalf("%s has no GPOS table", name)
+	}
+	lookups := g.Lookups("latn", "dflt", "test")
+	if len(lookups) == 0 {
+		t.Fatalf("%s has no lookups for the test feature", name)
+	}
+	return g, lookups
+}
+
+func TestPairAdjustment(t *testing.T) {
+	g, lookups := gposLookups(t, "gpos2_1_simple_f1.otf")
+
+	// In the sequence 17 18 19, glyph 18 is drawn at 1300 and glyph 19 at
+	// (3000, -100).
+	adj1, adj2, ok := g.PairAdjustment(lookups, 18, 19)
+	if !ok {
+		t.Fatal("no adjustment for the pair 18 19")
+	}
+	if want := (Adjustment{XPlacement: -200}); adj1 != want {
+		t.Errorf("got first adjustment %+v, want %+v", adj1, want)
+	}
+	if want := (Adjustment{YPlacement: -100}); adj2 != want {
+		t.Errorf("got second adjustment %+v, want %+v", adj2, want)
+	}
+
+	for _, pair := range [][2]opentype.GlyphID{{17, 18}, {18, 20}, {19, 18}} {
+		if _, _, ok := g.PairAdjustment(lookups, pair[0], pair[1]); ok {
+			t.Errorf("unexpected adjustment for the pair %d %d", pair[0], pair[1])
+		}
+	}
+	if _, _, ok := g.PairAdjustment(nil, 18, 19); ok {
+		t.Error("adjustment without any lookups")
+	}
+}
+
+func TestMarkAnchors(t *testing.T) {
+	g, lookups := gposLookups(t, "gpos4_simple_1.otf")
+
+	// Glyph 19 is a mark that is drawn at (1400, -80) when it follows glyph
+	// 18 at 1500.
+	base, mark, ok := g.MarkAnchors(lookups, 18, 19)
+	if !ok {
+		t.Fatal("mark 19 doesn't attach to base 18")
+	}
+	if dx, dy := base.X-mark.X, base.Y-mark.Y; dx != -100 || dy != -80 {
+		t.Errorf("mark is offset by (%d, %d), want (-100, -80)", dx, dy)
+	}
+
+	for _, base := range []opentype.GlyphID{17, 25} {
+		if _, _, ok := g.MarkAnchors(lookups, base, 19); ok {
+			t.Errorf("mark 19 attaches to base %d", base)
+		}
+	}
+}
+
+func TestLigatureMarkAnchors(t *testing.T) {
+	g, lookups := gposLookups(t, "gpos5_font1.otf")
+
+	// Glyph 18 is a ligature of two components. Glyph 19 is drawn at
+	// (1400, -80) when attached to the first component, and at
+	// (1401, -79) when attached to the second.
+	tests := []struct {
+		component int
+		dx, dy    int
+	}{
+		{0, -100, -80},
+		{1, -99, -79},
+	}
+	for _, tt := range tests {
+		lig, mark, ok := g.LigatureMarkAnchors(lookups, 18, tt.component, 19)
+		if !ok {
+			t.Errorf("mark doesn't attach to component %d", tt.component)
+			continue
+		}
+		if dx, dy := lig.X-mark.X, lig.Y-mark.Y; dx != tt.dx || dy != tt.dy {
+			t.Errorf("component %d: mark is offset by (%d, %d), want (%d, %d)", tt.component, dx, dy, tt.dx, tt.dy)
+		}
+	}
+	if _, _, ok := g.LigatureMarkAnchors(lookups, 18, 2, 19); ok {
+		t.Error("mark attaches to a component that doesn't exist")
+	}
+	if _, _, ok := g.MarkAnchors(lookups, 18, 19); ok {
+		t.Error("mark-to-ligature lookup was used for attaching to a base")
+	}
+}
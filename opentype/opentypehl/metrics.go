@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentypehl
+
+import (
+	"honnef.co/go/gutter/opentype"
+)
+
+// Metrics are font-wide metrics. Distances are measured from the baseline,
+// with the y axis pointing up, so that descenders are usually negative.
+type Metrics struct {
+	// UnitsPerEm is the size of the em square. For metrics returned by
+	// [File.Metrics], it is in font units; for scaled metrics, it is the
+	// font size.
+	UnitsPerEm float64
+
+	Ascender  float64
+	Descender float64
+	LineGap   float64
+
+	// The position of the top of the underline and its thickness.
+	UnderlinePosition  float64
+	UnderlineThickness float64
+	// The position of the top of the strikeout stroke and its thickness.
+	StrikeoutPosition  float64
+	StrikeoutThickness float64
+
+	XHeight   float64
+	CapHeight float64
+
+	// ItalicAngle is the angle of the font's stems in degrees
+	// counter-clockwise from the vertical. It doesn't get scaled.
+	ItalicAngle float64
+}
+
+// LineHeight returns the distance between consecutive baselines.
+func (m Metrics) LineHeight() float64 {
+	return m.Ascender - m.Descender + m.LineGap
+}
+
+// Scale returns the metrics for a font size, in the same unit as size.
+func (m Metrics) Scale(size float64) Metrics {
+	if m.UnitsPerEm == 0 {
+		return m
+	}
+	s := size / m.UnitsPerEm
+	return Metrics{
+		UnitsPerEm:         size,
+		Ascender:           m.Ascender * s,
+		Descender:          m.Descender * s,
+		LineGap:            m.LineGap * s,
+		UnderlinePosition:  m.UnderlinePosition * s,
+		UnderlineThickness: m.UnderlineThickness * s,
+		StrikeoutPosition:  m.StrikeoutPosition * s,
+		StrikeoutThickness: m.StrikeoutThickness * s,
+		XHeight:            m.XHeight * s,
+		CapHeight:          m.CapHeight * s,
+		ItalicAngle:        m.ItalicAngle,
+	}
+}
+
+// Metrics returns the font-wide metrics in font units. Coords are the
+// normalized variation coordinates of the instance, as returned by
+// [File.NormalizeCoordinates]; nil selects the default instance.
+//
+// The ascender, descender and line gap come from the OS/2 table's typographic
+// metrics if the font sets USE_TYPO_METRICS, and from the hhea table
+// otherwise. Fonts that have neither fall back to the OS/2 table's Windows
+// metrics.
+func (f *File) Metrics(coords []float64) Metrics {
+	var m Metrics
+	if rec, ok := f.directory.FindTable("head"); ok {
+		var head opentype.HeadTable
+		opentype.ParseHeadTable(rec.Data(), &head)
+		m.UnitsPerEm = float64(head.UnitsPerEm)
+	}
+
+	var os2 opentype.OS2Table
+	hasOS2 := false
+	if rec, ok := f.directory.FindTable("OS/2"); ok {
+		opentype.ParseOS2Table(rec.Data(), &os2)
+		hasOS2 = true
+	}
+	var hhea opentype.HheaTable
+	hasHhea := false
+	if rec, ok := f.directory.FindTable("hhea"); ok {
+		opentype.ParseHheaTable(rec.Data(), &hhea)
+		hasHhea = hhea.Ascender != 0 || hhea.Descender != 0
+	}
+
+	useTypo := hasOS2 &&
+		(os2.FsSelection&opentype.FsUseTypoMetrics != 0 ||
+			!hasHhea && (os2.STypoAscender != 0 || os2.STypoDescender != 0))
+
+	delta := func(tag opentype.Tag) float64 { return f.MetricDelta(tag, coords) }
+	switch {
+	case useTypo:
+		m.Ascender = float64(os2.STypoAscender) + delta("hasc")
+		m.Descender = float64(os2.STypoDescender) + delta("hdsc")
+		m.LineGap = float64(os2.STypoLineGap) + delta("hlgp")
+	case hasHhea:
+		m.Ascender = float64(hhea.Ascender) + delta("hasc")
+		m.Descender = float64(hhea.Descender) + delta("hdsc")
+		m.LineGap = float64(hhea.LineGap) + delta("hlgp")
+	case hasOS2:
+		m.Ascender = float64(os2.UsWinAscent) + delta("hcla")
+		m.Descender = -float64(os2.UsWinDescent) - delta("hcld")
+	}
+
+	if hasOS2 {
+		m.StrikeoutPosition = float64(os2.YStrikeoutPosition) + delta("stro")
+		m.StrikeoutThickness = float64(os2.YStrikeoutSize) + delta("strs")
+		m.XHeight = float64(os2.SxHeight) + delta("xhgt")
+		m.CapHeight = float64(os2.SCapHeight) + delta("cpht")
+	}
+	if rec, ok := f.directory.FindTable("post"); ok {
+		var post opentype.PostTable
+		opentype.ParsePostTable(rec.Data(), &post)
+		m.UnderlinePosition = float64(post.UnderlinePosition) + delta("undo")
+		m.UnderlineThickness = float64(post.UnderlineThickness) + delta("unds")
+		m.ItalicAngle = post.ItalicAngle.Float()
+	}
+	return m
+}
+
+// AdvanceWidth returns the advance width of a glyph in font units, for the
+// instance selected by coords.
+func (f *File) AdvanceWidth(glyph opentype.GlyphID, coords []float64) (float64, error) {
+	hheaRec, ok := f.directory.FindTable("hhea")
+	if !ok {
+		return 0, nil
+	}
+	hmtxRec, ok := f.directory.FindTable("hmtx")
+	if !ok {
+		return 0, nil
+	}
+	var hhea opentype.HheaTable
+	opentype.ParseHheaTable(hheaRec.Data(), &hhea)
+	var hmtx opentype.HmtxTable
+	opentype.ParseHmtxTable(hmtxRec.Data(), &hmtx)
+	adv := float64(hmtx.Metric(glyph, hhea.NumberOfHMetrics).AdvanceWidth)
+	d, err := f.AdvanceWidthDelta(glyph, coords)
+	return adv + d, err
+}
+
+// GlyphName returns the PostScript name of a glyph, as stored in the post
+// table. It returns false if the font doesn't name its glyphs.
+func (f *File) GlyphName(glyph opentype.GlyphID) (string, bool) {
+	rec, ok := f.directory.FindTable("post")
+	if !ok {
+		return "", false
+	}
+	var post opentype.PostTable
+	opentype.ParsePostTable(rec.Data(), &post)
+	return post.GlyphName(glyph)
+}
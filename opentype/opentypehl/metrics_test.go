@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentypehl
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"honnef.co/go/gutter/opentype"
+)
+
+// The expected values in these tests match those reported by
+// golang.org/x/image/font/sfnt for the same fonts.
+
+func parseFont(t *testing.T, data []byte) *File {
+	t.Helper()
+	f, err := NewFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestAdvanceWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []byte
+		glyph opentype.GlyphID
+		want  float64
+	}{
+		{"regular", goregular.TTF, 0, 1536},
+		{"regular", goregular.TTF, 1, 0},
+		{"regular", goregular.TTF, 3, 569},
+		{"regular", goregular.TTF, 43, 1479},
+		{"regular", goregular.TTF, 100, 1139},
+		// Go Regular stores 711 long metrics for its 712 glyphs, so the last
+		// glyph uses the advance of the last long metric.
+		{"regular", goregular.TTF, 711, 1139},
+		{"italic", goitalic.TTF, 0, 1558},
+		{"italic", goitalic.TTF, 43, 1501},
+		{"italic", goitalic.TTF, 711, 1161},
+		// Go Mono stores a single long metric that applies to all glyphs.
+		{"mono", gomono.TTF, 0, 1229},
+		{"mono", gomono.TTF, 1, 1229},
+		{"mono", gomono.TTF, 43, 1229},
+		{"mono", gomono.TTF, 711, 1229},
+	}
+	for _, tt := range tests {
+		got, err := parseFont(t, tt.data).AdvanceWidth(tt.glyph, nil)
+		if err != nil {
+			t.Errorf("%s: glyph %d: %s", tt.name, tt.glyph, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: glyph %d has advance %g, want %g", tt.name, tt.glyph, got, tt.want)
+		}
+	}
+}
+
+func TestGlyphName(t *testing.T) {
+	tests := []struct {
+		glyph opentype.GlyphID
+		want  string
+	}{
+		// Names from the standard Macintosh glyph order.
+		{0, ".notdef"},
+		{3, "space"},
+		{10, "quotesingle"},
+		{43, "H"},
+		{100, "cent"},
+		// Names stored in the table itself.
+		{1, "uni0000"},
+		{2, "uni000D"},
+		{711, "zero.empty"},
+	}
+	for _, data := range [][]byte{goregular.TTF, gomono.TTF} {
+		f := parseFont(t, data)
+		for _, tt := range tests {
+			if got, ok := f.GlyphName(tt.glyph); !ok || got != tt.want {
+				t.Errorf("glyph %d has name (%q, %t), want (%q, true)", tt.glyph, got, ok, tt.want)
+			}
+		}
+		if got, ok := f.GlyphName(712); ok {
+			t.Errorf("glyph out of range has name %q", got)
+		}
+	}
+
+	// Version 3.0 post tables don't contain glyph names.
+	if got, ok := openFont(t, "CFFTest.otf").GlyphName(1); ok {
+		t.Errorf("got name %q from version 3.0 post table", got)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want Metrics
+	}{
+		{"regular", goregular.TTF, Metrics{
+			UnitsPerEm:         2048,
+			Ascender:           1935,
+			Descender:          -432,
+			UnderlinePosition:  -275,
+			UnderlineThickness: 50,
+			StrikeoutPosition:  512,
+			StrikeoutThickness: 102,
+			XHeight:            1086,
+			CapHeight:          1480,
+		}},
+		{"italic", goitalic.TTF, Metrics{
+			UnitsPerEm:         2048,
+			Ascender:           1935,
+			Descender:          -432,
+			UnderlinePosition:  -275,
+			UnderlineThickness: 50,
+			StrikeoutPosition:  512,
+			StrikeoutThickness: 102,
+			XHeight:            1086,
+			CapHeight:          1480,
+			ItalicAngle:        -11,
+		}},
+		{"CFFTest", nil, Metrics{
+			UnitsPerEm:         1000,
+			Ascender:           800,
+			Descender:          -200,
+			LineGap:            90,
+			UnderlinePosition:  -125,
+			UnderlineThickness: 50,
+			StrikeoutPosition:  258,
+			StrikeoutThickness: 49,
+			CapHeight:          793,
+			ItalicAngle:        -11.25,
+		}},
+	}
+	for _, tt := range tests {
+		var f *File
+		if tt.data == nil {
+			f = openFont(t, tt.name+".otf")
+		} else {
+			f = parseFont(t, tt.data)
+		}
+		if got := f.Metrics(nil); got != tt.want {
+			t.Errorf("%s: got metrics %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,769 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentypehl
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+
+	"honnef.co/go/gutter/opentype"
+)
+
+// SubsetOptions selects the glyphs that [File.Subset] keeps.
+type SubsetOptions struct {
+	// Runes are mapped to glyphs using the font's cmap. Runes that the font
+	// doesn't support are ignored.
+	Runes  []rune
+	Glyphs []opentype.GlyphID
+
+	// RetainGlyphIDs keeps the original glyph IDs, emptying the glyphs that
+	// aren't kept, instead of renumbering the kept glyphs. This results in
+	// larger fonts, but keeps tables that refer to glyphs, such as GSUB and
+	// GPOS, valid, so that they can be copied. Without it, these tables get
+	// dropped, which suits text that has already been shaped, such as text
+	// in PDF files.
+	RetainGlyphIDs bool
+}
+
+// Tables that are copied unchanged, regardless of the glyphs kept.
+var subsetCopiedTables = [...]opentype.Tag{
+	"name", "cvt ", "fpgm", "prep", "gasp", "fvar", "avar", "cvar", "STAT", "MVAR",
+}
+
+// Tables that aren't copied when retaining glyph IDs. Tables that are built
+// for the subset aren't listed.
+var subsetDroppedTables = [...]opentype.Tag{
+	// The signature no longer matches.
+	"DSIG",
+}
+
+// Subset returns a font that only contains some of the font's glyphs, as well
+// as the IDs that the subset's glyphs have in the original font. The missing
+// glyph, glyph 0, is always kept, as are the components of composite glyphs.
+// When retaining glyph IDs, glyphs that GSUB can substitute for the kept
+// glyphs are kept as well.
+//
+// The subset's cmap maps the runes of all kept glyphs. The glyphs' hinting
+// instructions and variations are kept.
+//
+// Only fonts with TrueType outlines are supported.
+//
+// TODO(dh): support CFF outlines, and prune GSUB and GPOS when renumbering
+// glyphs instead of dropping them.
+func (f *File) Subset(opts SubsetOptions) ([]byte, []opentype.GlyphID, error) {
+	g, ok := f.Glyf()
+	if !ok {
+		return nil, nil, errors.New("subsetting is only supported for fonts with TrueType outlines")
+	}
+	s := subsetter{f: f, g: g, opts: opts}
+	for _, tag := range [...]opentype.Tag{"head", "hhea", "hmtx", "maxp"} {
+		if _, ok := f.directory.FindTable(tag); !ok {
+			return nil, nil, fmt.Errorf("font has no %s table", tag)
+		}
+	}
+	if err := s.selectGlyphs(); err != nil {
+		return nil, nil, err
+	}
+	tables, err := s.tables()
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := opentype.EncodeFont(f.directory.SfntVersion, tables)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, s.glyphs, nil
+}
+
+type subsetter struct {
+	f    *File
+	g    *Glyf
+	opts SubsetOptions
+
+	// keep reports whether to keep a glyph, indexed by original glyph ID.
+	keep []bool
+	// glyphs maps the subset's glyph IDs to the original glyph IDs. When
+	// retaining glyph IDs, it also contains the glyphs that aren't kept.
+	glyphs []opentype.GlyphID
+	// remap maps original glyph IDs to the subset's glyph IDs.
+	remap []opentype.GlyphID
+	// The subset's mappings from runes to glyphs.
+	runes []runeMapping
+}
+
+type runeMapping struct {
+	r     rune
+	glyph opentype.GlyphID
+}
+
+func (s *subsetter) table(tag opentype.Tag) ([]byte, bool) {
+	rec, ok := s.f.directory.FindTable(tag)
+	if !ok {
+		return nil, false
+	}
+	return rec.Data(), true
+}
+
+func (s *subsetter) selectGlyphs() error {
+	data, _ := s.table("maxp")
+	var maxp opentype.MaxpTable
+	opentype.ParseMaxpTable(data, &maxp)
+	numGlyphs := int(maxp.NumGlyphs)
+	if numGlyphs == 0 {
+		return errors.New("font has no glyphs")
+	}
+
+	s.keep = make([]bool, numGlyphs)
+	s.keep[0] = true
+	for _, glyph := range s.opts.Glyphs {
+		if glyph < 0 || int(glyph) >= numGlyphs {
+			return fmt.Errorf("invalid glyph %d", glyph)
+		}
+		s.keep[glyph] = true
+	}
+	for _, r := range s.opts.Runes {
+		if glyph := s.f.Cmap.Lookup(r); glyph > 0 && int(glyph) < numGlyphs {
+			s.keep[glyph] = true
+		}
+	}
+	if s.opts.RetainGlyphIDs {
+		s.closeOverSubstitutions()
+	}
+	if err := s.closeOverComponents(); err != nil {
+		return err
+	}
+
+	s.remap = make([]opentype.GlyphID, numGlyphs)
+	for old, keep := range s.keep {
+		if keep || s.opts.RetainGlyphIDs {
+			s.remap[old] = opentype.GlyphID(len(s.glyphs))
+			s.glyphs = append(s.glyphs, opentype.GlyphID(old))
+		}
+	}
+	if len(s.glyphs) > 0xFFFF {
+		return errors.New("too many glyphs")
+	}
+
+	for r, glyph := range s.f.Cmap.Mappings() {
+		if int(glyph) < numGlyphs && s.keep[glyph] {
+			s.runes = append(s.runes, runeMapping{r, s.remap[glyph]})
+		}
+	}
+	return nil
+}
+
+// closeOverComponents keeps the components of kept composite glyphs.
+func (s *subsetter) closeOverComponents() error {
+	var work []opentype.GlyphID
+	for glyph, keep := range s.keep {
+		if keep {
+			work = append(work, opentype.GlyphID(glyph))
+		}
+	}
+	var comps []opentype.GlyphComponent
+	for len(work) > 0 {
+		glyph := work[len(work)-1]
+		work = work[:len(work)-1]
+		gl, ok, err := s.g.glyph(glyph)
+		if err != nil {
+			return err
+		}
+		if !ok || gl.Kind != opentype.CompositeGlyphTableKind {
+			continue
+		}
+		comps, err = gl.Composite.Components(comps[:0])
+		if err != nil {
+			return err
+		}
+		for _, c := range comps {
+			if c.GlyphIndex < 0 || int(c.GlyphIndex) >= len(s.keep) {
+				return fmt.Errorf("invalid component in glyph %d", glyph)
+			}
+			if !s.keep[c.GlyphIndex] {
+				s.keep[c.GlyphIndex] = true
+				work = append(work, c.GlyphIndex)
+			}
+		}
+	}
+	return nil
+}
+
+// closeOverSubstitutions keeps the glyphs that the kept glyphs can be
+// substituted with. It doesn't consider which features, scripts and
+// contexts the substitutions apply to, which may keep more glyphs than
+// necessary.
+func (s *subsetter) closeOverSubstitutions() {
+	data, ok := s.table("GSUB")
+	if !ok {
+		return
+	}
+	var gsub opentype.GSUBTable
+	opentype.ParseGSUBTable(data, &gsub)
+	var lookups opentype.LookupListTable
+	if !gsub.LookupList(&lookups) {
+		return
+	}
+
+	// Malformed fonts may refer to glyphs that don't exist.
+	kept := func(glyph opentype.GlyphID) bool {
+		return glyph >= 0 && int(glyph) < len(s.keep) && s.keep[glyph]
+	}
+	changed := true
+	add := func(glyph opentype.GlyphID) {
+		if glyph >= 0 && int(glyph) < len(s.keep) && !s.keep[glyph] {
+			s.keep[glyph] = true
+			changed = true
+		}
+	}
+	var (
+		lookup   opentype.LookupTable
+		coverage opentype.CoverageTable
+	)
+	for changed {
+		changed = false
+		for i := range lookups.NumLookupOffsets() {
+			if !lookups.Lookup(i, &lookup) {
+				continue
+			}
+			for j := range lookup.NumSubtableOffsets() {
+				typ, data := lookup.LookupType, lookup.Subtable(j)
+				if typ == opentype.GSUBLookTypeExtensionSubstitution {
+					var ext opentype.ExtensionSubstTable
+					opentype.ParseExtensionSubstTable(data, &ext)
+					if ext.SubstFormat != 1 {
+						continue
+					}
+					typ, data = ext.Format1.ExtensionLookupType, ext.Format1.Extension()
+				}
+
+				// Contextual substitutions only invoke other lookups, which
+				// we consider anyway.
+				switch typ {
+				case opentype.GSUBLookTypeSingle:
+					var tbl opentype.SingleSubstTable
+					opentype.ParseSingleSubstTable(data, &tbl)
+					switch tbl.SubstFormat {
+					case 1:
+						if !tbl.Format1.Coverage(&coverage) {
+							continue
+						}
+						for _, glyph := range coverage.Glyphs() {
+							if kept(glyph) {
+								add(opentype.GlyphID(uint16(glyph) + tbl.Format1.DeltaGlyphID))
+							}
+						}
+					case 2:
+						if !tbl.Format2.Coverage(&coverage) {
+							continue
+						}
+						var subs []uint16
+						for _, sub := range tbl.Format2.SubstituteGlyphIDs() {
+							subs = append(subs, sub)
+						}
+						for k, glyph := range coverage.Glyphs() {
+							if kept(glyph) && k < len(subs) {
+								add(opentype.GlyphID(subs[k]))
+							}
+						}
+					}
+
+				case opentype.GSUBLookTypeMultiple:
+					var tbl opentype.MultipleSubstTable
+					opentype.ParseMultipleSubstTable(data, &tbl)
+					if tbl.SubstFormat != 1 || !tbl.Format1.Coverage(&coverage) {
+						continue
+					}
+					var seq opentype.SequenceTable
+					for k, glyph := range coverage.Glyphs() {
+						if kept(glyph) && tbl.Format1.Sequence(k, &seq) {
+							for _, sub := range seq.SubstituteGlyphIDs() {
+								add(opentype.GlyphID(sub))
+							}
+						}
+					}
+
+				case opentype.GSUBLookTypeAlternate:
+					var tbl opentype.AlternateSubstTable
+					opentype.ParseAlternateSubstTable(data, &tbl)
+					if tbl.SubstFormat != 1 || !tbl.Format1.Coverage(&coverage) {
+						continue
+					}
+					var set opentype.AlternateSetTable
+					for k, glyph := range coverage.Glyphs() {
+						if kept(glyph) && tbl.Format1.AlternateSet(k, &set) {
+							for _, alt := range set.AlternateGlyphIDs() {
+								add(opentype.GlyphID(alt))
+							}
+						}
+					}
+
+				case opentype.GSUBLookTypeLigature:
+					var tbl opentype.LigatureSubstTable
+					opentype.ParseLigatureSubstTable(data, &tbl)
+					if tbl.SubstFormat != 1 || !tbl.Format1.Coverage(&coverage) {
+						continue
+					}
+					var (
+						set opentype.LigatureSetTable
+						lig opentype.LigatureTable
+					)
+					for k, glyph := range coverage.Glyphs() {
+						if !kept(glyph) || !tbl.Format1.LigatureSet(k, &set) {
+							continue
+						}
+						for l := range set.NumLigatureOffsets() {
+							if !set.Ligature(l, &lig) {
+								continue
+							}
+							all := true
+							for _, comp := range lig.ComponentGlyphIDs() {
+								if !kept(opentype.GlyphID(comp)) {
+									all = false
+									break
+								}
+							}
+							if all {
+								add(opentype.GlyphID(lig.LigatureGlyph))
+							}
+						}
+					}
+
+				case opentype.GSUBLookTypeReverseChainingContextSingle:
+					var tbl opentype.ReverseChainSingleSubstTable
+					opentype.ParseReverseChainSingleSubstTable(data, &tbl)
+					if tbl.SubstFormat != 1 || !tbl.Format1.Coverage(&coverage) {
+						continue
+					}
+					for k, glyph := range coverage.Glyphs() {
+						if kept(glyph) && k < tbl.Format1.NumSubstituteGlyphIDs() {
+							add(opentype.GlyphID(tbl.Format1.SubstituteGlyphID(k)))
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func (s *subsetter) tables() ([]opentype.FontTable, error) {
+	var out []opentype.FontTable
+	add := func(tag opentype.Tag, data []byte) {
+		out = append(out, opentype.FontTable{Tag: tag, Data: data})
+	}
+
+	glyf, loca, long, err := s.glyf()
+	if err != nil {
+		return nil, err
+	}
+	add("glyf", glyf)
+	add("loca", loca)
+
+	head, _ := s.table("head")
+	if len(head) < 54 {
+		return nil, errors.New("head table is too short")
+	}
+	head = slices.Clone(head)
+	if long {
+		binary.BigEndian.PutUint16(head[50:], 1)
+	} else {
+		binary.BigEndian.PutUint16(head[50:], 0)
+	}
+	add("head", head)
+
+	maxp, _ := s.table("maxp")
+	if len(maxp) < 6 {
+		return nil, errors.New("maxp table is too short")
+	}
+	maxp = slices.Clone(maxp)
+	binary.BigEndian.PutUint16(maxp[4:], uint16(len(s.glyphs)))
+	add("maxp", maxp)
+
+	hhea, hmtx, err := s.metrics("hhea", "hmtx")
+	if err != nil {
+		return nil, err
+	}
+	add("hhea", hhea)
+	add("hmtx", hmtx)
+	if _, ok := s.table("vhea"); ok {
+		if _, ok := s.table("vmtx"); ok {
+			vhea, vmtx, err := s.metrics("vhea", "vmtx")
+			if err != nil {
+				return nil, err
+			}
+			add("vhea", vhea)
+			add("vmtx", vmtx)
+		}
+	}
+
+	cmap, err := s.cmap()
+	if err != nil {
+		return nil, err
+	}
+	add("cmap", cmap)
+
+	if data, ok := s.table("OS/2"); ok {
+		add("OS/2", s.os2(data))
+	}
+	if data, ok := s.table("post"); ok {
+		add("post", s.post(data))
+	}
+	if data, ok := s.table("gvar"); ok {
+		add("gvar", s.gvar(data))
+	}
+	if data, ok := s.table("HVAR"); ok && !s.opts.RetainGlyphIDs {
+		var hvar opentype.HVARTable
+		opentype.ParseHVARTable(data, &hvar)
+		if data := hvar.Subset(s.glyphs); data != nil {
+			add("HVAR", data)
+		}
+	}
+
+	if s.opts.RetainGlyphIDs {
+		for _, rec := range s.f.directory.TableRecords() {
+			if slices.Contains(subsetDroppedTables[:], rec.Tag) ||
+				slices.ContainsFunc(out, func(tbl opentype.FontTable) bool { return tbl.Tag == rec.Tag }) {
+				continue
+			}
+			add(rec.Tag, rec.Data())
+		}
+	} else {
+		for _, tag := range subsetCopiedTables {
+			if data, ok := s.table(tag); ok {
+				add(tag, data)
+			}
+		}
+	}
+	return out, nil
+}
+
+// glyf builds the glyf and loca tables. It returns whether loca uses long
+// offsets.
+func (s *subsetter) glyf() (glyf, loca []byte, long bool, err error) {
+	offsets := make([]int, 0, len(s.glyphs)+1)
+	for _, old := range s.glyphs {
+		offsets = append(offsets, len(glyf))
+		if !s.keep[old] {
+			continue
+		}
+		data, err := s.g.data(old)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		start := len(glyf)
+		glyf = append(glyf, data...)
+		if !s.opts.RetainGlyphIDs && len(data) > 0 {
+			err := opentype.RemapComponents(glyf[start:], func(glyph opentype.GlyphID) opentype.GlyphID {
+				return s.remap[glyph]
+			})
+			if err != nil {
+				return nil, nil, false, err
+			}
+		}
+		// Short offsets can only address even offsets.
+		if len(glyf)%2 != 0 {
+			glyf = append(glyf, 0)
+		}
+	}
+	offsets = append(offsets, len(glyf))
+
+	long = len(glyf) > 0xFFFF*2
+	for _, off := range offsets {
+		if long {
+			loca = binary.BigEndian.AppendUint32(loca, uint32(off))
+		} else {
+			loca = binary.BigEndian.AppendUint16(loca, uint16(off/2))
+		}
+	}
+	return glyf, loca, long, nil
+}
+
+// metrics builds the hhea and hmtx tables, or the vhea and vmtx tables.
+func (s *subsetter) metrics(headerTag, metricsTag opentype.Tag) (header, metrics []byte, err error) {
+	header, _ = s.table(headerTag)
+	data, _ := s.table(metricsTag)
+	// Both hhea and vhea store the number of long metrics last.
+	if len(header) < 36 {
+		return nil, nil, fmt.Errorf("%s table is too short", headerTag)
+	}
+	numLong := binary.BigEndian.Uint16(header[34:])
+
+	type metric struct {
+		advance uint16
+		bearing int16
+	}
+	ms := make([]metric, len(s.glyphs))
+	if headerTag == "hhea" {
+		var hmtx opentype.HmtxTable
+		opentype.ParseHmtxTable(data, &hmtx)
+		for i, old := range s.glyphs {
+			m := hmtx.Metric(old, numLong)
+			ms[i] = metric{m.AdvanceWidth, m.Lsb}
+		}
+	} else {
+		var vmtx opentype.VmtxTable
+		opentype.ParseVmtxTable(data, &vmtx)
+		for i, old := range s.glyphs {
+			m := vmtx.Metric(old, numLong)
+			ms[i] = metric{m.AdvanceHeight, m.TopSideBearing}
+		}
+	}
+
+	// Glyphs at the end that share the last advance only store their
+	// bearings.
+	n := len(ms)
+	for n > 1 && ms[n-1].advance == ms[n-2].advance {
+		n--
+	}
+	for i, m := range ms {
+		if i < n {
+			metrics = binary.BigEndian.AppendUint16(metrics, m.advance)
+		}
+		metrics = binary.BigEndian.AppendUint16(metrics, uint16(m.bearing))
+	}
+	header = slices.Clone(header)
+	binary.BigEndian.PutUint16(header[34:], uint16(n))
+	return header, metrics, nil
+}
+
+// cmap builds a cmap table with a format 4 subtable for the Basic
+// Multilingual Plane and, if necessary, a format 12 subtable for all of
+// Unicode.
+func (s *subsetter) cmap() ([]byte, error) {
+	bmp := s.runes
+	if i := slices.IndexFunc(s.runes, func(m runeMapping) bool { return m.r > 0xFFFF }); i != -1 {
+		bmp = s.runes[:i]
+	}
+	format4, err := appendCmapFormat4(nil, bmp)
+	if err != nil {
+		return nil, err
+	}
+	var format12 []byte
+	if len(bmp) < len(s.runes) {
+		format12 = appendCmapFormat12(nil, s.runes)
+	}
+
+	type record struct {
+		platform opentype.PlatformID
+		encoding opentype.EncodingID
+		full     bool
+	}
+	records := []record{
+		{opentype.PlatformUnicode, opentype.EncodingUnicode20BMP, false},
+		{opentype.PlatformWindows, opentype.EncodingWindowsUnicodeBMP, false},
+	}
+	if format12 != nil {
+		records = []record{
+			{opentype.PlatformUnicode, opentype.EncodingUnicode20BMP, false},
+			{opentype.PlatformUnicode, opentype.EncodingUnicode20, true},
+			{opentype.PlatformWindows, opentype.EncodingWindowsUnicodeBMP, false},
+			{opentype.PlatformWindows, opentype.EncodingWindowsUnicodeFullRepertoire, true},
+		}
+	}
+
+	header := 4 + 8*len(records)
+	out := binary.BigEndian.AppendUint16(nil, 0)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(records)))
+	for _, rec := range records {
+		off := header
+		if rec.full {
+			off += len(format4)
+		}
+		out = binary.BigEndian.AppendUint16(out, uint16(rec.platform))
+		out = binary.BigEndian.AppendUint16(out, uint16(rec.encoding))
+		out = binary.BigEndian.AppendUint32(out, uint32(off))
+	}
+	out = append(out, format4...)
+	out = append(out, format12...)
+	return out, nil
+}
+
+// appendCmapFormat4 appends a format 4 cmap subtable. Consecutive runes
+// that map to consecutive glyphs share a segment.
+func appendCmapFormat4(b []byte, mappings []runeMapping) ([]byte, error) {
+	type segment struct {
+		start, end uint16
+		delta      uint16
+	}
+	var segs []segment
+	for _, m := range mappings {
+		if m.r == 0xFFFF {
+			continue
+		}
+		delta := uint16(m.glyph) - uint16(m.r)
+		if n := len(segs); n > 0 && segs[n-1].end+1 == uint16(m.r) && segs[n-1].delta == delta {
+			segs[n-1].end++
+			continue
+		}
+		segs = append(segs, segment{uint16(m.r), uint16(m.r), delta})
+	}
+	// The last segment has to map 0xFFFF to the missing glyph.
+	segs = append(segs, segment{0xFFFF, 0xFFFF, 1})
+
+	n := len(segs)
+	length := 16 + 8*n
+	if length > 0xFFFF {
+		return nil, errors.New("too many cmap segments")
+	}
+	entrySelector := bits.Len(uint(n)) - 1
+	searchRange := 2 << entrySelector
+
+	b = binary.BigEndian.AppendUint16(b, 4)
+	b = binary.BigEndian.AppendUint16(b, uint16(length))
+	b = binary.BigEndian.AppendUint16(b, 0)
+	b = binary.BigEndian.AppendUint16(b, uint16(2*n))
+	b = binary.BigEndian.AppendUint16(b, uint16(searchRange))
+	b = binary.BigEndian.AppendUint16(b, uint16(entrySelector))
+	b = binary.BigEndian.AppendUint16(b, uint16(2*n-searchRange))
+	for _, seg := range segs {
+		b = binary.BigEndian.AppendUint16(b, seg.end)
+	}
+	b = binary.BigEndian.AppendUint16(b, 0)
+	for _, seg := range segs {
+		b = binary.BigEndian.AppendUint16(b, seg.start)
+	}
+	for _, seg := range segs {
+		b = binary.BigEndian.AppendUint16(b, seg.delta)
+	}
+	for range segs {
+		// We never use the glyph ID array.
+		b = binary.BigEndian.AppendUint16(b, 0)
+	}
+	return b, nil
+}
+
+// appendCmapFormat12 appends a format 12 cmap subtable.
+func appendCmapFormat12(b []byte, mappings []runeMapping) []byte {
+	type group struct {
+		start, end rune
+		glyph      opentype.GlyphID
+	}
+	var groups []group
+	for _, m := range mappings {
+		if n := len(groups); n > 0 {
+			last := &groups[n-1]
+			if last.end+1 == m.r && last.glyph+opentype.GlyphID(last.end-last.start)+1 == m.glyph {
+				last.end++
+				continue
+			}
+		}
+		groups = append(groups, group{m.r, m.r, m.glyph})
+	}
+
+	b = binary.BigEndian.AppendUint16(b, 12)
+	b = binary.BigEndian.AppendUint16(b, 0)
+	b = binary.BigEndian.AppendUint32(b, uint32(16+12*len(groups)))
+	b = binary.BigEndian.AppendUint32(b, 0)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(groups)))
+	for _, g := range groups {
+		b = binary.BigEndian.AppendUint32(b, uint32(g.start))
+		b = binary.BigEndian.AppendUint32(b, uint32(g.end))
+		b = binary.BigEndian.AppendUint32(b, uint32(g.glyph))
+	}
+	return b
+}
+
+// os2 updates the range of characters stored in the OS/2 table.
+func (s *subsetter) os2(data []byte) []byte {
+	if len(data) < 68 || len(s.runes) == 0 {
+		return data
+	}
+	data = slices.Clone(data)
+	first := min(s.runes[0].r, 0xFFFF)
+	last := min(s.runes[len(s.runes)-1].r, 0xFFFF)
+	binary.BigEndian.PutUint16(data[64:], uint16(first))
+	binary.BigEndian.PutUint16(data[66:], uint16(last))
+	return data
+}
+
+// post builds a post table. Glyph names are kept as a version 2.0 table;
+// fonts without glyph names get a version 3.0 table.
+func (s *subsetter) post(data []byte) []byte {
+	const headerSize = 32
+	if s.opts.RetainGlyphIDs || len(data) < headerSize {
+		return data
+	}
+	var post opentype.PostTable
+	opentype.ParsePostTable(data, &post)
+
+	names := make([]string, len(s.glyphs))
+	hasNames := true
+	for i, old := range s.glyphs {
+		name, ok := post.GlyphName(old)
+		if !ok || len(name) > 255 {
+			hasNames = false
+			break
+		}
+		names[i] = name
+	}
+
+	out := slices.Clone(data[:headerSize])
+	if !hasNames {
+		binary.BigEndian.PutUint32(out, 0x00030000)
+		return out
+	}
+	binary.BigEndian.PutUint32(out, 0x00020000)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(names)))
+	var (
+		strs    []byte
+		indices = map[string]int{}
+	)
+	for _, name := range names {
+		idx, ok := opentype.MacGlyphNameIndex(name)
+		if !ok {
+			idx, ok = indices[name]
+			if !ok {
+				idx = 258 + len(indices)
+				indices[name] = idx
+				strs = append(strs, byte(len(name)))
+				strs = append(strs, name...)
+			}
+		}
+		out = binary.BigEndian.AppendUint16(out, uint16(idx))
+	}
+	return append(out, strs...)
+}
+
+// gvar builds a gvar table containing the variations of the kept glyphs.
+func (s *subsetter) gvar(data []byte) []byte {
+	var gvar opentype.GvarTable
+	opentype.ParseGvarTable(data, &gvar)
+	shared := gvar.SharedTuples()
+
+	const headerSize = 20
+	sharedOffset := headerSize + 4*(len(s.glyphs)+1)
+	dataOffset := sharedOffset + len(shared)
+
+	out := make([]byte, dataOffset)
+	binary.BigEndian.PutUint16(out[0:], 1)
+	binary.BigEndian.PutUint16(out[2:], 0)
+	binary.BigEndian.PutUint16(out[4:], gvar.AxisCount)
+	binary.BigEndian.PutUint16(out[6:], uint16(len(shared)/max(1, 2*int(gvar.AxisCount))))
+	binary.BigEndian.PutUint32(out[8:], uint32(sharedOffset))
+	binary.BigEndian.PutUint16(out[12:], uint16(len(s.glyphs)))
+	// Long offsets.
+	binary.BigEndian.PutUint16(out[14:], 1)
+	binary.BigEndian.PutUint32(out[16:], uint32(dataOffset))
+	copy(out[sharedOffset:], shared)
+
+	var off int
+	for i, old := range s.glyphs {
+		binary.BigEndian.PutUint32(out[headerSize+4*i:], uint32(off))
+		if !s.keep[old] {
+			continue
+		}
+		vd := gvar.GlyphVariationData(old)
+		out = append(out, vd...)
+		off += len(vd)
+		if off%2 != 0 {
+			out = append(out, 0)
+			off++
+		}
+	}
+	binary.BigEndian.PutUint32(out[headerSize+4*len(s.glyphs):], uint32(off))
+	return out
+}
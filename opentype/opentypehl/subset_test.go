@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentypehl
+
+import (
+	"slices"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"honnef.co/go/gutter/opentype"
+)
+
+// subset subsets a font and parses the result.
+func subset(t *testing.T, f *File, opts SubsetOptions) (*File, []opentype.GlyphID) {
+	t.Helper()
+	data, glyphs, err := f.Subset(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := NewFile(data)
+	if err != nil {
+		t.Fatalf("couldn't parse subset: %s", err)
+	}
+	return sub, glyphs
+}
+
+// compareGlyph checks that a glyph in a subset has the same outline, advance
+// and name as the glyph it was copied from.
+func compareGlyph(t *testing.T, f, sub *File, glyph, subGlyph opentype.GlyphID, coords []float64) {
+	t.Helper()
+	g, _ := f.Glyf()
+	subg, ok := sub.Glyf()
+	if !ok {
+		t.Fatal("subset has no glyf table")
+	}
+	want, err := g.Outline(glyph, coords, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := subg.Outline(subGlyph, coords, nil)
+	if err != nil {
+		t.Fatalf("glyph %d: %s", subGlyph, err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("glyph %d at %v has outline %v, want %v", subGlyph, coords, got, want)
+	}
+
+	wantAdv, err := f.AdvanceWidth(glyph, coords)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotAdv, err := sub.AdvanceWidth(subGlyph, coords)
+	if err != nil {
+		t.Fatalf("glyph %d: %s", subGlyph, err)
+	}
+	if gotAdv != wantAdv {
+		t.Errorf("glyph %d at %v advances by %g, want %g", subGlyph, coords, gotAdv, wantAdv)
+	}
+
+	wantName, wantOK := f.GlyphName(glyph)
+	gotName, gotOK := sub.GlyphName(subGlyph)
+	if gotName != wantName || gotOK != wantOK {
+		t.Errorf("glyph %d has name (%q, %t), want (%q, %t)", subGlyph, gotName, gotOK, wantName, wantOK)
+	}
+}
+
+func TestSubset(t *testing.T) {
+	f := parseFont(t, goregular.TTF)
+	const runes = "Hello, fijord!"
+	sub, glyphs := subset(t, f, SubsetOptions{Runes: []rune(runes)})
+
+	if glyphs[0] != 0 {
+		t.Errorf("subset's first glyph is %d, want .notdef", glyphs[0])
+	}
+	for _, r := range runes {
+		glyph := sub.Cmap.Lookup(r)
+		if glyph == 0 || glyphs[glyph] != f.Cmap.Lookup(r) {
+			t.Errorf("%q maps to glyph %d", r, glyph)
+		}
+	}
+	if glyph := sub.Cmap.Lookup('x'); glyph != 0 {
+		t.Errorf("'x' maps to glyph %d, but wasn't kept", glyph)
+	}
+	for subGlyph, glyph := range glyphs {
+		compareGlyph(t, f, sub, glyph, opentype.GlyphID(subGlyph), nil)
+	}
+	if got, want := sub.Metrics(nil), f.Metrics(nil); got != want {
+		t.Errorf("got metrics %+v, want %+v", got, want)
+	}
+}
+
+func TestSubsetRetainGlyphIDs(t *testing.T) {
+	f := parseFont(t, goregular.TTF)
+	sub, glyphs := subset(t, f, SubsetOptions{Runes: []rune("Hi"), RetainGlyphIDs: true})
+
+	for i, glyph := range glyphs {
+		if glyph != opentype.GlyphID(i) {
+			t.Fatalf("glyph %d was renumbered to %d", glyph, i)
+		}
+	}
+	for _, r := range "Hi" {
+		glyph := f.Cmap.Lookup(r)
+		if got := sub.Cmap.Lookup(r); got != glyph {
+			t.Errorf("%q maps to glyph %d, want %d", r, got, glyph)
+		}
+		compareGlyph(t, f, sub, glyph, glyph, nil)
+	}
+
+	// Glyphs that aren't kept are empty.
+	subg, _ := sub.Glyf()
+	path, err := subg.Outline(f.Cmap.Lookup('x'), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(path) != 0 {
+		t.Errorf("glyph that wasn't kept has outline %v", path)
+	}
+}
+
+func TestSubsetVariations(t *testing.T) {
+	tests := []struct {
+		font string
+		opts SubsetOptions
+		want []opentype.GlyphID
+	}{
+		// SourceSans-VF.ttf stores its advance variations in gvar's phantom
+		// points. Á is a composite glyph whose components have to be kept.
+		{"SourceSans-VF.ttf", SubsetOptions{Runes: []rune("Á")}, []opentype.GlyphID{0, 1, 2, 3}},
+		// Dropping A and Á renumbers the acute accent, which isn't mapped
+		// to a rune, and with it its variations.
+		{"SourceSans-VF.ttf", SubsetOptions{Glyphs: []opentype.GlyphID{3}}, []opentype.GlyphID{0, 3}},
+		// SourceSerif-VF.ttf stores its advance variations in HVAR. Dropping
+		// a and b renumbers c.
+		{"SourceSerif-VF.ttf", SubsetOptions{Runes: []rune("c")}, []opentype.GlyphID{0, 3}},
+	}
+	for _, tt := range tests {
+		f := openFont(t, tt.font)
+		sub, glyphs := subset(t, f, tt.opts)
+		if !slices.Equal(glyphs, tt.want) {
+			t.Errorf("%s: kept glyphs %v, want %v", tt.font, glyphs, tt.want)
+		}
+		for _, weight := range []float64{200, 500, 700, 900} {
+			coords := f.NormalizeCoordinates(map[opentype.Tag]float64{"wght": weight})
+			if subCoords := sub.NormalizeCoordinates(map[opentype.Tag]float64{"wght": weight}); !slices.Equal(subCoords, coords) {
+				t.Errorf("%s: weight %g maps to %v in subset, want %v", tt.font, weight, subCoords, coords)
+			}
+			for subGlyph, glyph := range glyphs {
+				compareGlyph(t, f, sub, glyph, opentype.GlyphID(subGlyph), coords)
+			}
+			if got, want := sub.Metrics(coords), f.Metrics(coords); got != want {
+				t.Errorf("%s: weight %g has metrics %+v, want %+v", tt.font, weight, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentypehl
+
+import (
+	"math"
+
+	"honnef.co/go/gutter/opentype"
+)
+
+// NormalizeCoordinates maps user-space axis values, such as a weight of 700,
+// to the normalized variation coordinates that select an instance of a
+// variable font, applying the font's avar mappings. Axes missing from values
+// use their default values. It returns nil if the font has no fvar table.
+func (f *File) NormalizeCoordinates(values map[opentype.Tag]float64) []float64 {
+	rec, ok := f.directory.FindTable("fvar")
+	if !ok {
+		return nil
+	}
+	var fvar opentype.FvarTable
+	opentype.ParseFvarTable(rec.Data(), &fvar)
+
+	// Normalized coordinates are F2DOT14 values, both before and after the
+	// avar mapping. Rounding them matches other implementations.
+	round := func(v float64) float64 { return math.Round(v*(1<<14)) / (1 << 14) }
+	coords := make([]float64, fvar.NumAxes())
+	for i, axis := range fvar.Axes() {
+		if v, ok := values[axis.Tag]; ok {
+			coords[i] = round(axis.Normalize(v))
+		}
+	}
+	if rec, ok := f.directory.FindTable("avar"); ok {
+		var avar opentype.AvarTable
+		opentype.ParseAvarTable(rec.Data(), &avar)
+		avar.Map(coords)
+		for i := range coords {
+			coords[i] = round(coords[i])
+		}
+	}
+	return coords
+}
+
+// AdvanceWidthDelta returns the delta of a glyph's advance width for an
+// instance, given by its normalized variation coordinates. It uses the HVAR
+// table if there is one, and the glyph's phantom points in the gvar table
+// otherwise.
+func (f *File) AdvanceWidthDelta(glyph opentype.GlyphID, coords []float64) (float64, error) {
+	if len(coords) == 0 {
+		return 0, nil
+	}
+	if rec, ok := f.directory.FindTable("HVAR"); ok {
+		var hvar opentype.HVARTable
+		opentype.ParseHVARTable(rec.Data(), &hvar)
+		return hvar.AdvanceWidthDelta(glyph, coords), nil
+	}
+	g, ok := f.Glyf()
+	if !ok {
+		return 0, nil
+	}
+	return g.advanceWidthDelta(glyph, coords)
+}
+
+// MetricDelta returns the delta of a font-wide metric for an instance, given
+// by its normalized variation coordinates. The metric is identified by its
+// MVAR value tag, such as "hasc" for the horizontal ascender or "xhgt" for the
+// x-height.
+func (f *File) MetricDelta(tag opentype.Tag, coords []float64) float64 {
+	if len(coords) == 0 {
+		return 0
+	}
+	rec, ok := f.directory.FindTable("MVAR")
+	if !ok {
+		return 0
+	}
+	var mvar opentype.MVARTable
+	opentype.ParseMVARTable(rec.Data(), &mvar)
+	d, _ := mvar.Delta(tag, coords)
+	return d
+}
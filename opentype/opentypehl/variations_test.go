@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentypehl
+
+import (
+	"math"
+	"testing"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/opentype"
+)
+
+// The expected values in these tests match those computed by HarfBuzz and
+// go-text/typesetting for the same instances.
+
+func TestNormalizeCoordinates(t *testing.T) {
+	tests := []struct {
+		font   string
+		weight float64
+		// The normalized coordinate in F2DOT14 units.
+		want float64
+	}{
+		// Weights below the default map to negative coordinates.
+		{"CFF2-VF.otf", 200, -16384},
+		{"CFF2-VF.otf", 206, -16117},
+		{"CFF2-VF.otf", 400, 0},
+		{"CFF2-VF.otf", 450, 1529},
+		{"CFF2-VF.otf", 600, 6014},
+		// Values outside of the axis' range are clamped.
+		{"CFF2-VF.otf", 1000, 16384},
+		{"SourceSans-VF-HVAR.ttf", 100, 0},
+		{"SourceSans-VF-HVAR.ttf", 250, 819},
+		{"SourceSans-VF-HVAR.ttf", 400, 6029},
+		{"SourceSans-VF-HVAR.ttf", 500, 7930},
+		{"SourceSans-VF-HVAR.ttf", 700, 13500},
+		{"SourceSans-VF-HVAR.ttf", 900, 16384},
+	}
+	for _, tt := range tests {
+		coords := openFont(t, tt.font).NormalizeCoordinates(map[opentype.Tag]float64{"wght": tt.weight})
+		if len(coords) != 1 || coords[0] != tt.want/(1<<14) {
+			t.Errorf("%s: weight %g maps to %v, want [%g]", tt.font, tt.weight, coords, tt.want/(1<<14))
+		}
+	}
+
+	f := openFont(t, "CFF2-VF.otf")
+	if coords := f.NormalizeCoordinates(nil); len(coords) != 1 || coords[0] != 0 {
+		t.Errorf("missing axis maps to %v, want [0]", coords)
+	}
+	if coords := openFont(t, "CFFTest.otf").NormalizeCoordinates(nil); coords != nil {
+		t.Errorf("font without variations has coordinates %v", coords)
+	}
+}
+
+func TestGlyfVariations(t *testing.T) {
+	type rect = curve.Rect
+	tests := []struct {
+		font   string
+		r      rune
+		weight float64
+		// The difference to the advance of the default instance.
+		delta  float64
+		bounds rect
+	}{
+		// The advance deltas come from HVAR.
+		{"SourceSans-VF-HVAR.ttf", 'C', 200, 0, rect{X0: 56, Y0: -12, X1: 334, Y1: 672}},
+		{"SourceSans-VF-HVAR.ttf", 'C', 500, 11.6162109375, rect{X0: 50.19189453125, Y0: -12, X1: 348.520263671875, Y1: 667.159912109375}},
+		{"SourceSans-VF-HVAR.ttf", 'C', 900, 24, rect{X0: 44, Y0: -12, X1: 364, Y1: 662}},
+		{"SourceSans-VF-HVAR.ttf", 'O', 500, 21.29638671875, rect{X0: 50.19189453125, Y0: -12, X1: 642.008544921875, Y1: 667.159912109375}},
+		// The advance deltas come from the phantom points in gvar. Á is a
+		// composite glyph.
+		{"SourceSans-VF.ttf", 'A', 700, 52.734375, rect{X0: -6.4794921875, Y0: 0, X1: 579.2138671875, Y1: 651.76025390625}},
+		{"SourceSans-VF.ttf", 'A', 900, 64, rect{X0: -10, Y0: 0, X1: 594, Y1: 650}},
+		{"SourceSans-VF.ttf", 'Á', 700, 52.734375, rect{X0: -6.4794921875, Y0: 0, X1: 579.2138671875, Y1: 893.79052734375}},
+	}
+	const eps = 1e-3
+	for _, tt := range tests {
+		f := openFont(t, tt.font)
+		coords := f.NormalizeCoordinates(map[opentype.Tag]float64{"wght": tt.weight})
+		glyph := f.Cmap.Lookup(tt.r)
+		delta, err := f.AdvanceWidthDelta(glyph, coords)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(delta-tt.delta) > eps {
+			t.Errorf("%s: %c at weight %g has advance delta %g, want %g", tt.font, tt.r, tt.weight, delta, tt.delta)
+		}
+
+		g, ok := f.Glyf()
+		if !ok {
+			t.Fatalf("%s has no glyf table", tt.font)
+		}
+		path, err := g.Outline(glyph, coords, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := path.BoundingBox()
+		if math.Abs(b.X0-tt.bounds.X0) > eps || math.Abs(b.Y0-tt.bounds.Y0) > eps ||
+			math.Abs(b.X1-tt.bounds.X1) > eps || math.Abs(b.Y1-tt.bounds.Y1) > eps {
+			t.Errorf("%s: %c at weight %g has bounds %v, want %v", tt.font, tt.r, tt.weight, b, tt.bounds)
+		}
+	}
+}
+
+func TestMetricVariations(t *testing.T) {
+	f := openFont(t, "SourceSans-VF-HVAR.ttf")
+	tests := []struct {
+		weight             float64
+		xHeight, strikeout float64
+	}{
+		{200, 0, 0},
+		{500, 10.648193359375, 6.2921142578125},
+		{900, 22, 13},
+	}
+	for _, tt := range tests {
+		coords := f.NormalizeCoordinates(map[opentype.Tag]float64{"wght": tt.weight})
+		xHeight, strikeout := f.MetricDelta("xhgt", coords), f.MetricDelta("stro", coords)
+		if math.Abs(xHeight-tt.xHeight) > 1e-3 || math.Abs(strikeout-tt.strikeout) > 1e-3 {
+			t.Errorf("weight %g: got x-height delta %g and strikeout position delta %g, want %g and %g",
+				tt.weight, xHeight, strikeout, tt.xHeight, tt.strikeout)
+		}
+		// MVAR doesn't vary the cap height and ascender of this font.
+		if d1, d2 := f.MetricDelta("cpht", coords), f.MetricDelta("hasc", coords); d1 != 0 || d2 != 0 {
+			t.Errorf("weight %g: got cap height delta %g and ascender delta %g, want 0", tt.weight, d1, d2)
+		}
+	}
+	if d := f.MetricDelta("xhgt", nil); d != 0 {
+		t.Errorf("default instance has an x-height delta of %g", d)
+	}
+}
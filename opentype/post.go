@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import "sync"
+
+// GlyphName returns the PostScript name of a glyph. It returns false if the
+// table doesn't contain glyph names, as is the case for version 3.0, or if
+// the glyph has no name.
+func (tbl *PostTable) GlyphName(glyph GlyphID) (string, bool) {
+	if glyph < 0 {
+		return "", false
+	}
+	switch {
+	case tbl.MajorVersion == 1 && tbl.MinorVersion == 0:
+		if int(glyph) >= len(macGlyphNames) {
+			return "", false
+		}
+		return macGlyphNames[glyph], true
+
+	case tbl.MajorVersion == 2 && tbl.MinorVersion == 0:
+		var numGlyphs uint16
+		if len(tbl.names) < 2 {
+			return "", false
+		}
+		parseUint16(tbl.names, &numGlyphs)
+		if int(glyph) >= int(numGlyphs) || len(tbl.names) < 2+int(numGlyphs)*2 {
+			return "", false
+		}
+		var idx uint16
+		parseUint16(tbl.names[2+glyph*2:], &idx)
+		if int(idx) < len(macGlyphNames) {
+			return macGlyphNames[idx], true
+		}
+		// The remaining names are Pascal strings, which we have to skip
+		// over to find the one we want.
+		//
+		// OPT(dh): build an index of the strings if callers look up many
+		// names.
+		strs := tbl.names[2+int(numGlyphs)*2:]
+		for range int(idx) - len(macGlyphNames) {
+			if len(strs) == 0 {
+				return "", false
+			}
+			strs = strs[min(len(strs), 1+int(strs[0])):]
+		}
+		if len(strs) == 0 || len(strs) < 1+int(strs[0]) {
+			return "", false
+		}
+		return string(strs[1 : 1+int(strs[0])]), true
+
+	case tbl.MajorVersion == 2 && tbl.MinorVersion == 5:
+		// Version 2.5 is deprecated and stores the names as offsets into the
+		// standard Macintosh glyph order.
+		var numGlyphs uint16
+		if len(tbl.names) < 2 {
+			return "", false
+		}
+		parseUint16(tbl.names, &numGlyphs)
+		if int(glyph) >= int(numGlyphs) || len(tbl.names) < 2+int(numGlyphs) {
+			return "", false
+		}
+		idx := int(glyph) + int(int8(tbl.names[2+glyph]))
+		if idx < 0 || idx >= len(macGlyphNames) {
+			return "", false
+		}
+		return macGlyphNames[idx], true
+
+	default:
+		return "", false
+	}
+}
+
+// MacGlyphNameIndex returns the index of a glyph name in the standard
+// Macintosh glyph order. Version 2.0 post tables refer to these names by
+// index instead of storing them.
+func MacGlyphNameIndex(name string) (int, bool) {
+	idx, ok := macGlyphNameIndices()[name]
+	return idx, ok
+}
+
+var macGlyphNameIndices = sync.OnceValue(func() map[string]int {
+	m := make(map[string]int, len(macGlyphNames))
+	for i, name := range macGlyphNames {
+		m[name] = i
+	}
+	return m
+})
+
+// macGlyphNames are the names of the 258 glyphs of the standard Macintosh
+// glyph order.
+var macGlyphNames = [258]string{
+	".notdef", ".null", "nonmarkingreturn", "space", "exclam", "quotedbl",
+	"numbersign", "dollar", "percent", "ampersand", "quotesingle", "parenleft",
+	"parenright", "asterisk", "plus", "comma", "hyphen", "period", "slash",
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight",
+	"nine", "colon", "semicolon", "less", "equal", "greater", "question", "at",
+	"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O",
+	"P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z", "bracketleft",
+	"backslash", "bracketright", "asciicircum", "underscore", "grave", "a", "b",
+	"c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p", "q",
+	"r", "s", "t", "u", "v", "w", "x", "y", "z", "braceleft", "bar", "braceright",
+	"asciitilde", "Adieresis", "Aring", "Ccedilla", "Eacute", "Ntilde",
+	"Odieresis", "Udieresis", "aacute", "agrave", "acircumflex", "adieresis",
+	"atilde", "aring", "ccedilla", "eacute", "egrave", "ecircumflex", "edieresis",
+	"iacute", "igrave", "icircumflex", "idieresis", "ntilde", "oacute", "ograve",
+	"ocircumflex", "odieresis", "otilde", "uacute", "ugrave", "ucircumflex",
+	"udieresis", "dagger", "degree", "cent", "sterling", "section", "bullet",
+	"paragraph", "germandbls", "registered", "copyright", "trademark", "acute",
+	"dieresis", "notequal", "AE", "Oslash", "infinity", "plusminus", "lessequal",
+	"greaterequal", "yen", "mu", "partialdiff", "summation", "product", "pi",
+	"integral", "ordfeminine", "ordmasculine", "Omega", "ae", "oslash",
+	"questiondown", "exclamdown", "logicalnot", "radical", "florin",
+	"approxequal", "Delta", "guillemotleft", "guillemotright", "ellipsis",
+	"nonbreakingspace", "Agrave", "Atilde", "Otilde", "OE", "oe", "endash",
+	"emdash", "quotedblleft", "quotedblright", "quoteleft", "quoteright",
+	"divide", "lozenge", "ydieresis", "Ydieresis", "fraction", "currency",
+	"guilsinglleft", "guilsinglright", "fi", "fl", "daggerdbl", "periodcentered",
+	"quotesinglbase", "quotedblbase", "perthousand", "Acircumflex", "Ecircumflex",
+	"Aacute", "Edieresis", "Egrave", "Iacute", "Icircumflex", "Idieresis",
+	"Igrave", "Oacute", "Ocircumflex", "apple", "Ograve", "Uacute", "Ucircumflex",
+	"Ugrave", "dotlessi", "circumflex", "tilde", "macron", "breve", "dotaccent",
+	"ring", "cedilla", "hungarumlaut", "ogonek", "caron", "Lslash", "lslash",
+	"Scaron", "scaron", "Zcaron", "zcaron", "brokenbar", "Eth", "eth", "Yacute",
+	"yacute", "Thorn", "thorn", "minus", "multiply", "onesuperior", "twosuperior",
+	"threesuperior", "onehalf", "onequarter", "threequarters", "franc", "Gbreve",
+	"gbreve", "Idotaccent", "Scedilla", "scedilla", "Cacute", "cacute", "Ccaron",
+	"ccaron", "dcroat",
+}
@@ -0,0 +1,57 @@
+// Code generated by honnef.co/go/gutter/internal/cmd/gen_parser. DO NOT EDIT.
+
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+type PostTable struct {
+	MajorVersion uint16
+	MinorVersion uint16
+
+	ItalicAngle        Int16_16
+	UnderlinePosition  int16
+	UnderlineThickness int16
+	IsFixedPitch       uint32
+	MinMemType42       uint32
+	MaxMemType42       uint32
+	MinMemType1        uint32
+	MaxMemType1        uint32
+
+	// The glyph names of versions 2.0 and 2.5, whose layouts differ in ways
+	// that version delimiters can't express.
+	names []byte
+}
+func ParsePostTable(buf []byte, out *PostTable) int {
+	*out = PostTable{}
+	origBuf := buf
+	var dynSize int
+
+	/* FIXME return error */
+	if len(buf) < 32 {
+		return dynSize
+	}
+	parseUint16(buf[0:2], &out.MajorVersion)
+	/* XXX return error */
+	if out.MajorVersion > 3 {
+		return dynSize
+	}
+	parseWeirdMinorVersion(buf[2:4], &out.MinorVersion)
+	parseInt16_16(buf[4:8], &out.ItalicAngle)
+	parseInt16(buf[8:10], &out.UnderlinePosition)
+	parseInt16(buf[10:12], &out.UnderlineThickness)
+	parseUint32(buf[12:16], &out.IsFixedPitch)
+	parseUint32(buf[16:20], &out.MinMemType42)
+	parseUint32(buf[20:24], &out.MaxMemType42)
+	parseUint32(buf[24:28], &out.MinMemType1)
+	parseUint32(buf[28:32], &out.MaxMemType1)
+	{
+		out.names = buf[32:]
+	}
+
+	_ = buf
+	_ = origBuf
+	return dynSize
+}
+
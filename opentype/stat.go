@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+// AxisValue returns the i-th axis value table.
+func (tbl *STATTable) AxisValue(i int, into *AxisValueTable) bool {
+	if i < 0 || i >= tbl.NumAxisValueOffsets() {
+		return false
+	}
+	var off Offset16[AxisValueTable]
+	parseOffset16(tbl.axisValueOffsets[i*2:], &off)
+	// Offsets are relative to the start of the array of offsets.
+	start := int(tbl.offsetToAxisValueOffsets) + int(off)
+	if off == 0 || start >= len(tbl.data) {
+		return false
+	}
+	ParseAxisValueTable(tbl.data[start:], into)
+	return true
+}
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// ItemVariationData returns the i-th item variation data subtable.
+func (tbl *ItemVariationStoreTable) ItemVariationData(i int, into *ItemVariationDataSubtable) bool {
+	if i < 0 || i >= tbl.NumItemVariationDataOffsets() {
+		return false
+	}
+	var off Offset32[ItemVariationDataSubtable]
+	parseOffset32(tbl.itemVariationDataOffsets[i*4:], &off)
+	if off == 0 || int(off) >= len(tbl.data) {
+		return false
+	}
+	parseItemVariationDataSubtable(tbl.data[off:], into)
+	return true
+}
+
+// RegionScalar returns the scalar of a region for an instance, given by its
+// normalized variation coordinates. Missing coordinates are treated as zero.
+func (tbl *VariationRegionList) RegionScalar(region int, coords []float64) float64 {
+	if region < 0 || region >= int(tbl.RegionCount) {
+		return 0
+	}
+	scalar := 1.0
+	for axis := range int(tbl.AxisCount) {
+		rec := tbl.VariationRegion(region*int(tbl.AxisCount) + axis)
+		start, peak, end := rec.StartCoord.Float(), rec.PeakCoord.Float(), rec.EndCoord.Float()
+		var v float64
+		if axis < len(coords) {
+			v = coords[axis]
+		}
+		switch {
+		case start > peak || peak > end:
+			// Invalid regions don't restrict the axis.
+		case start < 0 && end > 0 && peak != 0:
+			// Neither do regions that cross zero.
+		case peak == 0 || v == peak:
+		case v <= start || v >= end:
+			return 0
+		case v < peak:
+			scalar *= (v - start) / (peak - start)
+		default:
+			scalar *= (end - v) / (end - peak)
+		}
+	}
+	return scalar
+}
+
+// Delta returns the delta of the delta set with the given outer and inner
+// indices for an instance, given by its normalized variation coordinates.
+func (tbl *ItemVariationStoreTable) Delta(outer, inner uint16, coords []float64) float64 {
+	var regions VariationRegionList
+	if !tbl.VariationRegionList(&regions) {
+		return 0
+	}
+	var data ItemVariationDataSubtable
+	if !tbl.ItemVariationData(int(outer), &data) || inner >= data.ItemCount {
+		return 0
+	}
+	n := data.NumRegionIndexes()
+	words := min(int(data.WordDeltaCount&0x7FFF), n)
+	wordSize := 2
+	if data.WordDeltaCount&0x8000 != 0 {
+		// LONG_WORDS
+		wordSize = 4
+	}
+	rowSize := words*wordSize + (n-words)*wordSize/2
+	row := int(inner) * rowSize
+	if row+rowSize > len(data.DeltaSets) {
+		return 0
+	}
+	b := data.DeltaSets[row : row+rowSize]
+
+	var out float64
+	for i := range n {
+		var d int32
+		sz := wordSize
+		if i >= words {
+			sz = wordSize / 2
+		}
+		switch sz {
+		case 1:
+			d = int32(int8(b[0]))
+		case 2:
+			d = int32(int16(uint16(b[0])<<8 | uint16(b[1])))
+		case 4:
+			d = int32(uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]))
+		}
+		b = b[sz:]
+		if d == 0 {
+			continue
+		}
+		out += float64(d) * regions.RegionScalar(int(data.RegionIndex(i)), coords)
+	}
+	return out
+}
+
+// Map returns the outer and inner delta-set indices for the i-th item. Items
+// past the end of the map use the map's last entry.
+func (tbl *DeltaSetIndexMapTable) Map(i uint32) (outer, inner uint16) {
+	if tbl.MapCount == 0 {
+		return 0, 0
+	}
+	i = min(i, tbl.MapCount-1)
+	entrySize := int(tbl.EntryFormat&0x30)>>4 + 1
+	innerBits := tbl.EntryFormat&0x0F + 1
+	off := int(i) * entrySize
+	if off+entrySize > len(tbl.mapData) {
+		return 0, 0
+	}
+	var entry uint32
+	for _, b := range tbl.mapData[off : off+entrySize] {
+		entry = entry<<8 | uint32(b)
+	}
+	return uint16(entry >> innerBits), uint16(entry & (1<<innerBits - 1))
+}
+
+// appendDeltaSetIndexMap appends a delta-set index map. Each entry stores the
+// outer index in its upper 16 bits and the inner index in its lower 16 bits.
+// The map uses the smallest entry format that fits all entries.
+func appendDeltaSetIndexMap(b []byte, entries []uint32) []byte {
+	var maxOuter, maxInner uint32
+	for _, e := range entries {
+		maxOuter = max(maxOuter, e>>16)
+		maxInner = max(maxInner, e&0xFFFF)
+	}
+	innerBits := max(1, bits.Len32(maxInner))
+	entrySize := (bits.Len32(maxOuter) + innerBits + 7) / 8
+
+	if len(entries) <= 0xFFFF {
+		b = append(b, 0, byte((entrySize-1)<<4|(innerBits-1)))
+		b = binary.BigEndian.AppendUint16(b, uint16(len(entries)))
+	} else {
+		b = append(b, 1, byte((entrySize-1)<<4|(innerBits-1)))
+		b = binary.BigEndian.AppendUint32(b, uint32(len(entries)))
+	}
+	for _, e := range entries {
+		v := e>>16<<innerBits | e&0xFFFF
+		for i := entrySize - 1; i >= 0; i-- {
+			b = append(b, byte(v>>(8*i)))
+		}
+	}
+	return b
+}
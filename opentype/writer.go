@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package opentype
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+const (
+	SfntVersionTrueType uint32 = 0x00010000
+	SfntVersionCFF      uint32 = 0x4F54544F // 'OTTO'
+)
+
+// The magic number that the checksum of a font plus the head table's
+// checksum adjustment add up to.
+const checksumMagic = 0xB1B0AFBA
+
+// Where the head table stores the checksum adjustment.
+const headChecksumAdjustmentOffset = 8
+
+// FontTable is a table of a font to be encoded by [EncodeFont].
+type FontTable struct {
+	Tag  Tag
+	Data []byte
+}
+
+// The order in which the OpenType specification recommends storing the
+// tables of fonts. Tables not listed are stored after these, sorted by tag.
+var recommendedTableOrder = [...]Tag{
+	// TrueType outlines
+	"head", "hhea", "maxp", "OS/2", "hmtx", "LTSH", "VDMX", "hdmx", "cmap",
+	"fpgm", "prep", "cvt ", "loca", "glyf", "kern", "name", "post", "gasp",
+	"PCLT",
+	// CFF outlines
+	"CFF ", "CFF2",
+}
+
+// TableChecksum computes the checksum of a table's data: the sum of its
+// big-endian uint32 words, with the data padded with zeros to a multiple of
+// four bytes.
+func TableChecksum(data []byte) uint32 {
+	var sum uint32
+	for len(data) >= 4 {
+		sum += binary.BigEndian.Uint32(data)
+		data = data[4:]
+	}
+	if len(data) > 0 {
+		var tail [4]byte
+		copy(tail[:], data)
+		sum += binary.BigEndian.Uint32(tail[:])
+	}
+	return sum
+}
+
+// EncodeFont serializes a font consisting of tables. sfntVersion is
+// [SfntVersionTrueType] for fonts with TrueType outlines and
+// [SfntVersionCFF] for fonts with CFF outlines.
+//
+// The table directory gets sorted by tag, the tables get aligned to four
+// bytes and their checksums get computed. If there is a head table, its
+// checksum adjustment gets updated in the output; the tables' data isn't
+// modified.
+func EncodeFont(sfntVersion uint32, tables []FontTable) ([]byte, error) {
+	if len(tables) > math.MaxUint16 {
+		return nil, errors.New("too many tables")
+	}
+	dir := slices.Clone(tables)
+	slices.SortFunc(dir, func(a, b FontTable) int {
+		return cmp.Compare(a.Tag.Uint32(), b.Tag.Uint32())
+	})
+	for i, tbl := range dir {
+		if len(tbl.Tag) != 4 {
+			return nil, fmt.Errorf("invalid table tag %q", tbl.Tag)
+		}
+		if i > 0 && dir[i-1].Tag == tbl.Tag {
+			return nil, fmt.Errorf("duplicate table %q", tbl.Tag)
+		}
+		if tbl.Tag == "head" && len(tbl.Data) < headChecksumAdjustmentOffset+4 {
+			return nil, errors.New("head table is too short")
+		}
+	}
+
+	order := slices.Clone(dir)
+	rank := func(tag Tag) int {
+		if i := slices.Index(recommendedTableOrder[:], tag); i != -1 {
+			return i
+		}
+		return len(recommendedTableOrder)
+	}
+	slices.SortStableFunc(order, func(a, b FontTable) int {
+		return cmp.Compare(rank(a.Tag), rank(b.Tag))
+	})
+
+	size := 12 + 16*len(dir)
+	offsets := make(map[Tag]int, len(dir))
+	for _, tbl := range order {
+		offsets[tbl.Tag] = size
+		size += (len(tbl.Data) + 3) &^ 3
+	}
+	if uint64(size) > math.MaxUint32 {
+		return nil, errors.New("font is too large")
+	}
+
+	// The search parameters of the table directory are derived from the
+	// largest power of two that is no greater than the number of tables.
+	n := len(dir)
+	var entrySelector int
+	if n > 0 {
+		entrySelector = bits.Len(uint(n)) - 1
+	}
+	searchRange := (1 << entrySelector) * 16
+	rangeShift := n*16 - searchRange
+
+	out := make([]byte, size)
+	binary.BigEndian.PutUint32(out[0:], sfntVersion)
+	binary.BigEndian.PutUint16(out[4:], uint16(n))
+	binary.BigEndian.PutUint16(out[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(out[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(out[10:], uint16(rangeShift))
+
+	headOffset := -1
+	for i, tbl := range dir {
+		off := offsets[tbl.Tag]
+		copy(out[off:], tbl.Data)
+		data := out[off : off+len(tbl.Data)]
+		if tbl.Tag == "head" {
+			// The checksum of the head table is computed with the
+			// checksum adjustment set to zero.
+			clear(data[headChecksumAdjustmentOffset : headChecksumAdjustmentOffset+4])
+			headOffset = off
+		}
+
+		rec := out[12+16*i:]
+		binary.BigEndian.PutUint32(rec[0:], tbl.Tag.Uint32())
+		binary.BigEndian.PutUint32(rec[4:], TableChecksum(data))
+		binary.BigEndian.PutUint32(rec[8:], uint32(off))
+		binary.BigEndian.PutUint32(rec[12:], uint32(len(tbl.Data)))
+	}
+
+	if headOffset != -1 {
+		adj := checksumMagic - TableChecksum(out)
+		binary.BigEndian.PutUint32(out[headOffset+headChecksumAdjustmentOffset:], adj)
+	}
+	return out, nil
+}
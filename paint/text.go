@@ -17,13 +17,16 @@ package paint
 // XXX optical bounds / https://github.com/harfbuzz/harfbuzz/issues/3458 / https://typedrawers.com/discussion/4527/glyph-sidebearings-and-text-alignment / opbd
 
 import (
+	"iter"
 	"math"
+	"sync"
 
 	"honnef.co/go/curve"
 	"honnef.co/go/gutter/debug"
 	"honnef.co/go/gutter/fontdb"
 	"honnef.co/go/gutter/text"
 	"honnef.co/go/gutter/text/bidi"
+	"honnef.co/go/stuff/container/maybe"
 )
 
 // Selecting fonts: we map BCP 47 tags to lists of fonts. Each font is tried in order until one covers the requested
@@ -105,24 +108,27 @@ var Aliases = map[string]string{
 	// language.
 }
 
-type PlaceholderAlignment int
+type PlaceholderAlignment = text.PlaceholderAlignment
 
 const (
-	PlaceholderAlignmentBaseline PlaceholderAlignment = iota
-	PlaceholderAlignmentAboveBaseline
-	PlaceholderAlignmentBelowBaseline
-	PlaceholderAlignmentTop
-	PlaceholderAlignmentBottom
-	PlaceholderAlignmentMiddle
+	PlaceholderAlignmentBaseline      = text.PlaceholderAlignmentBaseline
+	PlaceholderAlignmentAboveBaseline = text.PlaceholderAlignmentAboveBaseline
+	PlaceholderAlignmentBelowBaseline = text.PlaceholderAlignmentBelowBaseline
+	PlaceholderAlignmentTop           = text.PlaceholderAlignmentTop
+	PlaceholderAlignmentBottom        = text.PlaceholderAlignmentBottom
+	PlaceholderAlignmentMiddle        = text.PlaceholderAlignmentMiddle
 )
 
-type TextBaseline int
+type TextBaseline = text.TextBaseline
 
 const (
-	TextBaselineAlphabetic TextBaseline = iota
-	TextBaselineIdeiographic
+	TextBaselineAlphabetic  = text.TextBaselineAlphabetic
+	TextBaselineIdeographic = text.TextBaselineIdeographic
 )
 
+// Deprecated: Use TextBaselineIdeographic.
+const TextBaselineIdeiographic = TextBaselineIdeographic
+
 type PlaceholderDimensions struct {
 	Size           curve.Size
 	Alignment      PlaceholderAlignment
@@ -130,39 +136,62 @@ type PlaceholderDimensions struct {
 	BaselineOffset float64
 }
 
-type TextBox struct {
-	Rect      curve.Rect
-	Direction bidi.Direction
-}
-
-func (tb *TextBox) Start() float64 {
-	switch tb.Direction {
-	case bidi.LeftToRight:
-		return tb.Rect.X0
-	case bidi.RightToLeft:
-		return tb.Rect.X1
-	default:
-		return 0
-	}
-}
-
-func (tb *TextBox) End() float64 {
-	switch tb.Direction {
-	case bidi.LeftToRight:
-		return tb.Rect.X1
-	case bidi.RightToLeft:
-		return tb.Rect.X0
-	default:
-		return 0
-	}
-}
+type TextBox = text.TextBox
 
 type InlineSpan interface {
 	Build(pb *text.ParagraphBuilder, dimensions []PlaceholderDimensions)
 }
 
+// PlaceholderSpan is an InlineSpan that reserves space in the text, to be
+// filled by something other than text. Each placeholder span adds exactly one
+// placeholder to the paragraph, using the dimensions at index
+// ParagraphBuilder.PlaceholderCount.
 type PlaceholderSpan interface {
 	InlineSpan
+	PlaceholderAlignment() PlaceholderAlignment
+	PlaceholderBaseline() TextBaseline
+}
+
+// AddPlaceholder adds a placeholder for span to pb, using the next entry in
+// dimensions. If dimensions doesn't contain enough entries, for example
+// because the placeholders' contents haven't been laid out yet, an empty
+// placeholder is added.
+func AddPlaceholder(pb *text.ParagraphBuilder, span PlaceholderSpan, dimensions []PlaceholderDimensions) {
+	ph := text.Placeholder{
+		Alignment: span.PlaceholderAlignment(),
+		Baseline:  span.PlaceholderBaseline(),
+	}
+	if n := pb.PlaceholderCount(); n < len(dimensions) {
+		dims := dimensions[n]
+		ph.Width = dims.Size.Width
+		ph.Height = dims.Size.Height
+		ph.BaselineOffset = dims.BaselineOffset
+	}
+	pb.AddPlaceholder(ph)
+}
+
+// PlaceholderSpans returns all placeholder spans in span, in the order in
+// which they add placeholders to a paragraph.
+func PlaceholderSpans(span InlineSpan) iter.Seq[PlaceholderSpan] {
+	var walk func(span InlineSpan, yield func(PlaceholderSpan) bool) bool
+	walk = func(span InlineSpan, yield func(PlaceholderSpan) bool) bool {
+		switch span := span.(type) {
+		case PlaceholderSpan:
+			return yield(span)
+		case *TextSpan:
+			for _, child := range span.Children {
+				if !walk(child, yield) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return func(yield func(PlaceholderSpan) bool) {
+		if span != nil {
+			walk(span, yield)
+		}
+	}
 }
 
 type TextSpan struct {
@@ -184,6 +213,22 @@ func (txt *TextSpan) Build(pb *text.ParagraphBuilder, dimensions []PlaceholderDi
 	}
 }
 
+// PreeditSpan is an InlineSpan for text that is being composed by an input
+// method, such as the text of a wsi.PreeditString event. It is displayed
+// underlined, in addition to any decorations of Style.
+type PreeditSpan struct {
+	Text  string
+	Style text.Style
+}
+
+func (txt *PreeditSpan) Build(pb *text.ParagraphBuilder, dimensions []PlaceholderDimensions) {
+	style := txt.Style
+	style.Decoration = maybe.Some(style.Decoration.UnwrapOr(0) | text.DecorationUnderline)
+	pb.PushStyle(&style)
+	defer pb.PopStyle()
+	pb.AddString(txt.Text)
+}
+
 type TextPainter struct {
 	text          InlineSpan
 	textAlignment text.Alignment
@@ -195,10 +240,37 @@ type TextPainter struct {
 	// XXX textWidthBasis
 	textHeightBehavior    text.HeightBehavior
 	placeholderDimensions []PlaceholderDimensions
+	fonts                 *fontdb.Faces
 
 	layoutCache *textPainterLayoutCacheWithOffset
 }
 
+var defaultFonts struct {
+	mu    sync.Mutex
+	faces *fontdb.Faces
+}
+
+// SetDefaultFonts sets the font database used by text painters that don't
+// have a database of their own. By default, the system's fonts are used.
+// Applications that bundle fonts can use this to make their fonts available
+// to all text.
+func SetDefaultFonts(fdb *fontdb.Faces) {
+	defaultFonts.mu.Lock()
+	defer defaultFonts.mu.Unlock()
+	defaultFonts.faces = fdb
+}
+
+// DefaultFonts returns the default font database, scanning the system's fonts
+// if SetDefaultFonts hasn't been called.
+func DefaultFonts() *fontdb.Faces {
+	defaultFonts.mu.Lock()
+	defer defaultFonts.mu.Unlock()
+	if defaultFonts.faces == nil {
+		defaultFonts.faces = fontdb.New()
+	}
+	return defaultFonts.faces
+}
+
 type textPainterLayoutCacheWithOffset struct{}
 
 func (tp *TextPainter) Text() InlineSpan { return tp.text }
@@ -257,6 +329,17 @@ func (tp *TextPainter) SetTextHeightBehavior(hb text.HeightBehavior) {
 	tp.markNeedsLayout()
 }
 
+// Fonts returns the font database used for laying out text, or nil if the
+// default database is used.
+func (tp *TextPainter) Fonts() *fontdb.Faces { return tp.fonts }
+func (tp *TextPainter) SetFonts(fdb *fontdb.Faces) {
+	if tp.fonts == fdb {
+		return
+	}
+	tp.fonts = fdb
+	tp.markNeedsLayout()
+}
+
 func (tp *TextPainter) markNeedsLayout() {
 	tp.layoutCache = nil
 }
@@ -276,9 +359,12 @@ func (tp *TextPainter) Layout(minWidth, maxWidth float64) *text.Paragraph {
 	}
 
 	pb := text.NewParagraphBuilder(&ps)
-	tp.text.Build(pb, nil)
-	// XXX fdb and fl should be reused
-	fdb := fontdb.New()
+	tp.text.Build(pb, tp.placeholderDimensions)
+	fdb := tp.fonts
+	if fdb == nil {
+		fdb = DefaultFonts()
+	}
+	// XXX fl should be reused
 	fl := new(text.FontLoader)
 	p := pb.Build(fdb, fl)
 	p.Layout(maxWidth)
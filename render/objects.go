@@ -6,6 +6,7 @@
 package render
 
 import (
+	"maps"
 	"math"
 
 	"honnef.co/go/color"
@@ -496,6 +497,8 @@ type Lottie struct {
 
 	composition *lottie_model.Composition
 	frame       float64
+	slots       map[string]lottie_model.SlotValue
+	renderer    lottie_renderer.Renderer
 }
 
 func (l *Lottie) SetComposition(c *lottie_model.Composition) {
@@ -513,6 +516,25 @@ func (l *Lottie) SetFrame(f float64) {
 	}
 }
 
+// SetSlots sets the values of the composition's slots, by slot ID. Slots
+// without a value keep the value from the animation.
+func (l *Lottie) SetSlots(slots map[string]lottie_model.SlotValue) {
+	if !maps.Equal(l.slots, slots) {
+		l.slots = slots
+		MarkNeedsPaint(l)
+	}
+}
+
+// SetFrameCacheSize sets the number of bytes to use for caching rendered
+// frames. See [lottie_renderer.Renderer.FrameCacheSize].
+func (l *Lottie) SetFrameCacheSize(n int) {
+	if l.renderer.FrameCacheSize != n {
+		l.renderer.FrameCacheSize = n
+		l.renderer.Reset()
+		MarkNeedsPaint(l)
+	}
+}
+
 func (l *Lottie) PerformLayout() curve.Size {
 	if l.composition != nil {
 		w := float64(l.composition.Width)
@@ -527,8 +549,31 @@ func (l *Lottie) PerformPaint(p *Painter) {
 	if l.composition == nil {
 		return
 	}
-	var r lottie_renderer.Renderer
-	r.Render(l.composition, l.frame, 1, p.Canvas)
+	// The composition may be shared by other render objects using different
+	// slot values, or have slots set by its owner, such as by a theme. We
+	// only apply our values for the duration of rendering and then restore
+	// the previous ones.
+	type saved struct {
+		slot  *lottie_model.Slot
+		value lottie_model.SlotValue
+		set   bool
+	}
+	var prev []saved
+	for id, v := range l.slots {
+		if slot, ok := l.composition.Slots[id]; ok {
+			old, set := slot.Value()
+			prev = append(prev, saved{slot, old, set})
+			slot.Set(v)
+		}
+	}
+	l.renderer.Render(l.composition, l.frame, 1, p.Canvas)
+	for _, s := range prev {
+		if s.set {
+			s.slot.Set(s.value)
+		} else {
+			s.slot.Reset()
+		}
+	}
 }
 
 var _ Attacher = (*AnimatedOpacity)(nil)
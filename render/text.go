@@ -6,14 +6,16 @@
 package render
 
 import (
+	"math"
+
 	"honnef.co/go/curve"
-	"honnef.co/go/stuff/container/maybe"
 	"honnef.co/go/gutter/paint"
 	"honnef.co/go/gutter/text"
 	"honnef.co/go/gutter/text/bidi"
+	"honnef.co/go/stuff/container/maybe"
 )
 
-var _ Object = (*Paragraph)(nil)
+var _ ObjectWithChildren = (*Paragraph)(nil)
 
 type ParagraphAttributes struct {
 	Text          paint.InlineSpan
@@ -33,37 +35,103 @@ type ParagraphAttributes struct {
 
 // XXX move into the render package and rename
 type Paragraph struct {
+	Box
 	ManyChildren
 
-	attrs ParagraphAttributes
+	attrs     ParagraphAttributes
+	painter   paint.TextPainter
+	paragraph *text.Paragraph
 }
 
+// NewParagraph returns a paragraph. Its children are laid out inline, one per
+// placeholder span in attrs.Text, in order.
 func NewParagraph(attrs ParagraphAttributes) *Paragraph {
-	return &Paragraph{
-		attrs: attrs,
-	}
+	r := &Paragraph{}
+	r.setAttributes(attrs)
+	return r
 }
 
-// XXX add setters for Paragraph fields
+// XXX add setters for individual Paragraph fields
 
-// Handle implements Object.
-func (r *Paragraph) Handle() *ObjectHandle {
-	panic("unimplemented")
+func (r *Paragraph) Attributes() ParagraphAttributes { return r.attrs }
+
+func (r *Paragraph) SetAttributes(attrs ParagraphAttributes) {
+	// OPT(dh): compare the attributes and avoid layout where possible.
+	r.setAttributes(attrs)
+	MarkNeedsLayout(r)
+}
+
+func (r *Paragraph) setAttributes(attrs ParagraphAttributes) {
+	r.attrs = attrs
+	tp := &r.painter
+	tp.SetText(attrs.Text)
+	tp.SetTextAlignment(attrs.TextAlign)
+	tp.SetTextDirection(attrs.TextDirection)
+	tp.SetMaxLines(attrs.MaxLines.UnwrapOr(0))
+	if attrs.Overflow == text.OverflowEllipsis {
+		tp.SetEllipsis("\u2026")
+	} else {
+		tp.SetEllipsis("")
+	}
 }
 
 // PerformLayout implements Object.
 func (r *Paragraph) PerformLayout() (size curve.Size) {
-	panic("unimplemented")
+	cs := r.constraints
+
+	// Lay out the inline children first, so that we know the sizes of the
+	// placeholders.
+	//
+	// XXX use the children's baselines for PlaceholderAlignmentBaseline once
+	// render objects can report them. Until then, the bottom edge acts as the
+	// baseline.
+	var dims []paint.PlaceholderDimensions
+	if len(r.children) > 0 {
+		dims = make([]paint.PlaceholderDimensions, len(r.children))
+		i := 0
+		for span := range paint.PlaceholderSpans(r.attrs.Text) {
+			if i == len(dims) {
+				break
+			}
+			dims[i].Alignment = span.PlaceholderAlignment()
+			dims[i].Baseline = span.PlaceholderBaseline()
+			i++
+		}
+		childCs := Constraints{Max: curve.Sz(cs.Max.Width, math.Inf(1))}
+		for i, child := range r.children {
+			sz := Layout(child, childCs, true)
+			dims[i].Size = sz
+			dims[i].BaselineOffset = sz.Height
+		}
+	}
+	r.painter.SetPlaceholderDimensions(dims)
+	r.paragraph = r.painter.Layout(cs.Min.Width, cs.Max.Width)
+
+	for i, box := range r.paragraph.PlaceholderBoxes() {
+		if i == len(r.children) {
+			break
+		}
+		r.children[i].Handle().Offset = box.Rect.Origin()
+	}
+
+	width := cs.Max.Width
+	if math.IsInf(width, 1) {
+		width = r.paragraph.LongestLine()
+	}
+	return cs.Constrain(curve.Sz(width, r.paragraph.Height()))
 }
 
 // PerformPaint implements Object.
-func (*Paragraph) PerformPaint(p *Painter) {
-	panic("unimplemented")
+func (r *Paragraph) PerformPaint(p *Painter) {
+	r.paragraph.Paint(p.Canvas)
+	for _, child := range r.children {
+		p.PaintAt(child, child.Handle().Offset)
+	}
 }
 
-// VisitChildren implements Object.
+// VisitChildren calls yield for each inline child.
 func (r *Paragraph) VisitChildren(yield func(Object) bool) {
-	panic("unimplemented")
+	r.Children()(yield)
 }
 
 /*
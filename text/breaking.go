@@ -0,0 +1,539 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package text
+
+// # Line breaking
+//
+// Line breaking happens in terms of break candidates: positions in the text
+// before which a line may end. Normal candidates are the break opportunities
+// found by UAX #14. If hyphenation is enabled and patterns have been
+// registered for a run's language, words are additionally broken at the
+// hyphenation points found by the patterns. Finally, text between two
+// candidates that is too wide to fit on a line by itself may be broken at any
+// grapheme boundary, as a last resort.
+//
+// Measuring a line doesn't require shaping it from scratch. We know the
+// advance at each position in the text from the paragraph's initial shaping,
+// and the difference of two advances is the width of the text between them,
+// as long as both positions are safe to break at, i.e. shaping the text
+// between them in isolation would produce the same glyphs. For the remaining
+// positions, we only reshape the few runes between the position and the
+// nearest safe position. Hyphenated lines always reshape their end to include
+// the hyphen.
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"unicode"
+
+	"github.com/go-text/typesetting/segmenter"
+
+	"honnef.co/go/gutter/internal/harfbuzz"
+	xlanguage "honnef.co/go/gutter/internal/language"
+	"honnef.co/go/gutter/text/hyphen"
+)
+
+type LineBreaking int
+
+const (
+	// Put as many words on each line as fit, one line at a time. This is
+	// what web browsers do.
+	LineBreakingGreedy LineBreaking = iota
+	// Choose the line breaks for the whole paragraph at once, minimizing
+	// the differences in line lengths. This is the total-fit algorithm
+	// described by Knuth and Plass, and produces visually more pleasing
+	// paragraphs, at the cost of more work and lines that may change when
+	// text is added to the end of the paragraph.
+	LineBreakingOptimal
+)
+
+var hyphenation struct {
+	mu       sync.RWMutex
+	patterns map[string]*hyphen.Patterns
+}
+
+// RegisterHyphenation registers the hyphenation patterns to use for a
+// language, identified by a BCP 47 tag such as "de" or "en-US". Text is
+// hyphenated with the patterns of the closest registered parent of its
+// language, e.g. "de-CH" uses the patterns for "de" if there are none for
+// "de-CH". Passing nil patterns removes the registration.
+//
+// Hyphenation has to be enabled with ParagraphStyle.Hyphenate.
+func RegisterHyphenation(lang string, p *hyphen.Patterns) error {
+	tag, err := xlanguage.Parse(lang)
+	if err != nil {
+		return err
+	}
+	hyphenation.mu.Lock()
+	defer hyphenation.mu.Unlock()
+	if p == nil {
+		delete(hyphenation.patterns, tag.String())
+		return nil
+	}
+	if hyphenation.patterns == nil {
+		hyphenation.patterns = make(map[string]*hyphen.Patterns)
+	}
+	hyphenation.patterns[tag.String()] = p
+	return nil
+}
+
+func hyphenationPatterns(tag xlanguage.Tag) *hyphen.Patterns {
+	hyphenation.mu.RLock()
+	defer hyphenation.mu.RUnlock()
+	if len(hyphenation.patterns) == 0 {
+		return nil
+	}
+	for {
+		if p, ok := hyphenation.patterns[tag.String()]; ok {
+			return p
+		}
+		parent := tag.Parent()
+		if tag.IsRoot() || parent == tag {
+			return nil
+		}
+		tag = parent
+	}
+}
+
+type breakKind uint8
+
+const (
+	breakNormal breakKind = iota
+	// A break that requires inserting a hyphen.
+	breakHyphen
+	// A break at a grapheme boundary, for text that doesn't fit otherwise.
+	breakEmergency
+)
+
+type breakpoint struct {
+	// The position in the text before which to break.
+	pos  int
+	kind breakKind
+}
+
+// breaker holds the state for breaking a paragraph into lines that doesn't
+// depend on the maximum width.
+type breaker struct {
+	// Normal and hyphenation candidates, sorted by position.
+	candidates []breakpoint
+	// Whether breaking before a rune is safe, and the total advance of all
+	// runes before it. Advances are only valid for safe positions.
+	safe     bitset
+	advances []float64
+
+	// Widths of reshaped pieces of text.
+	pieces map[piece]float64
+	// The buffer for reshaping pieces of text, created when first needed.
+	// Layout destroys it before returning.
+	buf *harfbuzz.Buffer
+}
+
+type piece struct {
+	start, end int
+	hyphen     bool
+}
+
+func (p *Paragraph) initBreaker() {
+	n := len(p.text.runes)
+	b := &p.breaker
+	b.pieces = make(map[piece]float64)
+
+	b.safe = newBitset(n + 1)
+	b.advances = make([]float64, n+1)
+	x := 0.0
+	for i := range p.text.runs {
+		run := &p.text.runs[i]
+		scale := run.scaleFactor()
+		for j, glyph := range run.glyphs {
+			c := int(glyph.Cluster)
+			if j == 0 || run.glyphs[j-1].Cluster != glyph.Cluster {
+				b.advances[c] = x
+				if glyph.Flags()&harfbuzz.GlyphFlagsUnsafeToBreak == 0 {
+					b.safe.set(c)
+				}
+			}
+			x += float64(run.glyphPos[j].XAdvance) * scale
+		}
+	}
+	b.advances[n] = x
+	b.safe.set(n)
+
+	var hyphens bitset
+	if p.style.Hyphenate {
+		var seg segmenter.Segmenter
+		seg.Init(p.text.runes)
+		hyphens = newBitset(n + 1)
+		for it := seg.WordIterator(); it.Next(); {
+			word := it.Word()
+			run := p.runAt(word.Offset)
+			lang, ok := run.runStyle.Language.Get()
+			if !ok || run.placeholder != nil {
+				continue
+			}
+			pats := hyphenationPatterns(lang)
+			if pats == nil {
+				continue
+			}
+			if slices.Contains(word.Text, '\u00AD') {
+				// Words containing soft hyphens are only hyphenated where the
+				// author allowed it.
+				continue
+			}
+			for _, pos := range pats.Hyphenate(word.Text) {
+				hyphens.set(word.Offset + pos)
+			}
+		}
+	}
+
+	for i := 1; i < len(p.lb.Breaks); i++ {
+		switch {
+		case p.lb.Breaks[i]:
+			kind := breakNormal
+			if p.text.runes[i-1] == '\u00AD' {
+				kind = breakHyphen
+			}
+			b.candidates = append(b.candidates, breakpoint{i, kind})
+		case hyphens != nil && hyphens.get(i):
+			b.candidates = append(b.candidates, breakpoint{i, breakHyphen})
+		}
+	}
+	if n > 0 && (len(b.candidates) == 0 || b.candidates[len(b.candidates)-1].pos != n) {
+		// UAX #14 always allows breaking at the end of the text, but our
+		// lines have to cover the whole text no matter what.
+		b.candidates = append(b.candidates, breakpoint{n, breakNormal})
+	}
+}
+
+// runAt returns the run containing the rune at index pos.
+func (p *Paragraph) runAt(pos int) *run {
+	i, _ := slices.BinarySearchFunc(p.text.runs, pos, func(r run, pos int) int {
+		switch {
+		case r.End <= pos:
+			return -1
+		case r.Start > pos:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return &p.text.runs[i]
+}
+
+// hyphenRune returns the rune to use for hyphenating text set in font.
+func hyphenRune(font *Font) rune {
+	if _, ok := font.hb.NominalGlyph('\u2010'); ok {
+		return '\u2010'
+	}
+	return '-'
+}
+
+// lineEnd returns the end of the line ending at bp, excluding trailing
+// whitespace, which hangs into the margin.
+func (p *Paragraph) lineEnd(start int, bp breakpoint) int {
+	end := bp.pos
+	for end > start && unicode.IsSpace(p.text.runes[end-1]) {
+		end--
+	}
+	return end
+}
+
+// lineWidth returns the width of the line [start, bp.pos), excluding trailing
+// whitespace and including the hyphen, if any.
+func (p *Paragraph) lineWidth(start int, bp breakpoint) float64 {
+	b := &p.breaker
+	end := p.lineEnd(start, bp)
+	hyphen := bp.kind == breakHyphen
+	if start == end {
+		return 0
+	}
+	if !hyphen && b.safe.get(start) && b.safe.get(end) {
+		return b.advances[end] - b.advances[start]
+	}
+
+	// Find the range of the line whose shaping we already know.
+	safeStart := start
+	for safeStart < end && !b.safe.get(safeStart) {
+		safeStart++
+	}
+	safeEnd := end
+	if hyphen {
+		// The hyphen may interact with the preceding runes. Reshape the
+		// word fragment preceding it.
+		safeEnd--
+	}
+	for safeEnd > safeStart && !b.safe.get(safeEnd) {
+		safeEnd--
+	}
+	if safeStart >= safeEnd {
+		return p.pieceWidth(piece{start, end, hyphen})
+	}
+	w := b.advances[safeEnd] - b.advances[safeStart]
+	if safeStart > start {
+		w += p.pieceWidth(piece{start, safeStart, false})
+	}
+	if safeEnd < end || hyphen {
+		w += p.pieceWidth(piece{safeEnd, end, hyphen})
+	}
+	return w
+}
+
+// pieceWidth returns the width of the text in the piece, shaped in isolation.
+func (p *Paragraph) pieceWidth(pc piece) float64 {
+	b := &p.breaker
+	if w, ok := b.pieces[pc]; ok {
+		return w
+	}
+	if b.buf == nil {
+		b.buf = harfbuzz.NewBuffer()
+	}
+	runs := p.runsForInterval(pc.start, pc.end)
+	p.reshape(b.buf, runs, pc.start, pc.end, pc.hyphen)
+	w := 0.0
+	for i := range runs {
+		w += runs[i].advance()
+	}
+	b.pieces[pc] = w
+	return w
+}
+
+// reshape shapes runs, which have to cover exactly [start, end), without any
+// context from outside the interval. If hyphen is true, a hyphen is added to
+// the last rune.
+func (p *Paragraph) reshape(buf *harfbuzz.Buffer, runs []run, start, end int, hyphen bool) {
+	text := p.text.runes[start:end]
+	for i := range runs {
+		run := &runs[i]
+		var hy rune
+		if hyphen && i == len(runs)-1 && run.placeholder == nil {
+			hy = hyphenRune(run.font)
+		}
+		run.glyphs, run.glyphPos = p.shapeRun(buf, run, text, start, hy)
+		if run.placeholder != nil {
+			// Keep the extents computed by init.
+			continue
+		}
+		run.extents = make([]harfbuzz.GlyphExtents, len(run.glyphs))
+		for j, glyph := range run.glyphs {
+			run.extents[j], _ = run.font.hb.GlyphExtents(glyph.Codepoint)
+		}
+	}
+}
+
+// addEmergencyBreaks returns the candidates, plus grapheme boundaries inside
+// any text between two candidates that doesn't fit in maxWidth.
+func (p *Paragraph) addEmergencyBreaks(maxWidth float64) []breakpoint {
+	b := &p.breaker
+	var out []breakpoint
+	prev := 0
+	for idx, bp := range b.candidates {
+		if p.lineWidth(prev, bp) > maxWidth {
+			if out == nil {
+				out = slices.Clone(b.candidates[:idx])
+			}
+			for i := prev + 1; i < bp.pos; i++ {
+				if p.graphemes.get(i) {
+					out = append(out, breakpoint{i, breakEmergency})
+				}
+			}
+		}
+		if out != nil {
+			out = append(out, bp)
+		}
+		prev = bp.pos
+	}
+	if out == nil {
+		return b.candidates
+	}
+	return out
+}
+
+// breakGreedy breaks lines one at a time, ending each line at the last
+// candidate that fits.
+func (p *Paragraph) breakGreedy(cands []breakpoint, maxWidth float64) []breakpoint {
+	var out []breakpoint
+	start := 0
+	for i := 0; i < len(cands); {
+		best, bestEmergency := -1, -1
+		for j := i; j < len(cands); j++ {
+			if p.lineWidth(start, cands[j]) > maxWidth {
+				if cands[j].kind == breakHyphen {
+					// A later candidate without a hyphen may still fit.
+					continue
+				}
+				break
+			}
+			if cands[j].kind == breakEmergency {
+				bestEmergency = j
+			} else {
+				best = j
+			}
+		}
+		if best == -1 {
+			// Only break inside of words that don't fit on a line of their
+			// own.
+			best = bestEmergency
+		}
+		if best == -1 {
+			// Nothing fits, overflow.
+			best = i
+		}
+		out = append(out, cands[best])
+		start = cands[best].pos
+		i = best + 1
+	}
+	return out
+}
+
+// Costs used by breakOptimal, in the same units as TeX's demerits.
+const (
+	linePenalty          = 10
+	hyphenPenalty        = 50
+	emergencyPenalty     = 1000
+	doubleHyphenDemerits = 3000
+	// The badness of a line with no text, and of lines that are too wide.
+	maxBadness      = 100
+	overfullBadness = 10000
+	// Added to lines that are too wide, which we only allow if nothing else
+	// fits.
+	overfullDemerits = 1e9
+)
+
+// breakOptimal finds the breaks that minimize the total demerits of all
+// lines. Demerits grow with the square of a line's unused space, penalizing
+// lines that are much shorter than the others. Hyphens and breaks inside of
+// words are penalized further.
+func (p *Paragraph) breakOptimal(cands []breakpoint, maxWidth float64) []breakpoint {
+	n := len(p.text.runes)
+	// nodes[0] is the start of the paragraph, nodes[i+1] is cands[i].
+	type node struct {
+		demerits float64
+		prev     int
+	}
+	nodes := make([]node, len(cands)+1)
+	at := func(i int) breakpoint {
+		if i == 0 {
+			return breakpoint{}
+		}
+		return cands[i-1]
+	}
+	for j := 1; j < len(nodes); j++ {
+		bp := at(j)
+		nodes[j] = node{demerits: math.Inf(1), prev: j - 1}
+		for i := j - 1; i >= 0; i-- {
+			from := at(i)
+			w := p.lineWidth(from.pos, bp)
+			overfull := w > maxWidth
+			if overfull && i < j-1 {
+				// Lines starting even earlier will be wider still.
+				break
+			}
+
+			var badness float64
+			switch {
+			case overfull:
+				badness = overfullBadness
+			case bp.pos == n:
+				// The last line may be as short as it likes.
+			default:
+				r := (maxWidth - w) / maxWidth
+				badness = maxBadness * r * r
+			}
+			var penalty float64
+			switch bp.kind {
+			case breakHyphen:
+				penalty = hyphenPenalty
+			case breakEmergency:
+				penalty = emergencyPenalty
+			}
+			d := (linePenalty+badness)*(linePenalty+badness) + penalty*penalty
+			if bp.kind == breakHyphen && from.kind == breakHyphen {
+				d += doubleHyphenDemerits
+			}
+			if overfull {
+				d += overfullDemerits
+			}
+			if total := nodes[i].demerits + d; total < nodes[j].demerits {
+				nodes[j] = node{demerits: total, prev: i}
+			}
+		}
+	}
+
+	var out []breakpoint
+	for j := len(nodes) - 1; j > 0; j = nodes[j].prev {
+		out = append(out, at(j))
+	}
+	slices.Reverse(out)
+	return out
+}
+
+// Layout breaks the paragraph into lines no wider than maxWidth, if possible.
+func (p *Paragraph) Layout(maxWidth float64) {
+	b := &p.breaker
+	if b.advances == nil {
+		p.initBreaker()
+	}
+	defer func() {
+		if b.buf != nil {
+			b.buf.Destroy()
+			b.buf = nil
+		}
+	}()
+
+	var breaks []breakpoint
+	switch {
+	case len(p.text.runes) == 0:
+	case math.IsInf(maxWidth, 1) || p.lineWidth(0, breakpoint{pos: len(p.text.runes)}) <= maxWidth:
+		breaks = []breakpoint{{pos: len(p.text.runes)}}
+	default:
+		cands := p.addEmergencyBreaks(maxWidth)
+		switch p.style.LineBreaking {
+		case LineBreakingOptimal:
+			breaks = p.breakOptimal(cands, maxWidth)
+		default:
+			breaks = p.breakGreedy(cands, maxWidth)
+		}
+	}
+
+	clear(p.lines)
+	lines := p.lines[:0]
+	start := 0
+	for _, bp := range breaks {
+		lines = append(lines, line{
+			start: start,
+			end:   bp.pos,
+			width: p.lineWidth(start, bp),
+			runs:  p.lineRuns(start, bp),
+		})
+		start = bp.pos
+	}
+	if p.style.Alignment == AlignmentJustify && !math.IsInf(maxWidth, 1) {
+		// The last line isn't justified.
+		for i := range lines[:max(len(lines)-1, 0)] {
+			p.justify(&lines[i], maxWidth)
+		}
+	}
+
+	p.lines = lines
+	p.width = maxWidth
+	p.positionLines()
+}
+
+// lineRuns returns the runs of the line [start, bp.pos), reshaping the
+// line's edges if necessary.
+func (p *Paragraph) lineRuns(start int, bp breakpoint) []run {
+	b := &p.breaker
+	runs := p.runsForInterval(start, bp.pos)
+	hyphen := bp.kind == breakHyphen
+	if !hyphen && b.safe.get(start) && b.safe.get(bp.pos) {
+		return runs
+	}
+	if b.buf == nil {
+		b.buf = harfbuzz.NewBuffer()
+	}
+	// OPT(dh): only reshape the runs at the unsafe edges
+	p.reshape(b.buf, runs, start, bp.pos, hyphen)
+	return runs
+}
@@ -0,0 +1,456 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package text
+
+import (
+	"cmp"
+	"math"
+	"math/bits"
+	"slices"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/gfx"
+	"honnef.co/go/gutter/internal/harfbuzz"
+)
+
+// inkGlyph is a glyph that a decoration has to skip around.
+type inkGlyph struct {
+	font *Font
+	gid  int32
+	// The transform from font units to the paragraph's coordinate system.
+	transform curve.Affine
+	// The vertical extent of the glyph's ink, in the paragraph's coordinate
+	// system.
+	top, bottom float64
+}
+
+// decorationSpan is a horizontal section of a line that has a single kind of
+// decoration line. Adjacent glyphs with the same decoration are merged into a
+// single span so that the line is drawn without seams, even if the glyphs
+// belong to different runs.
+type decorationSpan struct {
+	x0, x1 float64
+	kind   Decoration
+	style  DecorationStyle
+	brush  gfx.Paint
+	// The position of the top of the line and its thickness, in the
+	// paragraph's coordinate system.
+	top, thickness float64
+	// The style the span was created from, used for cheaply merging spans.
+	textStyle *Style
+	// The glyphs covered by the span, used for skipping ink.
+	glyphs []inkGlyph
+	// The sections of the span to draw, computed by the first call to paint.
+	segs     [][2]float64
+	haveSegs bool
+}
+
+// backgroundSpan is a section of a line that has a background.
+type backgroundSpan struct {
+	rect  curve.Rect
+	brush gfx.Paint
+	style *Style
+}
+
+// lineDecorations collects the backgrounds and decoration lines of a single
+// line of text.
+type lineDecorations struct {
+	backgrounds []backgroundSpan
+	// Spans per kind of decoration, indexed by the bit position of the
+	// decoration.
+	spans [3][]decorationSpan
+}
+
+// adjacent reports whether the sections [a0, a1] and [b0, b1] touch.
+func adjacent(a0, a1, b0, b1 float64) bool {
+	const eps = 1e-6
+	return math.Abs(a1-b0) < eps || math.Abs(a0-b1) < eps
+}
+
+// samePaint reports whether two paints are known to be identical. Only solid
+// colors can be compared; other paints may contain slices.
+func samePaint(a, b gfx.Paint) bool {
+	sa, ok1 := a.(gfx.Solid)
+	sb, ok2 := b.(gfx.Solid)
+	return ok1 && ok2 && sa == sb
+}
+
+func decorationBrush(style *Style) (gfx.Paint, bool) {
+	if paint, ok := style.DecorationBrush.Get(); ok {
+		return paint, true
+	}
+	if fill, ok := style.Fill.Get(); ok {
+		return gfx.Solid(fill), true
+	}
+	return nil, false
+}
+
+// add records the backgrounds and decorations of a glyph that spans from x0
+// to x1, which may be in either order. The line's baseline is at y and it
+// extends vertically from lineTop to lineBottom. scale converts from font
+// units to logical pixels.
+func (ld *lineDecorations) add(
+	x0, x1, y, lineTop, lineBottom float64,
+	style *Style,
+	font *Font,
+	scale float64,
+	glyph inkGlyph,
+) {
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+
+	if bg, ok := style.Background.Get(); ok {
+		merged := false
+		if n := len(ld.backgrounds); n > 0 {
+			last := &ld.backgrounds[n-1]
+			if (last.style == style || samePaint(last.brush, bg)) &&
+				last.rect.Y0 == lineTop && last.rect.Y1 == lineBottom &&
+				adjacent(last.rect.X0, last.rect.X1, x0, x1) {
+				last.rect.X0 = min(last.rect.X0, x0)
+				last.rect.X1 = max(last.rect.X1, x1)
+				merged = true
+			}
+		}
+		if !merged {
+			ld.backgrounds = append(ld.backgrounds, backgroundSpan{
+				rect:  curve.NewRectFromPoints(curve.Pt(x0, lineTop), curve.Pt(x1, lineBottom)),
+				brush: bg,
+				style: style,
+			})
+		}
+	}
+
+	deco := style.Decoration.UnwrapOr(0)
+	if deco == 0 {
+		return
+	}
+	brush, ok := decorationBrush(style)
+	if !ok {
+		return
+	}
+	dstyle := style.DecorationStyle.UnwrapOr(DecorationSolid)
+
+	for deco != 0 {
+		idx := bits.TrailingZeros(uint(deco))
+		kind := Decoration(1 << idx)
+		deco &^= kind
+
+		// The underline and strikeout offsets are the distances from the
+		// baseline to the top of the line, with positive values being above
+		// the baseline.
+		var top, thickness float64
+		switch kind {
+		case DecorationUnderline:
+			top = y - float64(font.hb.Metric(harfbuzz.MetricsUnderlineOffset))*scale
+			thickness = float64(font.hb.Metric(harfbuzz.MetricsUnderlineSize)) * scale
+		case DecorationOverline:
+			top = y - float64(font.hb.Metric(harfbuzz.MetricsHorizontalAscender))*scale
+			thickness = float64(font.hb.Metric(harfbuzz.MetricsUnderlineSize)) * scale
+		case DecorationLineThrough:
+			top = y - float64(font.hb.Metric(harfbuzz.MetricsStrikeoutOffset))*scale
+			thickness = float64(font.hb.Metric(harfbuzz.MetricsStrikeoutSize)) * scale
+		default:
+			continue
+		}
+
+		spans := &ld.spans[idx]
+		if n := len(*spans); n > 0 {
+			last := &(*spans)[n-1]
+			if adjacent(last.x0, last.x1, x0, x1) && last.style == dstyle &&
+				(last.textStyle == style || samePaint(last.brush, brush)) {
+				// Runs using different fonts share a single line. Like
+				// browsers, we use the lowest underline and the thickest
+				// line.
+				last.x0 = min(last.x0, x0)
+				last.x1 = max(last.x1, x1)
+				switch kind {
+				case DecorationUnderline:
+					last.top = max(last.top, top)
+				case DecorationOverline:
+					last.top = min(last.top, top)
+				}
+				last.thickness = max(last.thickness, thickness)
+				last.glyphs = append(last.glyphs, glyph)
+				continue
+			}
+		}
+		*spans = append(*spans, decorationSpan{
+			x0:        x0,
+			x1:        x1,
+			kind:      kind,
+			style:     dstyle,
+			brush:     brush,
+			top:       top,
+			thickness: thickness,
+			textStyle: style,
+			glyphs:    []inkGlyph{glyph},
+		})
+	}
+}
+
+// paintBackgrounds draws all backgrounds. It has to be called before drawing
+// the glyphs.
+func (ld *lineDecorations) paintBackgrounds(rec gfx.Recorder) {
+	for _, bg := range ld.backgrounds {
+		rec.Fill(bg.rect, bg.brush)
+	}
+}
+
+// paintUnder draws underlines and overlines. It has to be called before
+// drawing the glyphs.
+func (ld *lineDecorations) paintUnder(rec gfx.Recorder) {
+	for _, kind := range [...]Decoration{DecorationUnderline, DecorationOverline} {
+		spans := ld.spans[bits.TrailingZeros(uint(kind))]
+		for i := range spans {
+			spans[i].paint(rec)
+		}
+	}
+}
+
+// paintOver draws line-throughs. It has to be called after drawing the
+// glyphs.
+func (ld *lineDecorations) paintOver(rec gfx.Recorder) {
+	spans := ld.spans[bits.TrailingZeros(uint(DecorationLineThrough))]
+	for i := range spans {
+		spans[i].paint(rec)
+	}
+}
+
+// band returns the vertical extent of the span's line, taking its style into
+// account.
+func (span *decorationSpan) band() (y0, y1 float64) {
+	t := span.thickness
+	switch span.style {
+	case DecorationDouble:
+		switch span.kind {
+		case DecorationUnderline:
+			return span.top, span.top + 3*t
+		case DecorationOverline:
+			return span.top - 2*t, span.top + t
+		default:
+			return span.top - t, span.top + 2*t
+		}
+	case DecorationWavy:
+		// The wave's amplitude is the line's thickness.
+		return span.top - t, span.top + 2*t
+	default:
+		return span.top, span.top + t
+	}
+}
+
+// segments returns the sections of the span that have to be drawn. For
+// underlines and overlines, these exclude the areas around glyph ink that
+// intersects the line.
+func (span *decorationSpan) segments() [][2]float64 {
+	whole := [][2]float64{{span.x0, span.x1}}
+	if span.kind == DecorationLineThrough {
+		return whole
+	}
+
+	y0, y1 := span.band()
+	var gaps [][2]float64
+	for _, g := range span.glyphs {
+		if g.bottom < y0 || g.top > y1 {
+			continue
+		}
+		gaps = append(gaps, inkIntervals(g, y0, y1)...)
+	}
+	if len(gaps) == 0 {
+		return whole
+	}
+
+	// Leave some space between the line and the ink.
+	pad := max(span.thickness, 1)
+	slices.SortFunc(gaps, func(a, b [2]float64) int { return cmp.Compare(a[0], b[0]) })
+	var out [][2]float64
+	x := span.x0
+	for _, gap := range gaps {
+		g0, g1 := gap[0]-pad, gap[1]+pad
+		if g0 > x {
+			out = append(out, [2]float64{x, min(g0, span.x1)})
+		}
+		x = max(x, g1)
+		if x >= span.x1 {
+			break
+		}
+	}
+	if x < span.x1 {
+		out = append(out, [2]float64{x, span.x1})
+	}
+
+	// Drop slivers that would look like stray dots.
+	return slices.DeleteFunc(out, func(seg [2]float64) bool {
+		return seg[1]-seg[0] < span.thickness
+	})
+}
+
+func (span *decorationSpan) paint(rec gfx.Recorder) {
+	t := span.thickness
+	if t <= 0 {
+		return
+	}
+	if !span.haveSegs {
+		span.segs, span.haveSegs = span.segments(), true
+	}
+	for _, seg := range span.segs {
+		a, b := seg[0], seg[1]
+		switch span.style {
+		case DecorationDouble:
+			var first, second float64
+			switch span.kind {
+			case DecorationUnderline:
+				first, second = span.top, span.top+2*t
+			case DecorationOverline:
+				first, second = span.top-2*t, span.top
+			default:
+				first, second = span.top-t, span.top+t
+			}
+			rec.Fill(curve.NewRectFromPoints(curve.Pt(a, first), curve.Pt(b, first+t)), span.brush)
+			rec.Fill(curve.NewRectFromPoints(curve.Pt(a, second), curve.Pt(b, second+t)), span.brush)
+
+		case DecorationDotted:
+			// Dots are placed relative to the start of the span so that they
+			// line up across gaps.
+			period := 2 * t
+			k := math.Ceil((a - span.x0) / period)
+			for x := span.x0 + k*period + t/2; x+t/2 <= b; x += period {
+				rec.Fill(curve.Circle{Center: curve.Pt(x, span.top+t/2), Radius: t / 2}, span.brush)
+			}
+
+		case DecorationDashed:
+			dash, period := 3*t, 5*t
+			k := math.Floor((a - span.x0) / period)
+			for x := span.x0 + k*period; x < b; x += period {
+				x0, x1 := max(x, a), min(x+dash, b)
+				if x1 > x0 {
+					rec.Fill(curve.NewRectFromPoints(curve.Pt(x0, span.top), curve.Pt(x1, span.top+t)), span.brush)
+				}
+			}
+
+		case DecorationWavy:
+			// Each half wave is a quadratic curve. The curve's extremum is
+			// half way between its end points and its control point.
+			half := 2 * t
+			mid := span.top + t/2
+			k := math.Floor((a - span.x0) / half)
+			x := span.x0 + k*half
+			up := int(k)%2 == 0
+			var wave curve.BezPath
+			wave.MoveTo(curve.Pt(x, mid))
+			for x < b {
+				ctrl := mid + 2*t
+				if up {
+					ctrl = mid - 2*t
+				}
+				wave.QuadTo(curve.Pt(x+half/2, ctrl), curve.Pt(x+half, mid))
+				x += half
+				up = !up
+			}
+			y0, y1 := span.band()
+			rec.PushClip(curve.NewRectFromPoints(curve.Pt(a, y0-t), curve.Pt(b, y1+t)))
+			rec.Stroke(wave, curve.DefaultStroke.WithWidth(t).WithCaps(curve.ButtCap), span.brush)
+			rec.PopClip()
+
+		default:
+			rec.Fill(curve.NewRectFromPoints(curve.Pt(a, span.top), curve.Pt(b, span.top+t)), span.brush)
+		}
+	}
+}
+
+// inkIntervals returns the horizontal sections in which the glyph's outline
+// covers the band between y0 and y1.
+func inkIntervals(g inkGlyph, y0, y1 float64) [][2]float64 {
+	outline := g.font.GlyphOutline(g.gid)
+	if len(outline) == 0 {
+		return nil
+	}
+
+	type crossing struct {
+		x       float64
+		winding int
+	}
+	scanlines := [3]float64{y0, (y0 + y1) / 2, y1}
+	var crossings [3][]crossing
+	var out [][2]float64
+
+	edge := func(p, q curve.Point) {
+		// The part of the edge that lies within the band.
+		if p.Y == q.Y {
+			if p.Y >= y0 && p.Y <= y1 {
+				out = append(out, [2]float64{min(p.X, q.X), max(p.X, q.X)})
+			}
+		} else {
+			t0 := (y0 - p.Y) / (q.Y - p.Y)
+			t1 := (y1 - p.Y) / (q.Y - p.Y)
+			if t0 > t1 {
+				t0, t1 = t1, t0
+			}
+			t0, t1 = max(t0, 0), min(t1, 1)
+			if t0 <= t1 {
+				xa := p.X + t0*(q.X-p.X)
+				xb := p.X + t1*(q.X-p.X)
+				out = append(out, [2]float64{min(xa, xb), max(xa, xb)})
+			}
+		}
+
+		// Crossings with the scanlines, for finding the glyph's interior.
+		for i, sy := range scanlines {
+			lo, hi, w := p, q, 1
+			if lo.Y > hi.Y {
+				lo, hi, w = hi, lo, -1
+			}
+			if sy < lo.Y || sy >= hi.Y {
+				continue
+			}
+			x := lo.X + (sy-lo.Y)/(hi.Y-lo.Y)*(hi.X-lo.X)
+			crossings[i] = append(crossings[i], crossing{x, w})
+		}
+	}
+
+	var start, cur curve.Point
+	open := false
+	els := func(yield func(curve.PathElement) bool) {
+		for el := range outline.PathElements(0) {
+			if !yield(el.Transform(g.transform)) {
+				return
+			}
+		}
+	}
+	for el := range curve.Flatten(els, 0.25) {
+		switch el.Kind {
+		case curve.MoveToKind:
+			if open {
+				edge(cur, start)
+			}
+			start, cur, open = el.P0, el.P0, true
+		case curve.LineToKind:
+			edge(cur, el.P0)
+			cur = el.P0
+		case curve.ClosePathKind:
+			edge(cur, start)
+			cur, open = start, false
+		}
+	}
+	if open {
+		edge(cur, start)
+	}
+
+	for _, cs := range crossings {
+		slices.SortFunc(cs, func(a, b crossing) int { return cmp.Compare(a.x, b.x) })
+		winding := 0
+		var x0 float64
+		for _, c := range cs {
+			if winding == 0 {
+				x0 = c.x
+			}
+			winding += c.winding
+			if winding == 0 {
+				out = append(out, [2]float64{x0, c.x})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package hyphen implements Liang's hyphenation algorithm, as used by TeX.
+//
+// This package doesn't contain any patterns. Patterns for many languages are
+// available from the hyph-utf8 project, in the format understood by Parse.
+package hyphen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type node struct {
+	children map[rune]*node
+	// The pattern's values, one per inter-letter position, or nil if no
+	// pattern ends at this node.
+	values []uint8
+}
+
+// Patterns is a set of hyphenation patterns and exceptions for a language.
+type Patterns struct {
+	// The minimum number of runes before and after a hyphen.
+	LeftMin, RightMin int
+
+	root       node
+	exceptions map[string][]int
+}
+
+// New returns an empty set of patterns, with TeX's default minimums of two
+// runes before and three runes after a hyphen.
+func New() *Patterns {
+	return &Patterns{
+		LeftMin:  2,
+		RightMin: 3,
+	}
+}
+
+// Parse parses patterns and exceptions in the syntax used by TeX. The input
+// may either consist of \patterns{...} and \hyphenation{...} blocks, or of a
+// plain list of whitespace-separated patterns, as found in hyph-utf8's .pat.txt
+// files. Comments start with % and extend to the end of the line.
+func Parse(r io.Reader) (*Patterns, error) {
+	p := New()
+	const (
+		modePatterns = iota
+		modeExceptions
+	)
+	mode := modePatterns
+
+	sc := bufio.NewScanner(r)
+	lineNum := 0
+	for sc.Scan() {
+		lineNum++
+		line := sc.Text()
+		if i := strings.IndexByte(line, '%'); i >= 0 {
+			line = line[:i]
+		}
+		for _, tok := range strings.Fields(line) {
+			switch {
+			case strings.HasPrefix(tok, `\patterns{`):
+				mode = modePatterns
+				tok = tok[len(`\patterns{`):]
+			case strings.HasPrefix(tok, `\hyphenation{`):
+				mode = modeExceptions
+				tok = tok[len(`\hyphenation{`):]
+			}
+			if tok == "}" {
+				mode = modePatterns
+				continue
+			}
+			tok = strings.TrimSuffix(tok, "}")
+			if tok == "" {
+				continue
+			}
+			var err error
+			switch mode {
+			case modePatterns:
+				err = p.AddPattern(tok)
+			case modeExceptions:
+				p.AddException(tok)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// AddPattern adds a single pattern, such as "hen5at" or ".ach4".
+func (p *Patterns) AddPattern(pat string) error {
+	letters := make([]rune, 0, len(pat))
+	values := make([]uint8, 1, len(pat)+1)
+	for _, r := range pat {
+		if r >= '0' && r <= '9' {
+			if values[len(values)-1] != 0 {
+				return fmt.Errorf("invalid pattern %q: consecutive digits", pat)
+			}
+			values[len(values)-1] = uint8(r - '0')
+		} else {
+			letters = append(letters, unicode.ToLower(r))
+			values = append(values, 0)
+		}
+	}
+	if len(letters) == 0 {
+		return fmt.Errorf("invalid pattern %q: no letters", pat)
+	}
+
+	n := &p.root
+	for _, r := range letters {
+		child, ok := n.children[r]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[rune]*node)
+			}
+			child = &node{}
+			n.children[r] = child
+		}
+		n = child
+	}
+	n.values = values
+	return nil
+}
+
+// AddException adds a word whose hyphenation points are given explicitly, such
+// as "ta-ble".
+func (p *Patterns) AddException(word string) {
+	var positions []int
+	var sb strings.Builder
+	n := 0
+	for _, r := range word {
+		if r == '-' {
+			positions = append(positions, n)
+			continue
+		}
+		sb.WriteRune(unicode.ToLower(r))
+		n++
+	}
+	if p.exceptions == nil {
+		p.exceptions = make(map[string][]int)
+	}
+	p.exceptions[sb.String()] = positions
+}
+
+// Hyphenate returns the indices of the runes in word before which the word
+// may be hyphenated, in ascending order. Word must consist of a single word,
+// without any surrounding whitespace or punctuation.
+func (p *Patterns) Hyphenate(word []rune) []int {
+	if len(word) < p.LeftMin+p.RightMin {
+		return nil
+	}
+
+	// The word, framed by word boundaries.
+	framed := make([]rune, 0, len(word)+2)
+	framed = append(framed, '.')
+	for _, r := range word {
+		framed = append(framed, unicode.ToLower(r))
+	}
+	framed = append(framed, '.')
+
+	if p.exceptions != nil {
+		// OPT(dh): avoid the allocation
+		if positions, ok := p.exceptions[string(framed[1:len(framed)-1])]; ok {
+			var out []int
+			for _, pos := range positions {
+				if pos >= p.LeftMin && pos <= len(word)-p.RightMin {
+					out = append(out, pos)
+				}
+			}
+			return out
+		}
+	}
+
+	// values[i] is the value of the position before framed[i].
+	values := make([]uint8, len(framed)+1)
+	for start := range framed {
+		n := &p.root
+		for i := start; i < len(framed); i++ {
+			n = n.children[framed[i]]
+			if n == nil {
+				break
+			}
+			for j, v := range n.values {
+				values[start+j] = max(values[start+j], v)
+			}
+		}
+	}
+
+	var out []int
+	for i := max(p.LeftMin, 1); i <= len(word)-p.RightMin; i++ {
+		// Position i in word is position i+1 in framed.
+		if values[i+1]%2 == 1 {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// HyphenateString is like Hyphenate but operates on a string and returns byte
+// offsets.
+func (p *Patterns) HyphenateString(word string) []int {
+	runes := []rune(word)
+	positions := p.Hyphenate(runes)
+	if len(positions) == 0 {
+		return nil
+	}
+	out := make([]int, 0, len(positions))
+	off, idx := 0, 0
+	for _, pos := range positions {
+		for idx < pos {
+			off += utf8.RuneLen(runes[idx])
+			idx++
+		}
+		out = append(out, off)
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package hyphen_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"honnef.co/go/gutter/text/hyphen"
+)
+
+// The patterns from The TEXbook, Appendix H, which are sufficient for
+// hyphenating "hyphenation".
+const texbookPatterns = `
+% comment
+\patterns{
+hy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n
+}
+\hyphenation{ta-ble}
+`
+
+func TestHyphenate(t *testing.T) {
+	p, err := hyphen.Parse(strings.NewReader(texbookPatterns))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		word string
+		want []int
+	}{
+		{"hyphenation", []int{2, 6}},
+		{"Hyphenation", []int{2, 6}},
+		{"table", []int{2}},
+		{"tables", nil},
+		{"hy", nil},
+	}
+	for _, tt := range tests {
+		got := p.Hyphenate([]rune(tt.word))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Hyphenate(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+
+	// Exceptions are subject to the minimums, too.
+	p.LeftMin, p.RightMin = 3, 1
+	if got := p.Hyphenate([]rune("table")); len(got) != 0 {
+		t.Errorf("Hyphenate(%q) = %v, want []", "table", got)
+	}
+	if got, want := p.Hyphenate([]rune("hyphenation")), []int{6}; !slices.Equal(got, want) {
+		t.Errorf("Hyphenate(%q) = %v, want %v", "hyphenation", got, want)
+	}
+}
+
+func TestHyphenateString(t *testing.T) {
+	p := hyphen.New()
+	p.LeftMin, p.RightMin = 1, 1
+	if err := p.AddPattern("ä1b"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.HyphenateString("xäbx"), []int{3}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParsePlain(t *testing.T) {
+	p, err := hyphen.Parse(strings.NewReader("hy3ph he2n hena4 hen5at\n1na n2at 1tio 2io o2n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.Hyphenate([]rune("hyphenation")), []int{2, 6}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestAddPatternInvalid(t *testing.T) {
+	p := hyphen.New()
+	for _, pat := range []string{"12a", "3"} {
+		if err := p.AddPattern(pat); err == nil {
+			t.Errorf("AddPattern(%q) succeeded, expected error", pat)
+		}
+	}
+}
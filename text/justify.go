@@ -0,0 +1,340 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package text
+
+import (
+	"math"
+	"slices"
+	"unicode"
+
+	"honnef.co/go/gutter/internal/harfbuzz"
+	xlanguage "honnef.co/go/gutter/internal/language"
+	"honnef.co/go/gutter/text/bidi"
+)
+
+// Scripts whose letters join, which mustn't be letter spaced.
+var cursiveScripts = map[xlanguage.Script]bool{
+	xlanguage.MustParseScript("Arab"): true,
+	xlanguage.MustParseScript("Syrc"): true,
+	xlanguage.MustParseScript("Mong"): true,
+	xlanguage.MustParseScript("Nkoo"): true,
+	xlanguage.MustParseScript("Mand"): true,
+	xlanguage.MustParseScript("Mani"): true,
+	xlanguage.MustParseScript("Phlp"): true,
+	xlanguage.MustParseScript("Phag"): true,
+	xlanguage.MustParseScript("Rohg"): true,
+	xlanguage.MustParseScript("Sogd"): true,
+	xlanguage.MustParseScript("Adlm"): true,
+}
+
+var arabicScript = xlanguage.MustParseScript("Arab")
+
+const tatweel = '\u0640'
+
+// isWordSeparator reports whether r separates words, for the purposes of word
+// spacing and justification. These are the word-separator characters defined
+// by CSS Text.
+func isWordSeparator(r rune) bool {
+	switch r {
+	case ' ', '\u00A0', '\u1361', '\U00010100', '\U00010101', '\U0001039F', '\U0001091F':
+		return true
+	default:
+		return false
+	}
+}
+
+// applySpacing adds the letter and word spacing of the run's styles to the
+// advances of the run's glyphs. Letter spacing is added after every grapheme
+// cluster, except in cursive scripts.
+func (p *Paragraph) applySpacing(run *run, info []harfbuzz.GlyphInfo, pos []harfbuzz.GlyphPosition) {
+	letters := !cursiveScripts[run.script]
+	scale := run.scaleFactor()
+	for i := range info {
+		c := int(info[i].Cluster)
+		next := run.End
+		if i+1 < len(info) {
+			next = int(info[i+1].Cluster)
+		}
+		if next == c {
+			// Spacing goes after the last glyph of a cluster.
+			continue
+		}
+		style := run.runeStyles[c-run.Start]
+		var extra float64
+		if letters && p.graphemes.get(next) {
+			extra += style.LetterSpacing.UnwrapOr(0)
+		}
+		if isWordSeparator(p.text.runes[c]) {
+			extra += style.WordSpacing.UnwrapOr(0)
+		}
+		if extra != 0 {
+			pos[i].XAdvance += int32(math.Round(extra / scale))
+		}
+	}
+}
+
+// joinsLeft reports whether the Arabic letter r connects to the following
+// letter, i.e. whether it is dual-joining.
+func joinsLeft(r rune) bool {
+	switch {
+	case r == '\u0626', r == '\u0628', r >= '\u062A' && r <= '\u062E',
+		r >= '\u0633' && r <= '\u063A', r >= '\u0640' && r <= '\u0647',
+		r == '\u0649', r == '\u064A', r == '\u066E', r == '\u066F',
+		r == '\u067E', r == '\u0686', r == '\u06A9', r == '\u06AF',
+		r == '\u06CC':
+		return true
+	default:
+		return false
+	}
+}
+
+// joinsRight reports whether the Arabic letter r connects to the preceding
+// letter.
+func joinsRight(r rune) bool {
+	if joinsLeft(r) {
+		return true
+	}
+	switch {
+	case r >= '\u0622' && r <= '\u0625', r == '\u0627', r == '\u0629',
+		r >= '\u062F' && r <= '\u0632', r == '\u0648', r == '\u0698':
+		return true
+	default:
+		return false
+	}
+}
+
+// opportunity is a position in a run at which justification may add space.
+type opportunity struct {
+	run int
+	// The index of the glyph after which to add space.
+	glyph int
+}
+
+// justify stretches a line to maxWidth. Lines containing Arabic text are
+// stretched by inserting kashidas, and the space that kashidas don't fill is
+// distributed across word separators. Lines that have neither, for example in
+// scripts that don't use spaces, are stretched by spacing out their letters.
+func (p *Paragraph) justify(l *line, maxWidth float64) {
+	extra := maxWidth - l.width
+	if extra <= 0 {
+		return
+	}
+	end := p.lineEnd(l.start, breakpoint{pos: l.end})
+
+	for i := range l.runs {
+		if l.runs[i].placeholder == nil {
+			l.runs[i].ownGlyphs()
+		}
+	}
+
+	var applied float64
+	if opps := p.kashidaOpportunities(l, end); len(opps) > 0 {
+		applied = p.insertKashidas(l, opps, extra)
+	}
+
+	opps := p.justificationOpportunities(l, end, func(run *run, c, next int) bool {
+		return isWordSeparator(p.text.runes[c])
+	})
+	if len(opps) == 0 && applied == 0 {
+		opps = p.justificationOpportunities(l, end, func(run *run, c, next int) bool {
+			return !cursiveScripts[run.script] && next < end && p.graphemes.get(next)
+		})
+	}
+	if rest := extra - applied; len(opps) > 0 && rest > 0 {
+		// Distribute the space evenly, carrying rounding errors forward.
+		var spaced float64
+		for k, opp := range opps {
+			run := &l.runs[opp.run]
+			scale := run.scaleFactor()
+			want := rest * float64(k+1) / float64(len(opps))
+			units := int32(math.Round((want - spaced) / scale))
+			run.glyphPos[opp.glyph].XAdvance += units
+			spaced += float64(units) * scale
+		}
+		applied += spaced
+	}
+	// Lines that couldn't be stretched keep their natural width, so that
+	// they are aligned to the start.
+	l.width += applied
+}
+
+// justificationOpportunities returns the last glyphs of all clusters before
+// end for which fn returns true. fn is called with the run, the cluster, and
+// the following cluster.
+func (p *Paragraph) justificationOpportunities(l *line, end int, fn func(run *run, c, next int) bool) []opportunity {
+	var out []opportunity
+	for i := range l.runs {
+		run := &l.runs[i]
+		if run.placeholder != nil {
+			continue
+		}
+		for j, glyph := range run.glyphs {
+			c := int(glyph.Cluster)
+			if c >= end {
+				break
+			}
+			next := run.End
+			if j+1 < len(run.glyphs) {
+				next = int(run.glyphs[j+1].Cluster)
+			}
+			if next != c && fn(run, c, next) {
+				out = append(out, opportunity{i, j})
+			}
+		}
+	}
+	return out
+}
+
+// kashidaOpportunities returns, for each Arabic word before end, the place at
+// which to insert kashidas. We use the last connection between two letters in
+// each word, which is where kashidas are least likely to stretch the
+// beginning of a word unnaturally.
+func (p *Paragraph) kashidaOpportunities(l *line, end int) []opportunity {
+	var out []opportunity
+	for i := range l.runs {
+		run := &l.runs[i]
+		if run.placeholder != nil || run.script != arabicScript {
+			continue
+		}
+		if _, ok := run.font.hb.NominalGlyph(tatweel); !ok {
+			continue
+		}
+
+		// The opportunity in the current word, as the index of the rune
+		// before which to insert kashidas.
+		cand := -1
+		flush := func() {
+			if cand == -1 {
+				return
+			}
+			// Kashidas go after all glyphs of the preceding runes.
+			for j, glyph := range run.glyphs {
+				if int(glyph.Cluster) >= cand {
+					out = append(out, opportunity{i, j - 1})
+					break
+				}
+			}
+			cand = -1
+		}
+		// The last letter, skipping over marks.
+		prev := rune(-1)
+		for k := run.Start; k < min(run.End, end); k++ {
+			r := p.text.runes[k]
+			if unicode.Is(unicode.Mn, r) {
+				continue
+			}
+			if prev != -1 && joinsLeft(prev) && joinsRight(r) && r != tatweel {
+				cand = k
+			}
+			if !joinsRight(r) && !joinsLeft(r) {
+				flush()
+			}
+			prev = r
+		}
+		flush()
+	}
+	return out
+}
+
+// insertKashidas distributes extra, in logical pixels, evenly across the
+// opportunities by inserting tatweel glyphs. It returns the width of the
+// inserted kashidas, which is less than extra if the font can't provide them.
+func (p *Paragraph) insertKashidas(l *line, opps []opportunity, extra float64) float64 {
+	// The number of font units to insert at each opportunity, carrying
+	// rounding errors forward.
+	units := make([]int32, len(opps))
+	var applied float64
+	for k, opp := range opps {
+		run := &l.runs[opp.run]
+		gid, _ := run.font.hb.NominalGlyph(tatweel)
+		if run.font.hb.GlyphHAdvance(gid) <= 0 {
+			continue
+		}
+		scale := run.scaleFactor()
+		want := extra * float64(k+1) / float64(len(opps))
+		units[k] = max(int32(math.Round((want-applied)/scale)), 0)
+		applied += float64(units[k]) * scale
+	}
+
+	// Process opportunities back to front so that inserting glyphs doesn't
+	// invalidate the glyph indices of the remaining opportunities.
+	for k := len(opps) - 1; k >= 0; k-- {
+		if units[k] == 0 {
+			continue
+		}
+		opp := opps[k]
+		run := &l.runs[opp.run]
+		gid, _ := run.font.hb.NominalGlyph(tatweel)
+		adv := run.font.hb.GlyphHAdvance(gid)
+		// Use as many kashidas as necessary to fill the space, letting them
+		// overlap so that we fill it exactly.
+		n := (units[k] + adv - 1) / adv
+		ext, _ := run.font.hb.GlyphExtents(gid)
+		cluster := run.glyphs[opp.glyph].Cluster
+		infos := make([]harfbuzz.GlyphInfo, n)
+		poss := make([]harfbuzz.GlyphPosition, n)
+		exts := make([]harfbuzz.GlyphExtents, n)
+		for i := range n {
+			infos[i] = harfbuzz.GlyphInfo{Codepoint: gid, Cluster: cluster}
+			poss[i] = harfbuzz.GlyphPosition{XAdvance: units[k] / n}
+			if i < units[k]%n {
+				poss[i].XAdvance++
+			}
+			exts[i] = ext
+		}
+		at := opp.glyph + 1
+		run.glyphs = slices.Insert(run.glyphs, at, infos...)
+		run.glyphPos = slices.Insert(run.glyphPos, at, poss...)
+		run.extents = slices.Insert(run.extents, at, exts...)
+	}
+	return applied
+}
+
+// ownGlyphs replaces the run's glyphs with a copy of the glyphs that belong
+// to the run's range of text, so that they can be modified without affecting
+// other lines.
+func (r *run) ownGlyphs() {
+	first, last := len(r.glyphs), 0
+	for i, glyph := range r.glyphs {
+		if c := int(glyph.Cluster); c >= r.Start && c < r.End {
+			first = min(first, i)
+			last = i + 1
+		}
+	}
+	first = min(first, last)
+	r.glyphs = slices.Clone(r.glyphs[first:last])
+	r.glyphPos = slices.Clone(r.glyphPos[first:last])
+	r.extents = slices.Clone(r.extents[first:last])
+}
+
+// lineStart returns the position of the pen at the start of a line, aligning
+// the line in a paragraph of width avail.
+func (p *Paragraph) lineStart(l *line, avail float64) float64 {
+	free := avail - l.width
+	rtl := p.style.Direction == bidi.RightToLeft
+	// The position of the left edge of the line's content.
+	var left float64
+	switch p.style.Alignment {
+	case AlignmentLeft:
+	case AlignmentRight:
+		left = free
+	case AlignmentCenter:
+		left = free / 2
+	case AlignmentStart, AlignmentJustify:
+		// The last line of justified text, and lines that couldn't be
+		// justified, are aligned to the start.
+		if rtl {
+			left = free
+		}
+	case AlignmentEnd:
+		if !rtl {
+			left = free
+		}
+	}
+	if rtl {
+		return left + l.width
+	}
+	return left
+}
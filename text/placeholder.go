@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package text
+
+import (
+	"fmt"
+
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/text/bidi"
+)
+
+// PlaceholderRune is the rune that represents placeholders in the text, for
+// the purposes of line breaking and bidi.
+const PlaceholderRune = '\uFFFC'
+
+type PlaceholderAlignment int
+
+const (
+	// Align the placeholder's baseline with the text's baseline.
+	PlaceholderAlignmentBaseline PlaceholderAlignment = iota
+	// Align the bottom edge of the placeholder with the baseline.
+	PlaceholderAlignmentAboveBaseline
+	// Align the top edge of the placeholder with the baseline.
+	PlaceholderAlignmentBelowBaseline
+	// Align the top edge of the placeholder with the top edge of the font.
+	PlaceholderAlignmentTop
+	// Align the bottom edge of the placeholder with the bottom edge of the
+	// font.
+	PlaceholderAlignmentBottom
+	// Align the middle of the placeholder with the middle of the font.
+	PlaceholderAlignmentMiddle
+)
+
+type TextBaseline int
+
+const (
+	TextBaselineAlphabetic TextBaseline = iota
+	TextBaselineIdeographic
+)
+
+// Placeholder describes an empty space in a paragraph, for example for
+// embedding a widget in text.
+type Placeholder struct {
+	Width, Height float64
+	Alignment     PlaceholderAlignment
+	// The baseline to align to, if Alignment is PlaceholderAlignmentBaseline.
+	Baseline TextBaseline
+	// The distance from the top of the placeholder to its baseline, if
+	// Alignment is PlaceholderAlignmentBaseline.
+	BaselineOffset float64
+}
+
+type placeholder struct {
+	Placeholder
+	// The box of the placeholder, as computed by Paragraph.Layout.
+	box TextBox
+}
+
+type TextBox struct {
+	Rect      curve.Rect
+	Direction bidi.Direction
+}
+
+func (tb *TextBox) Start() float64 {
+	switch tb.Direction {
+	case bidi.LeftToRight:
+		return tb.Rect.X0
+	case bidi.RightToLeft:
+		return tb.Rect.X1
+	default:
+		return 0
+	}
+}
+
+func (tb *TextBox) End() float64 {
+	switch tb.Direction {
+	case bidi.LeftToRight:
+		return tb.Rect.X1
+	case bidi.RightToLeft:
+		return tb.Rect.X0
+	default:
+		return 0
+	}
+}
+
+// AddPlaceholder adds a placeholder to the text. The placeholder is
+// represented by PlaceholderRune and uses the current style, but isn't drawn.
+// Use Paragraph.PlaceholderBoxes to find the placeholder's position after
+// layout.
+func (pb *ParagraphBuilder) AddPlaceholder(ph Placeholder) {
+	// Placeholders get a run of their own, which is never shaped.
+	if last := &pb.text.runs[len(pb.text.runs)-1]; last.End > last.Start {
+		pb.text.runs = append(pb.text.runs, run{
+			Run: bidi.Run{
+				Start: last.End,
+				End:   last.End,
+			},
+			runStyle: pb.currentRunStyle,
+		})
+	}
+	ptr := &placeholder{Placeholder: ph}
+	pb.text.placeholders = append(pb.text.placeholders, ptr)
+	r := &pb.text.runs[len(pb.text.runs)-1]
+	r.placeholder = ptr
+	pb.text.runes = append(pb.text.runes, PlaceholderRune)
+	r.runeStyles = append(r.runeStyles, pb.currentRuneStyle)
+	r.End++
+
+	// Text following the placeholder starts a new run.
+	pb.text.runs = append(pb.text.runs, run{
+		Run: bidi.Run{
+			Start: r.End,
+			End:   r.End,
+		},
+		runStyle: pb.currentRunStyle,
+	})
+}
+
+// PlaceholderCount returns the number of placeholders that have been added.
+func (pb *ParagraphBuilder) PlaceholderCount() int {
+	return len(pb.text.placeholders)
+}
+
+// PlaceholderBoxes returns the boxes of all placeholders, in the order in
+// which they were added, relative to the paragraph's origin. Placeholders
+// that don't fit within the paragraph's maximum number of lines have empty
+// boxes.
+func (p *Paragraph) PlaceholderBoxes() []TextBox {
+	out := make([]TextBox, len(p.text.placeholders))
+	for i, ph := range p.text.placeholders {
+		out[i] = ph.box
+	}
+	return out
+}
+
+// placeholderExtents returns the distances from the baseline to the top and
+// the bottom of a placeholder run, in logical pixels. Both values are positive
+// when the placeholder extends above and below the baseline, respectively.
+func (r *run) placeholderExtents() (ascent, descent float64) {
+	// XXX respect Placeholder.Baseline once we support ideographic baselines
+	ph := &r.placeholder.Placeholder
+	switch ph.Alignment {
+	case PlaceholderAlignmentBaseline:
+		return ph.BaselineOffset, ph.Height - ph.BaselineOffset
+	case PlaceholderAlignmentAboveBaseline:
+		return ph.Height, 0
+	case PlaceholderAlignmentBelowBaseline:
+		return 0, ph.Height
+	}
+
+	hz, _ := r.font.hb.HorizontalExtents()
+	scale := r.scaleFactor()
+	fontAscent := float64(hz.Ascender) * scale
+	fontDescent := -float64(hz.Descender) * scale
+	switch ph.Alignment {
+	case PlaceholderAlignmentTop:
+		return fontAscent, ph.Height - fontAscent
+	case PlaceholderAlignmentBottom:
+		return ph.Height - fontDescent, fontDescent
+	case PlaceholderAlignmentMiddle:
+		mid := (fontAscent - fontDescent) / 2
+		return mid + ph.Height/2, ph.Height/2 - mid
+	default:
+		panic(fmt.Sprintf("unhandled placeholder alignment %v", ph.Alignment))
+	}
+}
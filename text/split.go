@@ -319,8 +319,7 @@ func (pb *ParagraphBuilder) splitByFont(fdb *fontdb.Faces, fl *FontLoader) {
 				continue
 			}
 			// XXX actually check that the font covers the rune
-			// XXX pass the right index once we support font collections
-			face := Face{Path: fip.Font.Path, Index: 0}
+			face := Face{Path: fip.Font.Path, Index: fip.Font.Index, Data: fip.Font.Data}
 			key := makeFontKey(face, fip.AxisValues)
 			font, ok := pb.fonts[key]
 			if !ok {
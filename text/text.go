@@ -9,7 +9,6 @@ package text
 // TODO lerping of font features and variable axes, for animation purposes
 // FIXME figure out alphabetic vs ideographic baselines
 // TODO figure out leading distribution
-// TODO add support for placeholders
 // TODO leading trim (https://medium.com/microsoft-design/leading-trim-the-future-of-digital-typesetting-d082d84b202 / https://github.com/flutter/flutter/issues/146860)
 // TODO https://www.figma.com/blog/line-height-changes/
 // TODO https://aresluna.org/line-height-playground/
@@ -116,6 +115,9 @@ import (
 	"slices"
 	"strings"
 	"unicode/utf8"
+	"unsafe"
+
+	"github.com/go-text/typesetting/segmenter"
 
 	"honnef.co/go/color"
 	"honnef.co/go/curve"
@@ -187,32 +189,58 @@ const (
 type Face struct {
 	Path  string
 	Index int
+	// The contents of the font file, for fonts that were loaded from memory.
+	// If set, Path only serves to identify the font.
+	Data []byte
+}
+
+// faceKey identifies a face. In-memory fonts are identified by their data.
+type faceKey struct {
+	file  fileKey
+	index int
+}
+
+type fileKey struct {
+	path string
+	data *byte
+}
+
+func (f Face) key() faceKey {
+	return faceKey{
+		file:  fileKey{path: f.Path, data: unsafe.SliceData(f.Data)},
+		index: f.Index,
+	}
 }
 
 type FontLoader struct {
-	files map[string]*harfbuzz.Blob
-	faces map[Face]*harfbuzz.Face
+	files map[fileKey]*harfbuzz.Blob
+	faces map[faceKey]*harfbuzz.Face
 }
 
 // XXX
 //
 // The returned font must be manually destroyed to free its resources.
 func (fl *FontLoader) Font(face Face, vars map[opentype.Tag]float64) *harfbuzz.Font {
-	hbface, ok := fl.faces[face]
+	key := face.key()
+	hbface, ok := fl.faces[key]
 	if !ok {
-		blob, ok := fl.files[face.Path]
+		blob, ok := fl.files[key.file]
 		if !ok {
-			blob = harfbuzz.NewBlobFromFile(face.Path)
+			if face.Data != nil {
+				blob = harfbuzz.NewBlob(face.Data)
+			} else {
+				blob = harfbuzz.NewBlobFromFile(face.Path)
+			}
 			if fl.files == nil {
-				fl.files = make(map[string]*harfbuzz.Blob)
+				fl.files = make(map[fileKey]*harfbuzz.Blob)
 			}
-			fl.files[face.Path] = blob
+			fl.files[key.file] = blob
 		}
 		hbface = harfbuzz.NewFace(blob, face.Index)
 		if fl.faces == nil {
-			fl.faces = make(map[Face]*harfbuzz.Face)
+			fl.faces = make(map[faceKey]*harfbuzz.Face)
 		}
-		fl.faces[face] = hbface
+		fl.faces[key] = hbface
 	}
 
 	font := harfbuzz.NewFont(hbface)
@@ -261,7 +289,9 @@ type Style struct {
 
 	// The font size, in logical pixels.
 	//
-	// The font size specifies the number of logical pixels in one em.
+	// Glyphs are rendered at twice the font size, that is, one em spans
+	// 2*FontSize logical pixels. Use FontSizeForEm to compute the font size
+	// for a given size of the em.
 	FontSize   maybe.Option[float64]
 	FontWeight maybe.Option[float64]
 	FontWidth  maybe.Option[float64]
@@ -282,6 +312,16 @@ type Style struct {
 	Overflow            maybe.Option[Overflow]
 }
 
+// pxPerEmPerFontSize is the number of logical pixels in one em per logical
+// pixel of Style.FontSize.
+const pxPerEmPerFontSize = 2
+
+// FontSizeForEm returns the value of Style.FontSize at which one em spans px
+// logical pixels.
+func FontSizeForEm(px float64) float64 {
+	return px / pxPerEmPerFontSize
+}
+
 type runStyle struct {
 	FontFamilies maybe.Option[[]string]
 	FontSize     maybe.Option[float64]
@@ -392,6 +432,12 @@ type ParagraphStyle struct {
 	TextHeightBehavior HeightBehavior
 	// StrutStyle // XXX
 	Ellipsis string
+	// The algorithm for choosing line breaks.
+	LineBreaking LineBreaking
+	// Whether to hyphenate words, using the patterns registered with
+	// RegisterHyphenation for the text's language. Soft hyphens (U+00AD) are
+	// always honored.
+	Hyphenate bool
 }
 
 type Paragraph struct {
@@ -399,10 +445,17 @@ type Paragraph struct {
 	text  text
 	lines []line
 
+	// The width passed to Layout and the resulting height.
+	width, height float64
+	longestLine   float64
+
 	infos   [][]harfbuzz.GlyphInfo
 	poss    [][]harfbuzz.GlyphPosition
 	extents [][]harfbuzz.GlyphExtents
 	lb      linebreak.Result
+	breaker breaker
+	// The grapheme cluster boundaries of the text.
+	graphemes bitset
 
 	// OPT(dh): this only caches recordings, not sparse strips.
 	filledGlyphCache map[filledGlyphCacheKey]gfx.Recording
@@ -414,12 +467,18 @@ type filledGlyphCacheKey struct {
 	color color.Color
 }
 
-// func (p *Paragraph) Width() float64               {}
-// func (p *Paragraph) Height() float64              {}
+// Width returns the maximum width that the paragraph was laid out with.
+func (p *Paragraph) Width() float64 { return p.width }
+
+// Height returns the total height of all lines.
+func (p *Paragraph) Height() float64 { return p.height }
+
+// LongestLine returns the width of the widest line.
+func (p *Paragraph) LongestLine() float64 { return p.longestLine }
+
 // func (p *Paragraph) ExceededMaxLines() bool       {}
 // func (p *Paragraph) AlphabeticBaseline() float64  {}
 // func (p *Paragraph) IdeographicBaseline() float64 {}
-// func (p *Paragraph) LongestLine() float64         {}
 
 func (p *Paragraph) NumLines() int {
 	return len(p.lines)
@@ -427,69 +486,123 @@ func (p *Paragraph) NumLines() int {
 
 // func (p *Paragraph) LineMetrics() []LineMetrics {}
 
-// func (p *Paragraph) PlaceholderBoxes() []TextBox  {}
-
 type line struct {
 	start, end int
 	width      float64
 	runs       []run
+
+	// The following fields are computed by positionLines.
+
+	// The position of the baseline, and the maximum ascender, descender and
+	// line gap of all runs. Descender is negative for descenders below the
+	// baseline.
+	baseline                 float64
+	ascender, descender, gap float64
+	// The indices of runs in visual order, in the order in which they are
+	// laid out, starting at the paragraph's start edge.
+	order []int
+	// The position of the pen at the start of the line. This is the line's
+	// left edge for left-to-right paragraphs and its right edge for
+	// right-to-left paragraphs.
+	x float64
+	// The backgrounds and decorations of the line, collected the first time
+	// the line is painted.
+	deco *lineDecorations
 }
 
 func (p *Paragraph) shapeRuns(buf *harfbuzz.Buffer, runs []run) ([][]harfbuzz.GlyphInfo, [][]harfbuzz.GlyphPosition) {
 	info := make([][]harfbuzz.GlyphInfo, 0, len(runs))
 	pos := make([][]harfbuzz.GlyphPosition, 0, len(runs))
 	for runIdx := range runs {
-		run := &runs[runIdx]
-		buf.Reset()
-		var flags harfbuzz.BufferFlags
-		if run.Start == 0 {
-			flags |= harfbuzz.BufferFlagsBOT
-		}
-		if run.End == len(p.text.runes) {
-			flags |= harfbuzz.BufferFlagsEOT
-		}
-		buf.SetFlags(flags)
-		buf.SetClusterLevel(harfbuzz.ClusterLevelMonotoneCharacters)
-		if lang, ok := run.runStyle.Language.Get(); ok {
-			buf.SetLanguage(harfbuzz.LanguageFromString(lang.String()))
-		}
-		switch dir := run.Direction(); dir {
-		case bidi.LeftToRight:
-			buf.SetDirection(harfbuzz.LTR)
-		case bidi.RightToLeft:
-			buf.SetDirection(harfbuzz.RTL)
-		default:
-			panic(fmt.Sprintf("unhandled direction %v", dir))
-		}
-		buf.SetScript(opentype.Tag(run.script.String()))
-		buf.AddRunes(p.text.runes, run.Start, run.End-run.Start)
-		buf.GuessSegmentProperties()
-		// XXX handle runs with no fonts
-
-		// OPT it'd probably be better to create proper ranges of font features,
-		// instead of setting them for each rune.
-		var features []harfbuzz.Feature
-		for runeIdx, runeStyle := range run.runeStyles {
-			runeFeatures, _ := runeStyle.FontFeatures.Get()
-			for _, ft := range runeFeatures {
-				features = append(features, harfbuzz.Feature{
-					Tag:   ft.Feature,
-					Value: ft.Value,
-					Start: runeIdx,
-					End:   runeIdx + 1,
-				})
-			}
-		}
-		harfbuzz.Shape(run.font.hb, buf, features)
+		gi, gp := p.shapeRun(buf, &runs[runIdx], p.text.runes, 0, 0)
+		info = append(info, gi)
+		pos = append(pos, gp)
+	}
+	return info, pos
+}
 
-		if run.Direction() == bidi.RightToLeft {
-			buf.ReverseClusters()
+// shapeRun shapes a single run. text is the part of the paragraph's text that
+// is visible to the shaper as context, starting at the rune with index
+// textStart, and must contain the run. If hyphen isn't zero, it is shaped as
+// if it followed the run's last rune, and its glyphs are attributed to that
+// rune. The returned glyphs' clusters are indices into the paragraph's text.
+func (p *Paragraph) shapeRun(buf *harfbuzz.Buffer, run *run, text []rune, textStart int, hyphen rune) ([]harfbuzz.GlyphInfo, []harfbuzz.GlyphPosition) {
+	if run.placeholder != nil {
+		return []harfbuzz.GlyphInfo{{Cluster: int32(run.Start)}},
+			[]harfbuzz.GlyphPosition{{
+				XAdvance: int32(math.Round(run.placeholder.Width / run.scaleFactor())),
+			}}
+	}
+
+	offset := run.Start - textStart
+	length := run.End - run.Start
+	if hyphen != 0 {
+		// Don't let the shaper see any of the text following the hyphen.
+		end := run.End - textStart
+		text = append(text[:end:end], hyphen)
+		length++
+	}
+
+	buf.Reset()
+	var flags harfbuzz.BufferFlags
+	if run.Start == textStart {
+		flags |= harfbuzz.BufferFlagsBOT
+	}
+	if offset+length == len(text) {
+		flags |= harfbuzz.BufferFlagsEOT
+	}
+	buf.SetFlags(flags)
+	buf.SetClusterLevel(harfbuzz.ClusterLevelMonotoneCharacters)
+	if lang, ok := run.runStyle.Language.Get(); ok {
+		buf.SetLanguage(harfbuzz.LanguageFromString(lang.String()))
+	}
+	switch dir := run.Direction(); dir {
+	case bidi.LeftToRight:
+		buf.SetDirection(harfbuzz.LTR)
+	case bidi.RightToLeft:
+		buf.SetDirection(harfbuzz.RTL)
+	default:
+		panic(fmt.Sprintf("unhandled direction %v", dir))
+	}
+	buf.SetScript(opentype.Tag(run.script.String()))
+	buf.AddRunes(text, offset, length)
+	buf.GuessSegmentProperties()
+	// XXX handle runs with no fonts
+
+	// OPT it'd probably be better to create proper ranges of font features,
+	// instead of setting them for each rune.
+	var features []harfbuzz.Feature
+	for runeIdx, runeStyle := range run.runeStyles[:run.End-run.Start] {
+		runeFeatures, _ := runeStyle.FontFeatures.Get()
+		for _, ft := range runeFeatures {
+			// Features apply to clusters, which are indices into text.
+			features = append(features, harfbuzz.Feature{
+				Tag:   ft.Feature,
+				Value: ft.Value,
+				Start: offset + runeIdx,
+				End:   offset + runeIdx + 1,
+			})
 		}
+	}
+	harfbuzz.Shape(run.font.hb, buf, features)
 
-		info = append(info, slices.Clone(buf.GlyphInfos()))
-		pos = append(pos, slices.Clone(buf.GlyphPositions()))
+	if run.Direction() == bidi.RightToLeft {
+		buf.ReverseClusters()
 	}
 
+	info := slices.Clone(buf.GlyphInfos())
+	if textStart != 0 || hyphen != 0 {
+		for i := range info {
+			c := int(info[i].Cluster) + textStart
+			if c >= run.End {
+				// The hyphen belongs to the last rune.
+				c = run.End - 1
+			}
+			info[i].Cluster = int32(c)
+		}
+	}
+	pos := slices.Clone(buf.GlyphPositions())
+	p.applySpacing(run, info, pos)
 	return info, pos
 }
 
@@ -549,6 +662,14 @@ func (p *Paragraph) init() {
 		panic("unexpected mandatory line breaks")
 	}
 
+	var seg segmenter.Segmenter
+	seg.Init(p.text.runes)
+	p.graphemes = newBitset(len(p.text.runes) + 1)
+	for it := seg.GraphemeIterator(); it.Next(); {
+		p.graphemes.set(it.Grapheme().Offset)
+	}
+	p.graphemes.set(len(p.text.runes))
+
 	buf := harfbuzz.NewBuffer()
 	defer buf.Destroy()
 
@@ -556,8 +677,20 @@ func (p *Paragraph) init() {
 	extents := make([][]harfbuzz.GlyphExtents, len(p.infos))
 	for i, infos := range p.infos {
 		extents[i] = make([]harfbuzz.GlyphExtents, len(infos))
+		run := &p.text.runs[i]
+		if run.placeholder != nil {
+			// Placeholders are treated as ink covering their entire box.
+			ascent, descent := run.placeholderExtents()
+			scale := run.scaleFactor()
+			extents[i][0] = harfbuzz.GlyphExtents{
+				YBearing: int32(math.Round(ascent / scale)),
+				Width:    p.poss[i][0].XAdvance,
+				Height:   -int32(math.Round((ascent + descent) / scale)),
+			}
+			continue
+		}
 		for j, info := range infos {
-			extents[i][j], _ = p.text.runs[i].font.hb.GlyphExtents(info.Codepoint)
+			extents[i][j], _ = run.font.hb.GlyphExtents(info.Codepoint)
 		}
 	}
 	p.extents = extents
@@ -583,144 +716,135 @@ func (bs bitset) set(idx int) {
 	bs[idx/64] |= 1 << (idx % 64)
 }
 
-func (p *Paragraph) Layout(maxWidth float64) {
-	// OPT don't do any line breaking if the entire paragraph fits on one line
-
-	// safeToBreaks tracks for each rune whether breaking right before it is
-	// safe.
-	safeToBreaks := newBitset(len(p.text.runes) + 1)
-	for _, run := range p.text.runs {
-		for _, glyph := range run.glyphs {
-			if glyph.Flags()&harfbuzz.GlyphFlagsUnsafeToBreak == 0 {
-				safeToBreaks.set(int(glyph.Cluster))
-			}
-		}
+// positionLines computes the vertical metrics and the visual order of runs
+// of all lines, as well as the boxes of placeholders.
+func (p *Paragraph) positionLines() {
+	y := 0.0
+	p.longestLine = 0
+	for i := range p.lines {
+		p.longestLine = max(p.longestLine, p.lines[i].width)
 	}
-	safeToBreaks.set(len(p.text.runes))
-
-	// safeWidths tracks for each rune the width of the text if we broke right
-	// before the rune, assuming it is a safe break.
-	safeWidths := make([]float64, len(p.text.runes)+1)
-	origin := 0.0
-	rightmost := 0.0
-	for _, run := range p.text.runs {
-		upem := run.font.hb.Face().UPEM()
-		pxPerEm := run.runStyle.FontSize.UnwrapOr(0)
-		scale := (2 * pxPerEm) / float64(upem)
-		for i, info := range run.glyphs {
-			extents := run.extents[i]
-			pos := run.glyphPos[i]
-			// FIXME this doesn't take the added width from stroking
-			// into consideration
-
-			// Only extent the width if we've actually put down any ink.
-			// This allows lines to have trailing whitespace.
-			rightmost = max(rightmost, origin+float64(pos.XOffset+extents.XBearing+extents.Width)*scale)
-			safeWidths[info.Cluster+1] = rightmost
-			origin += float64(pos.XAdvance) * scale
-		}
+	// The width to align lines in.
+	avail := p.width
+	if math.IsInf(avail, 1) {
+		avail = p.longestLine
 	}
-
-	// The start of the current line
-	start := 0
-	clear(p.lines)
-	lines := p.lines[:0]
-	for start < len(p.text.runes) {
-		var curBestCandidate line
-		for i := start + 1; i < len(p.lb.Breaks); i++ {
-			if !p.lb.Breaks[i] {
+	for i := range p.lines {
+		l := &p.lines[i]
+		l.ascender, l.descender, l.gap = 0, 0, 0
+		for j := range l.runs {
+			run := &l.runs[j]
+			if run.placeholder != nil {
+				ascent, descent := run.placeholderExtents()
+				l.ascender = max(l.ascender, ascent)
+				l.descender = min(l.descender, -descent)
 				continue
 			}
-
-			if safeToBreaks.get(i) {
-				width := safeWidths[i] - safeWidths[start]
-				if width <= maxWidth {
-					if width >= curBestCandidate.width {
-						curBestCandidate = line{
-							start: start,
-							end:   i,
-							width: width,
-						}
-					}
-				}
-			} else {
-				panic("unimplemented")
-			}
-		}
-
-		// XXX if all lines were too long, fall back to doing something.
-
-		if curBestCandidate.runs == nil {
-			curBestCandidate.runs = p.runsForInterval(curBestCandidate.start, curBestCandidate.end)
-		}
-		lines = append(lines, curBestCandidate)
-		start = curBestCandidate.end
-	}
-
-	p.lines = lines
-}
-
-func (p *Paragraph) Paint(rec gfx.Recorder) {
-	rec = rec.Checkpoint()
-
-	// XXX figure out where this should get called from
-	// XXX get actual available width
-	const maxWidth = 601
-	p.Layout(maxWidth)
-	lines := p.lines
-
-	y := 0.0
-	for _, line := range lines {
-		var maxAscender, maxDescender, maxGap float64
-		for _, run := range line.runs {
 			hz, ok := run.font.hb.HorizontalExtents()
 			if !ok {
 				// TODO what would we even do if the font doesn't have valid
 				// metrics?
 			}
-			upem := run.font.hb.Face().UPEM()
-			pxPerEm := run.runStyle.FontSize.UnwrapOr(0)
-			scaleFactor := (2 * pxPerEm) / float64(upem)
-			maxAscender = max(maxAscender, float64(hz.Ascender)*scaleFactor)
-			maxDescender = min(maxDescender, float64(hz.Descender)*scaleFactor)
-			maxGap = max(maxGap, float64(hz.LineGap)*scaleFactor)
+			scaleFactor := run.scaleFactor()
+			l.ascender = max(l.ascender, float64(hz.Ascender)*scaleFactor)
+			l.descender = min(l.descender, float64(hz.Descender)*scaleFactor)
+			l.gap = max(l.gap, float64(hz.LineGap)*scaleFactor)
 		}
 
-		y += maxAscender
+		y += l.ascender
 		// Align baseline with logical pixel grid.
 		y = math.Round(y)
+		l.baseline = y
 
 		// XXX this isn't running L1 of the bidi algorithm, which is important for
 		// trailing whitespace
-		//
-		// OPT don't do this work every time we paint. this essentially belongs
-		// to Layout.
-		runs := line.runs
-		indices := make([]int, len(runs))
-
+		indices := make([]int, len(l.runs))
 		// OPT if we used SoA we could directly pass the runs to ReorderRuns, but
 		// AoS is simply more ergonomic. Maybe we can add a ReorderSeq and avoid
 		// allocating the slice that way?
 		bidiRuns := make([]bidi.Run, len(indices))
-		for i := range runs {
-			bidiRuns[i] = runs[i].Run
+		for j := range l.runs {
+			bidiRuns[j] = l.runs[j].Run
 		}
 		p.text.bidiParagraph.ReorderRuns(bidiRuns, indices)
-
-		var origin curve.Point
-		switch p.style.Direction {
-		case bidi.LeftToRight:
-			origin = curve.Pt(0, y)
-		case bidi.RightToLeft:
-			origin = curve.Pt(maxWidth, y)
+		if p.style.Direction == bidi.RightToLeft {
+			slices.Reverse(indices)
+		}
+		l.order = indices
+
+		l.x = p.lineStart(l, avail)
+		x := l.x
+		for _, idx := range l.order {
+			run := &l.runs[idx]
+			adv := run.advance()
+			x0 := x
+			if p.style.Direction == bidi.RightToLeft {
+				x -= adv
+				x0 = x
+			} else {
+				x += adv
+			}
+			if run.placeholder != nil {
+				ascent, descent := run.placeholderExtents()
+				run.placeholder.box = TextBox{
+					Rect:      curve.NewRectFromPoints(curve.Pt(x0, y-ascent), curve.Pt(x0+adv, y+descent)),
+					Direction: run.Direction(),
+				}
+			}
 		}
 
+		y += -l.descender
+		y += l.gap
+	}
+	p.height = y
+}
+
+// Paint paints the paragraph as it was laid out by the last call to Layout.
+// The paragraph must have been laid out.
+//
+// Paint caches the lines' decorations and the recordings of glyphs in the
+// paragraph. It is therefore not safe to call Paint concurrently, neither
+// with itself nor with Layout.
+func (p *Paragraph) Paint(rec gfx.Recorder) {
+	rec = rec.Checkpoint()
+	lines := p.lines
+
+	for lineIdx := range lines {
+		line := &lines[lineIdx]
+		y := line.baseline
+		maxAscender, maxDescender := line.ascender, line.descender
+		runs := line.runs
+
+		origin := curve.Pt(line.x, y)
+
+		lineTop, lineBottom := y-maxAscender, y-maxDescender
+		// Decorations only change when the paragraph is laid out again, and
+		// skipping ink is expensive, so we collect them once per layout.
+		deco := line.deco
+		collect := deco == nil
+		if collect {
+			deco = new(lineDecorations)
+			line.deco = deco
+		}
+		// Glyphs are drawn into a separate recording so that backgrounds and
+		// underlines, which we only know after processing all glyphs, can be
+		// drawn behind them.
+		glyphsRec := gfx.NewRecorder()
 		do := func(runIdx int) {
 			run := runs[runIdx]
 
-			upem := run.font.hb.Face().UPEM()
-			pxPerEm := run.runStyle.FontSize.UnwrapOr(0)
-			scaleFactor := (2 * pxPerEm) / float64(upem)
+			if run.placeholder != nil {
+				// Placeholders are painted by the user of the paragraph, using
+				// PlaceholderBoxes.
+				if p.style.Direction == bidi.RightToLeft {
+					origin.X -= run.advance()
+				} else {
+					origin.X += run.advance()
+				}
+				return
+			}
+
+			scaleFactor := run.scaleFactor()
 			scale := curve.Scale(scaleFactor, scaleFactor)
 
 			for i := range run.Glyphs(p.style.Direction) {
@@ -755,9 +879,9 @@ func (p *Paragraph) Paint(rec gfx.Recorder) {
 						glyphRec = glyphScene.Finish()
 						p.filledGlyphCache[key] = glyphRec
 					}
-					rec.PushTransform(scale.ThenTranslate(curve.Vec2(glyphOffset)))
-					rec.PlayRecording(glyphRec)
-					rec.PopTransform()
+					glyphsRec.PushTransform(scale.ThenTranslate(curve.Vec2(glyphOffset)))
+					glyphsRec.PlayRecording(glyphRec)
+					glyphsRec.PopTransform()
 				}
 
 				if stroke, ok := run.runeStyles[int(glyph.Cluster)-run.Start].Stroke.Get(); ok {
@@ -778,11 +902,11 @@ func (p *Paragraph) Paint(rec gfx.Recorder) {
 						gfx.Solid(stroke.Color),
 					)
 					glyphScene.PopTransform()
-					rec.PlayRecording(glyphScene.Finish())
+					glyphsRec.PlayRecording(glyphScene.Finish())
 				}
 
 				if debugText {
-					rec.Fill(
+					glyphsRec.Fill(
 						curve.Circle{
 							Center: glyphOffset,
 							Radius: 1.5,
@@ -799,7 +923,7 @@ func (p *Paragraph) Paint(rec gfx.Recorder) {
 					if glyph.Flags()&harfbuzz.GlyphFlagsUnsafeToBreak != 0 {
 						c = color.Make(color.SRGB, 0, 0, 1, 0.5)
 					}
-					rec.Fill(
+					glyphsRec.Fill(
 						bbox,
 						gfx.Solid(c),
 					)
@@ -810,11 +934,21 @@ func (p *Paragraph) Paint(rec gfx.Recorder) {
 					origin = origin.Translate(curve.Vec2(curve.Pt(float64(pos.XAdvance), 0).Transform(scale)))
 				}
 
+				if collect {
+					deco.add(oldOrigin.X, origin.X, y, lineTop, lineBottom, style, run.font, scaleFactor, inkGlyph{
+						font:      run.font,
+						gid:       glyph.Codepoint,
+						transform: scale.ThenTranslate(curve.Vec2(glyphOffset)),
+						top:       glyphOffset.Y - float64(extents.YBearing)*scaleFactor,
+						bottom:    glyphOffset.Y - float64(extents.YBearing+extents.Height)*scaleFactor,
+					})
+				}
+
 				if debugText {
 					p0 := oldOrigin
 					p1 := origin
 					p1.Y += 5
-					rec.Stroke(
+					glyphsRec.Stroke(
 						curve.NewRectFromPoints(curve.Point(p0), curve.Point(p1)),
 						curve.DefaultStroke.WithJoin(curve.MiterJoin),
 						gfx.Solid(color.Make(color.SRGB, 1, 0, 0, 1)),
@@ -823,15 +957,80 @@ func (p *Paragraph) Paint(rec gfx.Recorder) {
 			}
 		}
 
-		if p.style.Direction == bidi.RightToLeft {
-			slices.Reverse(indices)
-		}
-		for _, idx := range indices {
+		for _, idx := range line.order {
 			do(idx)
 		}
+		deco.paintBackgrounds(rec)
+		deco.paintUnder(rec)
+		rec.PlayRecording(glyphsRec.Finish())
+		deco.paintOver(rec)
+	}
+}
+
+// PositionedGlyph is a glyph of a laid out paragraph.
+type PositionedGlyph struct {
+	Font *Font
+	ID   int32
+	// Transform maps the glyph's outline, as returned by Font.GlyphOutline,
+	// to the paragraph's coordinate space.
+	Transform curve.Affine
+	// The position of the glyph's origin on its line's baseline, and its
+	// advance, in logical pixels.
+	Origin  curve.Point
+	Advance float64
+	// The index of the first rune of the glyph's cluster.
+	Cluster int
+	// The index of the glyph's line.
+	Line int
+}
+
+// Glyphs returns the paragraph's glyphs, line by line and in visual order,
+// positioned like Paint paints them. The paragraph must have been laid out.
+func (p *Paragraph) Glyphs() iter.Seq[PositionedGlyph] {
+	return func(yield func(PositionedGlyph) bool) {
+		for lineIdx, line := range p.lines {
+			origin := curve.Pt(line.x, line.baseline)
+			for _, idx := range line.order {
+				run := &line.runs[idx]
+				if run.placeholder != nil {
+					if p.style.Direction == bidi.RightToLeft {
+						origin.X -= run.advance()
+					} else {
+						origin.X += run.advance()
+					}
+					continue
+				}
 
-		y += -maxDescender
-		y += maxGap
+				scaleFactor := run.scaleFactor()
+				scale := curve.Scale(scaleFactor, scaleFactor)
+				for i := range run.Glyphs(p.style.Direction) {
+					glyph := run.glyphs[i]
+					pos := run.glyphPos[i]
+					adv := float64(pos.XAdvance) * scaleFactor
+					if p.style.Direction == bidi.RightToLeft {
+						origin.X -= adv
+					}
+					glyphOffset := origin.Translate(
+						curve.Vec(float64(pos.XOffset), -float64(pos.YOffset)).
+							Mul(scaleFactor))
+					pg := PositionedGlyph{
+						Font:      run.font,
+						ID:        glyph.Codepoint,
+						Transform: scale.ThenTranslate(curve.Vec2(glyphOffset)),
+						Origin:    origin,
+						Advance:   adv,
+						Cluster:   int(glyph.Cluster),
+						Line:      lineIdx,
+					}
+					if !yield(pg) {
+						return
+					}
+					if p.style.Direction == bidi.LeftToRight {
+						origin.X += adv
+					}
+				}
+			}
+		}
 	}
 }
 
@@ -1028,7 +1227,7 @@ type ParagraphBuilder struct {
 }
 
 type fontKey struct {
-	face       Face
+	face       faceKey
 	axisValues string
 }
 
@@ -1046,7 +1245,7 @@ func makeFontKey(face Face, axisValues map[opentype.Tag]float64) fontKey {
 	}
 
 	return fontKey{
-		face:       face,
+		face:       face.key(),
 		axisValues: s.String(),
 	}
 }
@@ -1091,6 +1290,7 @@ func (pb *ParagraphBuilder) startStyleRun() {
 		// The current run is empty, so just change its style
 		pb.text.runs[len(pb.text.runs)-1].runStyle = pb.currentRunStyle
 		if len(pb.text.runs) > 1 &&
+			pb.text.runs[len(pb.text.runs)-2].placeholder == nil &&
 			pb.text.runs[len(pb.text.runs)-2].runStyle.Equal(&pb.currentRunStyle) {
 			// Merge two runs with identical style
 			pb.text.runs = pb.text.runs[:len(pb.text.runs)-1]
@@ -1098,7 +1298,7 @@ func (pb *ParagraphBuilder) startStyleRun() {
 		return
 	}
 
-	if pb.currentRunStyle.Equal(&last.runStyle) {
+	if last.placeholder == nil && pb.currentRunStyle.Equal(&last.runStyle) {
 		return
 	}
 
@@ -1146,11 +1346,32 @@ type run struct {
 	// memory usage for this slice.
 	runeStyles []*Style
 
+	// The placeholder represented by this run, if any. Placeholder runs
+	// consist of a single rune and aren't shaped.
+	placeholder *placeholder
+
 	glyphs   []harfbuzz.GlyphInfo
 	glyphPos []harfbuzz.GlyphPosition
 	extents  []harfbuzz.GlyphExtents
 }
 
+// scaleFactor returns the factor for converting from font units to logical
+// pixels.
+func (r *run) scaleFactor() float64 {
+	upem := r.font.hb.Face().UPEM()
+	pxPerEm := pxPerEmPerFontSize * r.runStyle.FontSize.UnwrapOr(0)
+	return pxPerEm / float64(upem)
+}
+
+// advance returns the total advance of the run's glyphs, in logical pixels.
+func (r *run) advance() float64 {
+	var adv int32
+	for i := range r.Glyphs(r.Direction()) {
+		adv += r.glyphPos[i].XAdvance
+	}
+	return float64(adv) * r.scaleFactor()
+}
+
 func (r *run) Glyphs(mainDir bidi.Direction) iter.Seq[int] {
 	if r.Direction() == mainDir {
 		return func(yield func(int) bool) {
@@ -1190,7 +1411,8 @@ type text struct {
 	runes         []rune
 	bidiParagraph bidi.Paragraph
 
-	runs []run
+	runs         []run
+	placeholders []*placeholder
 }
 
 func (pb *ParagraphBuilder) Build(fdb *fontdb.Faces, fl *FontLoader) *Paragraph {
@@ -0,0 +1,431 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package text
+
+import (
+	"math"
+	"math/bits"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"honnef.co/go/color"
+	"honnef.co/go/curve"
+	"honnef.co/go/gutter/fontdb"
+	"honnef.co/go/gutter/gfx"
+	xlanguage "honnef.co/go/gutter/internal/language"
+	"honnef.co/go/gutter/text/hyphen"
+	"honnef.co/go/stuff/container/maybe"
+)
+
+func TestParagraphGlyphs(t *testing.T) {
+	fonts := fontdb.NewEmpty()
+	if err := fonts.AddData("Go-Regular.ttf", goregular.TTF); err != nil {
+		t.Fatal(err)
+	}
+	pb := NewParagraphBuilder(&ParagraphStyle{})
+	pb.PushStyle(&Style{
+		FontFamilies: maybe.Some([]string{"Go"}),
+		FontSize:     maybe.Some(10.0),
+	})
+	pb.AddString("Hi Hi")
+	pb.PopStyle()
+	var fl FontLoader
+	p := pb.Build(fonts, &fl)
+
+	// Glyphs are scaled by twice the font size. The Go font has 2048 units
+	// per em, and H, i, and space advance by 1479, 505, and 569 units.
+	const scale = 2 * 10 / 2048.0
+	advances := map[int]float64{0: 1479, 1: 505, 2: 569, 3: 1479, 4: 505}
+
+	tests := []struct {
+		width float64
+		// The line of each glyph.
+		lines []int
+	}{
+		{math.Inf(1), []int{0, 0, 0, 0, 0}},
+		// The second word doesn't fit on the first line.
+		{30, []int{0, 0, 0, 1, 1}},
+	}
+	for _, tt := range tests {
+		p.Layout(tt.width)
+		glyphs := slices.Collect(p.Glyphs())
+		if len(glyphs) != 5 {
+			t.Fatalf("width %g: got %d glyphs, want 5", tt.width, len(glyphs))
+		}
+		var x float64
+		for i, g := range glyphs {
+			if i > 0 && g.Line != glyphs[i-1].Line {
+				x = 0
+				if g.Origin.Y <= glyphs[i-1].Origin.Y {
+					t.Errorf("width %g: line %d isn't below the previous line", tt.width, g.Line)
+				}
+			}
+			if g.Cluster != i || g.Line != tt.lines[i] {
+				t.Errorf("width %g: glyph %d has cluster %d on line %d, want cluster %d on line %d",
+					tt.width, i, g.Cluster, g.Line, i, tt.lines[i])
+			}
+			if want := advances[i] * scale; math.Abs(g.Advance-want) > 1e-6 {
+				t.Errorf("width %g: glyph %d advances by %g, want %g", tt.width, i, g.Advance, want)
+			}
+			if math.Abs(g.Origin.X-x) > 1e-6 {
+				t.Errorf("width %g: glyph %d is at x = %g, want %g", tt.width, i, g.Origin.X, x)
+			}
+			want := curve.Scale(scale, scale).ThenTranslate(curve.Vec2(g.Origin))
+			if g.Transform != want {
+				t.Errorf("width %g: glyph %d has transform %v, want %v", tt.width, i, g.Transform, want)
+			}
+			x += g.Advance
+		}
+	}
+}
+
+func TestDecorationCache(t *testing.T) {
+	fonts := fontdb.NewEmpty()
+	if err := fonts.AddData("Go-Regular.ttf", goregular.TTF); err != nil {
+		t.Fatal(err)
+	}
+	pb := NewParagraphBuilder(&ParagraphStyle{})
+	pb.PushStyle(&Style{
+		FontFamilies: maybe.Some([]string{"Go"}),
+		FontSize:     maybe.Some(10.0),
+		Fill:         maybe.Some(color.Make(color.SRGB, 0, 0, 0, 1)),
+		Decoration:   maybe.Some(DecorationUnderline),
+	})
+	// The descenders of g and y cross the underline.
+	pb.AddString("gyp gyp")
+	pb.PopStyle()
+	var fl FontLoader
+	p := pb.Build(fonts, &fl)
+
+	paint := func() gfx.Recording {
+		rec := gfx.NewRecorder()
+		p.Paint(rec)
+		return rec.Finish()
+	}
+	p.Layout(math.Inf(1))
+	first := paint()
+	if p.lines[0].deco == nil {
+		t.Fatal("decorations weren't cached")
+	}
+	spans := p.lines[0].deco.spans[bits.TrailingZeros(uint(DecorationUnderline))]
+	if len(spans) != 1 || !spans[0].haveSegs || len(spans[0].segs) < 2 {
+		t.Fatalf("got underline spans %v, want a single span with ink gaps", spans)
+	}
+	if second := paint(); !reflect.DeepEqual(first, second) {
+		t.Error("painting with cached decorations differs")
+	}
+
+	// Laying out again discards the cache. Each word is about 32 pixels wide.
+	p.Layout(40)
+	for i, l := range p.lines {
+		if l.deco != nil {
+			t.Errorf("line %d kept its decorations", i)
+		}
+	}
+	paint()
+	if p.lines[1].deco == nil {
+		t.Error("decorations of the new lines weren't cached")
+	}
+}
+
+func TestJustify(t *testing.T) {
+	fonts := fontdb.NewEmpty()
+	if err := fonts.AddData("Go-Regular.ttf", goregular.TTF); err != nil {
+		t.Fatal(err)
+	}
+	build := func(s string) *Paragraph {
+		pb := NewParagraphBuilder(&ParagraphStyle{Alignment: AlignmentJustify})
+		pb.PushStyle(&Style{
+			FontFamilies: maybe.Some([]string{"Go"}),
+			FontSize:     maybe.Some(10.0),
+		})
+		pb.AddString(s)
+		pb.PopStyle()
+		var fl FontLoader
+		return pb.Build(fonts, &fl)
+	}
+
+	// "Hi Hi" is about 44 pixels wide, so the last word goes on the second
+	// line.
+	p := build("Hi Hi Hi")
+	p.Layout(50)
+	if len(p.lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(p.lines))
+	}
+	if w := p.lines[0].width; math.Abs(w-50) > 0.01 {
+		t.Errorf("justified line is %g pixels wide, want 50", w)
+	}
+	// The space absorbs all of the extra width.
+	glyphs := slices.Collect(p.Glyphs())
+	if x := glyphs[3].Origin.X; math.Abs(x+(1479+505)*2*10/2048.0-50) > 0.01 {
+		t.Errorf("second word starts at %g, want it to end at 50", x)
+	}
+	// The last line isn't justified.
+	if w, want := p.lines[1].width, (1479+505)*2*10/2048.0; math.Abs(w-want) > 1e-6 {
+		t.Errorf("last line is %g pixels wide, want %g", w, want)
+	}
+}
+
+// lineBounds returns the start and end of each of the paragraph's lines.
+func lineBounds(p *Paragraph) [][2]int {
+	var out [][2]int
+	for _, l := range p.lines {
+		out = append(out, [2]int{l.start, l.end})
+	}
+	return out
+}
+
+func TestLineBreaking(t *testing.T) {
+	fonts := fontdb.NewEmpty()
+	if err := fonts.AddData("Go-Regular.ttf", goregular.TTF); err != nil {
+		t.Fatal(err)
+	}
+	build := func(s string, lb LineBreaking) *Paragraph {
+		pb := NewParagraphBuilder(&ParagraphStyle{LineBreaking: lb})
+		pb.PushStyle(&Style{
+			FontFamilies: maybe.Some([]string{"Go"}),
+			FontSize:     maybe.Some(10.0),
+		})
+		pb.AddString(s)
+		pb.PopStyle()
+		var fl FontLoader
+		return pb.Build(fonts, &fl)
+	}
+
+	// Widths are in font units, which are 2*10/2048 pixels. a, b and d
+	// advance by 1139 units, c by 1024 and space by 569.
+	const unit = 2 * 10 / 2048.0
+	tests := []struct {
+		text  string
+		width float64
+		lb    LineBreaking
+		lines [][2]int
+	}{
+		// Greedy breaking fills the first line, leaving the second one
+		// mostly empty.
+		{"aaa bb cc ddddd", 6400, LineBreakingGreedy, [][2]int{{0, 7}, {7, 10}, {10, 15}}},
+		// Optimal breaking balances the first two lines.
+		{"aaa bb cc ddddd", 6400, LineBreakingOptimal, [][2]int{{0, 4}, {4, 10}, {10, 15}}},
+		// A word that doesn't fit on a line of its own is broken at grapheme
+		// boundaries, but words that fit aren't.
+		{"aa bbbbbbbbbb", 5000, LineBreakingGreedy, [][2]int{{0, 3}, {3, 7}, {7, 11}, {11, 13}}},
+		{"aa bbbbbbbbbb", 5000, LineBreakingOptimal, [][2]int{{0, 3}, {3, 7}, {7, 11}, {11, 13}}},
+		// Text that fits isn't broken.
+		{"aaa bb cc ddddd", 20000, LineBreakingOptimal, [][2]int{{0, 15}}},
+	}
+	for _, tt := range tests {
+		p := build(tt.text, tt.lb)
+		p.Layout(tt.width * unit)
+		if got := lineBounds(p); !slices.Equal(got, tt.lines) {
+			t.Errorf("%q at width %g with %v: got lines %v, want %v", tt.text, tt.width, tt.lb, got, tt.lines)
+		}
+		for i, l := range p.lines {
+			if l.width > tt.width*unit {
+				t.Errorf("%q at width %g with %v: line %d is %g units wide", tt.text, tt.width, tt.lb, i, l.width/unit)
+			}
+		}
+	}
+
+	// The width of a line excludes its trailing space.
+	p := build("aaa bb cc ddddd", LineBreakingOptimal)
+	p.Layout(6400 * unit)
+	if w, want := p.lines[0].width, 3*1139*unit; math.Abs(w-want) > 1e-6 {
+		t.Errorf("first line is %g pixels wide, want %g", w, want)
+	}
+}
+
+func TestHyphenation(t *testing.T) {
+	fonts := fontdb.NewEmpty()
+	if err := fonts.AddData("Go-Regular.ttf", goregular.TTF); err != nil {
+		t.Fatal(err)
+	}
+	build := func(s string, hyphenate bool) *Paragraph {
+		pb := NewParagraphBuilder(&ParagraphStyle{Hyphenate: hyphenate})
+		pb.PushStyle(&Style{
+			FontFamilies: maybe.Some([]string{"Go"}),
+			FontSize:     maybe.Some(10.0),
+			Language:     maybe.Some(xlanguage.MustParse("en-US")),
+		})
+		pb.AddString(s)
+		pb.PopStyle()
+		var fl FontLoader
+		return pb.Build(fonts, &fl)
+	}
+
+	// The patterns from The TEXbook, which hyphenate "hyphenation" as
+	// "hy-phen-ation".
+	pats, err := hyphen.Parse(strings.NewReader(`\patterns{hy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Text tagged as en-US uses the patterns registered for en.
+	if err := RegisterHyphenation("en", pats); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { RegisterHyphenation("en", nil) })
+	if err := RegisterHyphenation("not a language", pats); err == nil {
+		t.Error("invalid language tag didn't cause an error")
+	}
+
+	// "aaa hyphen-" is 11901 units wide and "aaa hyphenation" is 15206
+	// units wide. The Go font has no U+2010, so the hyphen is a
+	// hyphen-minus, which advances by 1196 units.
+	const unit = 2 * 10 / 2048.0
+	const width = 12000 * unit
+	p := build("aaa hyphenation", true)
+	p.Layout(width)
+	if got, want := lineBounds(p), [][2]int{{0, 10}, {10, 15}}; !slices.Equal(got, want) {
+		t.Fatalf("got lines %v, want %v", got, want)
+	}
+	if w, want := p.lines[0].width, 11901*unit; math.Abs(w-want) > 1e-6 {
+		t.Errorf("hyphenated line is %g pixels wide, want %g", w, want)
+	}
+	var first []PositionedGlyph
+	for g := range p.Glyphs() {
+		if g.Line == 0 {
+			first = append(first, g)
+		}
+	}
+	// The hyphen's glyph belongs to the last rune of the line.
+	if len(first) != 11 || first[10].Cluster != 9 || math.Abs(first[10].Advance-1196*unit) > 1e-6 {
+		t.Errorf("hyphenated line has glyphs %v, want 10 glyphs followed by a hyphen", first)
+	}
+
+	// Without hyphenation, the word moves to the next line.
+	p = build("aaa hyphenation", false)
+	p.Layout(width)
+	if got, want := lineBounds(p), [][2]int{{0, 4}, {4, 15}}; !slices.Equal(got, want) {
+		t.Errorf("without hyphenation, got lines %v, want %v", got, want)
+	}
+
+	// Neither do paragraphs built after removing the patterns.
+	RegisterHyphenation("en", nil)
+	p = build("aaa hyphenation", true)
+	p.Layout(width)
+	if got, want := lineBounds(p), [][2]int{{0, 4}, {4, 15}}; !slices.Equal(got, want) {
+		t.Errorf("after removing the patterns, got lines %v, want %v", got, want)
+	}
+}
+
+func TestReshapeUnsafeBreaks(t *testing.T) {
+	fonts := fontdb.NewEmpty()
+	if err := fonts.AddData("Go-Regular.ttf", goregular.TTF); err != nil {
+		t.Fatal(err)
+	}
+	pb := NewParagraphBuilder(&ParagraphStyle{})
+	pb.PushStyle(&Style{
+		FontFamilies: maybe.Some([]string{"Go"}),
+		FontSize:     maybe.Some(10.0),
+	})
+	pb.AddString("aaa bb cc ddddd")
+	pb.PopStyle()
+	var fl FontLoader
+	p := pb.Build(fonts, &fl)
+
+	const width = 6400 * 2 * 10 / 2048.0
+	p.Layout(width)
+	wantLines := lineBounds(p)
+	var wantWidths []float64
+	for _, bp := range p.breaker.candidates {
+		wantWidths = append(wantWidths, p.lineWidth(0, bp))
+	}
+	if len(p.breaker.pieces) != 0 {
+		t.Fatalf("reshaped pieces %v even though all positions are safe", p.breaker.pieces)
+	}
+
+	// Pretend that only the paragraph's ends are safe to break at, which
+	// forces measuring lines by reshaping them. The Go font has no
+	// contextual shaping, so this mustn't change any widths.
+	n := len(p.text.runes)
+	p.breaker.safe = newBitset(n + 1)
+	p.breaker.safe.set(0)
+	p.breaker.safe.set(n)
+	for i, bp := range p.breaker.candidates {
+		if w := p.lineWidth(0, bp); math.Abs(w-wantWidths[i]) > 1e-6 {
+			t.Errorf("line ending at %d is %g pixels wide when reshaped, want %g", bp.pos, w, wantWidths[i])
+		}
+	}
+	if len(p.breaker.pieces) == 0 {
+		t.Error("lines weren't reshaped")
+	}
+	p.Layout(width)
+	if got := lineBounds(p); !slices.Equal(got, wantLines) {
+		t.Errorf("got lines %v, want %v", got, wantLines)
+	}
+	if p.breaker.buf != nil {
+		t.Error("Layout didn't destroy its buffer")
+	}
+}
+
+func TestPlaceholderBoxes(t *testing.T) {
+	fonts := fontdb.NewEmpty()
+	if err := fonts.AddData("Go-Regular.ttf", goregular.TTF); err != nil {
+		t.Fatal(err)
+	}
+
+	// The Go font's ascender and descender are 1935 and 432 units, and H
+	// advances by 1479 units.
+	const scale = 2 * 10 / 2048.0
+	const fontAscent, fontDescent = 1935 * scale, 432 * scale
+	const x0 = 1479 * scale
+
+	tests := []struct {
+		alignment PlaceholderAlignment
+		// The distances from the baseline to the top and the bottom of the
+		// placeholder.
+		ascent, descent float64
+	}{
+		{PlaceholderAlignmentBaseline, 25, 5},
+		{PlaceholderAlignmentAboveBaseline, 30, 0},
+		{PlaceholderAlignmentBelowBaseline, 0, 30},
+		{PlaceholderAlignmentTop, fontAscent, 30 - fontAscent},
+		{PlaceholderAlignmentBottom, 30 - fontDescent, fontDescent},
+		{PlaceholderAlignmentMiddle, 15 + (fontAscent-fontDescent)/2, 15 - (fontAscent-fontDescent)/2},
+	}
+	for _, tt := range tests {
+		pb := NewParagraphBuilder(&ParagraphStyle{})
+		pb.PushStyle(&Style{
+			FontFamilies: maybe.Some([]string{"Go"}),
+			FontSize:     maybe.Some(10.0),
+		})
+		pb.AddString("H")
+		pb.AddPlaceholder(Placeholder{
+			Width:          20,
+			Height:         30,
+			Alignment:      tt.alignment,
+			BaselineOffset: 25,
+		})
+		pb.AddString("H")
+		pb.PopStyle()
+		if n := pb.PlaceholderCount(); n != 1 {
+			t.Fatalf("got %d placeholders, want 1", n)
+		}
+		var fl FontLoader
+		p := pb.Build(fonts, &fl)
+		p.Layout(math.Inf(1))
+
+		// The line is tall enough for both the font and the placeholder.
+		baseline := math.Round(max(fontAscent, tt.ascent))
+		if p.lines[0].baseline != baseline {
+			t.Errorf("alignment %d: baseline is at %g, want %g", tt.alignment, p.lines[0].baseline, baseline)
+		}
+		boxes := p.PlaceholderBoxes()
+		if len(boxes) != 1 {
+			t.Fatalf("alignment %d: got %d boxes, want 1", tt.alignment, len(boxes))
+		}
+		want := curve.Rect{X0: x0, Y0: baseline - tt.ascent, X1: x0 + 20, Y1: baseline + tt.descent}
+		got := boxes[0].Rect
+		if math.Abs(got.X0-want.X0) > 1e-9 || math.Abs(got.Y0-want.Y0) > 1e-9 ||
+			math.Abs(got.X1-want.X1) > 1e-9 || math.Abs(got.Y1-want.Y1) > 1e-9 {
+			t.Errorf("alignment %d: got box %v, want %v", tt.alignment, got, want)
+		}
+		if start, end := boxes[0].Start(), boxes[0].End(); start != got.X0 || end != got.X1 {
+			t.Errorf("alignment %d: box goes from %g to %g, want %g to %g", tt.alignment, start, end, got.X0, got.X1)
+		}
+	}
+}
@@ -5,6 +5,10 @@
 package widgets
 
 import (
+	"maps"
+	"math"
+	"time"
+
 	"honnef.co/go/gutter/animation"
 	"honnef.co/go/gutter/lottie/lottie_model"
 	"honnef.co/go/gutter/render"
@@ -14,6 +18,10 @@ import (
 type LottieFrame struct {
 	Composition *lottie_model.Composition
 	Frame       float64
+	// Values for the composition's slots, by slot ID.
+	Slots map[string]lottie_model.SlotValue
+	// The number of bytes to use for caching rendered frames.
+	FrameCacheSize int
 }
 
 // CreateRenderObject implements RenderObjectWidget.
@@ -21,6 +29,8 @@ func (l *LottieFrame) CreateRenderObject(ctx widget.BuildContext) render.Object
 	var obj render.Lottie
 	obj.SetComposition(l.Composition)
 	obj.SetFrame(l.Frame)
+	obj.SetSlots(l.Slots)
+	obj.SetFrameCacheSize(l.FrameCacheSize)
 	return &obj
 }
 
@@ -29,6 +39,8 @@ func (l *LottieFrame) UpdateRenderObject(ctx widget.BuildContext, obj render.Obj
 	obj_ := obj.(*render.Lottie)
 	obj_.SetComposition(l.Composition)
 	obj_.SetFrame(l.Frame)
+	obj_.SetSlots(l.Slots)
+	obj_.SetFrameCacheSize(l.FrameCacheSize)
 }
 
 type Lottie struct {
@@ -38,11 +50,32 @@ type Lottie struct {
 	Height      float64
 	// The animation controller that should drive the animation. If nil,
 	// animation will be handled implicitly.
+	//
+	// The controller's value is the frame to render. The widget only listens
+	// to the controller and leaves configuring and running it to its owner;
+	// Segment, EndSegment, Animate, Repeat, and Reverse only affect the
+	// implicit animation. To play a segment, set the controller's bounds to
+	// the frames of the segment's marker, see
+	// [lottie_model.Composition.Marker].
 	Controller *animation.Controller
-	// TODO(dh): support specifying first and last frame to render
-	Animate bool
-	Repeat  bool
-	Reverse bool
+	// The name of a marker whose frames to play instead of the whole
+	// animation.
+	Segment string
+	// The name of a marker at whose end to stop playing. Combined with
+	// Segment, this plays the frames from the start of one marker to the end
+	// of another. Unknown markers are ignored.
+	EndSegment string
+	Animate    bool
+	Repeat     bool
+	Reverse    bool
+	// Values for the composition's slots, by slot ID. This allows theming a
+	// single animation, for example to match a color palette.
+	Slots map[string]lottie_model.SlotValue
+	// FrameCacheSize limits the memory, in bytes, used to cache rendered
+	// frames. Caching frames benefits short, looping animations, especially
+	// when many of them are shown at once. If zero, frames aren't cached.
+	// Cached animations play at their native frame rate.
+	FrameCacheSize int
 }
 
 // CreateState implements StatefulWidget.
@@ -55,6 +88,18 @@ func (l *Lottie) CreateElement() widget.Element {
 	return widget.NewInteriorElement(l)
 }
 
+// frames returns the range of frames to play.
+func (l *Lottie) frames() (first, last float64) {
+	first, last = l.Composition.FirstFrame, l.Composition.LastFrame
+	if m, ok := l.Composition.Marker(l.Segment); ok && l.Segment != "" {
+		first, last = m.Frame, m.End()
+	}
+	if m, ok := l.Composition.Marker(l.EndSegment); ok && l.EndSegment != "" {
+		last = m.End()
+	}
+	return first, last
+}
+
 type lottieState struct {
 	widget.StateHandle[*Lottie]
 
@@ -66,21 +111,26 @@ type lottieState struct {
 func (l *lottieState) Transition(t widget.StateTransition[*Lottie]) {
 	switch t.Kind {
 	case widget.StateInitializing:
-		// XXX guard against malformed frame numbers and frame rates
 		l.autoAnimation = animation.NewController(l.GetStateHandle().BuildOwner())
-		l.autoAnimation.Duration = l.Widget.Composition.Duration()
-		l.autoAnimation.LowerBound = l.Widget.Composition.FirstFrame
-		l.autoAnimation.UpperBound = l.Widget.Composition.LastFrame
-		l.updateAutoAnimation()
+		l.updateAnimation()
 	case widget.StateUpdatedWidget:
-		if l.Widget.Composition != t.OldWidget.Composition ||
-			l.Widget.Controller != t.OldWidget.Controller {
-			l.autoAnimation.Duration = l.Widget.Composition.Duration()
-			l.autoAnimation.LowerBound = l.Widget.Composition.FirstFrame
-			l.autoAnimation.UpperBound = l.Widget.Composition.LastFrame
-			l.updateAutoAnimation()
+		old, cur := t.OldWidget, l.Widget
+		animationChanged := old.Composition != cur.Composition ||
+			old.Controller != cur.Controller ||
+			old.Segment != cur.Segment ||
+			old.EndSegment != cur.EndSegment ||
+			old.Animate != cur.Animate ||
+			old.Repeat != cur.Repeat ||
+			old.Reverse != cur.Reverse
+		if animationChanged {
+			l.updateAnimation()
 		}
-		if *l.Widget != *t.OldWidget {
+		if animationChanged ||
+			old.Fit != cur.Fit ||
+			old.Width != cur.Width ||
+			old.Height != cur.Height ||
+			old.FrameCacheSize != cur.FrameCacheSize ||
+			!maps.Equal(old.Slots, cur.Slots) {
 			widget.MarkNeedsBuild(l.Element)
 		}
 	case widget.StateDisposing:
@@ -88,18 +138,7 @@ func (l *lottieState) Transition(t widget.StateTransition[*Lottie]) {
 	}
 }
 
-func (l *lottieState) updateAutoAnimation() {
-	l.autoAnimation.Stop()
-	if l.Widget.Animate && l.Widget.Controller == nil {
-		if l.Widget.Repeat {
-			l.autoAnimation.Repeat(l.Widget.Reverse, -1)
-		} else {
-			l.autoAnimation.Forward()
-		}
-	}
-}
-
-func (l *lottieState) animation() animation.Animation[float64] {
+func (l *lottieState) controller() *animation.Controller {
 	if l.Widget.Controller != nil {
 		return l.Widget.Controller
 	} else {
@@ -107,12 +146,32 @@ func (l *lottieState) animation() animation.Animation[float64] {
 	}
 }
 
+// updateAnimation configures the implicit animation for the frames to play and
+// starts playing them from the beginning.
+func (l *lottieState) updateAnimation() {
+	c := l.autoAnimation
+	c.Stop()
+	// XXX guard against malformed frame numbers and frame rates
+	first, last := l.Widget.frames()
+	c.LowerBound = first
+	c.UpperBound = last
+	c.Duration = time.Duration(math.Round((last - first) / l.Widget.Composition.Framerate * float64(time.Second)))
+	c.Reset()
+	if l.Widget.Animate && l.Widget.Controller == nil {
+		if l.Widget.Repeat {
+			c.Repeat(l.Widget.Reverse, -1)
+		} else {
+			c.Forward()
+		}
+	}
+}
+
 // Build implements State.
 func (l *lottieState) Build(ctx widget.BuildContext) widget.Widget {
 	return &ListenableBuilder{
-		Listenable: l.animation(),
+		Listenable: l.controller(),
 		Builder: func(ctx widget.BuildContext, child widget.Widget) widget.Widget {
-			frame := l.animation().Value()
+			frame := l.controller().Value()
 			w := l.Widget.Width
 			h := l.Widget.Height
 			ar := float64(l.Widget.Composition.Width) / float64(l.Widget.Composition.Height)
@@ -131,8 +190,10 @@ func (l *lottieState) Build(ctx widget.BuildContext) widget.Widget {
 					Fit:  l.Widget.Fit,
 					Clip: true,
 					Child: &LottieFrame{
-						Composition: l.Widget.Composition,
-						Frame:       frame,
+						Composition:    l.Widget.Composition,
+						Frame:          frame,
+						Slots:          l.Widget.Slots,
+						FrameCacheSize: l.Widget.FrameCacheSize,
 					},
 				},
 			}
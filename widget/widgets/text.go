@@ -13,7 +13,45 @@ import (
 	"honnef.co/go/stuff/container/maybe"
 )
 
-type WidgetSpan interface{}
+var _ paint.PlaceholderSpan = (*WidgetSpan)(nil)
+
+// WidgetSpan embeds a widget in text. The widget must also be one of the
+// children of the RichText displaying the text; see WidgetSpanChildren.
+type WidgetSpan struct {
+	Child     widget.Widget
+	Alignment paint.PlaceholderAlignment
+	// The baseline to align to, if Alignment is
+	// paint.PlaceholderAlignmentBaseline.
+	Baseline paint.TextBaseline
+	Style    maybe.Option[text.Style]
+}
+
+// Build implements paint.InlineSpan.
+func (ws *WidgetSpan) Build(pb *text.ParagraphBuilder, dimensions []paint.PlaceholderDimensions) {
+	if style, ok := ws.Style.Get(); ok {
+		pb.PushStyle(&style)
+		defer pb.PopStyle()
+	}
+	paint.AddPlaceholder(pb, ws, dimensions)
+}
+
+// PlaceholderAlignment implements paint.PlaceholderSpan.
+func (ws *WidgetSpan) PlaceholderAlignment() paint.PlaceholderAlignment { return ws.Alignment }
+
+// PlaceholderBaseline implements paint.PlaceholderSpan.
+func (ws *WidgetSpan) PlaceholderBaseline() paint.TextBaseline { return ws.Baseline }
+
+// WidgetSpanChildren returns the widgets of all WidgetSpans in span, in the
+// order expected by RichText.Children.
+func WidgetSpanChildren(span paint.InlineSpan) []widget.Widget {
+	var out []widget.Widget
+	for ph := range paint.PlaceholderSpans(span) {
+		if ws, ok := ph.(*WidgetSpan); ok {
+			out = append(out, ws.Child)
+		}
+	}
+	return out
+}
 
 var _ widget.RenderObjectWidget = (*RichText)(nil)
 
@@ -31,16 +69,30 @@ type RichText struct {
 	// XXX textHeightBehavior (why is there no height field?)
 	// XXX selection registrar
 	// XXX selection color
+
+	// The widgets embedded in Text via WidgetSpans, in order. Use
+	// WidgetSpanChildren to compute them.
+	Children []widget.Widget
+}
+
+func (r *RichText) attributes() render.ParagraphAttributes {
+	return render.ParagraphAttributes{
+		Text:      r.Text,
+		TextAlign: r.TextAlign,
+		// XXX default to the ambient directionality once we have it
+		TextDirection: r.TextDirection.UnwrapOr(bidi.LeftToRight),
+		SingleLine:    r.SingleLine,
+		Overflow:      r.Overflow,
+		MaxLines:      r.MaxLines,
+	}
 }
 
 // CreateRenderObject implements widget.RenderObjectWidget.
 func (r *RichText) CreateRenderObject(ctx widget.BuildContext) render.Object {
-	// XXX
-	return &render.Paragraph{}
+	return render.NewParagraph(r.attributes())
 }
 
 // UpdateRenderObject implements widget.RenderObjectWidget.
 func (r *RichText) UpdateRenderObject(ctx widget.BuildContext, obj render.Object) {
-	panic("unimplemented")
-	// XXX update all the fields
+	obj.(*render.Paragraph).SetAttributes(r.attributes())
 }
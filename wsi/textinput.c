@@ -0,0 +1,32 @@
+/*
+ * SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+#include "./textinput.h"
+
+// The exported Go functions take the user data as a uintptr_t and strings as
+// char *, which is why we have to cast them to the listeners' exact types.
+
+static const struct wl_registry_listener registry_listener = {
+  .global = (void (*)(void *, struct wl_registry *, uint32_t, const char *, uint32_t)) wsiRegistryGlobal,
+  .global_remove = (void (*)(void *, struct wl_registry *, uint32_t)) wsiRegistryGlobalRemove,
+};
+
+static const struct zwp_text_input_v3_listener text_input_listener = {
+  .enter = (void (*)(void *, struct zwp_text_input_v3 *, struct wl_surface *)) wsiTextInputEnter,
+  .leave = (void (*)(void *, struct zwp_text_input_v3 *, struct wl_surface *)) wsiTextInputLeave,
+  .preedit_string = (void (*)(void *, struct zwp_text_input_v3 *, const char *, int32_t, int32_t)) wsiTextInputPreeditString,
+  .commit_string = (void (*)(void *, struct zwp_text_input_v3 *, const char *)) wsiTextInputCommitString,
+  .delete_surrounding_text = (void (*)(void *, struct zwp_text_input_v3 *, uint32_t, uint32_t)) wsiTextInputDeleteSurroundingText,
+  .done = (void (*)(void *, struct zwp_text_input_v3 *, uint32_t)) wsiTextInputDone,
+};
+
+void wsi_registry_add_listener(struct wl_registry *registry, uintptr_t data) {
+  wl_registry_add_listener(registry, &registry_listener, (void *) data);
+}
+
+void wsi_text_input_add_listener(struct zwp_text_input_v3 *text_input, uintptr_t data) {
+  zwp_text_input_v3_add_listener(text_input, &text_input_listener, (void *) data);
+}
@@ -0,0 +1,499 @@
+// SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+//
+// SPDX-License-Identifier: MIT
+
+package wsi
+
+// Support for input methods via text-input-unstable-v3.
+//
+// libwayland doesn't bind wl_seat or any of the text input protocols, so we
+// talk to libwayland-client directly. We create our own wl_registry on the
+// shared display and bind wl_seat and zwp_text_input_manager_v3 through it.
+// Because all of our objects live on the default queue, their events get
+// dispatched by the same calls to DispatchPending that drive the rest of the
+// event loop.
+//
+// Text input focus follows keyboard focus and is per seat. Each seat gets its
+// own zwp_text_input_v3 object. Windows record whether the application wants
+// text input, as well as the cursor rectangle and surrounding text, and we
+// forward that state to every text input object whose focus is on the window.
+
+// #cgo pkg-config: wayland-client
+// #include <stdlib.h>
+// #include <wayland-client.h>
+// #include "text-input-unstable-v3-client-protocol.h"
+// #include "textinput.h"
+import "C"
+
+import (
+	"runtime/cgo"
+	"unsafe"
+
+	"honnef.co/go/jello/mem"
+)
+
+// A PreeditString event replaces the text that is currently being composed by
+// an input method. The preedit text should be displayed at the cursor position,
+// in a way that distinguishes it from committed text, usually by underlining
+// it. An empty Text removes the preedit text.
+//
+// Preedit text is not part of the text being edited and must not be reported
+// via [WaylandWindow.SetTextInputSurroundingText].
+type PreeditString struct {
+	Text string
+	// CursorBegin and CursorEnd are byte offsets into Text that describe the
+	// cursor or selection inside the preedit text. When both are equal, the
+	// cursor is a caret. When both are -1, the cursor should be hidden.
+	CursorBegin, CursorEnd int
+}
+
+// A CommitString event inserts text at the cursor position, replacing the
+// selection, if any. The cursor should be placed after the inserted text.
+type CommitString struct {
+	Text string
+}
+
+// A DeleteSurroundingText event deletes text around the cursor. The lengths
+// are in bytes and exclude any preedit text. If there is a selection, the
+// lengths are relative to the selection's bounds instead of the cursor.
+type DeleteSurroundingText struct {
+	BeforeLength int
+	AfterLength  int
+}
+
+// ContentHint is a bitmask that modifies the behavior of input methods.
+type ContentHint uint32
+
+const (
+	ContentHintNone               ContentHint = C.ZWP_TEXT_INPUT_V3_CONTENT_HINT_NONE
+	ContentHintCompletion         ContentHint = C.ZWP_TEXT_INPUT_V3_CONTENT_HINT_COMPLETION
+	ContentHintSpellcheck         ContentHint = C.ZWP_TEXT_INPUT_V3_CONTENT_HINT_SPELLCHECK
+	ContentHintAutoCapitalization ContentHint = C.ZWP_TEXT_INPUT_V3_CONTENT_HINT_AUTO_CAPITALIZATION
+	ContentHintLowercase          ContentHint = C.ZWP_TEXT_INPUT_V3_CONTENT_HINT_LOWERCASE
+	ContentHintUppercase          ContentHint = C.ZWP_TEXT_INPUT_V3_CONTENT_HINT_UPPERCASE
+	ContentHintTitlecase          ContentHint = C.ZWP_TEXT_INPUT_V3_CONTENT_HINT_TITLECASE
+	ContentHintHiddenText         ContentHint = C.ZWP_TEXT_INPUT_V3_CONTENT_HINT_HIDDEN_TEXT
+	ContentHintSensitiveData      ContentHint = C.ZWP_TEXT_INPUT_V3_CONTENT_HINT_SENSITIVE_DATA
+	ContentHintLatin              ContentHint = C.ZWP_TEXT_INPUT_V3_CONTENT_HINT_LATIN
+	ContentHintMultiline          ContentHint = C.ZWP_TEXT_INPUT_V3_CONTENT_HINT_MULTILINE
+)
+
+// ContentPurpose describes the primary purpose of a text field.
+type ContentPurpose uint32
+
+const (
+	ContentPurposeNormal   ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_NORMAL
+	ContentPurposeAlpha    ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_ALPHA
+	ContentPurposeDigits   ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_DIGITS
+	ContentPurposeNumber   ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_NUMBER
+	ContentPurposePhone    ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_PHONE
+	ContentPurposeURL      ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_URL
+	ContentPurposeEmail    ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_EMAIL
+	ContentPurposeName     ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_NAME
+	ContentPurposePassword ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_PASSWORD
+	ContentPurposePIN      ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_PIN
+	ContentPurposeDate     ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_DATE
+	ContentPurposeTime     ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_TIME
+	ContentPurposeDatetime ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_DATETIME
+	ContentPurposeTerminal ContentPurpose = C.ZWP_TEXT_INPUT_V3_CONTENT_PURPOSE_TERMINAL
+)
+
+// windowTextInput is the text input state of a window, as requested by the
+// application.
+type windowTextInput struct {
+	enabled bool
+
+	cursorX, cursorY, cursorWidth, cursorHeight int
+	surrounding                                 string
+	surroundingCursor, surroundingAnchor        int
+	hasSurrounding                              bool
+	hint                                        ContentHint
+	purpose                                     ContentPurpose
+
+	// gen is incremented every time the state changes. Text inputs compare
+	// it with the generation they last sent to the compositor.
+	gen uint64
+}
+
+type textInputManager struct {
+	sys  *System
+	self cgo.Handle
+	reg  *C.struct_wl_registry
+	mgr  *C.struct_zwp_text_input_manager_v3
+
+	seats  map[uint32]*C.struct_wl_seat
+	inputs map[uint32]*textInput
+}
+
+type textInput struct {
+	sys  *System
+	self cgo.Handle
+	hnd  *C.struct_zwp_text_input_v3
+
+	// The window that has text input focus, if any.
+	focus *WaylandWindow
+	// Whether we've enabled text input on the focused window.
+	enabled bool
+	// The generation of windowTextInput that we last committed.
+	sentGen uint64
+	// The number of commit requests we've made. The compositor echoes this
+	// number in done events, which lets us tell whether it has seen our latest
+	// state.
+	commits uint32
+	// The serial of the most recent done event.
+	doneSerial uint32
+
+	// Double-buffered state that gets applied by done events.
+	pendingPreedit PreeditString
+	pendingCommit  string
+	pendingBefore  uint32
+	pendingAfter   uint32
+}
+
+func newTextInputManager(sys *System) *textInputManager {
+	tim := &textInputManager{
+		sys:    sys,
+		seats:  make(map[uint32]*C.struct_wl_seat),
+		inputs: make(map[uint32]*textInput),
+	}
+	tim.self = cgo.NewHandle(tim)
+	tim.reg = C.wl_display_get_registry((*C.struct_wl_display)(sys.wl.dsp.Handle()))
+	C.wsi_registry_add_listener(tim.reg, C.uintptr_t(tim.self))
+	// Wait for the initial burst of globals.
+	sys.wl.dsp.Roundtrip()
+	return tim
+}
+
+func (tim *textInputManager) destroy() {
+	for _, ti := range tim.inputs {
+		ti.destroy()
+	}
+	for _, seat := range tim.seats {
+		C.wl_seat_destroy(seat)
+	}
+	if tim.mgr != nil {
+		C.zwp_text_input_manager_v3_destroy(tim.mgr)
+	}
+	C.wl_registry_destroy(tim.reg)
+	tim.self.Delete()
+}
+
+func (tim *textInputManager) addSeat(name uint32, seat *C.struct_wl_seat) {
+	tim.seats[name] = seat
+	if tim.mgr != nil {
+		tim.createTextInput(name)
+	}
+}
+
+func (tim *textInputManager) createTextInput(seatName uint32) {
+	ti := &textInput{
+		sys: tim.sys,
+		hnd: C.zwp_text_input_manager_v3_get_text_input(tim.mgr, tim.seats[seatName]),
+	}
+	ti.self = cgo.NewHandle(ti)
+	C.wsi_text_input_add_listener(ti.hnd, C.uintptr_t(ti.self))
+	tim.inputs[seatName] = ti
+}
+
+// update forwards the window's text input state to all text inputs that are
+// focused on it.
+func (tim *textInputManager) update(win *WaylandWindow) {
+	if tim == nil {
+		return
+	}
+	for _, ti := range tim.inputs {
+		if ti.focus == win {
+			ti.sync()
+		}
+	}
+}
+
+func (ti *textInput) destroy() {
+	C.zwp_text_input_v3_destroy(ti.hnd)
+	ti.self.Delete()
+}
+
+// sync sends the focused window's state to the compositor, if it changed.
+func (ti *textInput) sync() {
+	win := ti.focus
+	if win == nil {
+		return
+	}
+	state := &win.textInput
+	switch {
+	case state.enabled && !ti.enabled:
+		// Enabling resets all state, so we have to send all of it, regardless
+		// of whether we've sent it before.
+		C.zwp_text_input_v3_enable(ti.hnd)
+		ti.enabled = true
+		ti.sendState(state)
+		ti.commit()
+	case !state.enabled && ti.enabled:
+		C.zwp_text_input_v3_disable(ti.hnd)
+		ti.enabled = false
+		ti.commit()
+	case state.enabled && state.gen != ti.sentGen:
+		if ti.doneSerial != ti.commits {
+			// The compositor hasn't caught up with our previous commit yet.
+			// The protocol requires us to wait for the matching done event
+			// before sending new state.
+			return
+		}
+		ti.sendState(state)
+		ti.commit()
+	}
+}
+
+func (ti *textInput) sendState(state *windowTextInput) {
+	if state.hasSurrounding {
+		cstr := C.CString(state.surrounding)
+		C.zwp_text_input_v3_set_surrounding_text(
+			ti.hnd,
+			cstr,
+			C.int32_t(state.surroundingCursor),
+			C.int32_t(state.surroundingAnchor),
+		)
+		C.free(unsafe.Pointer(cstr))
+	}
+	C.zwp_text_input_v3_set_content_type(ti.hnd, C.uint32_t(state.hint), C.uint32_t(state.purpose))
+	C.zwp_text_input_v3_set_cursor_rectangle(
+		ti.hnd,
+		C.int32_t(state.cursorX),
+		C.int32_t(state.cursorY),
+		C.int32_t(state.cursorWidth),
+		C.int32_t(state.cursorHeight),
+	)
+	ti.sentGen = state.gen
+}
+
+func (ti *textInput) commit() {
+	C.zwp_text_input_v3_commit(ti.hnd)
+	ti.commits++
+}
+
+func (ti *textInput) emit(ev any) {
+	win := ti.focus
+	win.sys.app.WindowEvent(
+		mem.Make(&win.sys.eventArena, Context{Window: win}),
+		ev,
+	)
+}
+
+func (sys *System) windowForSurface(surf *C.struct_wl_surface) *WaylandWindow {
+	for _, win := range sys.windows {
+		if win.surf.Handle() == unsafe.Pointer(surf) {
+			return win
+		}
+	}
+	return nil
+}
+
+//export wsiRegistryGlobal
+func wsiRegistryGlobal(
+	data C.uintptr_t,
+	reg *C.struct_wl_registry,
+	name C.uint32_t,
+	iface *C.char,
+	version C.uint32_t,
+) {
+	tim := cgo.Handle(data).Value().(*textInputManager)
+	switch C.GoString(iface) {
+	case "wl_seat":
+		// We don't care about any of the seat's events, only its identity.
+		seat := (*C.struct_wl_seat)(C.wl_registry_bind(reg, name, &C.wl_seat_interface, 1))
+		tim.addSeat(uint32(name), seat)
+	case "zwp_text_input_manager_v3":
+		tim.mgr = (*C.struct_zwp_text_input_manager_v3)(
+			C.wl_registry_bind(reg, name, &C.zwp_text_input_manager_v3_interface, 1))
+		for seatName := range tim.seats {
+			tim.createTextInput(seatName)
+		}
+	}
+}
+
+//export wsiRegistryGlobalRemove
+func wsiRegistryGlobalRemove(data C.uintptr_t, reg *C.struct_wl_registry, name C.uint32_t) {
+	tim := cgo.Handle(data).Value().(*textInputManager)
+	seat, ok := tim.seats[uint32(name)]
+	if !ok {
+		// XXX handle the text input manager going away
+		return
+	}
+	if ti, ok := tim.inputs[uint32(name)]; ok {
+		if ti.focus != nil {
+			ti.emit(mem.Make(&ti.sys.eventArena, PreeditString{CursorBegin: -1, CursorEnd: -1}))
+		}
+		ti.destroy()
+		delete(tim.inputs, uint32(name))
+	}
+	C.wl_seat_destroy(seat)
+	delete(tim.seats, uint32(name))
+}
+
+//export wsiTextInputEnter
+func wsiTextInputEnter(data C.uintptr_t, hnd *C.struct_zwp_text_input_v3, surf *C.struct_wl_surface) {
+	ti := cgo.Handle(data).Value().(*textInput)
+	ti.focus = ti.sys.windowForSurface(surf)
+	ti.enabled = false
+	ti.sync()
+}
+
+//export wsiTextInputLeave
+func wsiTextInputLeave(data C.uintptr_t, hnd *C.struct_zwp_text_input_v3, surf *C.struct_wl_surface) {
+	ti := cgo.Handle(data).Value().(*textInput)
+	if ti.focus == nil {
+		return
+	}
+	// The compositor ignores our requests until the next enter event, and it
+	// expects us to reset the preedit text.
+	ti.emit(mem.Make(&ti.sys.eventArena, PreeditString{CursorBegin: -1, CursorEnd: -1}))
+	ti.focus = nil
+	ti.enabled = false
+	ti.pendingPreedit = PreeditString{}
+	ti.pendingCommit = ""
+	ti.pendingBefore, ti.pendingAfter = 0, 0
+}
+
+//export wsiTextInputPreeditString
+func wsiTextInputPreeditString(
+	data C.uintptr_t,
+	hnd *C.struct_zwp_text_input_v3,
+	text *C.char,
+	cursorBegin, cursorEnd C.int32_t,
+) {
+	ti := cgo.Handle(data).Value().(*textInput)
+	ti.pendingPreedit = PreeditString{
+		CursorBegin: int(cursorBegin),
+		CursorEnd:   int(cursorEnd),
+	}
+	if text != nil {
+		ti.pendingPreedit.Text = C.GoString(text)
+	}
+}
+
+//export wsiTextInputCommitString
+func wsiTextInputCommitString(data C.uintptr_t, hnd *C.struct_zwp_text_input_v3, text *C.char) {
+	ti := cgo.Handle(data).Value().(*textInput)
+	if text != nil {
+		ti.pendingCommit = C.GoString(text)
+	} else {
+		ti.pendingCommit = ""
+	}
+}
+
+//export wsiTextInputDeleteSurroundingText
+func wsiTextInputDeleteSurroundingText(
+	data C.uintptr_t,
+	hnd *C.struct_zwp_text_input_v3,
+	before, after C.uint32_t,
+) {
+	ti := cgo.Handle(data).Value().(*textInput)
+	ti.pendingBefore = uint32(before)
+	ti.pendingAfter = uint32(after)
+}
+
+//export wsiTextInputDone
+func wsiTextInputDone(data C.uintptr_t, hnd *C.struct_zwp_text_input_v3, serial C.uint32_t) {
+	ti := cgo.Handle(data).Value().(*textInput)
+	ti.doneSerial = uint32(serial)
+
+	preedit := ti.pendingPreedit
+	commit := ti.pendingCommit
+	before, after := ti.pendingBefore, ti.pendingAfter
+	ti.pendingPreedit = PreeditString{}
+	ti.pendingCommit = ""
+	ti.pendingBefore, ti.pendingAfter = 0, 0
+
+	if ti.focus == nil {
+		return
+	}
+
+	// Emit the changes in the order mandated by the protocol: the old preedit
+	// text is replaced by the new one only after deleting surrounding text and
+	// inserting the commit string.
+	arena := &ti.sys.eventArena
+	if before != 0 || after != 0 {
+		ti.emit(mem.Make(arena, DeleteSurroundingText{
+			BeforeLength: int(before),
+			AfterLength:  int(after),
+		}))
+	}
+	if commit != "" {
+		ti.emit(mem.Make(arena, CommitString{Text: commit}))
+	}
+	ti.emit(mem.Make(arena, preedit))
+
+	// Any state changes the application made while we were waiting for the
+	// compositor can be sent now.
+	ti.sync()
+}
+
+// EnableTextInput requests input from input methods, such as IMEs, for the
+// window. It should be called when a text field gains focus. The window
+// receives [PreeditString], [CommitString], and [DeleteSurroundingText]
+// events while text input is enabled and the window has keyboard focus.
+func (win *WaylandWindow) EnableTextInput() {
+	if win.textInput.enabled {
+		return
+	}
+	// Enabling text input resets all state.
+	win.textInput = windowTextInput{
+		enabled: true,
+		gen:     win.textInput.gen + 1,
+	}
+	win.sys.textInput.update(win)
+}
+
+// DisableTextInput stops input from input methods. It should be called when a
+// text field loses focus.
+func (win *WaylandWindow) DisableTextInput() {
+	if !win.textInput.enabled {
+		return
+	}
+	win.textInput.enabled = false
+	win.textInput.gen++
+	win.sys.textInput.update(win)
+}
+
+// SetTextInputCursorRect sets the area around the text cursor, in logical
+// window coordinates. Input methods use it to position candidate windows.
+func (win *WaylandWindow) SetTextInputCursorRect(x, y, width, height int) {
+	ti := &win.textInput
+	if ti.cursorX == x && ti.cursorY == y && ti.cursorWidth == width && ti.cursorHeight == height {
+		return
+	}
+	ti.cursorX, ti.cursorY, ti.cursorWidth, ti.cursorHeight = x, y, width, height
+	ti.gen++
+	win.sys.textInput.update(win)
+}
+
+// SetTextInputSurroundingText informs input methods of the text around the
+// cursor, which they may use for predictions. The text should not contain
+// preedit text and should be limited to a reasonable amount of context. Cursor
+// and anchor are byte offsets into text; they are equal if nothing is
+// selected.
+func (win *WaylandWindow) SetTextInputSurroundingText(text string, cursor, anchor int) {
+	ti := &win.textInput
+	if ti.hasSurrounding && ti.surrounding == text && ti.surroundingCursor == cursor && ti.surroundingAnchor == anchor {
+		return
+	}
+	ti.surrounding = text
+	ti.surroundingCursor = cursor
+	ti.surroundingAnchor = anchor
+	ti.hasSurrounding = true
+	ti.gen++
+	win.sys.textInput.update(win)
+}
+
+// SetTextInputContentType informs input methods of the kind of text that is
+// being edited.
+func (win *WaylandWindow) SetTextInputContentType(hint ContentHint, purpose ContentPurpose) {
+	ti := &win.textInput
+	if ti.hint == hint && ti.purpose == purpose {
+		return
+	}
+	ti.hint = hint
+	ti.purpose = purpose
+	ti.gen++
+	win.sys.textInput.update(win)
+}
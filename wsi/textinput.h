@@ -0,0 +1,66 @@
+/*
+ * SPDX-FileCopyrightText: 2026 Dominik Honnef and contributors
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+#include <stdint.h>
+#include <wayland-client.h>
+#include "text-input-unstable-v3-client-protocol.h"
+
+void wsiRegistryGlobal(
+  uintptr_t data,
+  struct wl_registry *registry,
+  uint32_t name,
+  char *interface,
+  uint32_t version
+);
+
+void wsiRegistryGlobalRemove(
+  uintptr_t data,
+  struct wl_registry *registry,
+  uint32_t name
+);
+
+void wsiTextInputEnter(
+  uintptr_t data,
+  struct zwp_text_input_v3 *text_input,
+  struct wl_surface *surface
+);
+
+void wsiTextInputLeave(
+  uintptr_t data,
+  struct zwp_text_input_v3 *text_input,
+  struct wl_surface *surface
+);
+
+void wsiTextInputPreeditString(
+  uintptr_t data,
+  struct zwp_text_input_v3 *text_input,
+  char *text,
+  int32_t cursor_begin,
+  int32_t cursor_end
+);
+
+void wsiTextInputCommitString(
+  uintptr_t data,
+  struct zwp_text_input_v3 *text_input,
+  char *text
+);
+
+void wsiTextInputDeleteSurroundingText(
+  uintptr_t data,
+  struct zwp_text_input_v3 *text_input,
+  uint32_t before_length,
+  uint32_t after_length
+);
+
+void wsiTextInputDone(
+  uintptr_t data,
+  struct zwp_text_input_v3 *text_input,
+  uint32_t serial
+);
+
+void wsi_registry_add_listener(struct wl_registry *registry, uintptr_t data);
+
+void wsi_text_input_add_listener(struct zwp_text_input_v3 *text_input, uintptr_t data);
@@ -29,6 +29,7 @@ type System struct {
 	requestedFrame chan struct{}
 	eventArena     mem.Arena
 	windows        []*WaylandWindow
+	textInput      *textInputManager
 
 	mu              sync.Mutex
 	requestedFrames map[*WaylandWindow]struct{}
@@ -285,6 +286,7 @@ func (sys *System) Run(ctx context.Context) (err error) {
 		return fmt.Errorf("couldn't connect to Wayland: %w", err)
 	}
 	sys.wl = dsp
+	sys.textInput = newTextInputManager(sys)
 	defer sys.cleanup()
 
 	// Call PrepareRead so we're ready to start polling.
@@ -446,6 +448,7 @@ func (sys *System) cleanup() {
 		win.xdgSurf.Destroy()
 		win.surf.Destroy()
 	}
+	sys.textInput.destroy()
 	if sys.wl.porter != nil {
 		sys.wl.porter.Destroy()
 	}
@@ -697,6 +700,9 @@ type WaylandWindow struct {
 	size    LogicalSize
 	scale   float64
 
+	// text input state requested by the application
+	textInput windowTextInput
+
 	needNewBuffers bool
 	buffers        []*Buffer
 